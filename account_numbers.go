@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+)
+
+// runAccountNumbers implements `simple-bank account-numbers`. It gives every
+// account opened before account numbers existed one of its own, and can be
+// rerun safely since accounts that have a number are left alone.
+func runAccountNumbers(store db.Store, bankCode string, args []string) int {
+	flags := flag.NewFlagSet("account-numbers", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 1000, "accounts numbered per query")
+	flags.Parse(args)
+
+	if bankCode == "" {
+		bankCode = utils.DefaultBankCode
+	}
+
+	ctx := context.Background()
+	assigned := 0
+	for {
+		accounts, err := store.ListAccountsWithoutNumber(ctx, int32(*batchSize))
+		if err != nil {
+			log.Printf("[ERROR] cannot list accounts : %v", err)
+			return 2
+		}
+		if len(accounts) == 0 {
+			break
+		}
+
+		for _, account := range accounts {
+			sequence, err := store.NextAccountSequence(ctx)
+			if err != nil {
+				log.Printf("[ERROR] cannot draw account sequence : %v", err)
+				return 2
+			}
+
+			number, err := utils.NewAccountNumber(bankCode, account.CurrencyCode, sequence)
+			if err != nil {
+				log.Printf("[ERROR] cannot number account %d : %v", account.ID, err)
+				return 2
+			}
+
+			_, err = store.SetAccountNumber(ctx, db.SetAccountNumberParams{
+				ID:            account.ID,
+				AccountNumber: sql.NullString{String: number, Valid: true},
+			})
+			if err != nil {
+				log.Printf("[ERROR] cannot number account %d : %v", account.ID, err)
+				return 2
+			}
+			assigned++
+		}
+	}
+
+	log.Printf("[INFO] assigned %d account numbers", assigned)
+	return 0
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
@@ -19,6 +21,7 @@ var (
 
 type createAccountRequest struct {
 	CurrencyCode string `json:"currency_code" binding:"required,oneof=USD EUR GBP NGN AUD CAD CDF"`
+	ProductCode  string `json:"product_code" binding:"required,oneof=checking savings escrow business"`
 }
 
 func (server *Server) createAccountHandler(ctx *gin.Context) {
@@ -29,14 +32,30 @@ func (server *Server) createAccountHandler(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.productAllowed(ctx, authPayload.Username, request.ProductCode) {
+		return
+	}
+
+	accountNumber, err := server.newAccountNumber(ctx, request.CurrencyCode)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	arg := db.CreateAccountParams{
-		Owner:        authPayload.Username,
-		CurrencyCode: request.CurrencyCode,
-		Balance:      0,
+		Owner:         authPayload.Username,
+		CurrencyCode:  request.CurrencyCode,
+		Balance:       0,
+		ProductCode:   request.ProductCode,
+		AccountNumber: accountNumber,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.OpenAccountTrxn(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrAccountLimit) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
 		if perr, ok := err.(*pq.Error); ok {
 			switch perr.Code.Name() {
 			case ErrForeginKeyViolation, ErrUniqueViolation:
@@ -50,6 +69,20 @@ func (server *Server) createAccountHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, successResponse("account created successfully", account))
 }
 
+// newAccountNumber draws the external number of a new account in currencyCode
+func (server *Server) newAccountNumber(ctx *gin.Context, currencyCode string) (sql.NullString, error) {
+	sequence, err := server.store.NextAccountSequence(ctx)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	number, err := utils.NewAccountNumber(server.config.BankCode, currencyCode, sequence)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return nullString(number), nil
+}
+
 type getAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
@@ -73,10 +106,7 @@ func (server *Server) getAccountHandler(ctx *gin.Context) {
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
 		return
 	}
 
@@ -84,10 +114,12 @@ func (server *Server) getAccountHandler(ctx *gin.Context) {
 }
 
 type listAccountsRequest struct {
-	PageID   int32 `json:"page_id" binding:"required,min=1"`
-	PageSize int32 `json:"page_size" binding:"required,min=5,max=10"`
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
 }
 
+// listAccountHandler lists the accounts the user owns along with the joint
+// accounts they are an active member of
 func (server *Server) listAccountHandler(ctx *gin.Context) {
 	var request listAccountsRequest
 
@@ -100,9 +132,9 @@ func (server *Server) listAccountHandler(ctx *gin.Context) {
 
 	offset := (request.PageID - 1) * request.PageSize
 	arg := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Offset: offset,
-		Limit:  request.PageSize,
+		Username:   authPayload.Username,
+		PageOffset: offset,
+		PageLimit:  request.PageSize,
 	}
 
 	accounts, err := server.store.ListAccounts(ctx, arg)
@@ -111,30 +143,50 @@ func (server *Server) listAccountHandler(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+
+	if authPayload.ClientID != "" {
+		shared := accounts[:0]
+		for _, account := range accounts {
+			if authPayload.CanAccessAccount(account.ID) {
+				shared = append(shared, account)
+			}
+		}
+		accounts = shared
+	}
+
 	ctx.JSON(http.StatusOK, successResponse(
 		fmt.Sprintf("retrieved accounts from offset %d with size %d",
 			offset, request.PageSize),
 		accounts))
 }
 
-type deleteAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
-}
-
+// deleteAccount deletes an account for its owner or a co-owner
 func (server *Server) deleteAccount(ctx *gin.Context) {
-	var request deleteAccountRequest
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
 
-	if err := ctx.ShouldBindUri(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionManage); !ok {
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, request.ID)
+	err := server.store.DeleteAccount(ctx, account.ID)
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(fmt.Sprintf("deleted account with id (%d) successfully", account.ID), nil))
+}
 
+func (server *Server) listAccountProducts(ctx *gin.Context) {
+	products, err := server.store.ListAccountProducts(ctx)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(fmt.Sprintf("deleted account with id (%d) successfully", request.ID), nil))
+	ctx.JSON(http.StatusOK, successResponse("account products retrieved successfully", products))
 }
@@ -13,6 +13,7 @@ import (
 
 	mockdb "github.com/caleberi/simple-bank/db/mock"
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
 	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/golang/mock/gomock"
@@ -40,15 +41,23 @@ func Test_CreateAccount(t *testing.T) {
 
 	createAccountRequest := createAccountRequest{
 		CurrencyCode: utils.RandomCurrencyCode(),
+		ProductCode:  db.AccountProductChecking,
 	}
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	store := mockdb.NewMockStore(ctrl)
-	store.EXPECT().CreateAccount(gomock.Any(), db.CreateAccountParams{
-		CurrencyCode: createAccountRequest.CurrencyCode,
-		Balance:      0,
-		Owner:        user.Username,
+	accountNumber, err := utils.NewAccountNumber(utils.DefaultBankCode, createAccountRequest.CurrencyCode, 7)
+	require.NoError(t, err)
+
+	store.EXPECT().GetKycTierForUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(standardKYCTier(), nil)
+	store.EXPECT().NextAccountSequence(gomock.Any()).Times(1).Return(int64(7), nil)
+	store.EXPECT().OpenAccountTrxn(gomock.Any(), db.CreateAccountParams{
+		CurrencyCode:  createAccountRequest.CurrencyCode,
+		Balance:       0,
+		Owner:         user.Username,
+		ProductCode:   db.AccountProductChecking,
+		AccountNumber: sql.NullString{String: accountNumber, Valid: true},
 	}).Times(1).Return(account, nil)
 
 	url := "/accounts"
@@ -58,7 +67,7 @@ func Test_CreateAccount(t *testing.T) {
 
 	buf := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buf)
-	err := encoder.Encode(createAccountRequest)
+	err = encoder.Encode(createAccountRequest)
 	require.NoError(t, err)
 
 	// build  response struct
@@ -96,6 +105,118 @@ func Test_CreateAccount(t *testing.T) {
 
 }
 
+func Test_CreateAccountProduct(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+	account.ProductCode = db.AccountProductSavings
+	accountNumber, err := utils.NewAccountNumber(utils.DefaultBankCode, account.CurrencyCode, 8)
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name          string
+		productCode   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Savings",
+			productCode: db.AccountProductSavings,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycTierForUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.KycTier{
+					Tier:            "verified",
+					Level:           1,
+					AllowedProducts: []string{db.AccountProductChecking, db.AccountProductSavings},
+				}, nil)
+				store.EXPECT().NextAccountSequence(gomock.Any()).Times(1).Return(int64(8), nil)
+				store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Eq(db.CreateAccountParams{
+					CurrencyCode:  account.CurrencyCode,
+					Balance:       0,
+					Owner:         user.Username,
+					ProductCode:   db.AccountProductSavings,
+					AccountNumber: sql.NullString{String: accountNumber, Valid: true},
+				})).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:        "AccountLimitReached",
+			productCode: db.AccountProductSavings,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycTierForUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.KycTier{
+					Tier:            "verified",
+					Level:           1,
+					AllowedProducts: []string{db.AccountProductChecking, db.AccountProductSavings},
+				}, nil)
+				store.EXPECT().NextAccountSequence(gomock.Any()).Times(1).Return(int64(8), nil)
+				store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrAccountLimit)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "TierTooLow",
+			productCode: db.AccountProductSavings,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycTierForUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(standardKYCTier(), nil)
+				store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "MissingProduct",
+			productCode: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "UnknownProduct",
+			productCode: "premium",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(createAccountRequest{
+				CurrencyCode: account.CurrencyCode,
+				ProductCode:  tc.productCode,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func Test_GetAccount(t *testing.T) {
 	user, _ := randomUser(t)
 	account := generateRandomAccount(user.Username)
@@ -182,6 +303,7 @@ func Test_GetAccount(t *testing.T) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Any()).
 					Times(1)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationBearerType, "unauthorized_user", time.Minute)
@@ -233,6 +355,74 @@ func Test_GetAccount(t *testing.T) {
 
 }
 
+func Test_ListAccountsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	owned := generateRandomAccount(user.Username)
+	joint := generateRandomAccount(utils.RandomOwner())
+	joint.ID = owned.ID + 1
+
+	testcases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{
+						Username:   user.Username,
+						PageLimit:  5,
+						PageOffset: 0,
+					})).
+					Times(1).
+					Return([]db.Account{owned, joint}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data []db.Account `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				// the joint account the user is a member of is listed with their own
+				require.Equal(t, []db.Account{owned, joint}, body.Data)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/accounts?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Account) {
 
 	data, err := io.ReadAll(body)
@@ -252,3 +442,87 @@ func requireBodyMatchAccount(t *testing.T, body *bytes.Buffer, account db.Accoun
 	require.Equal(t, account, resultAccount)
 
 }
+
+func Test_DeleteAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotMember",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Spender",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.AccountMember{Role: access.RoleSpender, Status: access.MemberActive}, nil)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d", account.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
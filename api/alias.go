@@ -0,0 +1,337 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/alias"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+type aliasResponse struct {
+	Alias      string     `json:"alias"`
+	Kind       string     `json:"kind"`
+	Verified   bool       `json:"verified"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAliasResponse(a db.Alias) aliasResponse {
+	return aliasResponse{
+		Alias:      a.Alias,
+		Kind:       a.Kind,
+		Verified:   a.VerifiedAt.Valid,
+		VerifiedAt: nullTimePtr(a.VerifiedAt),
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+// registrableAlias normalizes an email address or phone number a user wants
+// to register, usernames are aliases already and can't be registered
+func registrableAlias(ctx *gin.Context, value string) (string, string, bool) {
+	kind := alias.Kind(value)
+	if kind == alias.KindUsername {
+		err := errors.New("only email addresses and phone numbers can be registered as aliases")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return "", "", false
+	}
+
+	normalized, err := alias.Normalize(kind, value)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return "", "", false
+	}
+	return normalized, kind, true
+}
+
+type createAliasRequest struct {
+	Alias string `json:"alias" binding:"required,max=254"`
+}
+
+// createAlias sends a verification code to an email address or phone number.
+// It only resolves to the user once they verify it, until then anyone can
+// claim it again once the code sent for it expires. Wrong guesses aren't
+// forgotten by claiming again, so each code only ever gets a few.
+func (server *Server) createAlias(ctx *gin.Context) {
+	var request createAliasRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	value, kind, ok := registrableAlias(ctx, request.Alias)
+	if !ok {
+		return
+	}
+
+	code, err := alias.NewCode()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	claimed, err := server.store.ClaimAlias(ctx, db.ClaimAliasParams{
+		Alias:         value,
+		Kind:          kind,
+		Username:      authPayload.Username,
+		CodeHash:      alias.HashCode(value, code),
+		CodeExpiresAt: time.Now().Add(alias.CodeTTL),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			server.respondAliasTaken(ctx, value)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.aliasSender.Send(ctx, kind, value, code); err != nil {
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("verification code sent", newAliasResponse(claimed)))
+}
+
+// respondAliasTaken answers a claim that was skipped, either someone verified
+// the alias or the code sent for it hasn't expired yet
+func (server *Server) respondAliasTaken(ctx *gin.Context, value string) {
+	claimed, err := server.store.GetAlias(ctx, value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err != nil || claimed.VerifiedAt.Valid {
+		err := fmt.Errorf("%s is already registered", value)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	err = fmt.Errorf("a verification code was already sent to %s, request a new one after %s",
+		value, claimed.CodeExpiresAt.Format(time.RFC3339))
+	ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+}
+
+type verifyAliasRequest struct {
+	Alias string `json:"alias" binding:"required,max=254"`
+	Code  string `json:"code" binding:"required,numeric"`
+}
+
+func (server *Server) verifyAlias(ctx *gin.Context) {
+	var request verifyAliasRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	value, _, ok := registrableAlias(ctx, request.Alias)
+	if !ok {
+		return
+	}
+
+	claimed, ok := server.userAlias(ctx, value)
+	if !ok {
+		return
+	}
+
+	switch {
+	case claimed.VerifiedAt.Valid:
+		err := fmt.Errorf("%s is already verified", value)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	case claimed.Attempts >= alias.MaxCodeAttempts:
+		err := errors.New("too many wrong codes, request a new one")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	case time.Now().After(claimed.CodeExpiresAt):
+		err := errors.New("verification code expired, request a new one")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !alias.CheckCode(value, request.Code, claimed.CodeHash) {
+		if _, err := server.store.IncrementAliasAttempts(ctx, value); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		err := errors.New("invalid verification code")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	verified, err := server.store.VerifyAlias(ctx, db.VerifyAliasParams{
+		Alias:    value,
+		Username: claimed.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("alias verified successfully", newAliasResponse(verified)))
+}
+
+// userAlias loads an alias claimed by the authenticated user, other users'
+// aliases look the same as missing ones
+func (server *Server) userAlias(ctx *gin.Context, value string) (db.Alias, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	claimed, err := server.store.GetAlias(ctx, value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return claimed, false
+	}
+	if err != nil || claimed.Username != authPayload.Username {
+		err := fmt.Errorf("alias %s does not exist", value)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return claimed, false
+	}
+
+	return claimed, true
+}
+
+func (server *Server) listAliases(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	aliases, err := server.store.ListUserAliases(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]aliasResponse, 0, len(aliases))
+	for _, a := range aliases {
+		response = append(response, newAliasResponse(a))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("aliases retrieved successfully", response))
+}
+
+type deleteAliasRequest struct {
+	Alias string `uri:"alias" binding:"required"`
+}
+
+func (server *Server) deleteAlias(ctx *gin.Context) {
+	var request deleteAliasRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	value, _, ok := registrableAlias(ctx, request.Alias)
+	if !ok {
+		return
+	}
+
+	claimed, ok := server.userAlias(ctx, value)
+	if !ok {
+		return
+	}
+
+	err := server.store.DeleteAlias(ctx, db.DeleteAliasParams{
+		Alias:    claimed.Alias,
+		Username: claimed.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("alias deleted successfully", nil))
+}
+
+type lookupAliasRequest struct {
+	Alias        string `form:"alias" binding:"required,max=254"`
+	CurrencyCode string `form:"currency_code" binding:"required,currency"`
+}
+
+type aliasLookupResponse struct {
+	Alias         string `json:"alias"`
+	Name          string `json:"name"`
+	CurrencyCode  string `json:"currency_code"`
+	AccountNumber string `json:"account_number"`
+}
+
+// lookupAlias shows who an alias pays before sending to it, only a masked name
+// and the account number the money would go to
+func (server *Server) lookupAlias(ctx *gin.Context) {
+	var request lookupAliasRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, account, ok := server.resolveAlias(ctx, request.Alias, request.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("alias resolved successfully", aliasLookupResponse{
+		Alias:         request.Alias,
+		Name:          alias.MaskName(user.FullName),
+		CurrencyCode:  account.CurrencyCode,
+		AccountNumber: account.AccountNumber.String,
+	}))
+}
+
+// resolveAlias finds the user a username or verified alias belongs to and
+// their account that receives payments in currencyCode
+func (server *Server) resolveAlias(ctx *gin.Context, value, currencyCode string) (db.User, db.Account, bool) {
+	kind := alias.Kind(value)
+	normalized, err := alias.Normalize(kind, value)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.User{}, db.Account{}, false
+	}
+
+	notFound := func() (db.User, db.Account, bool) {
+		err := fmt.Errorf("no one is registered as %s", value)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return db.User{}, db.Account{}, false
+	}
+
+	username := normalized
+	if kind != alias.KindUsername {
+		registered, err := server.store.GetAlias(ctx, normalized)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return db.User{}, db.Account{}, false
+		}
+		if err != nil || !registered.VerifiedAt.Valid {
+			return notFound()
+		}
+		username = registered.Username
+	}
+
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound()
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return user, db.Account{}, false
+	}
+
+	account, err := server.store.GetPrimaryAccount(ctx, db.GetPrimaryAccountParams{
+		Owner:        user.Username,
+		CurrencyCode: currencyCode,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("%s can't receive payments in %s", value, currencyCode)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return user, account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return user, account, false
+	}
+
+	return user, account, true
+}
@@ -0,0 +1,548 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/alias"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSender keeps the codes it is asked to send
+type recordingSender struct {
+	codes map[string]string
+}
+
+func (sender *recordingSender) Send(_ context.Context, _, alias, code string) error {
+	sender.codes[alias] = code
+	return nil
+}
+
+func Test_CreateAliasAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		alias         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder, sender *recordingSender)
+	}{
+		{
+			name:  "OK",
+			alias: " Ada@Example.com",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ClaimAliasParams) (db.Alias, error) {
+						require.Equal(t, "ada@example.com", arg.Alias)
+						require.Equal(t, alias.KindEmail, arg.Kind)
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now().Add(alias.CodeTTL), arg.CodeExpiresAt, time.Second)
+						return db.Alias{Alias: arg.Alias, Kind: arg.Kind, Username: arg.Username, CodeHash: arg.CodeHash}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, sender.codes, "ada@example.com")
+				require.NotContains(t, recorder.Body.String(), sender.codes["ada@example.com"])
+			},
+		},
+		{
+			name:  "Phone",
+			alias: "+234 (801) 234-5678",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ClaimAliasParams) (db.Alias, error) {
+						require.Equal(t, "+2348012345678", arg.Alias)
+						require.Equal(t, alias.KindPhone, arg.Kind)
+						return db.Alias{Alias: arg.Alias, Kind: arg.Kind, Username: arg.Username}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, sender.codes, "+2348012345678")
+			},
+		},
+		{
+			name:  "AlreadyVerified",
+			alias: "ada@example.com",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(1).Return(db.Alias{}, sql.ErrNoRows)
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq("ada@example.com")).Times(1).Return(db.Alias{
+					Alias:      "ada@example.com",
+					Username:   utils.RandomOwner(),
+					VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.Empty(t, sender.codes)
+			},
+		},
+		{
+			// claiming again doesn't reset the attempts or send another code
+			// until the locked out code expires
+			name:  "LockedOutCodeNotExpired",
+			alias: "ada@example.com",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(1).Return(db.Alias{}, sql.ErrNoRows)
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq("ada@example.com")).Times(1).Return(db.Alias{
+					Alias:         "ada@example.com",
+					Username:      user.Username,
+					Attempts:      alias.MaxCodeAttempts,
+					CodeExpiresAt: time.Now().Add(time.Minute),
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Empty(t, sender.codes)
+			},
+		},
+		{
+			name:  "Username",
+			alias: "ada",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPhone",
+			alias: "+234-801-abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, sender *recordingSender) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			sender := &recordingSender{codes: map[string]string{}}
+			server.aliasSender = sender
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"alias": tc.alias})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/aliases", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, sender)
+		})
+	}
+}
+
+func Test_VerifyAliasAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	const email = "ada@example.com"
+	const code = "123456"
+
+	claimed := db.Alias{
+		Alias:         email,
+		Kind:          alias.KindEmail,
+		Username:      user.Username,
+		CodeHash:      alias.HashCode(email, code),
+		CodeExpiresAt: time.Now().Add(alias.CodeTTL),
+	}
+	verifyArg := db.VerifyAliasParams{Alias: email, Username: user.Username}
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(claimed, nil)
+				verified := claimed
+				verified.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Eq(verifyArg)).Times(1).Return(verified, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"verified":true`)
+			},
+		},
+		{
+			name: "WrongCode",
+			code: "654321",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(claimed, nil)
+				store.EXPECT().IncrementAliasAttempts(gomock.Any(), gomock.Eq(email)).Times(1).Return(claimed, nil)
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyAttempts",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				locked := claimed
+				locked.Attempts = alias.MaxCodeAttempts
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(locked, nil)
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := claimed
+				expired.CodeExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(expired, nil)
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ClaimedByOther",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				theirs := claimed
+				theirs.Username = other.Username
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(theirs, nil)
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			code: code,
+			buildStubs: func(store *mockdb.MockStore) {
+				verified := claimed
+				verified.VerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(verified, nil)
+				store.EXPECT().VerifyAlias(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"alias": "Ada@example.com", "code": tc.code})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/aliases/verify", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_LookupAliasAPI(t *testing.T) {
+	sender, _ := randomUser(t)
+	recipient, _ := randomUser(t)
+	recipient.FullName = "Ada Lovelace King"
+
+	account := generateRandomAccount(recipient.Username)
+	account.CurrencyCode = utils.USD
+	account.AccountNumber = sql.NullString{String: "9990000000042", Valid: true}
+
+	const email = "ada@example.com"
+	primaryArg := db.GetPrimaryAccountParams{Owner: recipient.Username, CurrencyCode: utils.USD}
+
+	testCases := []struct {
+		name          string
+		alias         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "VerifiedEmail",
+			alias: email,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.Alias{
+					Alias:      email,
+					Kind:       alias.KindEmail,
+					Username:   recipient.Username,
+					VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(primaryArg)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data aliasLookupResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "Ada L. K.", response.Data.Name)
+				require.Equal(t, account.AccountNumber.String, response.Data.AccountNumber)
+				require.NotContains(t, recorder.Body.String(), recipient.Username)
+			},
+		},
+		{
+			name:  "Username",
+			alias: recipient.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(primaryArg)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Unverified",
+			alias: email,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(email)).Times(1).Return(db.Alias{
+					Alias:    email,
+					Kind:     alias.KindEmail,
+					Username: recipient.Username,
+				}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "NoAccountInCurrency",
+			alias: recipient.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(primaryArg)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			query := url.Values{"alias": {tc.alias}, "currency_code": {utils.USD}}
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/aliases/lookup?%s", query.Encode()), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, sender.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_TransferByAliasAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := generateRandomAccount(user1.Username)
+	account2 := generateRandomAccount(user2.Username)
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+
+	const phone = "+2348012345678"
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_alias":        "+234 801 234 5678",
+				"amount":          10,
+				"currency_code":   utils.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(phone)).Times(1).Return(db.Alias{
+					Alias:      phone,
+					Kind:       alias.KindPhone,
+					Username:   user2.Username,
+					VerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user2.Username)).Times(1).Return(user2, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(db.GetPrimaryAccountParams{
+					Owner:        user2.Username,
+					CurrencyCode: utils.USD,
+				})).Times(1).Return(account2, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Eq(db.TransferTxnParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        10,
+					InitiatedBy:   user1.Username,
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownAlias",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_alias":        phone,
+				"amount":          10,
+				"currency_code":   utils.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAlias(gomock.Any(), gomock.Eq(phone)).Times(1).Return(db.Alias{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AliasAndAccountNumber",
+			body: gin.H{
+				"from_account_id":   account1.ID,
+				"to_alias":          phone,
+				"to_account_number": "9990000000042",
+				"amount":            10,
+				"currency_code":     utils.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AliasAndAccountID",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"to_alias":        phone,
+				"amount":          10,
+				"currency_code":   utils.USD,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user1.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_NewAliasSender(t *testing.T) {
+	sender, err := newAliasSender(utils.Config{Environment: utils.EnvDevelopment})
+	require.NoError(t, err)
+	require.IsType(t, alias.LogSender{}, sender)
+
+	// codes must never end up in a production log
+	_, err = newAliasSender(utils.Config{AliasSender: alias.LogSenderName})
+	require.Error(t, err)
+
+	_, err = newAliasSender(utils.Config{})
+	require.Error(t, err)
+
+	sender, err = newAliasSender(utils.Config{
+		AliasSender:       alias.HTTPSenderName,
+		AliasSenderConfig: "https://notify.internal/codes",
+	})
+	require.NoError(t, err)
+	require.IsType(t, &alias.HTTPSender{}, sender)
+}
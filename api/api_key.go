@@ -0,0 +1,200 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type createAPIKeyRequest struct {
+	Name       string     `json:"name" binding:"required,max=64"`
+	Scopes     []string   `json:"scopes" binding:"required,min=1,dive,oneof=accounts:read accounts:write transfers:create"`
+	AllowedIPs []string   `json:"allowed_ips" binding:"omitempty,dive,required"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type apiKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createAPIKeyResponse struct {
+	Key    string         `json:"key"`
+	APIKey apiKeyResponse `json:"api_key"`
+}
+
+func newAPIKeyResponse(apiKey db.ApiKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.Scopes,
+		AllowedIPs: apiKey.AllowedIps,
+		ExpiresAt:  nullTimePtr(apiKey.ExpiresAt),
+		RevokedAt:  nullTimePtr(apiKey.RevokedAt),
+		LastUsedAt: nullTimePtr(apiKey.LastUsedAt),
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func (server *Server) createAPIKey(ctx *gin.Context) {
+	var request createAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	for _, rule := range request.AllowedIPs {
+		if !utils.ValidIPRule(rule) {
+			err := fmt.Errorf("%s is not a valid IP address or CIDR range", rule)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	expiresAt := sql.NullTime{}
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(time.Now()) {
+			err := errors.New("expires_at must be in the future")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		expiresAt = sql.NullTime{Time: *request.ExpiresAt, Valid: true}
+	}
+
+	allowedIPs := request.AllowedIPs
+	if allowedIPs == nil {
+		allowedIPs = []string{}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	key, apiKey, err := server.insertAPIKey(ctx, db.CreateApiKeyParams{
+		Owner:      authPayload.Username,
+		Name:       request.Name,
+		Scopes:     request.Scopes,
+		AllowedIps: allowedIPs,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("api key created, it will not be shown again", createAPIKeyResponse{
+		Key:    key,
+		APIKey: newAPIKeyResponse(apiKey),
+	}))
+}
+
+// maxAPIKeyAttempts bounds how many keys are generated when the prefix of a
+// new key is already taken by another one
+const maxAPIKeyAttempts = 3
+
+// apiKeyPrefixConstraint is the unique constraint on api_keys.prefix
+const apiKeyPrefixConstraint = "api_keys_prefix_key"
+
+// insertAPIKey generates a key and stores it. Keys are looked up by their
+// short prefix, which can collide with an existing key's, so a new key is
+// generated whenever the prefix is already taken.
+func (server *Server) insertAPIKey(ctx *gin.Context, arg db.CreateApiKeyParams) (string, db.ApiKey, error) {
+	var err error
+	for attempt := 0; attempt < maxAPIKeyAttempts; attempt++ {
+		var key string
+		key, arg.Prefix, err = utils.GenerateAPIKey()
+		if err != nil {
+			return "", db.ApiKey{}, err
+		}
+		arg.HashedKey = utils.HashAPIKey(key)
+
+		var apiKey db.ApiKey
+		apiKey, err = server.store.CreateApiKey(ctx, arg)
+		if err == nil {
+			return key, apiKey, nil
+		}
+		if pqErr, ok := err.(*pq.Error); !ok || pqErr.Code.Name() != ErrUniqueViolation || pqErr.Constraint != apiKeyPrefixConstraint {
+			return "", db.ApiKey{}, err
+		}
+	}
+	return "", db.ApiKey{}, err
+}
+
+type listAPIKeysRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listAPIKeys(ctx *gin.Context) {
+	var request listAPIKeysRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	apiKeys, err := server.store.ListApiKeys(ctx, db.ListApiKeysParams{
+		Owner:  authPayload.Username,
+		Limit:  request.PageSize,
+		Offset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]apiKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		response[i] = newAPIKeyResponse(apiKey)
+	}
+	ctx.JSON(http.StatusOK, successResponse("api keys retrieved successfully", response))
+}
+
+type revokeAPIKeyRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) revokeAPIKey(ctx *gin.Context) {
+	var request revokeAPIKeyRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	apiKey, err := server.store.RevokeApiKey(ctx, db.RevokeApiKeyParams{
+		ID:    request.ID,
+		Owner: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("active api key with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("api key revoked successfully", newAPIKeyResponse(apiKey)))
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
@@ -0,0 +1,423 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAPIKey(t *testing.T, owner string, scopes ...string) (apiKey db.ApiKey, key string) {
+	key, prefix, err := utils.GenerateAPIKey()
+	require.NoError(t, err)
+
+	apiKey = db.ApiKey{
+		ID:         utils.RandomInt(1, 1000),
+		Owner:      owner,
+		Name:       utils.RandomString(6),
+		Prefix:     prefix,
+		HashedKey:  utils.HashAPIKey(key),
+		Scopes:     scopes,
+		AllowedIps: []string{},
+		CreatedAt:  time.Now().Add(-time.Hour),
+	}
+	return
+}
+
+func addAPIKeyAuthorization(request *http.Request, key string) {
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", "ApiKey", key))
+}
+
+func Test_CreateAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	_, machineKey := randomAPIKey(t, user.Username, token.ScopeAccountsRead)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":        "reporting",
+				"scopes":      []string{token.ScopeAccountsRead, token.ScopeTransfersCreate},
+				"allowed_ips": []string{"10.0.0.0/24"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, []string{"10.0.0.0/24"}, arg.AllowedIps)
+						require.False(t, arg.ExpiresAt.Valid)
+						return db.ApiKey{
+							ID:         1,
+							Owner:      arg.Owner,
+							Name:       arg.Name,
+							Prefix:     arg.Prefix,
+							HashedKey:  arg.HashedKey,
+							Scopes:     arg.Scopes,
+							AllowedIps: arg.AllowedIps,
+							CreatedAt:  time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data createAPIKeyResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+				prefix, ok := utils.APIKeyPrefix(body.Data.Key)
+				require.True(t, ok)
+				require.Equal(t, prefix, body.Data.APIKey.Prefix)
+				require.NotContains(t, recorder.Body.String(), "hashed_key")
+			},
+		},
+		{
+			name: "PrefixCollision",
+			body: gin.H{
+				"name":   "reporting",
+				"scopes": []string{token.ScopeAccountsRead},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				var takenPrefix string
+				gomock.InOrder(
+					store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(_ interface{}, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+							takenPrefix = arg.Prefix
+							return db.ApiKey{}, &pq.Error{Code: "23505", Constraint: apiKeyPrefixConstraint}
+						}),
+					store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(1).
+						DoAndReturn(func(_ interface{}, arg db.CreateApiKeyParams) (db.ApiKey, error) {
+							require.NotEqual(t, takenPrefix, arg.Prefix)
+							return db.ApiKey{ID: 1, Owner: arg.Owner, Prefix: arg.Prefix, HashedKey: arg.HashedKey, Scopes: arg.Scopes}, nil
+						}),
+				)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data createAPIKeyResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+
+				prefix, ok := utils.APIKeyPrefix(body.Data.Key)
+				require.True(t, ok)
+				require.Equal(t, prefix, body.Data.APIKey.Prefix)
+			},
+		},
+		{
+			name: "PrefixCollisionsExhausted",
+			body: gin.H{
+				"name":   "reporting",
+				"scopes": []string{token.ScopeAccountsRead},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(maxAPIKeyAttempts).
+					Return(db.ApiKey{}, &pq.Error{Code: "23505", Constraint: apiKeyPrefixConstraint})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidScope",
+			body: gin.H{
+				"name":   "reporting",
+				"scopes": []string{"admin"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAllowedIP",
+			body: gin.H{
+				"name":        "reporting",
+				"scopes":      []string{token.ScopeAccountsRead},
+				"allowed_ips": []string{"10.0.0.0/33"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "APIKeyCannotCreateKeys",
+			body: gin.H{
+				"name":   "escalation",
+				"scopes": []string{token.ScopeAccountsWrite},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAPIKeyAuthorization(request, machineKey)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				apiKey, _ := randomAPIKey(t, user.Username, token.ScopeAccountsRead)
+				apiKey.HashedKey = utils.HashAPIKey(machineKey)
+				store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Any()).Times(1).Return(apiKey, nil)
+				store.EXPECT().TouchApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).Times(1).Return(nil)
+				store.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api-keys", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_APIKeyAuthentication(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildKey      func(apiKey *db.ApiKey, key *string)
+		remoteAddr    string
+		forwardedFor  string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			buildKey: func(apiKey *db.ApiKey, key *string) {},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WrongSecret",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				*key = *key + "0"
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Revoked",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.ExpiresAt = sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AllowedIP",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.AllowedIps = []string{"10.0.0.0/24"}
+			},
+			remoteAddr: "10.0.0.7:4000",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "IPNotAllowed",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.AllowedIps = []string{"10.0.0.0/24"}
+			},
+			remoteAddr: "10.0.1.7:4000",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "SpoofedForwardedFor",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.AllowedIps = []string{"10.0.0.0/24"}
+			},
+			remoteAddr:   "10.0.1.7:4000",
+			forwardedFor: "10.0.0.7",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "MissingScope",
+			buildKey: func(apiKey *db.ApiKey, key *string) {
+				apiKey.Scopes = []string{token.ScopeTransfersCreate}
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			apiKey, key := randomAPIKey(t, user.Username, token.ScopeAccountsRead)
+			tc.buildKey(&apiKey, &key)
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetApiKeyByPrefix(gomock.Any(), gomock.Eq(apiKey.Prefix)).Times(1).Return(apiKey, nil)
+			store.EXPECT().TouchApiKey(gomock.Any(), gomock.Eq(apiKey.ID)).AnyTimes().Return(nil)
+
+			server := newTestServer(t, store)
+
+			authPath := "/auth"
+			server.router.GET(
+				authPath,
+				authMiddleware(server.tokenGenerator, server.store),
+				requireScope(token.ScopeAccountsRead),
+				func(ctx *gin.Context) {
+					payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+					require.Equal(t, user.Username, payload.Username)
+					require.Equal(t, token.PurposeAccess, payload.Purpose)
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+
+			request.RemoteAddr = "192.0.2.1:1234"
+			if tc.remoteAddr != "" {
+				request.RemoteAddr = tc.remoteAddr
+			}
+			if tc.forwardedFor != "" {
+				request.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			addAPIKeyAuthorization(request, key)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_RevokeAPIKeyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	apiKey, _ := randomAPIKey(t, user.Username, token.ScopeAccountsRead)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				revoked := apiKey
+				revoked.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+				store.EXPECT().RevokeApiKey(gomock.Any(), gomock.Eq(db.RevokeApiKeyParams{
+					ID:    apiKey.ID,
+					Owner: user.Username,
+				})).Times(1).Return(revoked, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().RevokeApiKey(gomock.Any(), gomock.Any()).Times(1).Return(db.ApiKey{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api-keys/%d", apiKey.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
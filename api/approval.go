@@ -0,0 +1,369 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultApprovalExpiry = 24 * time.Hour
+
+// requireApproval holds a transfer for approval when one of the source
+// account's policies covers its amount, answering 202 with the pending
+// transfer and returning false
+func (server *Server) requireApproval(ctx *gin.Context, from, to db.Account, arg db.TransferTxnParams, initiatedBy string) bool {
+	policy, err := server.store.GetApplicableApprovalPolicy(ctx, db.GetApplicableApprovalPolicyParams{
+		AccountID: from.ID,
+		Amount:    arg.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	pending, err := server.store.CreatePendingTransfer(ctx, db.PendingTransferFor(policy, arg, initiatedBy, time.Now()))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse("transfer is pending approval", pending))
+	return false
+}
+
+// canApprove reports whether user may decide on a pending transfer, nobody
+// approves a transfer they made themselves. Approving by role is for staff,
+// customers only approve the transfers they are named on.
+func canApprove(pending db.PendingTransfer, user db.User) bool {
+	if pending.InitiatedBy == user.Username {
+		return false
+	}
+	if pending.ApproverRole.Valid && pending.ApproverRole.String == approverRole(user) {
+		return true
+	}
+	for _, approver := range pending.Approvers {
+		if approver == user.Username {
+			return true
+		}
+	}
+	return false
+}
+
+// approverRole is the role user approves transfers by, none for customers
+func approverRole(user db.User) string {
+	if user.Role == roleCustomer {
+		return ""
+	}
+	return user.Role
+}
+
+type listApprovableTransfersRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listApprovableTransfers lists the pending transfers waiting on the user's decision
+func (server *Server) listApprovableTransfers(ctx *gin.Context) {
+	var request listApprovableTransfersRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	pending, err := server.store.ListApprovableTransfers(ctx, db.ListApprovableTransfersParams{
+		Username:   user.Username,
+		Role:       nullString(approverRole(user)),
+		PageLimit:  request.PageSize,
+		PageOffset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pending transfers retrieved successfully", pending))
+}
+
+type pendingTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type pendingTransferResponse struct {
+	PendingTransfer db.PendingTransfer    `json:"pending_transfer"`
+	Approvals       []db.TransferApproval `json:"approvals"`
+}
+
+// getPendingTransfer shows a pending transfer and its decisions so far to the
+// user who made it and to its approvers
+func (server *Server) getPendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pending, user, ok := server.pendingTransfer(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if pending.InitiatedBy != user.Username && !canApprove(pending, user) {
+		err := fmt.Errorf("pending transfer with ID [%d] does not exist", uri.ID)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	approvals, err := server.store.ListTransferApprovals(ctx, pending.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pending transfer retrieved successfully", pendingTransferResponse{
+		PendingTransfer: pending,
+		Approvals:       approvals,
+	}))
+}
+
+type decideTransferRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+func (server *Server) approveTransfer(ctx *gin.Context) {
+	server.decideTransfer(ctx, db.ApprovalDecisionApprove)
+}
+
+func (server *Server) rejectTransfer(ctx *gin.Context) {
+	server.decideTransfer(ctx, db.ApprovalDecisionReject)
+}
+
+// decideTransfer records an approver's decision, the approval that completes
+// the quorum executes the transfer
+func (server *Server) decideTransfer(ctx *gin.Context, decision string) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request decideTransferRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if decision == db.ApprovalDecisionReject && request.Note == "" {
+		err := errors.New("a note is required when rejecting")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pending, user, ok := server.pendingTransfer(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if !canApprove(pending, user) {
+		err := errors.New("user is not an approver of this transfer")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.DecideTransferTrxn(ctx, db.DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: user.Username,
+		Decision: decision,
+		Note:     request.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrPendingTransferClosed),
+			errors.Is(err, db.ErrPendingTransferExpired),
+			errors.Is(err, db.ErrAlreadyDecided),
+			settlementConflict(err):
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		case errors.Is(err, db.ErrAccountBlocked), errors.Is(err, db.ErrInsufficientFunds), errors.Is(err, db.ErrPotTransfer):
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusForbidden, limitErrorResponse(limitErr))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	message := "decision recorded successfully"
+	if result.Transfer != nil || result.Escrow != nil {
+		message = "transfer approved and executed successfully"
+	}
+	ctx.JSON(http.StatusOK, successResponse(message, result))
+}
+
+// pendingTransfer loads a pending transfer along with the authenticated user
+func (server *Server) pendingTransfer(ctx *gin.Context, id int64) (db.PendingTransfer, db.User, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.PendingTransfer{}, user, false
+	}
+
+	pending, err := server.store.GetPendingTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("pending transfer with ID [%d] does not exist", id)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return pending, user, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pending, user, false
+	}
+	return pending, user, true
+}
+
+type approvalPolicyURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// upsertApprovalPolicyRequest sets who must approve transfers of at least
+// MinAmount from an account. Approvers are named users, users holding
+// ApproverRole, or both.
+type upsertApprovalPolicyRequest struct {
+	MinAmount         int64    `json:"min_amount" binding:"required,gt=0"`
+	RequiredApprovals int32    `json:"required_approvals" binding:"required,min=1,max=10"`
+	ApproverRole      string   `json:"approver_role" binding:"omitempty,alphanum,max=32"`
+	Approvers         []string `json:"approvers" binding:"omitempty,max=20,dive,alphanum"`
+	ExpirySeconds     int32    `json:"expiry_seconds" binding:"omitempty,min=60,max=604800"`
+}
+
+func (server *Server) upsertApprovalPolicy(ctx *gin.Context) {
+	var uri approvalPolicyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request upsertApprovalPolicyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.ApproverRole == roleCustomer {
+		err := errors.New("approver_role must be a staff role")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.ApproverRole == "" && len(request.Approvers) < int(request.RequiredApprovals) {
+		err := errors.New("not enough approvers to ever reach the required approvals")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.ExpirySeconds == 0 {
+		request.ExpirySeconds = int32(defaultApprovalExpiry / time.Second)
+	}
+
+	if _, err := server.store.GetAccount(ctx, uri.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account with ID [%d] does not exist", uri.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	approvers := []string{}
+	for _, username := range request.Approvers {
+		if _, err := server.store.GetUser(ctx, username); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := fmt.Errorf("user %s does not exist", username)
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		approvers = append(approvers, username)
+	}
+
+	policy, err := server.store.UpsertApprovalPolicy(ctx, db.UpsertApprovalPolicyParams{
+		AccountID:         uri.ID,
+		MinAmount:         request.MinAmount,
+		RequiredApprovals: request.RequiredApprovals,
+		ApproverRole:      nullString(request.ApproverRole),
+		Approvers:         approvers,
+		ExpirySeconds:     request.ExpirySeconds,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("approval policy saved successfully", policy))
+}
+
+func (server *Server) listApprovalPolicies(ctx *gin.Context) {
+	var uri approvalPolicyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	policies, err := server.store.ListApprovalPolicies(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("approval policies retrieved successfully", policies))
+}
+
+// deleteApprovalPolicy removes a policy, transfers already pending under it
+// keep the approvers they were created with
+func (server *Server) deleteApprovalPolicy(ctx *gin.Context) {
+	var uri approvalPolicyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetApprovalPolicy(ctx, uri.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("approval policy with ID [%d] does not exist", uri.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteApprovalPolicy(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(fmt.Sprintf("deleted approval policy with id (%d) successfully", uri.ID), nil))
+}
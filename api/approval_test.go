@@ -0,0 +1,383 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TransferNeedsApprovalAPI(t *testing.T) {
+	maker, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := generateRandomAccount(maker.Username)
+	account2 := generateRandomAccount(user2.Username)
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+
+	policy := db.ApprovalPolicy{
+		ID:                utils.RandomInt(1, 1000),
+		AccountID:         account1.ID,
+		MinAmount:         100,
+		RequiredApprovals: 2,
+		Approvers:         []string{"checker1", "checker2"},
+		ExpirySeconds:     3600,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+	store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Eq(db.GetApplicableApprovalPolicyParams{
+		AccountID: account1.ID,
+		Amount:    500,
+	})).Times(1).Return(policy, nil)
+	store.EXPECT().CreatePendingTransfer(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreatePendingTransferParams) (db.PendingTransfer, error) {
+			require.Equal(t, maker.Username, arg.InitiatedBy)
+			require.Equal(t, policy.ID, arg.PolicyID.Int64)
+			require.Equal(t, policy.RequiredApprovals, arg.RequiredApprovals)
+			require.Equal(t, policy.Approvers, arg.Approvers)
+			require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Minute)
+			return db.PendingTransfer{ID: 42, Status: db.PendingTransferPending}, nil
+		})
+	store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": account1.ID,
+		"to_account_id":   account2.ID,
+		"amount":          500,
+		"currency_code":   utils.USD,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, maker.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	var body struct {
+		Data db.PendingTransfer `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, int64(42), body.Data.ID)
+}
+
+func Test_DecideTransferAPI(t *testing.T) {
+	maker, _ := randomUser(t)
+	checker, _ := randomUser(t)
+	treasurer, _ := randomUser(t)
+	treasurer.Role = "treasury"
+	outsider, _ := randomUser(t)
+
+	pending := db.PendingTransfer{
+		ID:                utils.RandomInt(1, 1000),
+		FromAccountID:     utils.RandomInt(1, 1000),
+		ToAccountID:       utils.RandomInt(1, 1000),
+		Amount:            utils.RandomMoney(),
+		InitiatedBy:       maker.Username,
+		Status:            db.PendingTransferPending,
+		RequiredApprovals: 2,
+		ApproverRole:      sql.NullString{String: "treasury", Valid: true},
+		Approvers:         []string{checker.Username, maker.Username},
+		ExpiresAt:         time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		action        string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			user:   checker,
+			action: "approve",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Eq(db.DecideTransferTxnParams{
+					ID:       pending.ID,
+					Approver: checker.Username,
+					Decision: db.ApprovalDecisionApprove,
+				})).Times(1).Return(db.DecideTransferTxnResult{PendingTransfer: pending}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "QuorumByRole",
+			user:   treasurer,
+			action: "approve",
+			body:   gin.H{"note": "checked the invoice"},
+			buildStubs: func(store *mockdb.MockStore) {
+				executed := pending
+				executed.Status = db.PendingTransferExecuted
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Eq(db.DecideTransferTxnParams{
+					ID:       pending.ID,
+					Approver: treasurer.Username,
+					Decision: db.ApprovalDecisionApprove,
+					Note:     "checked the invoice",
+				})).Times(1).Return(db.DecideTransferTxnResult{
+					PendingTransfer: executed,
+					Transfer:        &db.TransferTrxResult{Transfer: db.Transfer{ID: 9}},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data db.DecideTransferTxnResult `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Equal(t, db.PendingTransferExecuted, body.Data.PendingTransfer.Status)
+				require.NotNil(t, body.Data.Transfer)
+			},
+		},
+		{
+			name:   "Reject",
+			user:   checker,
+			action: "reject",
+			body:   gin.H{"note": "wrong payee"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Eq(db.DecideTransferTxnParams{
+					ID:       pending.ID,
+					Approver: checker.Username,
+					Decision: db.ApprovalDecisionReject,
+					Note:     "wrong payee",
+				})).Times(1).Return(db.DecideTransferTxnResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "RejectWithoutNote",
+			user:   checker,
+			action: "reject",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Maker",
+			user:   maker,
+			action: "approve",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAnApprover",
+			user:   outsider,
+			action: "approve",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Expired",
+			user:   checker,
+			action: "approve",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.DecideTransferTxnResult{}, db.ErrPendingTransferExpired)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "AlreadyDecided",
+			user:   checker,
+			action: "approve",
+			body:   gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.DecideTransferTxnResult{}, db.ErrAlreadyDecided)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(tc.user.Username)).AnyTimes().Return(tc.user, nil)
+			store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).AnyTimes().Return(pending, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/pending-transfers/%d/%s", pending.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_CanApprove(t *testing.T) {
+	maker, _ := randomUser(t)
+	customer, _ := randomUser(t)
+	customer.Role = roleCustomer
+	treasurer, _ := randomUser(t)
+	treasurer.Role = "treasury"
+
+	pending := db.PendingTransfer{
+		InitiatedBy:  maker.Username,
+		ApproverRole: sql.NullString{String: roleCustomer, Valid: true},
+		Approvers:    []string{},
+	}
+	// a policy naming the customer role doesn't let every customer approve
+	require.False(t, canApprove(pending, customer))
+
+	pending.ApproverRole = sql.NullString{String: "treasury", Valid: true}
+	require.True(t, canApprove(pending, treasurer))
+
+	pending.Approvers = []string{customer.Username}
+	require.True(t, canApprove(pending, customer))
+}
+
+func Test_UpsertApprovalPolicyAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	checker, _ := randomUser(t)
+	accountID := utils.RandomInt(1, 1000)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"min_amount": 1000, "required_approvals": 1, "approvers": []string{checker.Username}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(accountID)).Times(1).Return(db.Account{ID: accountID}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(checker.Username)).Times(1).Return(checker, nil)
+				store.EXPECT().UpsertApprovalPolicy(gomock.Any(), gomock.Eq(db.UpsertApprovalPolicyParams{
+					AccountID:         accountID,
+					MinAmount:         1000,
+					RequiredApprovals: 1,
+					Approvers:         []string{checker.Username},
+					ExpirySeconds:     86400,
+				})).Times(1).Return(db.ApprovalPolicy{ID: 1}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "QuorumUnreachable",
+			body: gin.H{"min_amount": 1000, "required_approvals": 2, "approvers": []string{checker.Username}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertApprovalPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CustomerRole",
+			body: gin.H{"min_amount": 1000, "required_approvals": 1, "approver_role": roleCustomer},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertApprovalPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownApprover",
+			body: gin.H{"min_amount": 1000, "required_approvals": 1, "approvers": []string{"ghost"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(accountID)).Times(1).Return(db.Account{ID: accountID}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq("ghost")).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().UpsertApprovalPolicy(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/accounts/%d/approval-policies", accountID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeaderKey = "X-Request-ID"
+	anonymousActor     = "anonymous"
+	roleAdmin          = "admin"
+	roleCustomer       = "customer"
+	auditVerifyBatch   = 500
+)
+
+// auditMiddleware tags every request with an id and the caller's IP so store
+// operations can attribute the changes they audit
+func auditMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeaderKey)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(requestIDHeaderKey, requestID)
+
+		ctx.Set(db.AuditMetadataKey, db.AuditMetadata{
+			Actor:     anonymousActor,
+			IP:        ctx.ClientIP(),
+			RequestID: requestID,
+		})
+		ctx.Next()
+	}
+}
+
+// setAuditActor records who is behind the request once they are authenticated
+func setAuditActor(ctx *gin.Context, actor string) {
+	metadata := db.AuditMetadataFromContext(ctx)
+	if metadata.RequestID == "" {
+		return
+	}
+	metadata.Actor = actor
+	ctx.Set(db.AuditMetadataKey, metadata)
+}
+
+// requireAdmin only lets users with the admin role through
+func (server *Server) requireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		user, err := server.store.GetUser(ctx, payload.Username)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if user.Role != roleAdmin {
+			err := errors.New("this action requires the admin role")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+type listAuditEventsRequest struct {
+	Actor        string    `form:"actor"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resource_type"`
+	ResourceID   string    `form:"resource_id"`
+	From         time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	PageID       int32     `form:"page_id" binding:"required,min=1"`
+	PageSize     int32     `form:"page_size" binding:"required,min=5,max=100"`
+}
+
+func (server *Server) listAuditEvents(ctx *gin.Context) {
+	var request listAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		Actor:        nullString(request.Actor),
+		Action:       nullString(request.Action),
+		ResourceType: nullString(request.ResourceType),
+		ResourceID:   nullString(request.ResourceID),
+		FromTime:     nullTime(request.From),
+		ToTime:       nullTime(request.To),
+		PageLimit:    request.PageSize,
+		PageOffset:   (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("audit events retrieved successfully", events))
+}
+
+type verifyAuditChainResponse struct {
+	Valid    bool  `json:"valid"`
+	Events   int64 `json:"events"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
+
+// verifyAuditChain walks the whole log and reports the first event whose hash
+// doesn't match its contents or doesn't link to the event before it in its chain
+func (server *Server) verifyAuditChain(ctx *gin.Context) {
+	response := verifyAuditChainResponse{Valid: true}
+
+	var afterID int64
+	prevHashes := map[int32]string{}
+	for {
+		events, err := server.store.ListAuditEventsAfter(ctx, db.ListAuditEventsAfterParams{
+			ID:    afterID,
+			Limit: auditVerifyBatch,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		for _, event := range events {
+			if event.PrevHash != prevHashes[event.Chain] || event.Hash != event.ComputeHash() {
+				response.Valid = false
+				response.BrokenAt = event.ID
+				ctx.JSON(http.StatusOK, successResponse("audit log has been tampered with", response))
+				return
+			}
+			prevHashes[event.Chain] = event.Hash
+			afterID = event.ID
+			response.Events++
+		}
+
+		if len(events) < auditVerifyBatch {
+			break
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("audit log is intact", response))
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
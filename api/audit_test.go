@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomAuditChain(t *testing.T, n int) []db.AuditEvent {
+	events := make([]db.AuditEvent, n)
+
+	for i := range events {
+		events[i] = db.AuditEvent{
+			ID:           int64(i + 1),
+			Actor:        utils.RandomOwner(),
+			Action:       "account.create",
+			ResourceType: "account",
+			ResourceID:   utils.RandomString(4),
+			Before:       json.RawMessage("null"),
+			After:        json.RawMessage(`{"balance":0}`),
+			Ip:           "10.0.0.1",
+			RequestID:    utils.RandomString(12),
+			CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+		}
+	}
+	linkAuditChains(events)
+	return events
+}
+
+// linkAuditChains links each event to the one before it in its chain
+func linkAuditChains(events []db.AuditEvent) {
+	prevHashes := map[int32]string{}
+	for i := range events {
+		events[i].PrevHash = prevHashes[events[i].Chain]
+		events[i].Hash = events[i].ComputeHash()
+		prevHashes[events[i].Chain] = events[i].Hash
+	}
+}
+
+func Test_AuditMetadata(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetKycTierForUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(standardKYCTier(), nil)
+	store.EXPECT().NextAccountSequence(gomock.Any()).Times(1).Return(int64(1), nil)
+	store.EXPECT().OpenAccountTrxn(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+			metadata := db.AuditMetadataFromContext(ctx)
+			require.Equal(t, user.Username, metadata.Actor)
+			require.Equal(t, "request-1", metadata.RequestID)
+			require.Equal(t, "192.0.2.1", metadata.IP)
+			return account, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(createAccountRequest{CurrencyCode: account.CurrencyCode, ProductCode: db.AccountProductChecking})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(requestIDHeaderKey, "request-1")
+	request.RemoteAddr = "192.0.2.1:1234"
+
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "request-1", recorder.Header().Get(requestIDHeaderKey))
+}
+
+func Test_ListAuditEventsAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	customer, _ := randomUser(t)
+	events := randomAuditChain(t, 3)
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			user:  admin,
+			query: "?page_id=1&page_size=10&actor=" + events[0].Actor + "&from=2023-01-01T00:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+						require.Equal(t, events[0].Actor, arg.Actor.String)
+						require.True(t, arg.Actor.Valid)
+						require.False(t, arg.Action.Valid)
+						require.True(t, arg.FromTime.Valid)
+						require.False(t, arg.ToTime.Valid)
+						require.Equal(t, int32(10), arg.PageLimit)
+						return events[:1], nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data []db.AuditEvent `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Len(t, body.Data, 1)
+				require.Equal(t, events[0].Hash, body.Data[0].Hash)
+			},
+		},
+		{
+			name:  "NotAdmin",
+			user:  customer,
+			query: "?page_id=1&page_size=10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).Times(1).Return(customer, nil)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			user:  admin,
+			query: "?page_id=1&page_size=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit-events"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_VerifyAuditChainAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+
+	testCases := []struct {
+		name          string
+		buildEvents   func(events []db.AuditEvent)
+		checkResponse func(response verifyAuditChainResponse)
+	}{
+		{
+			name:        "Intact",
+			buildEvents: func(events []db.AuditEvent) {},
+			checkResponse: func(response verifyAuditChainResponse) {
+				require.True(t, response.Valid)
+				require.Equal(t, int64(3), response.Events)
+			},
+		},
+		{
+			name: "EditedSnapshot",
+			buildEvents: func(events []db.AuditEvent) {
+				events[1].After = json.RawMessage(`{"balance":1000000}`)
+			},
+			checkResponse: func(response verifyAuditChainResponse) {
+				require.False(t, response.Valid)
+				require.Equal(t, int64(2), response.BrokenAt)
+			},
+		},
+		{
+			name: "InterleavedChains",
+			buildEvents: func(events []db.AuditEvent) {
+				events[1].Chain = 1
+				linkAuditChains(events)
+			},
+			checkResponse: func(response verifyAuditChainResponse) {
+				require.True(t, response.Valid)
+				require.Equal(t, int64(3), response.Events)
+			},
+		},
+		{
+			name: "MovedToAnotherChain",
+			buildEvents: func(events []db.AuditEvent) {
+				events[1].Chain = 1
+			},
+			checkResponse: func(response verifyAuditChainResponse) {
+				require.False(t, response.Valid)
+				require.Equal(t, int64(2), response.BrokenAt)
+			},
+		},
+		{
+			name: "DeletedEvent",
+			buildEvents: func(events []db.AuditEvent) {
+				events[1] = events[2]
+			},
+			checkResponse: func(response verifyAuditChainResponse) {
+				require.False(t, response.Valid)
+				require.Equal(t, int64(3), response.BrokenAt)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			events := randomAuditChain(t, 3)
+			tc.buildEvents(events)
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			store.EXPECT().ListAuditEventsAfter(gomock.Any(), gomock.Any()).Times(1).Return(events, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/audit-events/verify", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var body struct {
+				Data verifyAuditChainResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			tc.checkResponse(body.Data)
+		})
+	}
+}
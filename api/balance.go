@@ -0,0 +1,103 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/gin-gonic/gin"
+)
+
+type getBalanceRequest struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type balanceResponse struct {
+	AccountID    int64     `json:"account_id"`
+	CurrencyCode string    `json:"currency_code"`
+	At           time.Time `json:"at"`
+	Balance      int64     `json:"balance"`
+}
+
+func (server *Server) getAccountBalance(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
+		return
+	}
+
+	server.respondBalanceAt(ctx, account)
+}
+
+// adminGetAccountBalance lets support staff and auditors look up any account's balance
+func (server *Server) adminGetAccountBalance(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	server.respondBalanceAt(ctx, account)
+}
+
+func (server *Server) balanceAccount(ctx *gin.Context) (db.Account, bool) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Account{}, false
+	}
+
+	account, err := server.store.GetAccount(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+	return account, true
+}
+
+// respondBalanceAt answers with the balance at the time in the at query parameter,
+// which defaults to now and can't be in the future
+func (server *Server) respondBalanceAt(ctx *gin.Context, account db.Account) {
+	var request getBalanceRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	if request.At.IsZero() {
+		request.At = now
+	}
+	if request.At.After(now) {
+		err := errors.New("at must not be in the future")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	balance, err := server.store.GetBalanceAt(ctx, db.BalanceAtParams{
+		AccountID: account.ID,
+		At:        request.At,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("retrieved balance successfully", balanceResponse{
+		AccountID:    account.ID,
+		CurrencyCode: account.CurrencyCode,
+		At:           request.At,
+		Balance:      balance,
+	}))
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+type beneficiaryResponse struct {
+	ID            int64     `json:"id"`
+	AccountID     int64     `json:"account_id"`
+	AccountNumber *string   `json:"account_number"`
+	CurrencyCode  string    `json:"currency_code"`
+	Nickname      *string   `json:"nickname"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	// CoolingOffUntil is when large transfers to the beneficiary are allowed
+	CoolingOffUntil time.Time `json:"cooling_off_until"`
+}
+
+func (server *Server) newBeneficiaryResponse(beneficiary db.Beneficiary, account db.Account) beneficiaryResponse {
+	return beneficiaryResponse{
+		ID:              beneficiary.ID,
+		AccountID:       beneficiary.AccountID,
+		AccountNumber:   nullStringPtr(account.AccountNumber),
+		CurrencyCode:    account.CurrencyCode,
+		Nickname:        nullStringPtr(beneficiary.Nickname),
+		CreatedAt:       beneficiary.CreatedAt,
+		UpdatedAt:       beneficiary.UpdatedAt,
+		CoolingOffUntil: beneficiary.CreatedAt.Add(server.config.BeneficiaryCoolingOff),
+	}
+}
+
+type createBeneficiaryRequest struct {
+	AccountID     int64  `json:"account_id" binding:"required_without=AccountNumber,excluded_with=AccountNumber,omitempty,min=1"`
+	AccountNumber string `json:"account_number" binding:"omitempty,numeric"`
+	Nickname      string `json:"nickname" binding:"max=64"`
+}
+
+// createBeneficiary saves a payee so transfers can name it by its beneficiary
+// id. Large transfers to it are refused during the cooling-off period.
+func (server *Server) createBeneficiary(ctx *gin.Context) {
+	var request createBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, ok := server.lookupAccount(ctx, request.AccountID, request.AccountNumber)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner == authPayload.Username {
+		err := errors.New("your own accounts can't be saved as beneficiaries")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	beneficiary, err := server.store.CreateBeneficiary(ctx, db.CreateBeneficiaryParams{
+		Owner:     authPayload.Username,
+		AccountID: account.ID,
+		Nickname:  nullString(request.Nickname),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == ErrUniqueViolation {
+			err := fmt.Errorf("account [%d] is already a beneficiary", account.ID)
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("beneficiary created successfully", server.newBeneficiaryResponse(beneficiary, account)))
+}
+
+// listBeneficiaries lists the user's saved payees. They belong to the user
+// rather than to one of their accounts, so clients limited to some accounts
+// can't list them.
+func (server *Server) listBeneficiaries(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	rows, err := server.store.ListBeneficiaries(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	beneficiaries := make([]beneficiaryResponse, 0, len(rows))
+	for _, row := range rows {
+		beneficiary := db.Beneficiary{
+			ID:        row.ID,
+			Owner:     row.Owner,
+			AccountID: row.AccountID,
+			Nickname:  row.Nickname,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}
+		account := db.Account{
+			ID:            row.AccountID,
+			AccountNumber: row.AccountNumber,
+			CurrencyCode:  row.CurrencyCode,
+		}
+		beneficiaries = append(beneficiaries, server.newBeneficiaryResponse(beneficiary, account))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("beneficiaries retrieved successfully", beneficiaries))
+}
+
+type beneficiaryURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type renameBeneficiaryRequest struct {
+	Nickname string `json:"nickname" binding:"max=64"`
+}
+
+// renameBeneficiary replaces a beneficiary's nickname, an empty one removes it
+func (server *Server) renameBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request renameBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.userBeneficiary(ctx, uri.ID); !ok {
+		return
+	}
+
+	beneficiary, err := server.store.RenameBeneficiary(ctx, db.RenameBeneficiaryParams{
+		ID:       uri.ID,
+		Nickname: nullString(request.Nickname),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, beneficiary.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("beneficiary renamed successfully", server.newBeneficiaryResponse(beneficiary, account)))
+}
+
+func (server *Server) deleteBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.userBeneficiary(ctx, uri.ID); !ok {
+		return
+	}
+
+	if err := server.store.DeleteBeneficiary(ctx, uri.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("beneficiary deleted successfully", nil))
+}
+
+// userBeneficiary loads a beneficiary saved by the authenticated user, other
+// users' beneficiaries look the same as missing ones
+func (server *Server) userBeneficiary(ctx *gin.Context, id int64) (db.Beneficiary, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	beneficiary, err := server.store.GetBeneficiary(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return beneficiary, false
+	}
+	if err != nil || beneficiary.Owner != authPayload.Username {
+		err := fmt.Errorf("beneficiary with ID [%d] does not exist", id)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return beneficiary, false
+	}
+
+	return beneficiary, true
+}
+
+// checkCoolingOff refuses large transfers to an account the user saved as a
+// beneficiary within the cooling-off period, however the transfer names it.
+// Payees that were never saved are left to the fraud rules.
+func (server *Server) checkCoolingOff(ctx *gin.Context, username string, to db.Account, amount int64) bool {
+	if amount < server.config.BeneficiaryCoolingOffAmount {
+		return true
+	}
+
+	beneficiary, err := server.store.GetBeneficiaryByAccount(ctx, db.GetBeneficiaryByAccountParams{
+		Owner:     username,
+		AccountID: to.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	until := beneficiary.CreatedAt.Add(server.config.BeneficiaryCoolingOff)
+	if time.Now().Before(until) {
+		err := fmt.Errorf("transfers of %d or more to a new beneficiary are allowed from %s",
+			server.config.BeneficiaryCoolingOffAmount, until.Format(time.RFC3339))
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+
+	return true
+}
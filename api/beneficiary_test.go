@@ -0,0 +1,398 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBeneficiary(owner string, account db.Account) db.Beneficiary {
+	return db.Beneficiary{
+		ID:        utils.RandomInt(1, 1000),
+		Owner:     owner,
+		AccountID: account.ID,
+		CreatedAt: time.Now().Add(-48 * time.Hour),
+		UpdatedAt: time.Now().Add(-48 * time.Hour),
+	}
+}
+
+func Test_CreateBeneficiaryAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	payee, _ := randomUser(t)
+
+	account := generateRandomAccount(payee.Username)
+	number, err := utils.NewAccountNumber(utils.DefaultBankCode, account.CurrencyCode, account.ID)
+	require.NoError(t, err)
+	account.AccountNumber = sql.NullString{String: number, Valid: true}
+
+	ownAccount := generateRandomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_number": number, "nickname": "Landlord"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(account.AccountNumber)).Times(1).Return(account, nil)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Eq(db.CreateBeneficiaryParams{
+					Owner:     user.Username,
+					AccountID: account.ID,
+					Nickname:  sql.NullString{String: "Landlord", Valid: true},
+				})).Times(1).Return(db.Beneficiary{
+					ID:        1,
+					Owner:     user.Username,
+					AccountID: account.ID,
+					Nickname:  sql.NullString{String: "Landlord", Valid: true},
+					CreatedAt: time.Now(),
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data beneficiaryResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "Landlord", *response.Data.Nickname)
+				require.Equal(t, number, *response.Data.AccountNumber)
+				require.True(t, response.Data.CoolingOffUntil.After(time.Now()))
+			},
+		},
+		{
+			name: "OwnAccount",
+			body: gin.H{"account_id": ownAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(ownAccount.ID)).Times(1).Return(ownAccount, nil)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadySaved",
+			body: gin.H{"account_id": account.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.Beneficiary{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "IDAndNumber",
+			body: gin.H{"account_id": account.ID, "account_number": number},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/beneficiaries", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ListBeneficiariesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	payee, _ := randomUser(t)
+
+	account := generateRandomAccount(payee.Username)
+	beneficiary := randomBeneficiary(user.Username, account)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListBeneficiaries(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.ListBeneficiariesRow{{
+					ID:           beneficiary.ID,
+					Owner:        beneficiary.Owner,
+					AccountID:    account.ID,
+					CurrencyCode: account.CurrencyCode,
+				}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OAuthClient",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{account.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+				store.EXPECT().ListBeneficiaries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/beneficiaries", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_RenameBeneficiaryAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+	payee, _ := randomUser(t)
+
+	account := generateRandomAccount(payee.Username)
+	beneficiary := randomBeneficiary(user.Username, account)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				renamed := beneficiary
+				renamed.Nickname = sql.NullString{String: "Mum", Valid: true}
+				store.EXPECT().RenameBeneficiary(gomock.Any(), gomock.Eq(db.RenameBeneficiaryParams{
+					ID:       beneficiary.ID,
+					Nickname: renamed.Nickname,
+				})).Times(1).Return(renamed, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherUsersBeneficiary",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().RenameBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"nickname": "Mum"})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/beneficiaries/%d", beneficiary.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_TransferToBeneficiaryAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	account1 := generateRandomAccount(user1.Username)
+	account2 := generateRandomAccount(user2.Username)
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+
+	beneficiary := randomBeneficiary(user1.Username, account2)
+	newBeneficiary := beneficiary
+	newBeneficiary.CreatedAt = time.Now().Add(-time.Hour)
+
+	byAccountArg := db.GetBeneficiaryByAccountParams{Owner: user1.Username, AccountID: account2.ID}
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(newBeneficiary, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				// small transfers skip the cooling-off check
+				store.EXPECT().GetBeneficiaryByAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Eq(db.TransferTxnParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        10,
+					InitiatedBy:   user1.Username,
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "CoolingOff",
+			amount: 100_000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(newBeneficiary, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetBeneficiaryByAccount(gomock.Any(), gomock.Eq(byAccountArg)).Times(1).Return(newBeneficiary, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "CooledOff",
+			amount: 100_000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetBeneficiaryByAccount(gomock.Any(), gomock.Eq(byAccountArg)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "OtherUsersBeneficiary",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				theirs := beneficiary
+				theirs.Owner = other.Username
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(theirs, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          tc.amount,
+				"currency_code":   utils.USD,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user1.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultEscrowRelease is how long the buyer has to confirm delivery or raise
+// a dispute before the seller is paid, when the contract doesn't say
+const defaultEscrowRelease = 14 * 24 * time.Hour
+
+type createEscrowRequest struct {
+	Seller            string `json:"seller" binding:"required,alphanum"`
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,excluded_with=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"omitempty,numeric"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Description       string `json:"description" binding:"max=500"`
+	ReleaseInHours    int64  `json:"release_in_hours" binding:"omitempty,min=1,max=2160"`
+	TOTPCode          string `json:"totp_code"`
+}
+
+// createEscrow moves the buyer's money into escrow for a purchase from the
+// seller. It is paid into the seller's primary account in the currency once
+// the buyer confirms delivery or the release time passes. Funding goes through
+// the same controls as a transfer to the seller, escrow held by them is funded
+// once it is let through.
+func (server *Server) createEscrow(ctx *gin.Context) {
+	var request createEscrowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Seller == authPayload.Username {
+		err := errors.New("you can't buy from yourself")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fromAccount, ok := server.lookupAccount(ctx, request.FromAccountID, request.FromAccountNumber)
+	if !ok {
+		return
+	}
+	if fromAccount, ok = checkTransferAccount(ctx, fromAccount, fromAccount.CurrencyCode); !ok {
+		return
+	}
+
+	grant, ok := server.authorizeAccount(ctx, fromAccount, access.PermissionTransfer)
+	if !ok {
+		return
+	}
+
+	if err := grant.CanSpend(request.Amount); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if !server.requireTransferMFA(ctx, authPayload.Username, request.Amount, request.TOTPCode) {
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, request.Seller); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s does not exist", request.Seller)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sellerAccount, err := server.store.GetPrimaryAccount(ctx, db.GetPrimaryAccountParams{
+		Owner:        request.Seller,
+		CurrencyCode: fromAccount.CurrencyCode,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("%s has no active %s account to be paid into", request.Seller, fromAccount.CurrencyCode)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	release := defaultEscrowRelease
+	if request.ReleaseInHours > 0 {
+		release = time.Duration(request.ReleaseInHours) * time.Hour
+	}
+
+	arg := db.TransferTxnParams{
+		FromAccountID:        fromAccount.ID,
+		ToAccountID:          sellerAccount.ID,
+		Amount:               request.Amount,
+		EscrowReleaseSeconds: int64(release / time.Second),
+		EscrowDescription:    request.Description,
+	}
+	if !server.clearTransfer(ctx, fromAccount, sellerAccount, arg, authPayload.Username) {
+		return
+	}
+
+	result, err := server.store.CreateEscrowTrxn(ctx, db.EscrowContractFor(fromAccount, sellerAccount, arg, authPayload.Username, time.Now()))
+	if err != nil {
+		if errors.Is(err, db.ErrAccountBlocked) || errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrPotTransfer) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusForbidden, limitErrorResponse(limitErr))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow funded successfully", result))
+}
+
+type listEscrowRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listEscrow lists the contracts the user is the buyer or seller in, newest first
+func (server *Server) listEscrow(ctx *gin.Context) {
+	var request listEscrowRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	contracts, err := server.store.ListEscrowContracts(ctx, db.ListEscrowContractsParams{
+		Username:   authPayload.Username,
+		PageSize:   request.PageSize,
+		PageOffset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if authPayload.ClientID != "" {
+		shared := contracts[:0]
+		for _, contract := range contracts {
+			if authPayload.CanAccessAccount(escrowAccountID(contract, authPayload.Username)) {
+				shared = append(shared, contract)
+			}
+		}
+		contracts = shared
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow contracts retrieved successfully", contracts))
+}
+
+// escrowAccountID is the account on the user's side of the contract
+func escrowAccountID(contract db.EscrowContract, username string) int64 {
+	if contract.Buyer == username {
+		return contract.BuyerAccountID
+	}
+	return contract.SellerAccountID
+}
+
+type escrowURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getEscrow(ctx *gin.Context) {
+	contract, ok := server.escrowContract(ctx, true)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow contract retrieved successfully", contract))
+}
+
+// confirmEscrow is the buyer confirming delivery, which pays the seller
+func (server *Server) confirmEscrow(ctx *gin.Context) {
+	contract, ok := server.escrowContract(ctx, false)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.ReleaseEscrowTrxn(ctx, contract.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrEscrowNotFunded) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrAccountBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow released successfully", result))
+}
+
+type disputeEscrowRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// disputeEscrow lets either side hold the money in escrow until an admin
+// decides who gets it
+func (server *Server) disputeEscrow(ctx *gin.Context) {
+	var request disputeEscrowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	contract, ok := server.escrowContract(ctx, true)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	contract, err := server.store.DisputeEscrowTrxn(ctx, db.DisputeEscrowContractParams{
+		ID:            contract.ID,
+		DisputedBy:    nullString(authPayload.Username),
+		DisputeReason: request.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrEscrowNotFunded) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow disputed successfully", contract))
+}
+
+// escrowContract loads the contract in the :id URI when the authenticated user
+// is its buyer, or either side of it when sellerToo is set, and the token may
+// access the account on their side. Other users' contracts look the same as
+// missing ones.
+func (server *Server) escrowContract(ctx *gin.Context, sellerToo bool) (db.EscrowContract, bool) {
+	var uri escrowURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.EscrowContract{}, false
+	}
+
+	contract, err := server.store.GetEscrowContract(ctx, uri.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return contract, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	party := contract.Buyer == authPayload.Username ||
+		(sellerToo && contract.Seller == authPayload.Username)
+	if err != nil || !party || !authPayload.CanAccessAccount(escrowAccountID(contract, authPayload.Username)) {
+		err := fmt.Errorf("escrow contract with ID [%d] does not exist", uri.ID)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return contract, false
+	}
+
+	return contract, true
+}
+
+type listEscrowDisputesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=funded disputed released refunded"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listEscrowDisputes is the back-office dispute queue, oldest contract first
+func (server *Server) listEscrowDisputes(ctx *gin.Context) {
+	var request listEscrowDisputesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.Status == "" {
+		request.Status = db.EscrowDisputed
+	}
+
+	contracts, err := server.store.ListEscrowContractsByStatus(ctx, db.ListEscrowContractsByStatusParams{
+		Status: request.Status,
+		Limit:  request.PageSize,
+		Offset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow contracts retrieved successfully", contracts))
+}
+
+type resolveEscrowRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=released refunded"`
+	Note    string `json:"note" binding:"required,max=500"`
+}
+
+// resolveEscrow settles a dispute, paying the seller or refunding the buyer
+func (server *Server) resolveEscrow(ctx *gin.Context) {
+	var uri escrowURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request resolveEscrowRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ResolveEscrowTrxn(ctx, db.ResolveEscrowTxnParams{
+		ID:         uri.ID,
+		Outcome:    request.Outcome,
+		ResolvedBy: authPayload.Username,
+		Note:       request.Note,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("escrow contract with ID [%d] does not exist", uri.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrEscrowNotDisputed) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrAccountBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("escrow dispute resolved successfully", result))
+}
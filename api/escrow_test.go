@@ -0,0 +1,557 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEscrowContract(buyerAccount, sellerAccount db.Account) db.EscrowContract {
+	return db.EscrowContract{
+		ID:              utils.RandomInt(1, 1000),
+		Buyer:           buyerAccount.Owner,
+		Seller:          sellerAccount.Owner,
+		BuyerAccountID:  buyerAccount.ID,
+		SellerAccountID: sellerAccount.ID,
+		Amount:          utils.RandomInt(1, 1000),
+		CurrencyCode:    buyerAccount.CurrencyCode,
+		Status:          db.EscrowFunded,
+		ReleaseAt:       time.Now().Add(defaultEscrowRelease),
+		CreatedAt:       time.Now(),
+	}
+}
+
+func Test_CreateEscrowAPI(t *testing.T) {
+	buyer, _ := randomUser(t)
+	seller, _ := randomUser(t)
+
+	buyerAccount := generateRandomAccount(buyer.Username)
+	sellerAccount := generateRandomAccount(seller.Username)
+	buyerAccount.CurrencyCode = utils.USD
+	sellerAccount.CurrencyCode = utils.USD
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250, "description": "bike"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(db.GetPrimaryAccountParams{
+					Owner:        seller.Username,
+					CurrencyCode: utils.USD,
+				})).Times(1).Return(sellerAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Eq(db.GetApplicableApprovalPolicyParams{
+					AccountID: buyerAccount.ID,
+					Amount:    250,
+				})).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateEscrowContractParams) (db.EscrowTxnResult, error) {
+						require.Equal(t, buyer.Username, arg.Buyer)
+						require.Equal(t, seller.Username, arg.Seller)
+						require.Equal(t, buyerAccount.ID, arg.BuyerAccountID)
+						require.Equal(t, sellerAccount.ID, arg.SellerAccountID)
+						require.Equal(t, int64(250), arg.Amount)
+						require.Equal(t, utils.USD, arg.CurrencyCode)
+						require.WithinDuration(t, time.Now().Add(defaultEscrowRelease), arg.ReleaseAt, time.Second)
+						return db.EscrowTxnResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CustomRelease",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250, "release_in_hours": 48},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(sellerAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateEscrowContractParams) (db.EscrowTxnResult, error) {
+						require.WithinDuration(t, time.Now().Add(48*time.Hour), arg.ReleaseAt, time.Second)
+						return db.EscrowTxnResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "HeldForApproval",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250, "description": "bike"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(sellerAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ApprovalPolicy{ID: 1, RequiredApprovals: 2, ExpirySeconds: 3600}, nil)
+				store.EXPECT().CreatePendingTransfer(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePendingTransferParams) (db.PendingTransfer, error) {
+						// the escrow is funded once the transfer is approved
+						require.Equal(t, sellerAccount.ID, arg.ToAccountID)
+						require.Equal(t, int64(defaultEscrowRelease/time.Second), arg.EscrowReleaseSeconds.Int64)
+						require.Equal(t, "bike", arg.EscrowDescription)
+						return db.PendingTransfer{ID: 1}, nil
+					})
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "OverLimit",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(sellerAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.EscrowTxnResult{}, &db.LimitExceededError{Limit: db.LimitDailyAmount})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "FromSelf",
+			body: gin.H{"seller": buyer.Username, "from_account_id": buyerAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownSeller",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "SellerHasNoAccountInCurrency",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{"seller": seller.Username, "from_account_id": buyerAccount.ID, "amount": 250},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(buyerAccount.ID)).Times(1).Return(buyerAccount, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(seller.Username)).Times(1).Return(seller, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(sellerAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().CreateEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.EscrowTxnResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/escrow", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, buyer.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_EscrowActionsAPI(t *testing.T) {
+	buyer, _ := randomUser(t)
+	seller, _ := randomUser(t)
+	stranger, _ := randomUser(t)
+
+	contract := randomEscrowContract(generateRandomAccount(buyer.Username), generateRandomAccount(seller.Username))
+
+	testCases := []struct {
+		name          string
+		action        string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "BuyerConfirms",
+			action:   "confirm",
+			username: buyer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(contract.ID)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "SellerCantConfirm",
+			action:   "confirm",
+			username: seller.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "ConfirmDisputed",
+			action:   "confirm",
+			username: buyer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(contract.ID)).Times(1).
+					Return(db.EscrowTxnResult{}, db.ErrEscrowNotFunded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "SellerDisputes",
+			action:   "dispute",
+			username: seller.Username,
+			body:     gin.H{"reason": "buyer says it never arrived but tracking shows delivered"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().DisputeEscrowTrxn(gomock.Any(), gomock.Eq(db.DisputeEscrowContractParams{
+					ID:            contract.ID,
+					DisputedBy:    sql.NullString{String: seller.Username, Valid: true},
+					DisputeReason: "buyer says it never arrived but tracking shows delivered",
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "DisputeWithoutReason",
+			action:   "dispute",
+			username: buyer.Username,
+			body:     gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DisputeEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "StrangerCantDispute",
+			action:   "dispute",
+			username: stranger.Username,
+			body:     gin.H{"reason": "scam"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().DisputeEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "DisputeReleased",
+			action:   "dispute",
+			username: buyer.Username,
+			body:     gin.H{"reason": "item broken"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+				store.EXPECT().DisputeEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.EscrowContract{}, db.ErrEscrowNotFunded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/escrow/%d/%s", contract.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ListEscrowAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	granted := generateRandomAccount(user.Username)
+	notGranted := generateRandomAccount(user.Username)
+	otherAccount := generateRandomAccount(other.Username)
+
+	bought := randomEscrowContract(granted, otherAccount)
+	sold := randomEscrowContract(otherAccount, granted)
+	hidden := randomEscrowContract(notGranted, otherAccount)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListEscrowContracts(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.EscrowContract{bought, sold, hidden}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEscrowIDs(t, recorder.Body, bought.ID, sold.ID, hidden.ID)
+			},
+		},
+		{
+			name: "OAuthClient",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{granted.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+				store.EXPECT().ListEscrowContracts(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.EscrowContract{bought, sold, hidden}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEscrowIDs(t, recorder.Body, bought.ID, sold.ID)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/escrow?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetEscrowOutsideGrantAPI(t *testing.T) {
+	buyer, _ := randomUser(t)
+	seller, _ := randomUser(t)
+	granted := generateRandomAccount(buyer.Username)
+	contract := randomEscrowContract(generateRandomAccount(buyer.Username), generateRandomAccount(seller.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+	store.EXPECT().GetEscrowContract(gomock.Any(), gomock.Eq(contract.ID)).Times(1).Return(contract, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/escrow/%d", contract.ID), nil)
+	require.NoError(t, err)
+
+	addOAuthAuthorization(t, request, server.tokenGenerator, buyer.Username, []int64{granted.ID})
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func requireBodyMatchEscrowIDs(t *testing.T, body *bytes.Buffer, ids ...int64) {
+	var response struct {
+		Data []db.EscrowContract `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+	require.Len(t, response.Data, len(ids))
+	for i, id := range ids {
+		require.Equal(t, id, response.Data[i].ID)
+	}
+}
+
+func Test_ResolveEscrowAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	customer, _ := randomUser(t)
+
+	id := utils.RandomInt(1, 1000)
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Refund",
+			user: admin,
+			body: gin.H{"outcome": "refunded", "note": "seller never shipped"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ResolveEscrowTrxn(gomock.Any(), gomock.Eq(db.ResolveEscrowTxnParams{
+					ID:         id,
+					Outcome:    db.EscrowRefunded,
+					ResolvedBy: admin.Username,
+					Note:       "seller never shipped",
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotDisputed",
+			user: admin,
+			body: gin.H{"outcome": "released", "note": "proof of delivery"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ResolveEscrowTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.EscrowTxnResult{}, db.ErrEscrowNotDisputed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidOutcome",
+			user: admin,
+			body: gin.H{"outcome": "split", "note": "half each"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ResolveEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			user: customer,
+			body: gin.H{"outcome": "refunded", "note": "refund me"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).Times(1).Return(customer, nil)
+				store.EXPECT().ResolveEscrowTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/escrow/%d/resolve", id)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/fraud"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// heldTransferResponse is what customers see of a held transfer, the rules
+// that held it stay with the reviewers
+type heldTransferResponse struct {
+	ID            int64      `json:"id"`
+	Status        string     `json:"status"`
+	FromAccountID int64      `json:"from_account_id"`
+	ToAccountID   int64      `json:"to_account_id"`
+	Amount        int64      `json:"amount"`
+	TransferID    *int64     `json:"transfer_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+}
+
+func newHeldTransferResponse(fraudCase db.FraudCase) heldTransferResponse {
+	return heldTransferResponse{
+		ID:            fraudCase.ID,
+		Status:        fraudCase.Status,
+		FromAccountID: fraudCase.FromAccountID,
+		ToAccountID:   fraudCase.ToAccountID,
+		Amount:        fraudCase.Amount,
+		TransferID:    nullInt64Ptr(fraudCase.TransferID),
+		CreatedAt:     fraudCase.CreatedAt,
+		ReviewedAt:    nullTimePtr(fraudCase.ReviewedAt),
+	}
+}
+
+// screenTransfer runs the fraud rules on a transfer. A blocked transfer is
+// refused and a held one queued for review, either way it is recorded as a
+// fraud case and false is returned.
+func (server *Server) screenTransfer(ctx *gin.Context, from, to db.Account, arg db.TransferTxnParams, initiatedBy string) bool {
+	result, err := server.fraudEngine.Screen(ctx, fraud.Transfer{
+		From:        from,
+		To:          to,
+		Amount:      arg.Amount,
+		InitiatedBy: initiatedBy,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+	if result.Decision == fraud.DecisionAllow {
+		return true
+	}
+
+	verdicts, err := json.Marshal(result.Verdicts)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	status := db.FraudCaseOpen
+	if result.Decision == fraud.DecisionBlock {
+		status = db.FraudCaseBlocked
+	}
+
+	fraudCase, err := server.store.CreateFraudCase(ctx, db.CreateFraudCaseParams{
+		Status:        status,
+		Decision:      result.Decision,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        arg.Amount,
+		InitiatedBy:   initiatedBy,
+		Verdicts:      verdicts,
+		// the payment request is settled if a reviewer releases the transfer
+		PaymentRequestID:     nullID(arg.PaymentRequestID),
+		InvoiceID:            nullID(arg.InvoiceID),
+		EscrowReleaseSeconds: nullID(arg.EscrowReleaseSeconds),
+		EscrowDescription:    arg.EscrowDescription,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if status == db.FraudCaseBlocked {
+		err := errors.New("transfer was blocked")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse("transfer held for review", newHeldTransferResponse(fraudCase)))
+	return false
+}
+
+type fraudCaseURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// getHeldTransfer lets the customer who made a transfer follow its review
+func (server *Server) getHeldTransfer(ctx *gin.Context) {
+	var uri fraudCaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fraudCase, ok := server.fraudCase(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fraudCase.InitiatedBy != authPayload.Username ||
+		!authPayload.CanAccessAccount(fraudCase.FromAccountID) ||
+		fraudCase.Status == db.FraudCaseBlocked {
+		err := fmt.Errorf("held transfer with ID [%d] does not exist", uri.ID)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("held transfer retrieved successfully", newHeldTransferResponse(fraudCase)))
+}
+
+type listFraudCasesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=open approved rejected blocked"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listFraudCases is the back-office review queue, oldest case first
+func (server *Server) listFraudCases(ctx *gin.Context) {
+	var request listFraudCasesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.Status == "" {
+		request.Status = db.FraudCaseOpen
+	}
+
+	cases, err := server.store.ListFraudCases(ctx, db.ListFraudCasesParams{
+		Status: request.Status,
+		Limit:  request.PageSize,
+		Offset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("fraud cases retrieved successfully", cases))
+}
+
+func (server *Server) getFraudCase(ctx *gin.Context) {
+	var uri fraudCaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fraudCase, ok := server.fraudCase(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("fraud case retrieved successfully", fraudCase))
+}
+
+type reviewFraudCaseRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// approveFraudCase releases a held transfer, which is made there and then
+// unless the source account's approval policies cover it
+func (server *Server) approveFraudCase(ctx *gin.Context) {
+	var uri fraudCaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request reviewFraudCaseRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fraudCase, ok := server.reviewableFraudCase(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.ApproveFraudCaseTrxn(ctx, db.ApproveFraudCaseTxnParams{
+		ID:       fraudCase.ID,
+		Reviewer: authPayload.Username,
+		Note:     request.Note,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrFraudCaseClosed) || settlementConflict(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrAccountBlocked) || errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrPotTransfer) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusForbidden, limitErrorResponse(limitErr))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if result.PendingTransfer != nil {
+		ctx.JSON(http.StatusAccepted, successResponse("fraud case approved, transfer is pending approval", result))
+		return
+	}
+	ctx.JSON(http.StatusOK, successResponse("fraud case approved successfully", result))
+}
+
+// rejectFraudCase drops a held transfer, a note explaining why is required
+func (server *Server) rejectFraudCase(ctx *gin.Context) {
+	var uri fraudCaseURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request reviewFraudCaseRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.Note == "" {
+		err := errors.New("a note is required when rejecting")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fraudCase, ok := server.reviewableFraudCase(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	fraudCase, err := server.store.CloseFraudCase(ctx, db.CloseFraudCaseParams{
+		ID:         fraudCase.ID,
+		Status:     db.FraudCaseRejected,
+		Reviewer:   sql.NullString{String: authPayload.Username, Valid: true},
+		ReviewNote: request.Note,
+	})
+	if err != nil {
+		// another reviewer got there first
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusConflict, errorResponse(db.ErrFraudCaseClosed))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("fraud case rejected successfully", fraudCase))
+}
+
+func (server *Server) fraudCase(ctx *gin.Context, id int64) (db.FraudCase, bool) {
+	fraudCase, err := server.store.GetFraudCase(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("fraud case with ID [%d] does not exist", id)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return fraudCase, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return fraudCase, false
+	}
+	return fraudCase, true
+}
+
+// reviewableFraudCase returns an open case the reviewer may decide on, nobody
+// reviews their own transfers
+func (server *Server) reviewableFraudCase(ctx *gin.Context, id int64) (db.FraudCase, bool) {
+	fraudCase, ok := server.fraudCase(ctx, id)
+	if !ok {
+		return fraudCase, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fraudCase.InitiatedBy == authPayload.Username {
+		err := errors.New("reviewers can't decide on their own transfers")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return fraudCase, false
+	}
+
+	if fraudCase.Status != db.FraudCaseOpen {
+		ctx.JSON(http.StatusConflict, errorResponse(db.ErrFraudCaseClosed))
+		return fraudCase, false
+	}
+	return fraudCase, true
+}
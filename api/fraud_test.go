@@ -0,0 +1,397 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/fraud"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRule decides every transfer the same way
+type stubRule string
+
+func (stubRule) Name() string {
+	return "stub"
+}
+
+func (r stubRule) Evaluate(context.Context, fraud.Transfer) (fraud.Verdict, error) {
+	return fraud.Verdict{Decision: string(r), Reason: "stubbed"}, nil
+}
+
+func Test_TransferScreeningAPI(t *testing.T) {
+	amount := int64(10)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := generateRandomAccount(user1.Username)
+	account2 := generateRandomAccount(user2.Username)
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+
+	caseArg := func(status, decision string) db.CreateFraudCaseParams {
+		verdicts, err := json.Marshal([]fraud.Verdict{{Rule: "stub", Decision: decision, Reason: "stubbed"}})
+		require.NoError(t, err)
+
+		return db.CreateFraudCaseParams{
+			Status:        status,
+			Decision:      decision,
+			FromAccountID: account1.ID,
+			ToAccountID:   account2.ID,
+			Amount:        amount,
+			InitiatedBy:   user1.Username,
+			Verdicts:      verdicts,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		decision      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Allow",
+			decision: fraud.DecisionAllow,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateFraudCase(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Hold",
+			decision: fraud.DecisionHold,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := caseArg(db.FraudCaseOpen, fraud.DecisionHold)
+				store.EXPECT().CreateFraudCase(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.FraudCase{ID: 7, Status: arg.Status, Verdicts: arg.Verdicts}, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var body struct {
+					Data map[string]interface{} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Equal(t, float64(7), body.Data["id"])
+				require.Equal(t, db.FraudCaseOpen, body.Data["status"])
+				require.NotContains(t, body.Data, "verdicts")
+			},
+		},
+		{
+			name:     "Block",
+			decision: fraud.DecisionBlock,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := caseArg(db.FraudCaseBlocked, fraud.DecisionBlock)
+				store.EXPECT().CreateFraudCase(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.FraudCase{ID: 8, Status: arg.Status}, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.fraudEngine = fraud.NewEngine(stubRule(tc.decision))
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount,
+				"currency_code":   utils.USD,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user1.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetHeldTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+	otherAccount := generateRandomAccount(user.Username)
+
+	fraudCase := db.FraudCase{
+		ID:            utils.RandomInt(1, 1000),
+		Status:        db.FraudCaseOpen,
+		Decision:      fraud.DecisionHold,
+		FromAccountID: account.ID,
+		ToAccountID:   utils.RandomInt(1, 1000),
+		Amount:        utils.RandomMoney(),
+		InitiatedBy:   user.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "GrantedAccount",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{account.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AccountNotGranted",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{otherAccount.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUser",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, utils.RandomOwner(), time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetFraudCase(gomock.Any(), gomock.Eq(fraudCase.ID)).Times(1).Return(fraudCase, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/transfers/held/%d", fraudCase.ID), nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ReviewFraudCaseAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	customer, _ := randomUser(t)
+
+	open := db.FraudCase{
+		ID:            utils.RandomInt(1, 1000),
+		Status:        db.FraudCaseOpen,
+		Decision:      fraud.DecisionHold,
+		FromAccountID: utils.RandomInt(1, 1000),
+		ToAccountID:   utils.RandomInt(1, 1000),
+		Amount:        utils.RandomMoney(),
+		InitiatedBy:   customer.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		action        string
+		fraudCase     db.FraudCase
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Approve",
+			action:    "approve",
+			fraudCase: open,
+			body:      gin.H{"note": "known payee"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveFraudCaseTrxn(gomock.Any(), gomock.Eq(db.ApproveFraudCaseTxnParams{
+					ID:       open.ID,
+					Reviewer: admin.Username,
+					Note:     "known payee",
+				})).Times(1).Return(db.ApproveFraudCaseTxnResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "ApproveUnderApprovalPolicy",
+			action:    "approve",
+			fraudCase: open,
+			body:      gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveFraudCaseTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ApproveFraudCaseTxnResult{PendingTransfer: &db.PendingTransfer{ID: 1}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:      "ApproveOverLimit",
+			action:    "approve",
+			fraudCase: open,
+			body:      gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveFraudCaseTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ApproveFraudCaseTxnResult{}, &db.LimitExceededError{Limit: db.LimitDailyAmount})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "Reject",
+			action:    "reject",
+			fraudCase: open,
+			body:      gin.H{"note": "mule account"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CloseFraudCase(gomock.Any(), gomock.Eq(db.CloseFraudCaseParams{
+					ID:         open.ID,
+					Status:     db.FraudCaseRejected,
+					Reviewer:   sql.NullString{String: admin.Username, Valid: true},
+					ReviewNote: "mule account",
+				})).Times(1).Return(db.FraudCase{ID: open.ID, Status: db.FraudCaseRejected}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "RejectWithoutNote",
+			action:    "reject",
+			fraudCase: open,
+			body:      gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetFraudCase(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CloseFraudCase(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "AlreadyClosed",
+			action: "approve",
+			fraudCase: func() db.FraudCase {
+				closed := open
+				closed.Status = db.FraudCaseRejected
+				return closed
+			}(),
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveFraudCaseTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "OwnTransfer",
+			action: "approve",
+			fraudCase: func() db.FraudCase {
+				own := open
+				own.InitiatedBy = admin.Username
+				return own
+			}(),
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ApproveFraudCaseTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			store.EXPECT().GetFraudCase(gomock.Any(), gomock.Eq(tc.fraudCase.ID)).AnyTimes().Return(tc.fraudCase, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/fraud/cases/%d/%s", tc.fraudCase.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
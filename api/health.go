@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type ledgerHealthResponse struct {
+	State         string     `json:"state"`
+	Discrepancies int        `json:"discrepancies"`
+	CheckedAt     *time.Time `json:"checked_at,omitempty"`
+}
+
+// ledgerHealth exposes the latest reconciliation as a health signal, answering
+// 503 once it found discrepancies or failed. Details are only shown to admins.
+func (server *Server) ledgerHealth(ctx *gin.Context) {
+	status := server.ledgerMonitor.Status()
+
+	response := ledgerHealthResponse{State: status.State}
+	if status.Summary != nil {
+		response.Discrepancies = status.Summary.Discrepancies
+		response.CheckedAt = &status.Summary.FinishedAt
+	}
+
+	code := http.StatusOK
+	if !status.Healthy() {
+		code = http.StatusServiceUnavailable
+	}
+	ctx.JSON(code, response)
+}
+
+func (server *Server) getReconciliation(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, successResponse("reconciliation retrieved successfully", server.ledgerMonitor.Status()))
+}
+
+func (server *Server) unblockAccount(ctx *gin.Context) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.SetAccountStatus(ctx, db.SetAccountStatusParams{
+		ID:     request.ID,
+		Status: db.AccountStatusActive,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("account unblocked successfully", account))
+}
+
+// ReconcileEvery checks the ledger every interval until ctx is cancelled
+func (server *Server) ReconcileEvery(ctx context.Context, interval time.Duration) {
+	server.ledgerMonitor.Run(ctx, interval)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/reconcile"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LedgerHealthAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountEntryTotals(gomock.Any(), gomock.Any()).Times(1).
+		Return([]db.ListAccountEntryTotalsRow{{ID: 1, Balance: 20, EntriesTotal: 10}}, nil)
+	store.EXPECT().ListTransferEntryCounts(gomock.Any(), gomock.Any()).Times(1).
+		Return([]db.ListTransferEntryCountsRow{}, nil)
+	store.EXPECT().SumPostingsByCurrency(gomock.Any()).Times(1).
+		Return([]db.SumPostingsByCurrencyRow{}, nil)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/health/ledger", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	server.ledgerMonitor.RunOnce(context.Background())
+
+	recorder = httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	var response ledgerHealthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, reconcile.StateDiscrepancies, response.State)
+	require.Equal(t, 1, response.Discrepancies)
+	require.NotContains(t, recorder.Body.String(), "account_id")
+}
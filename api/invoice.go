@@ -0,0 +1,444 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/pkg/invoice"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const dueDateLayout = "2006-01-02"
+
+type invoiceResponse struct {
+	Invoice   db.Invoice          `json:"invoice"`
+	AmountDue int64               `json:"amount_due"`
+	Items     []db.InvoiceItem    `json:"items"`
+	Payments  []db.InvoicePayment `json:"payments"`
+}
+
+// payableInvoiceResponse is what anyone holding an invoice's pay link sees
+type payableInvoiceResponse struct {
+	Merchant     string           `json:"merchant"`
+	Customer     string           `json:"customer"`
+	CurrencyCode string           `json:"currency_code"`
+	Items        []db.InvoiceItem `json:"items"`
+	Subtotal     int64            `json:"subtotal"`
+	TaxRateBps   int32            `json:"tax_rate_bps"`
+	TaxAmount    int64            `json:"tax_amount"`
+	Total        int64            `json:"total"`
+	AmountPaid   int64            `json:"amount_paid"`
+	AmountDue    int64            `json:"amount_due"`
+	Status       string           `json:"status"`
+	Memo         string           `json:"memo"`
+	DueDate      string           `json:"due_date"`
+}
+
+type invoiceItemRequest struct {
+	Description string `json:"description" binding:"required,max=200"`
+	Quantity    int64  `json:"quantity" binding:"required,gt=0"`
+	UnitAmount  int64  `json:"unit_amount" binding:"min=0"`
+}
+
+type createInvoiceRequest struct {
+	CurrencyCode string               `json:"currency_code" binding:"required,currency"`
+	ToAccountID  int64                `json:"to_account_id" binding:"omitempty,min=1"`
+	Customer     string               `json:"customer" binding:"max=128"`
+	TaxRateBps   int32                `json:"tax_rate_bps" binding:"min=0,max=10000"`
+	DueDate      string               `json:"due_date" binding:"required,datetime=2006-01-02"`
+	Memo         string               `json:"memo" binding:"max=500"`
+	Items        []invoiceItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// createInvoice drafts an invoice. Tax is charged on the sum of the items and
+// it is paid into the merchant's primary account in the currency unless they
+// name one. Nobody can pay it until it is sent.
+func (server *Server) createInvoice(ctx *gin.Context) {
+	var request createInvoiceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dueDate, err := time.Parse(dueDateLayout, request.DueDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if dueDate.Before(time.Now().UTC().Truncate(24 * time.Hour)) {
+		err := errors.New("due date can't be in the past")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	items := make([]invoice.Item, 0, len(request.Items))
+	for _, item := range request.Items {
+		items = append(items, invoice.Item{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+		})
+	}
+
+	subtotal, tax, total, err := invoice.Totals(items, request.TaxRateBps)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if total <= 0 {
+		err := errors.New("invoice total must be more than zero")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	toAccount, ok := server.receivingAccount(ctx, request.ToAccountID, request.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	payToken, err := invoice.NewPayToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateInvoiceTxnParams{
+		Invoice: db.CreateInvoiceParams{
+			Merchant:     authPayload.Username,
+			ToAccountID:  toAccount.ID,
+			Customer:     request.Customer,
+			CurrencyCode: request.CurrencyCode,
+			Subtotal:     subtotal,
+			TaxRateBps:   request.TaxRateBps,
+			TaxAmount:    tax,
+			Total:        total,
+			PayToken:     payToken,
+			Memo:         request.Memo,
+			DueDate:      dueDate,
+		},
+		Items: make([]db.CreateInvoiceItemParams, 0, len(items)),
+	}
+	for _, item := range items {
+		// Totals already checked the amounts don't overflow
+		amount, _ := item.Amount()
+		arg.Items = append(arg.Items, db.CreateInvoiceItemParams{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+			Amount:      amount,
+		})
+	}
+
+	result, err := server.store.CreateInvoiceTrxn(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invoice created successfully", invoiceResponse{
+		Invoice:   result.Invoice,
+		AmountDue: result.Invoice.Total - result.Invoice.AmountPaid,
+		Items:     result.Items,
+		Payments:  []db.InvoicePayment{},
+	}))
+}
+
+type listInvoicesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listInvoices(ctx *gin.Context) {
+	var request listInvoicesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	invoices, err := server.store.ListInvoices(ctx, db.ListInvoicesParams{
+		Merchant: authPayload.Username,
+		Limit:    request.PageSize,
+		Offset:   (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if authPayload.ClientID != "" {
+		shared := invoices[:0]
+		for _, inv := range invoices {
+			if authPayload.CanAccessAccount(inv.ToAccountID) {
+				shared = append(shared, inv)
+			}
+		}
+		invoices = shared
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invoices retrieved successfully", invoices))
+}
+
+type invoiceURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getInvoice(ctx *gin.Context) {
+	var uri invoiceURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inv, ok := server.merchantInvoice(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	items, err := server.store.ListInvoiceItems(ctx, inv.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payments, err := server.store.ListInvoicePayments(ctx, inv.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invoice retrieved successfully", invoiceResponse{
+		Invoice:   inv,
+		AmountDue: inv.Total - inv.AmountPaid,
+		Items:     items,
+		Payments:  payments,
+	}))
+}
+
+// sendInvoice makes a draft payable through its pay link
+func (server *Server) sendInvoice(ctx *gin.Context) {
+	server.changeInvoice(ctx, server.store.SendInvoice, "invoice sent successfully")
+}
+
+// voidInvoice cancels an invoice nothing was paid on yet
+func (server *Server) voidInvoice(ctx *gin.Context) {
+	server.changeInvoice(ctx, server.store.VoidInvoice, "invoice voided successfully")
+}
+
+// changeInvoice moves one of the merchant's invoices to its next status. An
+// invoice in the wrong status for the change, or one that was already paid
+// towards when voiding, is a conflict.
+func (server *Server) changeInvoice(ctx *gin.Context, change func(ctx context.Context, id int64) (db.Invoice, error), message string) {
+	var uri invoiceURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inv, ok := server.merchantInvoice(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	changed, err := change(ctx, inv.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("invoice with ID [%d] can't be changed while %s", inv.ID, inv.Status)
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(message, changed))
+}
+
+// merchantInvoice loads an invoice the authenticated user issued into an
+// account the token may access. Other merchants' invoices look the same as
+// missing ones.
+func (server *Server) merchantInvoice(ctx *gin.Context, id int64) (db.Invoice, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	inv, err := server.store.GetInvoice(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return inv, false
+	}
+	if err != nil || inv.Merchant != authPayload.Username || !authPayload.CanAccessAccount(inv.ToAccountID) {
+		err := fmt.Errorf("invoice with ID [%d] does not exist", id)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return inv, false
+	}
+
+	return inv, true
+}
+
+// getInvoiceAging sums what the merchant is still owed on sent and overdue
+// invoices by how many days past due they are
+func (server *Server) getInvoiceAging(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	invoices, err := server.store.ListOutstandingInvoices(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	outstanding := make([]invoice.Outstanding, 0, len(invoices))
+	for _, inv := range invoices {
+		if !authPayload.CanAccessAccount(inv.ToAccountID) {
+			continue
+		}
+		outstanding = append(outstanding, invoice.Outstanding{
+			CurrencyCode: inv.CurrencyCode,
+			DueDate:      inv.DueDate,
+			AmountDue:    inv.Total - inv.AmountPaid,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invoice aging retrieved successfully", invoice.Aging(outstanding, time.Now().UTC())))
+}
+
+type payInvoiceURI struct {
+	Token string `uri:"token" binding:"required,max=64"`
+}
+
+// payableInvoice loads the invoice behind a pay link. Drafts aren't shown to
+// anyone but the merchant until they are sent.
+func (server *Server) payableInvoice(ctx *gin.Context) (db.Invoice, bool) {
+	var uri payInvoiceURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Invoice{}, false
+	}
+
+	inv, err := server.store.GetInvoiceByPayToken(ctx, uri.Token)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return inv, false
+	}
+	if err != nil || inv.Status == db.InvoiceDraft {
+		err := errors.New("invoice does not exist")
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return inv, false
+	}
+
+	return inv, true
+}
+
+// getPayableInvoice shows an invoice to whoever holds its pay link
+func (server *Server) getPayableInvoice(ctx *gin.Context) {
+	inv, ok := server.payableInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	items, err := server.store.ListInvoiceItems(ctx, inv.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invoice retrieved successfully", payableInvoiceResponse{
+		Merchant:     inv.Merchant,
+		Customer:     inv.Customer,
+		CurrencyCode: inv.CurrencyCode,
+		Items:        items,
+		Subtotal:     inv.Subtotal,
+		TaxRateBps:   inv.TaxRateBps,
+		TaxAmount:    inv.TaxAmount,
+		Total:        inv.Total,
+		AmountPaid:   inv.AmountPaid,
+		AmountDue:    inv.Total - inv.AmountPaid,
+		Status:       inv.Status,
+		Memo:         inv.Memo,
+		DueDate:      inv.DueDate.Format(dueDateLayout),
+	}))
+}
+
+type payInvoiceRequest struct {
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,excluded_with=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"omitempty,numeric"`
+	// Amount pays part of the invoice, the whole amount due is paid without it
+	Amount   int64  `json:"amount" binding:"omitempty,gt=0"`
+	TOTPCode string `json:"totp_code"`
+}
+
+// payInvoice pays an invoice through its pay link, in full or in part, through
+// the same checks as any other transfer. A payment that is held or needs
+// approval is counted towards the invoice once it is released.
+func (server *Server) payInvoice(ctx *gin.Context) {
+	var request payInvoiceRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inv, ok := server.payableInvoice(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if inv.Merchant == authPayload.Username {
+		err := errors.New("you can't pay your own invoice")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !db.InvoicePayable(inv.Status) {
+		ctx.JSON(http.StatusConflict, errorResponse(db.ErrInvoiceNotPayable))
+		return
+	}
+
+	amountDue := inv.Total - inv.AmountPaid
+	amount := request.Amount
+	if amount == 0 {
+		amount = amountDue
+	}
+	if amount > amountDue {
+		ctx.JSON(http.StatusBadRequest, errorResponse(db.ErrInvoiceOverpaid))
+		return
+	}
+
+	fromAccount, ok := server.transferAccount(ctx, request.FromAccountID, request.FromAccountNumber, inv.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	grant, ok := server.authorizeAccount(ctx, fromAccount, access.PermissionTransfer)
+	if !ok {
+		return
+	}
+
+	if err := grant.CanSpend(amount); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	if !server.requireTransferMFA(ctx, authPayload.Username, amount, request.TOTPCode) {
+		return
+	}
+
+	toAccount, ok := server.transferAccount(ctx, inv.ToAccountID, "", inv.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	server.sendTransfer(ctx, fromAccount, toAccount, db.TransferTxnParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+		InvoiceID:     inv.ID,
+	}, authPayload.Username)
+}
@@ -0,0 +1,563 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/invoice"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomInvoice(merchant string, toAccount db.Account) db.Invoice {
+	total := utils.RandomInt(100, 1000)
+	return db.Invoice{
+		ID:           utils.RandomInt(1, 1000),
+		Merchant:     merchant,
+		ToAccountID:  toAccount.ID,
+		CurrencyCode: toAccount.CurrencyCode,
+		Subtotal:     total,
+		Total:        total,
+		Status:       db.InvoiceSent,
+		PayToken:     utils.RandomString(32),
+		DueDate:      time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 14),
+	}
+}
+
+func Test_CreateInvoiceAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+
+	account := generateRandomAccount(merchant.Username)
+	account.CurrencyCode = utils.USD
+
+	dueDate := time.Now().UTC().AddDate(0, 0, 30).Format(dueDateLayout)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"currency_code": utils.USD,
+				"customer":      "Acme Ltd",
+				"tax_rate_bps":  750,
+				"due_date":      dueDate,
+				"items": []gin.H{
+					{"description": "design", "quantity": 3, "unit_amount": 1500},
+					{"description": "hosting", "quantity": 1, "unit_amount": 999},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(db.GetPrimaryAccountParams{
+					Owner:        merchant.Username,
+					CurrencyCode: utils.USD,
+				})).Times(1).Return(account, nil)
+				store.EXPECT().CreateInvoiceTrxn(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateInvoiceTxnParams) (db.CreateInvoiceTxnResult, error) {
+						require.Equal(t, merchant.Username, arg.Invoice.Merchant)
+						require.Equal(t, account.ID, arg.Invoice.ToAccountID)
+						require.Equal(t, int64(5499), arg.Invoice.Subtotal)
+						require.Equal(t, int64(412), arg.Invoice.TaxAmount)
+						require.Equal(t, int64(5911), arg.Invoice.Total)
+						require.Equal(t, dueDate, arg.Invoice.DueDate.Format(dueDateLayout))
+						require.NotEmpty(t, arg.Invoice.PayToken)
+						require.Len(t, arg.Items, 2)
+						require.Equal(t, int64(4500), arg.Items[0].Amount)
+						return db.CreateInvoiceTxnResult{Invoice: db.Invoice{ID: 1, Status: db.InvoiceDraft}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DueDateInPast",
+			body: gin.H{
+				"currency_code": utils.USD,
+				"due_date":      time.Now().UTC().AddDate(0, 0, -2).Format(dueDateLayout),
+				"items":         []gin.H{{"description": "design", "quantity": 1, "unit_amount": 100}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvoiceTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ZeroTotal",
+			body: gin.H{
+				"currency_code": utils.USD,
+				"due_date":      dueDate,
+				"items":         []gin.H{{"description": "goodwill", "quantity": 1, "unit_amount": 0}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvoiceTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoItems",
+			body: gin.H{
+				"currency_code": utils.USD,
+				"due_date":      dueDate,
+				"items":         []gin.H{},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateInvoiceTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/invoices", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, merchant.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_PayInvoiceAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	customer, _ := randomUser(t)
+
+	toAccount := generateRandomAccount(merchant.Username)
+	fromAccount := generateRandomAccount(customer.Username)
+	toAccount.CurrencyCode = utils.USD
+	fromAccount.CurrencyCode = utils.USD
+
+	inv := randomInvoice(merchant.Username, toAccount)
+	inv.AmountPaid = 40
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "PaysAmountDue",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Eq(db.TransferTxnParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        inv.Total - inv.AmountPaid,
+					InvoiceID:     inv.ID,
+					InitiatedBy:   customer.Username,
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "PartialPayment",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID, "amount": 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Eq(db.TransferTxnParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        10,
+					InvoiceID:     inv.ID,
+					InitiatedBy:   customer.Username,
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "MoreThanDue",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID, "amount": inv.Total},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnInvoice",
+			username: merchant.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Void",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				voided := inv
+				voided.Status = db.InvoiceVoid
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(voided, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "Draft",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				draft := inv
+				draft.Status = db.InvoiceDraft
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(draft, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "PaidInTheMeantime",
+			username: customer.Username,
+			body:     gin.H{"from_account_id": fromAccount.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.TransferTrxResult{}, db.ErrInvoiceOverpaid)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/invoices/pay/%s", inv.PayToken)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetPayableInvoiceAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	inv := randomInvoice(merchant.Username, generateRandomAccount(merchant.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetInvoiceByPayToken(gomock.Any(), gomock.Eq(inv.PayToken)).Times(1).Return(inv, nil)
+	store.EXPECT().ListInvoiceItems(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return([]db.InvoiceItem{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	// the pay link works without logging in
+	request, err := http.NewRequest(http.MethodGet, "/invoices/pay/"+inv.PayToken, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data payableInvoiceResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, inv.Total, response.Data.AmountDue)
+	require.Equal(t, inv.DueDate.Format(dueDateLayout), response.Data.DueDate)
+}
+
+func Test_ChangeInvoiceAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	inv := randomInvoice(merchant.Username, generateRandomAccount(merchant.Username))
+	inv.Status = db.InvoiceDraft
+
+	testCases := []struct {
+		name          string
+		action        string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Send",
+			action:   "send",
+			username: merchant.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				sent := inv
+				sent.Status = db.InvoiceSent
+				store.EXPECT().GetInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(inv, nil)
+				store.EXPECT().SendInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(sent, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "VoidPartlyPaid",
+			action:   "void",
+			username: merchant.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(inv, nil)
+				store.EXPECT().VoidInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(db.Invoice{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherMerchant",
+			action:   "void",
+			username: other.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(inv, nil)
+				store.EXPECT().VoidInvoice(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/invoices/%d/%s", inv.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetInvoiceAgingAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	account := generateRandomAccount(merchant.Username)
+	account.CurrencyCode = utils.USD
+
+	current := randomInvoice(merchant.Username, account)
+	late := randomInvoice(merchant.Username, account)
+	late.Status = db.InvoiceOverdue
+	late.DueDate = time.Now().UTC().AddDate(0, 0, -45)
+	late.AmountPaid = 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListOutstandingInvoices(gomock.Any(), gomock.Eq(merchant.Username)).Times(1).
+		Return([]db.Invoice{late, current}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/invoices/aging", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, merchant.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data []invoice.AgingRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, []invoice.AgingRow{{
+		CurrencyCode: utils.USD,
+		Current:      current.Total,
+		Days31To60:   late.Total - 1,
+		Total:        current.Total + late.Total - 1,
+		Invoices:     2,
+	}}, response.Data)
+}
+
+func Test_ListInvoicesAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	granted := generateRandomAccount(merchant.Username)
+	notGranted := generateRandomAccount(merchant.Username)
+
+	shared := randomInvoice(merchant.Username, granted)
+	hidden := randomInvoice(merchant.Username, notGranted)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, merchant.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListInvoices(gomock.Any(), gomock.Any()).Times(1).Return([]db.Invoice{shared, hidden}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchInvoiceIDs(t, recorder.Body, shared.ID, hidden.ID)
+			},
+		},
+		{
+			name: "OAuthClient",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, merchant.Username, []int64{granted.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+				store.EXPECT().ListInvoices(gomock.Any(), gomock.Any()).Times(1).Return([]db.Invoice{shared, hidden}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchInvoiceIDs(t, recorder.Body, shared.ID)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/invoices?page_id=1&page_size=5", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetInvoiceOutsideGrantAPI(t *testing.T) {
+	merchant, _ := randomUser(t)
+	granted := generateRandomAccount(merchant.Username)
+	inv := randomInvoice(merchant.Username, generateRandomAccount(merchant.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+	store.EXPECT().GetInvoice(gomock.Any(), gomock.Eq(inv.ID)).Times(1).Return(inv, nil)
+	store.EXPECT().ListInvoiceItems(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/invoices/%d", inv.ID), nil)
+	require.NoError(t, err)
+
+	addOAuthAuthorization(t, request, server.tokenGenerator, merchant.Username, []int64{granted.ID})
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func requireBodyMatchInvoiceIDs(t *testing.T, body *bytes.Buffer, ids ...int64) {
+	var response struct {
+		Data []db.Invoice `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+	require.Len(t, response.Data, len(ids))
+	for i, id := range ids {
+		require.Equal(t, id, response.Data[i].ID)
+	}
+}
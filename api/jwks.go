@@ -0,0 +1,18 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) getJWKS(ctx *gin.Context) {
+	jwks := token.JWKSet{Keys: []token.JWK{}}
+	if server.keyRing != nil {
+		jwks = server.keyRing.JWKS()
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
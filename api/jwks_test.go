@@ -0,0 +1,76 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_JWKSAPI(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+
+	server, err := NewServer(utils.Config{
+		Environment:         utils.EnvDevelopment,
+		TokenSigningKeyID:   "2023-11",
+		TokenSigningKey:     base64.StdEncoding.EncodeToString(seed),
+		AccessTokenDuration: time.Minute,
+	}, nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var jwks token.JWKSet
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	require.Equal(t, "2023-11", jwks.Keys[0].KeyID)
+
+	// a downstream service only holding the published key can verify our tokens
+	accessToken, err := server.tokenGenerator.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	publicKey, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+	require.NoError(t, err)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	verifierRing, err := token.NewKeyRing("verifier-only", otherKey)
+	require.NoError(t, err)
+	require.NoError(t, verifierRing.AddVerificationKey(jwks.Keys[0].KeyID, publicKey))
+
+	verifier, err := token.NewPasetoPublicMaker(verifierRing)
+	require.NoError(t, err)
+
+	_, err = verifier.VerifyToken(accessToken)
+	require.NoError(t, err)
+}
+
+func Test_JWKSAPISymmetric(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var jwks token.JWKSet
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &jwks))
+	require.Empty(t, jwks.Keys)
+}
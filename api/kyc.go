@@ -0,0 +1,458 @@
+package api
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/kyc"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const dateLayout = "2006-01-02"
+
+// kycProfileResponse is what customers see of their own profile, the ID number
+// is never sent back in full
+type kycProfileResponse struct {
+	Status        string     `json:"status"`
+	RequestedTier string     `json:"requested_tier"`
+	DateOfBirth   string     `json:"date_of_birth"`
+	PhoneNumber   string     `json:"phone_number"`
+	AddressLine1  string     `json:"address_line1"`
+	AddressLine2  string     `json:"address_line2"`
+	City          string     `json:"city"`
+	PostalCode    string     `json:"postal_code"`
+	CountryCode   string     `json:"country_code"`
+	Nationality   string     `json:"nationality"`
+	IDNumberLast4 string     `json:"id_number_last4"`
+	ReviewNote    string     `json:"review_note"`
+	SubmittedAt   time.Time  `json:"submitted_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+}
+
+func newKYCProfileResponse(profile db.KycProfile) *kycProfileResponse {
+	last4 := profile.IDNumber
+	if len(last4) > 4 {
+		last4 = last4[len(last4)-4:]
+	}
+
+	return &kycProfileResponse{
+		Status:        profile.Status,
+		RequestedTier: profile.RequestedTier,
+		DateOfBirth:   profile.DateOfBirth.Format(dateLayout),
+		PhoneNumber:   profile.PhoneNumber,
+		AddressLine1:  profile.AddressLine1,
+		AddressLine2:  profile.AddressLine2,
+		City:          profile.City,
+		PostalCode:    profile.PostalCode,
+		CountryCode:   profile.CountryCode,
+		Nationality:   profile.Nationality,
+		IDNumberLast4: last4,
+		ReviewNote:    profile.ReviewNote,
+		SubmittedAt:   profile.SubmittedAt,
+		ReviewedAt:    nullTimePtr(profile.ReviewedAt),
+	}
+}
+
+type kycResponse struct {
+	Tier      string              `json:"tier"`
+	Profile   *kycProfileResponse `json:"profile"`
+	Documents []db.KycDocument    `json:"documents"`
+}
+
+func (server *Server) getKYC(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := kycResponse{Tier: user.Tier}
+
+	profile, err := server.store.GetKycProfile(ctx, authPayload.Username)
+	switch {
+	case err == nil:
+		response.Profile = newKYCProfileResponse(profile)
+	case !errors.Is(err, sql.ErrNoRows):
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response.Documents, err = server.store.ListKycDocuments(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc status retrieved successfully", response))
+}
+
+func (server *Server) listKYCTiers(ctx *gin.Context) {
+	tiers, err := server.store.ListKycTiers(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc tiers retrieved successfully", tiers))
+}
+
+type createKYCDocumentRequest struct {
+	DocumentType string `json:"document_type" binding:"required,oneof=passport national_id drivers_license proof_of_address selfie"`
+	FileName     string `json:"file_name" binding:"required,max=255"`
+	ContentType  string `json:"content_type" binding:"required,oneof=image/jpeg image/png application/pdf"`
+	SizeBytes    int64  `json:"size_bytes" binding:"required,min=1,max=10485760"`
+	SHA256       string `json:"sha256" binding:"required,len=64,hexadecimal"`
+}
+
+// createKYCDocument records an uploaded document's metadata. The file itself
+// goes to document storage under the returned storage key.
+func (server *Server) createKYCDocument(ctx *gin.Context) {
+	var request createKYCDocumentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.kycEditable(ctx, authPayload.Username) {
+		return
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	document, err := server.store.CreateKycDocument(ctx, db.CreateKycDocumentParams{
+		Username:     authPayload.Username,
+		DocumentType: request.DocumentType,
+		FileName:     request.FileName,
+		ContentType:  request.ContentType,
+		SizeBytes:    request.SizeBytes,
+		Sha256:       request.SHA256,
+		StorageKey:   fmt.Sprintf("kyc/%s/%s", authPayload.Username, hex.EncodeToString(suffix)),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc document recorded successfully", document))
+}
+
+type submitKYCRequest struct {
+	RequestedTier string `json:"requested_tier" binding:"required,alphanum"`
+	DateOfBirth   string `json:"date_of_birth" binding:"required,datetime=2006-01-02"`
+	PhoneNumber   string `json:"phone_number" binding:"required,e164"`
+	AddressLine1  string `json:"address_line1" binding:"required,max=200"`
+	AddressLine2  string `json:"address_line2" binding:"max=200"`
+	City          string `json:"city" binding:"required,max=100"`
+	PostalCode    string `json:"postal_code" binding:"required,max=20"`
+	CountryCode   string `json:"country_code" binding:"required,iso3166_1_alpha2"`
+	Nationality   string `json:"nationality" binding:"required,iso3166_1_alpha2"`
+	IDNumber      string `json:"id_number" binding:"required,max=64"`
+}
+
+// submitKYC sends the customer's profile to the identity provider and queues it
+// for back-office review
+func (server *Server) submitKYC(ctx *gin.Context) {
+	var request submitKYCRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// validated by the binding above
+	dateOfBirth, _ := time.Parse(dateLayout, request.DateOfBirth)
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.kycEditable(ctx, authPayload.Username) {
+		return
+	}
+
+	tier, err := server.store.GetKycTier(ctx, request.RequestedTier)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("tier %s does not exist", request.RequestedTier)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if tier.Level == 0 {
+		err := fmt.Errorf("tier %s does not need verification", tier.Tier)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	documents, err := server.store.ListKycDocuments(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	applicant := kyc.Applicant{
+		Username:    user.Username,
+		FullName:    user.FullName,
+		Email:       user.Email,
+		DateOfBirth: dateOfBirth,
+		CountryCode: request.CountryCode,
+		Nationality: request.Nationality,
+		IDNumber:    request.IDNumber,
+		Documents:   make([]kyc.Document, len(documents)),
+	}
+	for i, document := range documents {
+		applicant.Documents[i] = kyc.Document{Type: document.DocumentType, SHA256: document.Sha256}
+	}
+
+	if !kyc.HasIdentityDocument(applicant.Documents) {
+		err := errors.New("upload a passport, national ID or driver's license before submitting")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := server.kycProvider.Check(ctx, applicant)
+	if err != nil {
+		err := fmt.Errorf("identity check failed: %w", err)
+		ctx.JSON(http.StatusBadGateway, errorResponse(err))
+		return
+	}
+
+	profile, err := server.store.SubmitKycProfile(ctx, db.SubmitKycProfileParams{
+		Username:          user.Username,
+		RequestedTier:     tier.Tier,
+		DateOfBirth:       dateOfBirth,
+		PhoneNumber:       request.PhoneNumber,
+		AddressLine1:      request.AddressLine1,
+		AddressLine2:      request.AddressLine2,
+		City:              request.City,
+		PostalCode:        request.PostalCode,
+		CountryCode:       request.CountryCode,
+		Nationality:       request.Nationality,
+		IDNumber:          request.IDNumber,
+		Provider:          result.Provider,
+		ProviderReference: result.Reference,
+		ProviderOutcome:   result.Outcome,
+		ProviderReasons:   result.Reasons,
+	})
+	if err != nil {
+		// another submission got there first
+		if errors.Is(err, sql.ErrNoRows) {
+			err := errors.New("a kyc submission is already pending review")
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse("kyc submitted for review", newKYCProfileResponse(profile)))
+}
+
+// kycEditable refuses changes while a submission waits for review
+func (server *Server) kycEditable(ctx *gin.Context, username string) bool {
+	profile, err := server.store.GetKycProfile(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if profile.Status == db.KYCStatusPending {
+		err := errors.New("a kyc submission is already pending review")
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return false
+	}
+	return true
+}
+
+type listKYCProfilesRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listKYCProfiles is the back-office review queue, oldest submission first
+func (server *Server) listKYCProfiles(ctx *gin.Context) {
+	var request listKYCProfilesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.Status == "" {
+		request.Status = db.KYCStatusPending
+	}
+
+	profiles, err := server.store.ListKycProfilesByStatus(ctx, db.ListKycProfilesByStatusParams{
+		Status: request.Status,
+		Limit:  request.PageSize,
+		Offset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc profiles retrieved successfully", profiles))
+}
+
+type kycUsernameURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type adminKYCResponse struct {
+	Profile   db.KycProfile    `json:"profile"`
+	Documents []db.KycDocument `json:"documents"`
+}
+
+func (server *Server) adminGetKYC(ctx *gin.Context) {
+	var uri kycUsernameURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	profile, err := server.store.GetKycProfile(ctx, uri.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s has not submitted kyc", uri.Username)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	documents, err := server.store.ListKycDocuments(ctx, uri.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc profile retrieved successfully", adminKYCResponse{
+		Profile:   profile,
+		Documents: documents,
+	}))
+}
+
+type reviewKYCRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Tier     string `json:"tier" binding:"omitempty,alphanum"`
+	Note     string `json:"note" binding:"max=500"`
+}
+
+// reviewKYC approves or rejects a pending submission. Approval grants the
+// requested tier unless the reviewer picks another one.
+func (server *Server) reviewKYC(ctx *gin.Context) {
+	var uri kycUsernameURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request reviewKYCRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username == authPayload.Username {
+		err := errors.New("reviewers can't decide on their own kyc")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	arg := db.ReviewKYCTxnParams{
+		Username: uri.Username,
+		Status:   db.KYCStatusRejected,
+		Reviewer: authPayload.Username,
+		Note:     request.Note,
+	}
+
+	if request.Decision == "reject" && request.Note == "" {
+		err := errors.New("a note is required when rejecting")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.Decision == "approve" {
+		profile, err := server.store.GetKycProfile(ctx, uri.Username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := fmt.Errorf("user %s has not submitted kyc", uri.Username)
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		arg.Status = db.KYCStatusApproved
+		arg.Tier = profile.RequestedTier
+		if request.Tier != "" {
+			arg.Tier = request.Tier
+		}
+
+		if _, err := server.store.GetKycTier(ctx, arg.Tier); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := fmt.Errorf("tier %s does not exist", arg.Tier)
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	result, err := server.store.ReviewKYCTrxn(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrKYCNotPending) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("kyc reviewed successfully", result.Profile))
+}
+
+// productAllowed refuses account products the user's tier doesn't allow
+func (server *Server) productAllowed(ctx *gin.Context, username, productCode string) bool {
+	tier, err := server.store.GetKycTierForUser(ctx, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	for _, allowed := range tier.AllowedProducts {
+		if allowed == productCode {
+			return true
+		}
+	}
+
+	err = fmt.Errorf("%s accounts require a higher kyc tier", productCode)
+	ctx.JSON(http.StatusForbidden, errorResponse(err))
+	return false
+}
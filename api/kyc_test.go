@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/kyc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func standardKYCTier() db.KycTier {
+	return db.KycTier{
+		Tier:            db.UserTierStandard,
+		Level:           0,
+		AllowedProducts: []string{db.AccountProductChecking},
+	}
+}
+
+func verifiedKYCTier() db.KycTier {
+	return db.KycTier{
+		Tier:            "verified",
+		Level:           1,
+		AllowedProducts: []string{db.AccountProductChecking, db.AccountProductSavings, db.AccountProductEscrow},
+	}
+}
+
+func randomKYCDocument(username, documentType string) db.KycDocument {
+	return db.KycDocument{
+		ID:           utils.RandomInt(1, 1000),
+		Username:     username,
+		DocumentType: documentType,
+		FileName:     "scan.pdf",
+		ContentType:  "application/pdf",
+		SizeBytes:    1024,
+		Sha256:       utils.RandomString(64),
+		StorageKey:   "kyc/" + username + "/" + utils.RandomString(32),
+	}
+}
+
+func Test_SubmitKYCAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	body := gin.H{
+		"requested_tier": "verified",
+		"date_of_birth":  "1990-04-12",
+		"phone_number":   "+2348012345678",
+		"address_line1":  "12 Marina Road",
+		"city":           "Lagos",
+		"postal_code":    "101001",
+		"country_code":   "NG",
+		"nationality":    "NG",
+		"id_number":      "A12345678",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycProfile(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.KycProfile{}, sql.ErrNoRows)
+				store.EXPECT().GetKycTier(gomock.Any(), gomock.Eq("verified")).Times(1).Return(verifiedKYCTier(), nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListKycDocuments(gomock.Any(), gomock.Eq(user.Username)).Times(1).
+					Return([]db.KycDocument{randomKYCDocument(user.Username, kyc.DocumentPassport)}, nil)
+				store.EXPECT().SubmitKycProfile(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SubmitKycProfileParams) (db.KycProfile, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, "verified", arg.RequestedTier)
+						require.Equal(t, time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC), arg.DateOfBirth)
+						require.Equal(t, kyc.FakeProviderName, arg.Provider)
+						require.Equal(t, kyc.OutcomeClear, arg.ProviderOutcome)
+						return db.KycProfile{
+							Username:      arg.Username,
+							Status:        db.KYCStatusPending,
+							RequestedTier: arg.RequestedTier,
+							DateOfBirth:   arg.DateOfBirth,
+							IDNumber:      arg.IDNumber,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var response struct {
+					Data kycProfileResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, db.KYCStatusPending, response.Data.Status)
+				require.Equal(t, "5678", response.Data.IDNumberLast4)
+				require.NotContains(t, recorder.Body.String(), "A12345678")
+			},
+		},
+		{
+			name: "NoIdentityDocument",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycProfile(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.KycProfile{}, sql.ErrNoRows)
+				store.EXPECT().GetKycTier(gomock.Any(), gomock.Eq("verified")).Times(1).Return(verifiedKYCTier(), nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListKycDocuments(gomock.Any(), gomock.Eq(user.Username)).Times(1).
+					Return([]db.KycDocument{randomKYCDocument(user.Username, kyc.DocumentProofOfAddress)}, nil)
+				store.EXPECT().SubmitKycProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyPending",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycProfile(gomock.Any(), gomock.Eq(user.Username)).Times(1).
+					Return(db.KycProfile{Username: user.Username, Status: db.KYCStatusPending}, nil)
+				store.EXPECT().SubmitKycProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCountry",
+			body: gin.H{
+				"requested_tier": "verified",
+				"date_of_birth":  "1990-04-12",
+				"phone_number":   "+2348012345678",
+				"address_line1":  "12 Marina Road",
+				"city":           "Lagos",
+				"postal_code":    "101001",
+				"country_code":   "Nigeria",
+				"nationality":    "NG",
+				"id_number":      "A12345678",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetKycProfile(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SubmitKycProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/kyc/submit", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ReviewKYCAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	customer, _ := randomUser(t)
+
+	pending := db.KycProfile{
+		Username:      customer.Username,
+		Status:        db.KYCStatusPending,
+		RequestedTier: "verified",
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Approve",
+			username: customer.Username,
+			body:     gin.H{"decision": "approve"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetKycProfile(gomock.Any(), gomock.Eq(customer.Username)).Times(1).Return(pending, nil)
+				store.EXPECT().GetKycTier(gomock.Any(), gomock.Eq("verified")).Times(1).Return(verifiedKYCTier(), nil)
+				store.EXPECT().ReviewKYCTrxn(gomock.Any(), gomock.Eq(db.ReviewKYCTxnParams{
+					Username: customer.Username,
+					Status:   db.KYCStatusApproved,
+					Tier:     "verified",
+					Reviewer: admin.Username,
+				})).Times(1).Return(db.ReviewKYCTxnResult{
+					Profile: db.KycProfile{Username: customer.Username, Status: db.KYCStatusApproved},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Reject",
+			username: customer.Username,
+			body:     gin.H{"decision": "reject", "note": "document is expired"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReviewKYCTrxn(gomock.Any(), gomock.Eq(db.ReviewKYCTxnParams{
+					Username: customer.Username,
+					Status:   db.KYCStatusRejected,
+					Reviewer: admin.Username,
+					Note:     "document is expired",
+				})).Times(1).Return(db.ReviewKYCTxnResult{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RejectWithoutNote",
+			username: customer.Username,
+			body:     gin.H{"decision": "reject"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReviewKYCTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotPending",
+			username: customer.Username,
+			body:     gin.H{"decision": "reject", "note": "duplicate"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReviewKYCTrxn(gomock.Any(), gomock.Any()).Times(1).Return(db.ReviewKYCTxnResult{}, db.ErrKYCNotPending)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnProfile",
+			username: admin.Username,
+			body:     gin.H{"decision": "approve"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReviewKYCTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/kyc/%s/review", tc.username)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
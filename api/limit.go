@@ -0,0 +1,296 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/gin-gonic/gin"
+)
+
+type transferLimitsResponse struct {
+	MaxAmount     *int64 `json:"max_amount"`
+	DailyAmount   *int64 `json:"daily_amount"`
+	MonthlyAmount *int64 `json:"monthly_amount"`
+	DailyCount    *int64 `json:"daily_count"`
+	MonthlyCount  *int64 `json:"monthly_count"`
+}
+
+type transferLimitResponse struct {
+	ID           int64   `json:"id"`
+	AccountID    *int64  `json:"account_id"`
+	ProductCode  *string `json:"product_code"`
+	Tier         *string `json:"tier"`
+	CurrencyCode *string `json:"currency_code"`
+	transferLimitsResponse
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newTransferLimitResponse(limit db.TransferLimit) transferLimitResponse {
+	return transferLimitResponse{
+		ID:           limit.ID,
+		AccountID:    nullInt64Ptr(limit.AccountID),
+		ProductCode:  nullStringPtr(limit.ProductCode),
+		Tier:         nullStringPtr(limit.Tier),
+		CurrencyCode: nullStringPtr(limit.CurrencyCode),
+		transferLimitsResponse: transferLimitsResponse{
+			MaxAmount:     nullInt64Ptr(limit.MaxAmount),
+			DailyAmount:   nullInt64Ptr(limit.DailyAmount),
+			MonthlyAmount: nullInt64Ptr(limit.MonthlyAmount),
+			DailyCount:    nullInt64Ptr(limit.DailyCount),
+			MonthlyCount:  nullInt64Ptr(limit.MonthlyCount),
+		},
+		CreatedAt: limit.CreatedAt,
+		UpdatedAt: limit.UpdatedAt,
+	}
+}
+
+type accountLimitsResponse struct {
+	AccountID int64                           `json:"account_id"`
+	Limits    transferLimitsResponse          `json:"limits"`
+	Daily     db.GetOutgoingTransferTotalsRow `json:"daily"`
+	Monthly   db.GetOutgoingTransferTotalsRow `json:"monthly"`
+}
+
+// getAccountLimits shows the limits in force for an account and how much of them is used
+func (server *Server) getAccountLimits(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
+		return
+	}
+
+	limits, err := server.store.GetAccountLimits(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("retrieved account limits successfully", accountLimitsResponse{
+		AccountID: account.ID,
+		Limits: transferLimitsResponse{
+			MaxAmount:     nullInt64Ptr(limits.Limits.MaxAmount),
+			DailyAmount:   nullInt64Ptr(limits.Limits.DailyAmount),
+			MonthlyAmount: nullInt64Ptr(limits.Limits.MonthlyAmount),
+			DailyCount:    nullInt64Ptr(limits.Limits.DailyCount),
+			MonthlyCount:  nullInt64Ptr(limits.Limits.MonthlyCount),
+		},
+		Daily:   limits.Daily,
+		Monthly: limits.Monthly,
+	}))
+}
+
+// upsertTransferLimitRequest sets the limits of one scope: a single account, an
+// account product, a user tier, a currency, or a combination of the last three.
+// Omitted limits aren't enforced at that scope and fall through to the less
+// specific ones.
+type upsertTransferLimitRequest struct {
+	AccountID     *int64 `json:"account_id" binding:"omitempty,min=1"`
+	ProductCode   string `json:"product_code" binding:"omitempty,oneof=checking savings escrow business"`
+	Tier          string `json:"tier" binding:"omitempty,alphanum,max=32"`
+	CurrencyCode  string `json:"currency_code" binding:"omitempty,currency"`
+	MaxAmount     *int64 `json:"max_amount" binding:"omitempty,gt=0"`
+	DailyAmount   *int64 `json:"daily_amount" binding:"omitempty,gt=0"`
+	MonthlyAmount *int64 `json:"monthly_amount" binding:"omitempty,gt=0"`
+	DailyCount    *int64 `json:"daily_count" binding:"omitempty,min=0"`
+	MonthlyCount  *int64 `json:"monthly_count" binding:"omitempty,min=0"`
+}
+
+func (server *Server) upsertTransferLimit(ctx *gin.Context) {
+	var request upsertTransferLimitRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if request.AccountID != nil && (request.ProductCode != "" || request.Tier != "" || request.CurrencyCode != "") {
+		err := errors.New("account limits can't also be scoped to a product, tier or currency")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if request.AccountID == nil && request.ProductCode == "" && request.Tier == "" && request.CurrencyCode == "" {
+		err := errors.New("one of account_id, product_code, tier or currency_code is required")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpsertTransferLimitParams{
+		ProductCode:   nullString(request.ProductCode),
+		Tier:          nullString(request.Tier),
+		CurrencyCode:  nullString(request.CurrencyCode),
+		MaxAmount:     nullInt64(request.MaxAmount),
+		DailyAmount:   nullInt64(request.DailyAmount),
+		MonthlyAmount: nullInt64(request.MonthlyAmount),
+		DailyCount:    nullInt64(request.DailyCount),
+		MonthlyCount:  nullInt64(request.MonthlyCount),
+	}
+
+	if request.AccountID != nil {
+		_, err := server.store.GetAccount(ctx, *request.AccountID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := fmt.Errorf("account with ID [%d] does not exist", *request.AccountID)
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.AccountID = nullInt64(request.AccountID)
+	}
+
+	limit, err := server.store.UpsertTransferLimit(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("transfer limit saved successfully", newTransferLimitResponse(limit)))
+}
+
+type listTransferLimitsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listTransferLimits(ctx *gin.Context) {
+	var request listTransferLimitsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	limits, err := server.store.ListTransferLimits(ctx, db.ListTransferLimitsParams{
+		Limit:  request.PageSize,
+		Offset: (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]transferLimitResponse, len(limits))
+	for i, limit := range limits {
+		response[i] = newTransferLimitResponse(limit)
+	}
+	ctx.JSON(http.StatusOK, successResponse("transfer limits retrieved successfully", response))
+}
+
+type deleteTransferLimitRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteTransferLimit(ctx *gin.Context) {
+	var request deleteTransferLimitRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetTransferLimit(ctx, request.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("transfer limit with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteTransferLimit(ctx, request.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(fmt.Sprintf("deleted transfer limit with id (%d) successfully", request.ID), nil))
+}
+
+type setUserTierURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type setUserTierRequest struct {
+	Tier string `json:"tier" binding:"required,alphanum,max=32"`
+}
+
+// setUserTier moves a user to another tier, which changes the limits their accounts fall back to
+func (server *Server) setUserTier(ctx *gin.Context) {
+	var uri setUserTierURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request setUserTierRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetKycTier(ctx, request.Tier); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("tier %s does not exist", request.Tier)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.SetUserTier(ctx, db.SetUserTierParams{
+		Username: uri.Username,
+		Tier:     request.Tier,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s does not exist", uri.Username)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("user tier updated successfully", newUserResponse(user)))
+}
+
+// limitErrorResponse adds which limit was hit, and when it resets, to the error
+func limitErrorResponse(err *db.LimitExceededError) gin.H {
+	res := errorResponse(err)
+	res["limit"] = err
+	return res
+}
+
+func nullInt64(i *int64) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *i, Valid: true}
+}
+
+// nullID turns an optional id, zero when absent, into a nullable column
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+func nullInt64Ptr(i sql.NullInt64) *int64 {
+	if !i.Valid {
+		return nil
+	}
+	return &i.Int64
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
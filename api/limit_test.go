@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpsertTransferLimitAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = roleAdmin
+	customer, _ := randomUser(t)
+	account := generateRandomAccount(customer.Username)
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "TierInCurrency",
+			user: admin,
+			body: gin.H{"tier": "standard", "currency_code": utils.NGN, "daily_amount": 5000, "daily_count": 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Eq(db.UpsertTransferLimitParams{
+					Tier:         sql.NullString{String: "standard", Valid: true},
+					CurrencyCode: sql.NullString{String: utils.NGN, Valid: true},
+					DailyAmount:  sql.NullInt64{Int64: 5000, Valid: true},
+					DailyCount:   sql.NullInt64{Int64: 10, Valid: true},
+				})).Times(1).Return(db.TransferLimit{
+					ID:           1,
+					Tier:         sql.NullString{String: "standard", Valid: true},
+					CurrencyCode: sql.NullString{String: utils.NGN, Valid: true},
+					DailyAmount:  sql.NullInt64{Int64: 5000, Valid: true},
+					DailyCount:   sql.NullInt64{Int64: 10, Valid: true},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data transferLimitResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Nil(t, body.Data.AccountID)
+				require.Nil(t, body.Data.MaxAmount)
+				require.Equal(t, int64(5000), *body.Data.DailyAmount)
+			},
+		},
+		{
+			name: "Account",
+			user: admin,
+			body: gin.H{"account_id": account.ID, "max_amount": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Eq(db.UpsertTransferLimitParams{
+					AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+					MaxAmount: sql.NullInt64{Int64: 100, Valid: true},
+				})).Times(1).Return(db.TransferLimit{ID: 2}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AccountAndTier",
+			user: admin,
+			body: gin.H{"account_id": account.ID, "tier": "standard", "max_amount": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoScope",
+			user: admin,
+			body: gin.H{"max_amount": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			user: customer,
+			body: gin.H{"currency_code": utils.USD, "max_amount": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(customer.Username)).Times(1).Return(customer, nil)
+				store.EXPECT().UpsertTransferLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/transfer-limits", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_GetAccountLimitsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountLimits(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.AccountLimits{
+					Limits: db.TransferLimits{DailyAmount: sql.NullInt64{Int64: 1000, Valid: true}},
+					Daily:  db.GetOutgoingTransferTotalsRow{Count: 2, Total: 300},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data accountLimitsResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.Equal(t, int64(1000), *body.Data.Limits.DailyAmount)
+				require.Nil(t, body.Data.Limits.MonthlyAmount)
+				require.Equal(t, int64(300), body.Data.Daily.Total)
+			},
+		},
+		{
+			name:     "NotOwner",
+			username: "someoneelse",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+				store.EXPECT().GetAccountLimits(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/limits", account.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/fraud"
 	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,7 @@ import (
 
 func newTestServer(t *testing.T, store db.Store) *Server {
 	config := utils.Config{
+		Environment:         utils.EnvDevelopment,
 		TokenSymmetricKey:   utils.RandomString(32),
 		AccessTokenDuration: time.Minute,
 	}
@@ -21,6 +23,9 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
+	// the fraud rules are tested on their own, tests that need screening set their own engine
+	server.fraudEngine = fraud.NewEngine()
+
 	return server
 }
 
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeAccount checks the authenticated user may do permission on account,
+// as its owner or a member, and that their token was given access to it
+func (server *Server) authorizeAccount(ctx *gin.Context, account db.Account, permission access.Permission) (access.Grant, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	grant, err := server.access.Grant(ctx, account, authPayload.Username)
+	if err != nil {
+		if errors.Is(err, access.ErrNoAccess) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return grant, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return grant, false
+	}
+
+	if !grant.Can(permission) {
+		err := fmt.Errorf("account %s can't %s this account", grant.Role, permission)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return grant, false
+	}
+
+	if !authPayload.CanAccessAccount(account.ID) {
+		err := errors.New("account was not shared with this application")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return grant, false
+	}
+
+	return grant, true
+}
+
+type inviteAccountMemberRequest struct {
+	Username   string `json:"username" binding:"required,alphanum"`
+	Role       string `json:"role" binding:"required,oneof=co_owner spender viewer"`
+	SpendLimit *int64 `json:"spend_limit" binding:"omitempty,gt=0"`
+}
+
+// inviteAccountMember invites a user to an account, they get access once they accept
+func (server *Server) inviteAccountMember(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	var request inviteAccountMemberRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if (request.Role == access.RoleSpender) != (request.SpendLimit != nil) {
+		err := errors.New("spend_limit is required for spenders and only for them")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionManage); !ok {
+		return
+	}
+
+	if request.Username == account.Owner {
+		err := errors.New("the owner is already a member of the account")
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, request.Username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s does not exist", request.Username)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  request.Username,
+	})
+	switch {
+	case err == nil:
+		err := fmt.Errorf("user %s is already a member of the account or invited to it", request.Username)
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	case !errors.Is(err, sql.ErrNoRows):
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID:  account.ID,
+		Username:   request.Username,
+		Role:       request.Role,
+		SpendLimit: nullInt64(request.SpendLimit),
+		InvitedBy:  authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("member invited successfully", member))
+}
+
+func (server *Server) listAccountMembers(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
+		return
+	}
+
+	members, err := server.store.ListAccountMembers(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("account members retrieved successfully", members))
+}
+
+// acceptAccountMember accepts the authenticated user's invitation to an account
+func (server *Server) acceptAccountMember(ctx *gin.Context) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.store.AcceptAccountMember(ctx, db.AcceptAccountMemberParams{
+		AccountID: request.ID,
+		Username:  authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("no pending invitation to account [%d]", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invitation accepted successfully", member))
+}
+
+func (server *Server) listAccountInvitations(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	invitations, err := server.store.ListAccountInvitations(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("invitations retrieved successfully", invitations))
+}
+
+type removeAccountMemberURI struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// removeAccountMember removes a member or withdraws an invitation. Members
+// can also remove themselves, everyone else needs to manage the account.
+func (server *Server) removeAccountMember(ctx *gin.Context) {
+	var uri removeAccountMemberURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, uri.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account with ID [%d] does not exist", uri.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if uri.Username != authPayload.Username {
+		if _, ok := server.authorizeAccount(ctx, account, access.PermissionManage); !ok {
+			return
+		}
+	}
+
+	arg := db.DeleteAccountMemberParams{AccountID: account.ID, Username: uri.Username}
+	if _, err := server.store.GetAccountMember(ctx, db.GetAccountMemberParams(arg)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s is not a member of the account", uri.Username)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if err := server.store.DeleteAccountMember(ctx, arg); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(fmt.Sprintf("removed %s from account (%d) successfully", uri.Username, account.ID), nil))
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MemberTransferAPI(t *testing.T) {
+	owner, _ := randomUser(t)
+	member, _ := randomUser(t)
+	payee, _ := randomUser(t)
+
+	account1 := generateRandomAccount(owner.Username)
+	account2 := generateRandomAccount(payee.Username)
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+
+	memberArg := db.GetAccountMemberParams{AccountID: account1.ID, Username: member.Username}
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "SpenderWithinLimit",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.AccountMember{
+					Role:       access.RoleSpender,
+					Status:     access.MemberActive,
+					SpendLimit: sql.NullInt64{Int64: 100, Valid: true},
+				}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "SpenderOverLimit",
+			amount: 101,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.AccountMember{
+					Role:       access.RoleSpender,
+					Status:     access.MemberActive,
+					SpendLimit: sql.NullInt64{Int64: 100, Valid: true},
+				}, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Viewer",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.AccountMember{
+					Role:   access.RoleViewer,
+					Status: access.MemberActive,
+				}, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InvitationNotAccepted",
+			amount: 10,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(memberArg)).Times(1).Return(db.AccountMember{
+					Role:   access.RoleCoOwner,
+					Status: access.MemberInvited,
+				}, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          tc.amount,
+				"currency_code":   utils.USD,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, member.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_InviteAccountMemberAPI(t *testing.T) {
+	owner, _ := randomUser(t)
+	viewer, _ := randomUser(t)
+	invitee, _ := randomUser(t)
+	account := generateRandomAccount(owner.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": access.RoleSpender, "spend_limit": 500},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(invitee.Username)).Times(1).Return(invitee, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{
+					AccountID: account.ID,
+					Username:  invitee.Username,
+				})).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Eq(db.CreateAccountMemberParams{
+					AccountID:  account.ID,
+					Username:   invitee.Username,
+					Role:       access.RoleSpender,
+					SpendLimit: sql.NullInt64{Int64: 500, Valid: true},
+					InvitedBy:  owner.Username,
+				})).Times(1).Return(db.AccountMember{Status: access.MemberInvited}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "SpenderWithoutLimit",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": access.RoleSpender},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "ViewerCantInvite",
+			username: viewer.Username,
+			body:     gin.H{"username": invitee.Username, "role": access.RoleViewer},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{
+					AccountID: account.ID,
+					Username:  viewer.Username,
+				})).Times(1).Return(db.AccountMember{Role: access.RoleViewer, Status: access.MemberActive}, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyMember",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": access.RoleViewer},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(invitee.Username)).Times(1).Return(invitee, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.AccountMember{Role: access.RoleViewer, Status: access.MemberActive}, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/members", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
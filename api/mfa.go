@@ -0,0 +1,404 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMFAIssuer        = "Simple Bank"
+	defaultMFATokenDuration = 5 * time.Minute
+	defaultMFAMaxAttempts   = 5
+	defaultMFALockout       = 15 * time.Minute
+	recoveryCodeCount       = 10
+)
+
+var (
+	errInvalidMFACode    = errors.New("invalid two-factor authentication code")
+	errMFALocked         = errors.New("too many invalid two-factor authentication codes")
+	errMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	errMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+)
+
+type enrollMFAResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+func (server *Server) enrollMFA(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMFAAlreadyEnabled))
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.UpdateUserTOTPSecret(ctx, db.UpdateUserTOTPSecretParams{
+		Username:   user.Username,
+		TotpSecret: secret,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := enrollMFAResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(server.mfaIssuer(), user.Username, secret),
+	}
+	ctx.JSON(http.StatusOK, successResponse("scan the provisioning uri and confirm with a code", response))
+}
+
+type confirmMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (server *Server) confirmMFA(ctx *gin.Context) {
+	var request confirmMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMFAAlreadyEnabled))
+		return
+	}
+
+	if user.TotpSecret == "" {
+		err := errors.New("two-factor enrollment has not been started")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	server.issueRecoveryCodes(ctx, user, request.Code, true)
+}
+
+func (server *Server) regenerateRecoveryCodes(ctx *gin.Context) {
+	var request confirmMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMFANotEnabled))
+		return
+	}
+
+	server.issueRecoveryCodes(ctx, user, request.Code, false)
+}
+
+func (server *Server) issueRecoveryCodes(ctx *gin.Context, user db.User, code string, enable bool) {
+	if err := server.verifyTOTPCode(ctx, user, code); err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	hashedCodes := make([]string, len(codes))
+	for i, code := range codes {
+		hashedCodes[i], err = hasher.HashPassword(code)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	_, err = server.store.ReplaceRecoveryCodesTrxn(ctx, db.ReplaceRecoveryCodesTxnParams{
+		Username:    user.Username,
+		HashedCodes: hashedCodes,
+		EnableTOTP:  enable,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("store these recovery codes somewhere safe", recoveryCodesResponse{
+		RecoveryCodes: codes,
+	}))
+}
+
+type disableMFARequest struct {
+	Password     string `json:"password" binding:"required,min=6"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func (server *Server) disableMFA(ctx *gin.Context) {
+	var request disableMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMFANotEnabled))
+		return
+	}
+
+	if err := hasher.CheckPassword(request.Password, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if err := server.verifySecondFactor(ctx, user, request.Code, request.RecoveryCode); err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	user, err = server.store.DisableMFATrxn(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("two-factor authentication disabled", newUserResponse(user)))
+}
+
+type loginMFARequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type loginMFARequiredResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+func (server *Server) loginUserMFA(ctx *gin.Context) {
+	var request loginMFARequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := server.tokenGenerator.VerifyToken(request.MFAToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if payload.Purpose != token.PurposeMFA {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(token.ErrInvalidToken))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.TotpEnabled {
+		ctx.JSON(http.StatusForbidden, errorResponse(errMFANotEnabled))
+		return
+	}
+
+	if err := server.verifySecondFactor(ctx, user, request.Code, request.RecoveryCode); err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return
+	}
+
+	server.respondWithAccessToken(ctx, user)
+}
+
+// requireTransferMFA demands a fresh TOTP code for transfers above the configured threshold
+func (server *Server) requireTransferMFA(ctx *gin.Context, username string, amount int64, code string) bool {
+	threshold := server.config.MFATransferThreshold
+	if threshold <= 0 || amount <= threshold {
+		return true
+	}
+
+	user, err := server.store.GetUser(ctx, username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if !user.TotpEnabled {
+		err := fmt.Errorf("two-factor authentication must be enabled for transfers above %d", threshold)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+
+	if code == "" {
+		err := fmt.Errorf("a fresh totp_code is required for transfers above %d", threshold)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return false
+	}
+
+	if err := server.verifyTOTPCode(ctx, user, code); err != nil {
+		ctx.JSON(mfaErrorStatus(err), errorResponse(err))
+		return false
+	}
+	return true
+}
+
+func (server *Server) verifySecondFactor(ctx *gin.Context, user db.User, code, recoveryCode string) error {
+	if code != "" {
+		return server.verifyTOTPCode(ctx, user, code)
+	}
+	return server.useRecoveryCode(ctx, user, recoveryCode)
+}
+
+// verifyTOTPCode validates a code and records its time step so it can't be replayed
+func (server *Server) verifyTOTPCode(ctx *gin.Context, user db.User, code string) error {
+	if err := checkMFALockout(user, time.Now()); err != nil {
+		return err
+	}
+
+	counter, ok := utils.ValidateTOTPCode(user.TotpSecret, code, time.Now())
+	if !ok {
+		return server.recordMFAFailure(ctx, user)
+	}
+
+	_, err := server.store.UpdateUserTOTPCounter(ctx, db.UpdateUserTOTPCounterParams{
+		Username: user.Username,
+		Counter:  counter,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return errInvalidMFACode
+	}
+	return err
+}
+
+func (server *Server) useRecoveryCode(ctx *gin.Context, user db.User, recoveryCode string) error {
+	if err := checkMFALockout(user, time.Now()); err != nil {
+		return err
+	}
+
+	codes, err := server.store.ListUnusedRecoveryCodes(ctx, user.Username)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		if hasher.CheckPassword(recoveryCode, code.HashedCode) != nil {
+			continue
+		}
+		_, err = server.store.MarkRecoveryCodeUsed(ctx, code.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errInvalidMFACode
+		}
+		return err
+	}
+	return server.recordMFAFailure(ctx, user)
+}
+
+// checkMFALockout refuses second factor codes while the user is locked out
+// after too many invalid ones
+func checkMFALockout(user db.User, now time.Time) error {
+	if user.TotpLockedUntil.Valid && now.Before(user.TotpLockedUntil.Time) {
+		return fmt.Errorf("%w, try again after %s", errMFALocked, user.TotpLockedUntil.Time.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// recordMFAFailure counts an invalid code towards the lockout and returns
+// errInvalidMFACode once it is counted
+func (server *Server) recordMFAFailure(ctx *gin.Context, user db.User) error {
+	_, err := server.store.RecordTOTPFailure(ctx, db.RecordTOTPFailureParams{
+		MaxAttempts: server.mfaMaxAttempts(),
+		LockedUntil: time.Now().Add(server.mfaLockoutDuration()),
+		Username:    user.Username,
+	})
+	if err != nil {
+		return err
+	}
+	return errInvalidMFACode
+}
+
+func (server *Server) mfaIssuer() string {
+	if server.config.MFAIssuer != "" {
+		return server.config.MFAIssuer
+	}
+	return defaultMFAIssuer
+}
+
+func (server *Server) mfaTokenDuration() time.Duration {
+	if server.config.MFATokenDuration > 0 {
+		return server.config.MFATokenDuration
+	}
+	return defaultMFATokenDuration
+}
+
+func (server *Server) mfaMaxAttempts() int32 {
+	if server.config.MFAMaxAttempts > 0 {
+		return server.config.MFAMaxAttempts
+	}
+	return defaultMFAMaxAttempts
+}
+
+func (server *Server) mfaLockoutDuration() time.Duration {
+	if server.config.MFALockoutDuration > 0 {
+		return server.config.MFALockoutDuration
+	}
+	return defaultMFALockout
+}
+
+func mfaErrorStatus(err error) int {
+	if errors.Is(err, errInvalidMFACode) {
+		return http.StatusUnauthorized
+	}
+	if errors.Is(err, errMFALocked) {
+		return http.StatusTooManyRequests
+	}
+	return http.StatusInternalServerError
+}
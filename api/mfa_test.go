@@ -0,0 +1,395 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomMFAUser(t *testing.T) (user db.User, password string) {
+	user, password = randomUser(t)
+
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	user.TotpSecret = secret
+	user.TotpEnabled = true
+	return
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := utils.GenerateTOTPCode(secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// wrongTOTPCode returns a code that isn't valid for the secret right now
+func wrongTOTPCode(t *testing.T, secret string) string {
+	for {
+		code := fmt.Sprintf("%06d", utils.RandomInt(0, 999999))
+		if _, ok := utils.ValidateTOTPCode(secret, code, time.Now()); !ok {
+			return code
+		}
+	}
+}
+
+func Test_EnrollMFAAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	enrolledUser, _ := randomMFAUser(t)
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			user: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserTOTPSecret(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateUserTOTPSecretParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NotEmpty(t, arg.TotpSecret)
+						user.TotpSecret = arg.TotpSecret
+						return user, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data enrollMFAResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.NotEmpty(t, body.Data.Secret)
+				require.Contains(t, body.Data.ProvisioningURI, "otpauth://totp/")
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			user: enrolledUser,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(enrolledUser.Username)).Times(1).Return(enrolledUser, nil)
+				store.EXPECT().UpdateUserTOTPSecret(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/mfa/enroll", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ConfirmMFAAPI(t *testing.T) {
+	user, _ := randomMFAUser(t)
+	user.TotpEnabled = false
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().ReplaceRecoveryCodesTrxn(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg db.ReplaceRecoveryCodesTxnParams) (db.User, error) {
+			require.True(t, arg.EnableTOTP)
+			require.Len(t, arg.HashedCodes, recoveryCodeCount)
+			return user, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"code": currentTOTPCode(t, user.TotpSecret)})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/mfa/confirm", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var body struct {
+		Data recoveryCodesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Len(t, body.Data.RecoveryCodes, recoveryCodeCount)
+}
+
+func Test_LoginMFAAPI(t *testing.T) {
+	user, password := randomMFAUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// first step only hands out an MFA token
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+
+	data, err := json.Marshal(gin.H{"username": user.Username, "password": password})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var stepOne loginMFARequiredResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &stepOne))
+	require.True(t, stepOne.MFARequired)
+
+	payload, err := server.tokenGenerator.VerifyToken(stepOne.MFAToken)
+	require.NoError(t, err)
+	require.Equal(t, token.PurposeMFA, payload.Purpose)
+
+	// the MFA token can't be used as an access token
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/accounts", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, authorizationBearerType+" "+stepOne.MFAToken)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// a wrong code is rejected and counts towards the lockout
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().RecordTOTPFailure(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+
+	data, err = json.Marshal(gin.H{"mfa_token": stepOne.MFAToken, "code": "000000"})
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// even a valid code is refused while the user is locked out
+	locked := user
+	locked.TotpLockedUntil = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(locked, nil)
+	store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(0)
+
+	data, err = json.Marshal(gin.H{"mfa_token": stepOne.MFAToken, "code": currentTOTPCode(t, user.TotpSecret)})
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	// second step exchanges the MFA token and a valid code for an access token
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+
+	data, err = json.Marshal(gin.H{"mfa_token": stepOne.MFAToken, "code": currentTOTPCode(t, user.TotpSecret)})
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	var stepTwo loginUserResponse
+	require.NoError(t, json.Unmarshal(body, &stepTwo))
+
+	payload, err = server.tokenGenerator.VerifyToken(stepTwo.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, token.PurposeAccess, payload.Purpose)
+	require.Equal(t, user.Username, payload.Username)
+}
+
+func Test_TransferMFAThreshold(t *testing.T) {
+	user, _ := randomMFAUser(t)
+	account1 := generateRandomAccount(user.Username)
+	account2 := generateRandomAccount(utils.RandomOwner())
+	account1.CurrencyCode = utils.USD
+	account2.CurrencyCode = utils.USD
+	account2.ID = account1.ID + 1
+
+	threshold := int64(1000)
+
+	testCases := []struct {
+		name          string
+		amount        int64
+		code          func() string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "BelowThreshold",
+			amount: threshold,
+			code:   func() string { return "" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingCode",
+			amount: threshold + 1,
+			code:   func() string { return "" },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "ReplayedCode",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, user.TotpSecret) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "WrongCode",
+			amount: threshold + 1,
+			code:   func() string { return wrongTOTPCode(t, user.TotpSecret) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().RecordTOTPFailure(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.RecordTOTPFailureParams) (db.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, int32(defaultMFAMaxAttempts), arg.MaxAttempts)
+						require.WithinDuration(t, time.Now().Add(defaultMFALockout), arg.LockedUntil, time.Second)
+						return user, nil
+					})
+				store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "LockedOut",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, user.TotpSecret) },
+			buildStubs: func(store *mockdb.MockStore) {
+				locked := user
+				locked.TotpLockedUntil = sql.NullTime{Time: time.Now().Add(time.Minute), Valid: true}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(locked, nil)
+				store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RecordTOTPFailure(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name:   "FreshCode",
+			amount: threshold + 1,
+			code:   func() string { return currentTOTPCode(t, user.TotpSecret) },
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserTOTPCounter(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.MFATransferThreshold = threshold
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          tc.amount,
+				"currency_code":   utils.USD,
+				"totp_code":       tc.code(),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			request.Header.Set("Content-Type", "application/json")
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
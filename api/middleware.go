@@ -1,22 +1,33 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationBearerType = "bearer"
+	authorizationAPIKeyType = "apikey"
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+var (
+	errInvalidAPIKey      = errors.New("api key is invalid")
+	errAPIKeyIPNotAllowed = errors.New("api key cannot be used from this IP")
+)
+
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
@@ -32,23 +43,143 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		var (
+			payload *token.Payload
+			err     error
+		)
+
 		authorizationType := strings.ToLower(fields[0])
-		if authorizationType != authorizationBearerType {
-			err := fmt.Errorf("unsupported authorization type %s", authorizationBearerType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		switch authorizationType {
+		case authorizationBearerType:
+			payload, err = verifyAccessToken(ctx, tokenMaker, store, fields[1])
+		case authorizationAPIKeyType:
+			payload, err = verifyAPIKey(ctx, store, fields[1])
+		default:
+			err = fmt.Errorf("unsupported authorization type %s", authorizationType)
+		}
+		if errors.Is(err, errAPIKeyIPNotAllowed) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
 			return
 		}
-
-		accessToken := fields[1]
-
-		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
 		ctx.Set(authorizationPayloadKey, payload)
+		setAuditActor(ctx, payload.Username)
 		ctx.Next()
 
 	}
 }
+
+// verifyAccessToken checks a bearer token. Tokens issued to OAuth2 clients
+// can be revoked before they expire so those are also checked against the store.
+func verifyAccessToken(ctx *gin.Context, tokenMaker token.Maker, store db.Store, accessToken string) (*token.Payload, error) {
+	payload, err := tokenMaker.VerifyToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Purpose != token.PurposeAccess {
+		return nil, errors.New("token is not an access token")
+	}
+
+	if payload.ClientID != "" {
+		_, err := store.GetRevokedAccessToken(ctx, payload.ID.String())
+		if err == nil {
+			return nil, errors.New("token has been revoked")
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+// verifyAPIKey authenticates a machine client and returns a payload equivalent
+// to an access token limited to the key's scopes
+func verifyAPIKey(ctx *gin.Context, store db.Store, key string) (*token.Payload, error) {
+	prefix, ok := utils.APIKeyPrefix(key)
+	if !ok {
+		return nil, errInvalidAPIKey
+	}
+
+	apiKey, err := store.GetApiKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if !utils.CheckAPIKey(key, apiKey.HashedKey) {
+		return nil, errInvalidAPIKey
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil, errors.New("api key has been revoked")
+	}
+
+	if apiKey.ExpiresAt.Valid && time.Now().After(apiKey.ExpiresAt.Time) {
+		return nil, errors.New("api key has expired")
+	}
+
+	if !utils.IPAllowed(ctx.ClientIP(), apiKey.AllowedIps) {
+		return nil, fmt.Errorf("%w: %s", errAPIKeyIPNotAllowed, ctx.ClientIP())
+	}
+
+	if err := store.TouchApiKey(ctx, apiKey.ID); err != nil {
+		return nil, err
+	}
+
+	return newAPIKeyPayload(apiKey)
+}
+
+func newAPIKeyPayload(apiKey db.ApiKey) (*token.Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	// keys without an expiry leave ExpiredAt unset, the key is checked on every request
+	payload := &token.Payload{
+		ID:        tokenID,
+		Username:  apiKey.Owner,
+		IssuedAt:  time.Now(),
+		Purpose:   token.PurposeAccess,
+		NotBefore: apiKey.CreatedAt,
+		Scopes:    apiKey.Scopes,
+	}
+	if apiKey.ExpiresAt.Valid {
+		payload.ExpiredAt = apiKey.ExpiresAt.Time
+	}
+	return payload, nil
+}
+
+// requireScope rejects tokens whose scopes don't grant the given scope
+func requireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !payload.HasScope(scope) {
+			err := fmt.Errorf("token is missing the %s scope", scope)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// requireUserSession rejects scoped credentials such as API keys so they
+// can't be used to manage the user's own credentials
+func requireUserSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if len(payload.Scopes) > 0 {
+			err := errors.New("this action requires a full user session")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,26 @@ func addAuthorization(
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+// addOAuthAuthorization adds an access token like the ones issued to OAuth2
+// clients, limited to the granted accounts
+func addOAuthAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	username string,
+	accountIDs []int64,
+) {
+	payload, err := token.NewPayload(username, time.Minute)
+	require.NoError(t, err)
+	payload.ClientID = utils.RandomString(16)
+	payload.Scopes = []string{token.ScopeAccountsRead, token.ScopeTransfersCreate}
+	payload.AccountIDs = accountIDs
+
+	accessToken, err := tokenMaker.CreateTokenFromPayload(payload)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationBearerType, accessToken))
+}
+
 func Test_AuthMiddleware(t *testing.T) {
 	testcases := []struct {
 		name          string
@@ -87,7 +108,7 @@ func Test_AuthMiddleware(t *testing.T) {
 			authPath := "/auth"
 			server.router.GET(
 				authPath,
-				authMiddleware(server.tokenGenerator),
+				authMiddleware(server.tokenGenerator, server.store),
 				func(ctx *gin.Context) {
 					ctx.JSON(http.StatusOK, gin.H{})
 				},
@@ -103,3 +124,104 @@ func Test_AuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func Test_RequireScope(t *testing.T) {
+	testcases := []struct {
+		name          string
+		scopes        []string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "FullSession",
+			scopes: nil,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "GrantedScope",
+			scopes: []string{token.ScopeAccountsRead, token.ScopeTransfersCreate},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingScope",
+			scopes: []string{token.ScopeAccountsRead},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testcases {
+		tc := testcases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+
+			scopedPath := "/scoped"
+			server.router.GET(
+				scopedPath,
+				authMiddleware(server.tokenGenerator, server.store),
+				requireScope(token.ScopeTransfersCreate),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			payload, err := token.NewPayload("user", time.Minute)
+			require.NoError(t, err)
+			payload.Scopes = tc.scopes
+
+			accessToken, err := server.tokenGenerator.CreateTokenFromPayload(payload)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, scopedPath, nil)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationBearerType, accessToken))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func Test_AudienceIsolation(t *testing.T) {
+	secretKey := utils.RandomString(32)
+
+	customerServer, err := NewServer(utils.Config{
+		Environment:       utils.EnvDevelopment,
+		TokenType:         token.TypeJWT,
+		TokenSymmetricKey: secretKey,
+		TokenIssuer:       "simple-bank",
+		TokenAudience:     "customer-api",
+	}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &token.JWTMarker{}, customerServer.tokenGenerator)
+
+	adminMaker, err := token.NewJWTMaker(secretKey, token.WithIssuer("simple-bank"), token.WithAudience("admin-console"))
+	require.NoError(t, err)
+
+	authPath := "/auth"
+	customerServer.router.GET(authPath, authMiddleware(customerServer.tokenGenerator, customerServer.store), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, adminMaker, authorizationBearerType, "admin", time.Minute)
+	customerServer.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, customerServer.tokenGenerator, authorizationBearerType, "user", time.Minute)
+	customerServer.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,655 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultOAuthCodeDuration         = 10 * time.Minute
+	defaultOAuthRefreshTokenDuration = 30 * 24 * time.Hour
+	maxConsentAccounts               = 100
+)
+
+// error codes defined by RFC 6749
+const (
+	oauthErrInvalidRequest   = "invalid_request"
+	oauthErrInvalidClient    = "invalid_client"
+	oauthErrInvalidGrant     = "invalid_grant"
+	oauthErrUnsupportedGrant = "unsupported_grant_type"
+	oauthErrAccessDenied     = "access_denied"
+	oauthErrServerError      = "server_error"
+)
+
+const (
+	oauthGrantAuthorizationCode = "authorization_code"
+	oauthGrantRefreshToken      = "refresh_token"
+)
+
+var (
+	errInvalidClient = errors.New("client authentication failed")
+	errInvalidGrant  = errors.New("authorization grant is invalid, expired or revoked")
+)
+
+// oauthScopes are the scopes third-party apps may request
+var oauthScopes = []string{token.ScopeAccountsRead, token.ScopeTransfersCreate}
+
+type createOAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=64"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,dive,url"`
+	Scopes       []string `json:"scopes" binding:"required,min=1,dive,oneof=accounts:read transfers:create"`
+	// Confidential clients get a secret, public clients (mobile and browser apps) rely on PKCE alone
+	Confidential bool `json:"confidential"`
+}
+
+type oauthClientResponse struct {
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type createOAuthClientResponse struct {
+	ClientSecret string              `json:"client_secret,omitempty"`
+	Client       oauthClientResponse `json:"client"`
+}
+
+func newOAuthClientResponse(client db.OauthClient) oauthClientResponse {
+	return oauthClientResponse{
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectUris,
+		Scopes:       client.Scopes,
+		Confidential: client.HashedSecret != "",
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+func (server *Server) createOAuthClient(ctx *gin.Context) {
+	var request createOAuthClientRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var secret, hashedSecret string
+	if request.Confidential {
+		var err error
+		secret, err = utils.GenerateOpaqueToken()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		hashedSecret = utils.HashOpaqueToken(secret)
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	client, err := server.store.CreateOAuthClient(ctx, db.CreateOAuthClientParams{
+		ClientID:     uuid.NewString(),
+		Owner:        authPayload.Username,
+		Name:         request.Name,
+		HashedSecret: hashedSecret,
+		RedirectUris: request.RedirectURIs,
+		Scopes:       request.Scopes,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("oauth client registered, the secret will not be shown again", createOAuthClientResponse{
+		ClientSecret: secret,
+		Client:       newOAuthClientResponse(client),
+	}))
+}
+
+type authorizeRequest struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required,eq=code"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri" binding:"required"`
+	Scope               string `form:"scope" json:"scope" binding:"required"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge" binding:"required,len=43"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method" binding:"required,eq=S256"`
+}
+
+type consentClient struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+}
+
+type consentResponse struct {
+	Client   consentClient `json:"client"`
+	Scopes   []string      `json:"scopes"`
+	Accounts []db.Account  `json:"accounts"`
+}
+
+// getConsent validates an authorization request and returns what the consent
+// screen shows the user: the requesting app, the scopes and the accounts to pick from
+func (server *Server) getConsent(ctx *gin.Context) {
+	var request authorizeRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	client, scopes, ok := server.validateAuthorizeRequest(ctx, request)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts, err := server.store.ListAccounts(ctx, db.ListAccountsParams{
+		Username:   authPayload.Username,
+		PageLimit:  maxConsentAccounts,
+		PageOffset: 0,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("authorization requested", consentResponse{
+		Client:   consentClient{ClientID: client.ClientID, Name: client.Name},
+		Scopes:   scopes,
+		Accounts: accounts,
+	}))
+}
+
+type consentRequest struct {
+	authorizeRequest
+	Approve    bool    `json:"approve"`
+	AccountIDs []int64 `json:"account_ids" binding:"omitempty,dive,min=1"`
+}
+
+type authorizeResponse struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// submitConsent records the user's decision and returns the redirect back to the
+// client carrying either an authorization code or an access_denied error
+func (server *Server) submitConsent(ctx *gin.Context) {
+	var request consentRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	client, scopes, ok := server.validateAuthorizeRequest(ctx, request.authorizeRequest)
+	if !ok {
+		return
+	}
+
+	params := url.Values{}
+	if request.State != "" {
+		params.Set("state", request.State)
+	}
+
+	if !request.Approve {
+		params.Set("error", oauthErrAccessDenied)
+		ctx.JSON(http.StatusOK, successResponse("authorization denied", authorizeResponse{
+			RedirectURI: appendQuery(request.RedirectURI, params),
+		}))
+		return
+	}
+
+	if len(request.AccountIDs) == 0 {
+		err := errors.New("at least one account must be shared with the app")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountID := range request.AccountIDs {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err := fmt.Errorf("account with ID [%d] does not exist", accountID)
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if account.Owner != authPayload.Username {
+			err := errors.New("account doesn't belong to the authenticated user")
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+	}
+
+	code, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = server.store.CreateAuthorizationCode(ctx, db.CreateAuthorizationCodeParams{
+		CodeHash:      utils.HashOpaqueToken(code),
+		ClientID:      client.ClientID,
+		Username:      authPayload.Username,
+		RedirectUri:   request.RedirectURI,
+		Scopes:        scopes,
+		AccountIds:    request.AccountIDs,
+		CodeChallenge: request.CodeChallenge,
+		ExpiresAt:     time.Now().Add(server.oauthCodeDuration()),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	params.Set("code", code)
+	ctx.JSON(http.StatusOK, successResponse("authorization granted", authorizeResponse{
+		RedirectURI: appendQuery(request.RedirectURI, params),
+	}))
+}
+
+func (server *Server) validateAuthorizeRequest(ctx *gin.Context, request authorizeRequest) (db.OauthClient, []string, bool) {
+	client, err := server.store.GetOAuthClient(ctx, request.ClientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidClient))
+			return client, nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return client, nil, false
+	}
+
+	if !containsString(client.RedirectUris, request.RedirectURI) {
+		err := errors.New("redirect_uri is not registered for this client")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return client, nil, false
+	}
+
+	scopes := strings.Fields(request.Scope)
+	for _, scope := range scopes {
+		if !containsString(client.Scopes, scope) || !containsString(oauthScopes, scope) {
+			err := fmt.Errorf("scope %s cannot be granted to this client", scope)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return client, nil, false
+		}
+	}
+	if len(scopes) == 0 {
+		err := errors.New("at least one scope must be requested")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return client, nil, false
+	}
+
+	return client, scopes, true
+}
+
+type oauthTokenRequest struct {
+	GrantType    string `form:"grant_type" binding:"required"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// issueOAuthToken is the RFC 6749 token endpoint. It exchanges authorization
+// codes and rotates refresh tokens.
+func (server *Server) issueOAuthToken(ctx *gin.Context) {
+	var request oauthTokenRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidRequest, err))
+		return
+	}
+
+	client, err := server.authenticateOAuthClient(ctx, request.ClientID, request.ClientSecret)
+	if err != nil {
+		ctx.JSON(oauthErrorStatus(err), oauthErrorResponse(oauthErrorCode(err), err))
+		return
+	}
+
+	switch request.GrantType {
+	case oauthGrantAuthorizationCode:
+		server.exchangeAuthorizationCode(ctx, client, request)
+	case oauthGrantRefreshToken:
+		server.exchangeRefreshToken(ctx, client, request)
+	default:
+		err := fmt.Errorf("grant type %s is not supported", request.GrantType)
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrUnsupportedGrant, err))
+	}
+}
+
+func (server *Server) exchangeAuthorizationCode(ctx *gin.Context, client db.OauthClient, request oauthTokenRequest) {
+	if request.Code == "" || request.CodeVerifier == "" {
+		err := errors.New("code and code_verifier are required")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidRequest, err))
+		return
+	}
+
+	// the code is burned before it is checked so it can never be tried twice
+	code, err := server.store.UseAuthorizationCode(ctx, utils.HashOpaqueToken(request.Code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidGrant, errInvalidGrant))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	if code.ClientID != client.ClientID ||
+		code.RedirectUri != request.RedirectURI ||
+		time.Now().After(code.ExpiresAt) ||
+		!utils.VerifyPKCE(request.CodeVerifier, code.CodeChallenge) {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidGrant, errInvalidGrant))
+		return
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	grant, err := server.store.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		TokenHash:  utils.HashOpaqueToken(refreshToken),
+		ClientID:   code.ClientID,
+		Username:   code.Username,
+		Scopes:     code.Scopes,
+		AccountIds: code.AccountIds,
+		ExpiresAt:  time.Now().Add(server.oauthRefreshTokenDuration()),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	server.respondWithOAuthTokens(ctx, grant, refreshToken)
+}
+
+func (server *Server) exchangeRefreshToken(ctx *gin.Context, client db.OauthClient, request oauthTokenRequest) {
+	if request.RefreshToken == "" {
+		err := errors.New("refresh_token is required")
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidRequest, err))
+		return
+	}
+
+	tokenHash := utils.HashOpaqueToken(request.RefreshToken)
+	current, err := server.store.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidGrant, errInvalidGrant))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	if current.ClientID != client.ClientID || current.RevokedAt.Valid || time.Now().After(current.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidGrant, errInvalidGrant))
+		return
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	grant, err := server.store.RotateRefreshTokenTrxn(ctx, db.RotateRefreshTokenTxnParams{
+		TokenHash:    tokenHash,
+		NewTokenHash: utils.HashOpaqueToken(refreshToken),
+		ExpiresAt:    time.Now().Add(server.oauthRefreshTokenDuration()),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidGrant, errInvalidGrant))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	server.respondWithOAuthTokens(ctx, grant, refreshToken)
+}
+
+func (server *Server) respondWithOAuthTokens(ctx *gin.Context, grant db.OauthRefreshToken, refreshToken string) {
+	payload, err := token.NewPayload(grant.Username, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+	payload.Scopes = grant.Scopes
+	payload.ClientID = grant.ClientID
+	payload.AccountIDs = grant.AccountIds
+
+	accessToken, err := server.tokenGenerator.CreateTokenFromPayload(payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, oauthTokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(server.config.AccessTokenDuration.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        strings.Join(grant.Scopes, " "),
+	})
+}
+
+type oauthTokenLookupRequest struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+type introspectionResponse struct {
+	Active     bool    `json:"active"`
+	Scope      string  `json:"scope,omitempty"`
+	ClientID   string  `json:"client_id,omitempty"`
+	Username   string  `json:"username,omitempty"`
+	TokenType  string  `json:"token_type,omitempty"`
+	ExpiresAt  int64   `json:"exp,omitempty"`
+	IssuedAt   int64   `json:"iat,omitempty"`
+	AccountIDs []int64 `json:"account_ids,omitempty"`
+}
+
+// introspectOAuthToken implements RFC 7662. Clients may only introspect their own tokens.
+func (server *Server) introspectOAuthToken(ctx *gin.Context) {
+	var request oauthTokenLookupRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidRequest, err))
+		return
+	}
+
+	client, err := server.authenticateOAuthClient(ctx, request.ClientID, request.ClientSecret)
+	if err != nil {
+		ctx.JSON(oauthErrorStatus(err), oauthErrorResponse(oauthErrorCode(err), err))
+		return
+	}
+
+	if payload, err := server.verifyOAuthAccessToken(ctx, client, request.Token); err == nil {
+		ctx.JSON(http.StatusOK, introspectionResponse{
+			Active:     true,
+			Scope:      strings.Join(payload.Scopes, " "),
+			ClientID:   payload.ClientID,
+			Username:   payload.Username,
+			TokenType:  "access_token",
+			ExpiresAt:  payload.ExpiredAt.Unix(),
+			IssuedAt:   payload.IssuedAt.Unix(),
+			AccountIDs: payload.AccountIDs,
+		})
+		return
+	}
+
+	grant, err := server.store.GetRefreshToken(ctx, utils.HashOpaqueToken(request.Token))
+	if err != nil || grant.ClientID != client.ClientID || grant.RevokedAt.Valid || time.Now().After(grant.ExpiresAt) {
+		ctx.JSON(http.StatusOK, introspectionResponse{Active: false})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, introspectionResponse{
+		Active:     true,
+		Scope:      strings.Join(grant.Scopes, " "),
+		ClientID:   grant.ClientID,
+		Username:   grant.Username,
+		TokenType:  "refresh_token",
+		ExpiresAt:  grant.ExpiresAt.Unix(),
+		IssuedAt:   grant.CreatedAt.Unix(),
+		AccountIDs: grant.AccountIds,
+	})
+}
+
+// revokeOAuthToken implements RFC 7009. Unknown tokens are not an error so
+// clients can't probe for valid tokens.
+func (server *Server) revokeOAuthToken(ctx *gin.Context) {
+	var request oauthTokenLookupRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, oauthErrorResponse(oauthErrInvalidRequest, err))
+		return
+	}
+
+	client, err := server.authenticateOAuthClient(ctx, request.ClientID, request.ClientSecret)
+	if err != nil {
+		ctx.JSON(oauthErrorStatus(err), oauthErrorResponse(oauthErrorCode(err), err))
+		return
+	}
+
+	if payload, err := server.verifyOAuthAccessToken(ctx, client, request.Token); err == nil {
+		err = server.store.RevokeAccessToken(ctx, db.RevokeAccessTokenParams{
+			TokenID:   payload.ID.String(),
+			ExpiresAt: payload.ExpiredAt,
+		})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+			return
+		}
+		ctx.Status(http.StatusOK)
+		return
+	}
+
+	tokenHash := utils.HashOpaqueToken(request.Token)
+	grant, err := server.store.GetRefreshToken(ctx, tokenHash)
+	if err == nil && grant.ClientID == client.ClientID {
+		_, err = server.store.RevokeRefreshToken(ctx, tokenHash)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, oauthErrorResponse(oauthErrServerError, err))
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+// authenticateOAuthClient accepts credentials from HTTP basic auth or the form body.
+// Public clients only identify themselves, confidential clients must present their secret.
+func (server *Server) authenticateOAuthClient(ctx *gin.Context, clientID, clientSecret string) (db.OauthClient, error) {
+	if username, password, ok := ctx.Request.BasicAuth(); ok {
+		clientID, clientSecret = username, password
+	}
+
+	if clientID == "" {
+		return db.OauthClient{}, errInvalidClient
+	}
+
+	client, err := server.store.GetOAuthClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return client, errInvalidClient
+		}
+		return client, err
+	}
+
+	if client.HashedSecret == "" && clientSecret != "" {
+		return client, errInvalidClient
+	}
+
+	if client.HashedSecret != "" && !utils.CheckOpaqueToken(clientSecret, client.HashedSecret) {
+		return client, errInvalidClient
+	}
+
+	setAuditActor(ctx, "oauth_client:"+client.ClientID)
+	return client, nil
+}
+
+func (server *Server) verifyOAuthAccessToken(ctx *gin.Context, client db.OauthClient, accessToken string) (*token.Payload, error) {
+	payload, err := verifyAccessToken(ctx, server.tokenGenerator, server.store, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if payload.ClientID != client.ClientID {
+		return nil, token.ErrInvalidToken
+	}
+	return payload, nil
+}
+
+func (server *Server) oauthCodeDuration() time.Duration {
+	if server.config.OAuthCodeDuration > 0 {
+		return server.config.OAuthCodeDuration
+	}
+	return defaultOAuthCodeDuration
+}
+
+func (server *Server) oauthRefreshTokenDuration() time.Duration {
+	if server.config.RefreshTokenDuration > 0 {
+		return server.config.RefreshTokenDuration
+	}
+	return defaultOAuthRefreshTokenDuration
+}
+
+func oauthErrorResponse(code string, err error) gin.H {
+	return gin.H{
+		"error":             code,
+		"error_description": err.Error(),
+	}
+}
+
+func oauthErrorCode(err error) string {
+	if errors.Is(err, errInvalidClient) {
+		return oauthErrInvalidClient
+	}
+	return oauthErrServerError
+}
+
+func oauthErrorStatus(err error) int {
+	if errors.Is(err, errInvalidClient) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+func appendQuery(rawURL string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + params.Encode()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
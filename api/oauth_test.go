@@ -0,0 +1,393 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomOAuthClient(t *testing.T, owner string) (client db.OauthClient, secret string) {
+	secret, err := utils.GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	client = db.OauthClient{
+		ClientID:     utils.RandomString(16),
+		Owner:        owner,
+		Name:         utils.RandomString(8),
+		HashedSecret: utils.HashOpaqueToken(secret),
+		RedirectUris: []string{"https://partner.example.com/callback"},
+		Scopes:       []string{token.ScopeAccountsRead, token.ScopeTransfersCreate},
+		CreatedAt:    time.Now(),
+	}
+	return
+}
+
+func postForm(t *testing.T, server *Server, path string, form url.Values) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_CreateOAuthClientAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Confidential",
+			body: gin.H{
+				"name":          "budgeting app",
+				"redirect_uris": []string{"https://partner.example.com/callback"},
+				"scopes":        []string{token.ScopeAccountsRead},
+				"confidential":  true,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOAuthClient(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateOAuthClientParams) (db.OauthClient, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.NotEmpty(t, arg.ClientID)
+						require.NotEmpty(t, arg.HashedSecret)
+						return db.OauthClient{
+							ClientID:     arg.ClientID,
+							Owner:        arg.Owner,
+							Name:         arg.Name,
+							HashedSecret: arg.HashedSecret,
+							RedirectUris: arg.RedirectUris,
+							Scopes:       arg.Scopes,
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data createOAuthClientResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.NotEmpty(t, body.Data.ClientSecret)
+				require.True(t, body.Data.Client.Confidential)
+			},
+		},
+		{
+			name: "Public",
+			body: gin.H{
+				"name":          "mobile app",
+				"redirect_uris": []string{"https://partner.example.com/callback"},
+				"scopes":        []string{token.ScopeAccountsRead},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOAuthClient(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateOAuthClientParams) (db.OauthClient, error) {
+						require.Empty(t, arg.HashedSecret)
+						return db.OauthClient{ClientID: arg.ClientID, RedirectUris: arg.RedirectUris, Scopes: arg.Scopes}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "client_secret")
+			},
+		},
+		{
+			name: "ScopeNotDelegable",
+			body: gin.H{
+				"name":          "budgeting app",
+				"redirect_uris": []string{"https://partner.example.com/callback"},
+				"scopes":        []string{token.ScopeAccountsWrite},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOAuthClient(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/oauth/clients", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_OAuthAuthorizationCodeFlow(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+	otherAccount := generateRandomAccount(user.Username)
+	otherAccount.ID = account.ID + 1
+	client, secret := randomOAuthClient(t, utils.RandomOwner())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	verifier, err := utils.GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	authorize := gin.H{
+		"response_type":         "code",
+		"client_id":             client.ClientID,
+		"redirect_uri":          client.RedirectUris[0],
+		"scope":                 token.ScopeAccountsRead,
+		"state":                 "xyz",
+		"code_challenge":        utils.PKCEChallenge(verifier),
+		"code_challenge_method": "S256",
+		"approve":               true,
+		"account_ids":           []int64{account.ID},
+	}
+
+	// the user consents to sharing a single account
+	var storedCode db.OauthAuthorizationCode
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().CreateAuthorizationCode(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateAuthorizationCodeParams) (db.OauthAuthorizationCode, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, []int64{account.ID}, arg.AccountIds)
+			storedCode = db.OauthAuthorizationCode{
+				CodeHash:      arg.CodeHash,
+				ClientID:      arg.ClientID,
+				Username:      arg.Username,
+				RedirectUri:   arg.RedirectUri,
+				Scopes:        arg.Scopes,
+				AccountIds:    arg.AccountIds,
+				CodeChallenge: arg.CodeChallenge,
+				ExpiresAt:     arg.ExpiresAt,
+			}
+			return storedCode, nil
+		})
+
+	data, err := json.Marshal(authorize)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/oauth/authorize", bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var consent struct {
+		Data authorizeResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &consent))
+
+	redirect, err := url.Parse(consent.Data.RedirectURI)
+	require.NoError(t, err)
+	require.Equal(t, "xyz", redirect.Query().Get("state"))
+	code := redirect.Query().Get("code")
+	require.NotEmpty(t, code)
+
+	// a wrong verifier burns the code without issuing tokens
+	wrongVerifier, err := utils.GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().UseAuthorizationCode(gomock.Any(), gomock.Eq(utils.HashOpaqueToken(code))).Times(1).Return(storedCode, nil)
+	store.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+	recorder = postForm(t, server, "/oauth/token", url.Values{
+		"grant_type":    {oauthGrantAuthorizationCode},
+		"code":          {code},
+		"redirect_uri":  {client.RedirectUris[0]},
+		"code_verifier": {wrongVerifier},
+		"client_id":     {client.ClientID},
+		"client_secret": {secret},
+	})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), oauthErrInvalidGrant)
+
+	// the right verifier exchanges the code for tokens
+	var grant db.OauthRefreshToken
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().UseAuthorizationCode(gomock.Any(), gomock.Eq(utils.HashOpaqueToken(code))).Times(1).Return(storedCode, nil)
+	store.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateRefreshTokenParams) (db.OauthRefreshToken, error) {
+			grant = db.OauthRefreshToken{
+				TokenHash:  arg.TokenHash,
+				ClientID:   arg.ClientID,
+				Username:   arg.Username,
+				Scopes:     arg.Scopes,
+				AccountIds: arg.AccountIds,
+				ExpiresAt:  arg.ExpiresAt,
+			}
+			return grant, nil
+		})
+
+	recorder = postForm(t, server, "/oauth/token", url.Values{
+		"grant_type":    {oauthGrantAuthorizationCode},
+		"code":          {code},
+		"redirect_uri":  {client.RedirectUris[0]},
+		"code_verifier": {verifier},
+		"client_id":     {client.ClientID},
+		"client_secret": {secret},
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var tokens oauthTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &tokens))
+	require.Equal(t, token.ScopeAccountsRead, tokens.Scope)
+	require.NotEmpty(t, tokens.RefreshToken)
+
+	payload, err := server.tokenGenerator.VerifyToken(tokens.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, client.ClientID, payload.ClientID)
+	require.Equal(t, []int64{account.ID}, payload.AccountIDs)
+
+	// the access token only reaches the shared account
+	getAccount := func(accountID int64) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d", accountID), nil)
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeaderKey, authorizationBearerType+" "+tokens.AccessToken)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Eq(payload.ID.String())).Times(2).
+		Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+
+	require.Equal(t, http.StatusOK, getAccount(account.ID).Code)
+	require.Equal(t, http.StatusForbidden, getAccount(otherAccount.ID).Code)
+
+	// the access token can't manage the user's credentials
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodPost, "/api-keys", nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, authorizationBearerType+" "+tokens.AccessToken)
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	// the refresh token is rotated
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().GetRefreshToken(gomock.Any(), gomock.Eq(utils.HashOpaqueToken(tokens.RefreshToken))).Times(1).Return(grant, nil)
+	store.EXPECT().RotateRefreshTokenTrxn(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ interface{}, arg db.RotateRefreshTokenTxnParams) (db.OauthRefreshToken, error) {
+			require.Equal(t, grant.TokenHash, arg.TokenHash)
+			require.NotEqual(t, grant.TokenHash, arg.NewTokenHash)
+			rotated := grant
+			rotated.TokenHash = arg.NewTokenHash
+			return rotated, nil
+		})
+
+	recorder = postForm(t, server, "/oauth/token", url.Values{
+		"grant_type":    {oauthGrantRefreshToken},
+		"refresh_token": {tokens.RefreshToken},
+		"client_id":     {client.ClientID},
+		"client_secret": {secret},
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var refreshed oauthTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &refreshed))
+	require.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+
+	// introspection reports the access token as active
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Eq(payload.ID.String())).Times(1).
+		Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+
+	recorder = postForm(t, server, "/oauth/introspect", url.Values{
+		"token":         {tokens.AccessToken},
+		"client_id":     {client.ClientID},
+		"client_secret": {secret},
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var introspection introspectionResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &introspection))
+	require.True(t, introspection.Active)
+	require.Equal(t, user.Username, introspection.Username)
+	require.Equal(t, "access_token", introspection.TokenType)
+
+	// revoking the access token locks it out of the API
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Eq(payload.ID.String())).Times(1).
+		Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+	store.EXPECT().RevokeAccessToken(gomock.Any(), gomock.Eq(db.RevokeAccessTokenParams{
+		TokenID:   payload.ID.String(),
+		ExpiresAt: payload.ExpiredAt,
+	})).Times(1).Return(nil)
+
+	recorder = postForm(t, server, "/oauth/revoke", url.Values{
+		"token":         {tokens.AccessToken},
+		"client_id":     {client.ClientID},
+		"client_secret": {secret},
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Eq(payload.ID.String())).Times(1).
+		Return(db.OauthRevokedAccessToken{TokenID: payload.ID.String()}, nil)
+	require.Equal(t, http.StatusUnauthorized, getAccount(account.ID).Code)
+}
+
+func Test_OAuthClientAuthentication(t *testing.T) {
+	client, _ := randomOAuthClient(t, utils.RandomOwner())
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	store.EXPECT().GetOAuthClient(gomock.Any(), gomock.Eq(client.ClientID)).Times(1).Return(client, nil)
+	store.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Times(0)
+
+	recorder := postForm(t, server, "/oauth/token", url.Values{
+		"grant_type":    {oauthGrantRefreshToken},
+		"refresh_token": {"token"},
+		"client_id":     {client.ClientID},
+		"client_secret": {"wrong"},
+	})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Contains(t, recorder.Body.String(), oauthErrInvalidClient)
+}
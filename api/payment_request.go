@@ -0,0 +1,306 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultPaymentRequestExpiry is how long a payment request stays payable when
+// the requester doesn't choose
+const defaultPaymentRequestExpiry = 7 * 24 * time.Hour
+
+// The sides of a payment request, each can only act on its own side
+const (
+	paymentRequestPayer     = "payer"
+	paymentRequestRequester = "requester"
+)
+
+type createPaymentRequestRequest struct {
+	Payer          string `json:"payer" binding:"required,alphanum"`
+	Amount         int64  `json:"amount" binding:"required,gt=0"`
+	CurrencyCode   string `json:"currency_code" binding:"required,currency"`
+	ToAccountID    int64  `json:"to_account_id" binding:"omitempty,min=1"`
+	Note           string `json:"note" binding:"max=140"`
+	ExpiresInHours int64  `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`
+}
+
+// createPaymentRequest asks another user for money. It is paid into the
+// requester's primary account in the currency unless they name one.
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var request createPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if request.Payer == authPayload.Username {
+		err := errors.New("you can't request money from yourself")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, err := server.store.GetUser(ctx, request.Payer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("user %s does not exist", request.Payer)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	toAccount, ok := server.receivingAccount(ctx, request.ToAccountID, request.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	expiry := defaultPaymentRequestExpiry
+	if request.ExpiresInHours > 0 {
+		expiry = time.Duration(request.ExpiresInHours) * time.Hour
+	}
+
+	paymentRequest, err := server.store.CreatePaymentRequestTrxn(ctx, db.CreatePaymentRequestParams{
+		Requester:    authPayload.Username,
+		Payer:        request.Payer,
+		ToAccountID:  toAccount.ID,
+		Amount:       request.Amount,
+		CurrencyCode: request.CurrencyCode,
+		Note:         request.Note,
+		ExpiresAt:    time.Now().Add(expiry),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("payment request created successfully", paymentRequest))
+}
+
+// receivingAccount loads the account a payment request or invoice is paid into,
+// the named one if the user may move its money or their primary account otherwise
+func (server *Server) receivingAccount(ctx *gin.Context, accountID int64, currencyCode string) (db.Account, bool) {
+	if accountID != 0 {
+		account, ok := server.transferAccount(ctx, accountID, "", currencyCode)
+		if !ok {
+			return account, false
+		}
+		_, ok = server.authorizeAccount(ctx, account, access.PermissionTransfer)
+		return account, ok
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetPrimaryAccount(ctx, db.GetPrimaryAccountParams{
+		Owner:        authPayload.Username,
+		CurrencyCode: currencyCode,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("you have no active %s account to be paid into", currencyCode)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return account, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return account, false
+	}
+	return account, true
+}
+
+type listPaymentRequestsRequest struct {
+	Direction string `form:"direction" binding:"required,oneof=incoming outgoing"`
+	PageID    int32  `form:"page_id" binding:"required,min=1"`
+	PageSize  int32  `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+// listPaymentRequests lists the requests the user was sent, incoming, or sent
+// to others, outgoing, newest first
+func (server *Server) listPaymentRequests(ctx *gin.Context) {
+	var request listPaymentRequestsRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	var (
+		requests []db.PaymentRequest
+		err      error
+	)
+	if request.Direction == "incoming" {
+		requests, err = server.store.ListIncomingPaymentRequests(ctx, db.ListIncomingPaymentRequestsParams{
+			Payer:  authPayload.Username,
+			Limit:  request.PageSize,
+			Offset: (request.PageID - 1) * request.PageSize,
+		})
+	} else {
+		requests, err = server.store.ListOutgoingPaymentRequests(ctx, db.ListOutgoingPaymentRequestsParams{
+			Requester: authPayload.Username,
+			Limit:     request.PageSize,
+			Offset:    (request.PageID - 1) * request.PageSize,
+		})
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if authPayload.ClientID != "" {
+		shared := requests[:0]
+		for _, paymentRequest := range requests {
+			if canSeePaymentRequest(authPayload, paymentRequest) {
+				shared = append(shared, paymentRequest)
+			}
+		}
+		requests = shared
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("payment requests retrieved successfully", requests))
+}
+
+// canSeePaymentRequest reports whether the token may see the request. The
+// requester's side belongs to the account it is paid into. The payer's side
+// only belongs to an account once paid, from one the token may not be granted.
+func canSeePaymentRequest(payload *token.Payload, request db.PaymentRequest) bool {
+	if request.Requester == payload.Username {
+		return payload.CanAccessAccount(request.ToAccountID)
+	}
+	return payload.ClientID == "" || !request.TransferID.Valid
+}
+
+type paymentRequestURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// pendingPaymentRequest loads the pending payment request in the :id URI when
+// the authenticated user is on the given side of it
+func (server *Server) pendingPaymentRequest(ctx *gin.Context, side string) (db.PaymentRequest, bool) {
+	var uri paymentRequestURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.PaymentRequest{}, false
+	}
+
+	request, err := server.store.GetPaymentRequest(ctx, uri.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return request, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	party := request.Payer
+	if side == paymentRequestRequester {
+		party = request.Requester
+	}
+	if err != nil || party != authPayload.Username {
+		err := fmt.Errorf("payment request with ID [%d] does not exist", uri.ID)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return request, false
+	}
+
+	switch {
+	case request.Status != db.PaymentRequestPending:
+		ctx.JSON(http.StatusConflict, errorResponse(db.ErrPaymentRequestClosed))
+		return request, false
+	// the expiry job closes it on its next run
+	case !time.Now().Before(request.ExpiresAt):
+		ctx.JSON(http.StatusConflict, errorResponse(db.ErrPaymentRequestExpired))
+		return request, false
+	}
+
+	return request, true
+}
+
+type payPaymentRequestRequest struct {
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,excluded_with=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"omitempty,numeric"`
+	TOTPCode          string `json:"totp_code"`
+}
+
+// payPaymentRequest pays a request through the same checks as any other
+// transfer. A payment that is held or needs approval settles the request once
+// it is released.
+func (server *Server) payPaymentRequest(ctx *gin.Context) {
+	var request payPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	paymentRequest, ok := server.pendingPaymentRequest(ctx, paymentRequestPayer)
+	if !ok {
+		return
+	}
+
+	fromAccount, ok := server.transferAccount(ctx, request.FromAccountID, request.FromAccountNumber, paymentRequest.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	grant, ok := server.authorizeAccount(ctx, fromAccount, access.PermissionTransfer)
+	if !ok {
+		return
+	}
+
+	if err := grant.CanSpend(paymentRequest.Amount); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if !server.requireTransferMFA(ctx, authPayload.Username, paymentRequest.Amount, request.TOTPCode) {
+		return
+	}
+
+	toAccount, ok := server.transferAccount(ctx, paymentRequest.ToAccountID, "", paymentRequest.CurrencyCode)
+	if !ok {
+		return
+	}
+
+	server.sendTransfer(ctx, fromAccount, toAccount, db.TransferTxnParams{
+		FromAccountID:    fromAccount.ID,
+		ToAccountID:      toAccount.ID,
+		Amount:           paymentRequest.Amount,
+		PaymentRequestID: paymentRequest.ID,
+	}, authPayload.Username)
+}
+
+func (server *Server) declinePaymentRequest(ctx *gin.Context) {
+	server.closePaymentRequest(ctx, paymentRequestPayer, db.PaymentRequestDeclined)
+}
+
+func (server *Server) cancelPaymentRequest(ctx *gin.Context) {
+	server.closePaymentRequest(ctx, paymentRequestRequester, db.PaymentRequestCancelled)
+}
+
+// closePaymentRequest lets the payer decline a request or the requester cancel it
+func (server *Server) closePaymentRequest(ctx *gin.Context, side, status string) {
+	paymentRequest, ok := server.pendingPaymentRequest(ctx, side)
+	if !ok {
+		return
+	}
+
+	paymentRequest, err := server.store.ClosePaymentRequestTrxn(ctx, db.ClosePaymentRequestTxnParams{
+		ID:     paymentRequest.ID,
+		Status: status,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrPaymentRequestClosed) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("payment request "+status+" successfully", paymentRequest))
+}
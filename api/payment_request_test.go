@@ -0,0 +1,470 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPaymentRequest(requester, payer string, toAccount db.Account) db.PaymentRequest {
+	return db.PaymentRequest{
+		ID:           utils.RandomInt(1, 1000),
+		Requester:    requester,
+		Payer:        payer,
+		ToAccountID:  toAccount.ID,
+		Amount:       utils.RandomInt(1, 1000),
+		CurrencyCode: toAccount.CurrencyCode,
+		Status:       db.PaymentRequestPending,
+		ExpiresAt:    time.Now().Add(time.Hour),
+		CreatedAt:    time.Now(),
+	}
+}
+
+func Test_CreatePaymentRequestAPI(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+
+	account := generateRandomAccount(requester.Username)
+	account.CurrencyCode = utils.USD
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"payer": payer.Username, "amount": 250, "currency_code": utils.USD, "note": "dinner"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Eq(db.GetPrimaryAccountParams{
+					Owner:        requester.Username,
+					CurrencyCode: utils.USD,
+				})).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+						require.Equal(t, requester.Username, arg.Requester)
+						require.Equal(t, payer.Username, arg.Payer)
+						require.Equal(t, account.ID, arg.ToAccountID)
+						require.Equal(t, int64(250), arg.Amount)
+						require.Equal(t, "dinner", arg.Note)
+						require.WithinDuration(t, time.Now().Add(defaultPaymentRequestExpiry), arg.ExpiresAt, time.Second)
+						return db.PaymentRequest{ID: 1, Status: db.PaymentRequestPending}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FromSelf",
+			body: gin.H{"payer": requester.Username, "amount": 250, "currency_code": utils.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownPayer",
+			body: gin.H{"payer": payer.Username, "amount": 250, "currency_code": utils.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoAccountInCurrency",
+			body: gin.H{"payer": payer.Username, "amount": 250, "currency_code": utils.USD},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().GetPrimaryAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().CreatePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/payment-requests", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, requester.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_PayPaymentRequestAPI(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+
+	toAccount := generateRandomAccount(requester.Username)
+	fromAccount := generateRandomAccount(payer.Username)
+	toAccount.CurrencyCode = utils.USD
+	fromAccount.CurrencyCode = utils.USD
+
+	paymentRequest := randomPaymentRequest(requester.Username, payer.Username, toAccount)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Eq(db.TransferTxnParams{
+					FromAccountID:    fromAccount.ID,
+					ToAccountID:      toAccount.ID,
+					Amount:           paymentRequest.Amount,
+					PaymentRequestID: paymentRequest.ID,
+					InitiatedBy:      payer.Username,
+				})).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "HeldForApproval",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{ID: 1, RequiredApprovals: 1}, nil)
+				store.EXPECT().CreatePendingTransfer(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePendingTransferParams) (db.PendingTransfer, error) {
+						// the request is settled when the pending transfer is made
+						require.Equal(t, sql.NullInt64{Int64: paymentRequest.ID, Valid: true}, arg.PaymentRequestID)
+						return db.PendingTransfer{ID: 1}, nil
+					})
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:     "NotPayer",
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyPaid",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				paid := paymentRequest
+				paid.Status = db.PaymentRequestPaid
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paid, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "Expired",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := paymentRequest
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "ClosedWhilePaying",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetApplicableApprovalPolicy(gomock.Any(), gomock.Any()).Times(1).Return(db.ApprovalPolicy{}, sql.ErrNoRows)
+				store.EXPECT().PerformTransactionTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.TransferTrxResult{}, db.ErrPaymentRequestClosed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"from_account_id": fromAccount.ID})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/payment-requests/%d/pay", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_ListPaymentRequestsAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	granted := generateRandomAccount(user.Username)
+	notGranted := generateRandomAccount(user.Username)
+	grantedRequest := randomPaymentRequest(user.Username, other.Username, granted)
+	otherRequest := randomPaymentRequest(user.Username, other.Username, notGranted)
+
+	pending := randomPaymentRequest(other.Username, user.Username, generateRandomAccount(other.Username))
+	paid := randomPaymentRequest(other.Username, user.Username, generateRandomAccount(other.Username))
+	paid.Status = db.PaymentRequestPaid
+	paid.TransferID = sql.NullInt64{Int64: utils.RandomInt(1, 1000), Valid: true}
+
+	testCases := []struct {
+		name          string
+		direction     string
+		setupAuth     func(t *testing.T, request *http.Request, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Outgoing",
+			direction: "outgoing",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListOutgoingPaymentRequests(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.PaymentRequest{grantedRequest, otherRequest}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequests(t, recorder.Body, []db.PaymentRequest{grantedRequest, otherRequest})
+			},
+		},
+		{
+			name:      "OAuthClientOutgoing",
+			direction: "outgoing",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{granted.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+				store.EXPECT().ListOutgoingPaymentRequests(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.PaymentRequest{grantedRequest, otherRequest}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequests(t, recorder.Body, []db.PaymentRequest{grantedRequest})
+			},
+		},
+		{
+			name:      "OAuthClientIncoming",
+			direction: "incoming",
+			setupAuth: func(t *testing.T, request *http.Request, server *Server) {
+				addOAuthAuthorization(t, request, server.tokenGenerator, user.Username, []int64{granted.ID})
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRevokedAccessToken(gomock.Any(), gomock.Any()).Times(1).Return(db.OauthRevokedAccessToken{}, sql.ErrNoRows)
+				store.EXPECT().ListIncomingPaymentRequests(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.PaymentRequest{pending, paid}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequests(t, recorder.Body, []db.PaymentRequest{pending})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payment-requests?direction=%s&page_id=1&page_size=5", tc.direction)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func requireBodyMatchPaymentRequests(t *testing.T, body *bytes.Buffer, requests []db.PaymentRequest) {
+	var response struct {
+		Data []db.PaymentRequest `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &response))
+	require.Len(t, response.Data, len(requests))
+	for i := range requests {
+		require.Equal(t, requests[i].ID, response.Data[i].ID)
+	}
+}
+
+func Test_ClosePaymentRequestAPI(t *testing.T) {
+	requester, _ := randomUser(t)
+	payer, _ := randomUser(t)
+
+	toAccount := generateRandomAccount(requester.Username)
+	paymentRequest := randomPaymentRequest(requester.Username, payer.Username, toAccount)
+
+	testCases := []struct {
+		name          string
+		action        string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "PayerDeclines",
+			action:   "decline",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().ClosePaymentRequestTrxn(gomock.Any(), gomock.Eq(db.ClosePaymentRequestTxnParams{
+					ID:     paymentRequest.ID,
+					Status: db.PaymentRequestDeclined,
+				})).Times(1).Return(paymentRequest, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RequesterCancels",
+			action:   "cancel",
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().ClosePaymentRequestTrxn(gomock.Any(), gomock.Eq(db.ClosePaymentRequestTxnParams{
+					ID:     paymentRequest.ID,
+					Status: db.PaymentRequestCancelled,
+				})).Times(1).Return(paymentRequest, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RequesterCantDecline",
+			action:   "decline",
+			username: requester.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().ClosePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "PayerCantCancel",
+			action:   "cancel",
+			username: payer.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().ClosePaymentRequestTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/payment-requests/%d/%s", paymentRequest.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const potDateLayout = "2006-01-02"
+
+type potRequest struct {
+	Name         string `json:"name" binding:"required,max=64"`
+	TargetAmount *int64 `json:"target_amount" binding:"omitempty,gt=0"`
+	TargetDate   string `json:"target_date" binding:"omitempty,datetime=2006-01-02"`
+	RoundUpUnit  *int64 `json:"round_up_unit" binding:"omitempty,gt=0"`
+}
+
+func (request potRequest) targetDate() sql.NullTime {
+	if request.TargetDate == "" {
+		return sql.NullTime{}
+	}
+	// already validated by the binding
+	t, _ := time.Parse(potDateLayout, request.TargetDate)
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+type potResponse struct {
+	AccountID       int64     `json:"account_id"`
+	ParentAccountID int64     `json:"parent_account_id"`
+	Name            string    `json:"name"`
+	Balance         int64     `json:"balance"`
+	TargetAmount    *int64    `json:"target_amount"`
+	TargetDate      *string   `json:"target_date"`
+	RoundUpUnit     *int64    `json:"round_up_unit"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func newPotResponse(pot db.Pot, balance int64) potResponse {
+	response := potResponse{
+		AccountID:       pot.AccountID,
+		ParentAccountID: pot.ParentAccountID,
+		Name:            pot.Name,
+		Balance:         balance,
+		TargetAmount:    nullInt64Ptr(pot.TargetAmount),
+		RoundUpUnit:     nullInt64Ptr(pot.RoundUpUnit),
+		CreatedAt:       pot.CreatedAt,
+		UpdatedAt:       pot.UpdatedAt,
+	}
+	if pot.TargetDate.Valid {
+		date := pot.TargetDate.Time.Format(potDateLayout)
+		response.TargetDate = &date
+	}
+	return response
+}
+
+// respondPotError answers a failed pot change, a pot name is unique under its
+// parent and only one pot per parent can take the round-ups
+func respondPotError(ctx *gin.Context, err error) {
+	if errors.Is(err, db.ErrPotParent) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == ErrUniqueViolation {
+		err := errors.New("a pot with this name, or another round-up pot, already exists on the account")
+		ctx.JSON(http.StatusConflict, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+}
+
+// createPot opens a savings pot under an account
+func (server *Server) createPot(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	var request potRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionManage); !ok {
+		return
+	}
+
+	accountNumber, err := server.newAccountNumber(ctx, account.CurrencyCode)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreatePotTrxn(ctx, db.CreatePotTxnParams{
+		ParentAccountID: account.ID,
+		Name:            request.Name,
+		TargetAmount:    nullInt64(request.TargetAmount),
+		TargetDate:      request.targetDate(),
+		RoundUpUnit:     nullInt64(request.RoundUpUnit),
+		AccountNumber:   accountNumber,
+	})
+	if err != nil {
+		respondPotError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pot created successfully", newPotResponse(result.Pot, result.Account.Balance)))
+}
+
+func (server *Server) listPots(ctx *gin.Context) {
+	account, ok := server.balanceAccount(ctx)
+	if !ok {
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
+		return
+	}
+
+	rows, err := server.store.ListPots(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	pots := make([]potResponse, 0, len(rows))
+	for _, row := range rows {
+		pot := db.Pot{
+			AccountID:       row.AccountID,
+			ParentAccountID: row.ParentAccountID,
+			Name:            row.Name,
+			TargetAmount:    row.TargetAmount,
+			TargetDate:      row.TargetDate,
+			RoundUpUnit:     row.RoundUpUnit,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		}
+		pots = append(pots, newPotResponse(pot, row.Balance))
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pots retrieved successfully", pots))
+}
+
+// authorizePot loads the pot in the :id URI and checks the authenticated user
+// may do permission on its parent account
+func (server *Server) authorizePot(ctx *gin.Context, permission access.Permission) (db.Pot, bool) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Pot{}, false
+	}
+
+	pot, err := server.store.GetPot(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("pot with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return pot, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pot, false
+	}
+
+	parent, err := server.store.GetAccount(ctx, pot.ParentAccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pot, false
+	}
+
+	_, ok := server.authorizeAccount(ctx, parent, permission)
+	return pot, ok
+}
+
+// updatePot replaces a pot's name, target and round-up rule
+func (server *Server) updatePot(ctx *gin.Context) {
+	var request potRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pot, ok := server.authorizePot(ctx, access.PermissionManage)
+	if !ok {
+		return
+	}
+
+	pot, err := server.store.UpdatePot(ctx, db.UpdatePotParams{
+		AccountID:    pot.AccountID,
+		Name:         request.Name,
+		TargetAmount: nullInt64(request.TargetAmount),
+		TargetDate:   request.targetDate(),
+		RoundUpUnit:  nullInt64(request.RoundUpUnit),
+	})
+	if err != nil {
+		respondPotError(ctx, err)
+		return
+	}
+
+	account, err := server.store.GetAccount(ctx, pot.AccountID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pot updated successfully", newPotResponse(pot, account.Balance)))
+}
+
+type movePotRequest struct {
+	Amount int64 `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) depositToPot(ctx *gin.Context) {
+	server.movePot(ctx, db.PotDeposit)
+}
+
+func (server *Server) withdrawFromPot(ctx *gin.Context) {
+	server.movePot(ctx, db.PotWithdraw)
+}
+
+// movePot moves money between a pot and its parent account. Moves are instant
+// and don't count towards the account's transfer limits.
+func (server *Server) movePot(ctx *gin.Context, direction string) {
+	var request movePotRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	pot, ok := server.authorizePot(ctx, access.PermissionTransfer)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.MovePotTrxn(ctx, db.MovePotTxnParams{
+		PotID:     pot.AccountID,
+		Direction: direction,
+		Amount:    request.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrAccountBlocked) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("pot "+direction+" completed successfully", result))
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreatePotAPI(t *testing.T) {
+	owner, _ := randomUser(t)
+	stranger, _ := randomUser(t)
+	account := generateRandomAccount(owner.Username)
+	accountNumber, err := utils.NewAccountNumber(utils.DefaultBankCode, account.CurrencyCode, 1)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: owner.Username,
+			body:     gin.H{"name": "holiday", "target_amount": 5000, "target_date": "2027-06-01", "round_up_unit": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreatePotTxnParams{
+					ParentAccountID: account.ID,
+					Name:            "holiday",
+					TargetAmount:    sql.NullInt64{Int64: 5000, Valid: true},
+					TargetDate:      sql.NullTime{Time: time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					RoundUpUnit:     sql.NullInt64{Int64: 100, Valid: true},
+					AccountNumber:   sql.NullString{String: accountNumber, Valid: true},
+				}
+				store.EXPECT().CreatePotTrxn(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.CreatePotTxnResult{
+					Pot: db.Pot{
+						AccountID:       account.ID + 1,
+						ParentAccountID: account.ID,
+						Name:            arg.Name,
+						TargetAmount:    arg.TargetAmount,
+						TargetDate:      arg.TargetDate,
+						RoundUpUnit:     arg.RoundUpUnit,
+					},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Data potResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "holiday", response.Data.Name)
+				require.NotNil(t, response.Data.TargetDate)
+				require.Equal(t, "2027-06-01", *response.Data.TargetDate)
+			},
+		},
+		{
+			name:     "InvalidTargetDate",
+			username: owner.Username,
+			body:     gin.H{"name": "holiday", "target_date": "next summer"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePotTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotMember",
+			username: stranger.Username,
+			body:     gin.H{"name": "holiday"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+				store.EXPECT().CreatePotTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "PotUnderPot",
+			username: owner.Username,
+			body:     gin.H{"name": "holiday"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePotTrxn(gomock.Any(), gomock.Any()).Times(1).Return(db.CreatePotTxnResult{}, db.ErrPotParent)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "DuplicateName",
+			username: owner.Username,
+			body:     gin.H{"name": "holiday"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePotTrxn(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.CreatePotTxnResult{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+			store.EXPECT().NextAccountSequence(gomock.Any()).AnyTimes().Return(int64(1), nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%d/pots", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_MovePotAPI(t *testing.T) {
+	owner, _ := randomUser(t)
+	parent := generateRandomAccount(owner.Username)
+	pot := db.Pot{AccountID: parent.ID + 1, ParentAccountID: parent.ID, Name: "holiday"}
+
+	testCases := []struct {
+		name          string
+		direction     string
+		amount        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Deposit",
+			direction: db.PotDeposit,
+			amount:    100,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.MovePotTxnParams{PotID: pot.AccountID, Direction: db.PotDeposit, Amount: 100}
+				store.EXPECT().MovePotTrxn(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "WithdrawInsufficientFunds",
+			direction: db.PotWithdraw,
+			amount:    100,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.MovePotTxnParams{PotID: pot.AccountID, Direction: db.PotWithdraw, Amount: 100}
+				store.EXPECT().MovePotTrxn(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.TransferTrxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidAmount",
+			direction: db.PotDeposit,
+			amount:    0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MovePotTrxn(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetPot(gomock.Any(), gomock.Eq(pot.AccountID)).AnyTimes().Return(pot, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(parent.ID)).AnyTimes().Return(parent, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"amount": tc.amount})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/pots/%d/%s", pot.AccountID, tc.direction)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, owner.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -1,10 +1,20 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"time"
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/pkg/alias"
+	"github.com/caleberi/simple-bank/pkg/fraud"
+	"github.com/caleberi/simple-bank/pkg/kyc"
+	"github.com/caleberi/simple-bank/pkg/reconcile"
+	"github.com/caleberi/simple-bank/pkg/stream"
 	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/pkg/webhook"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -15,46 +25,268 @@ type Server struct {
 	config         utils.Config
 	store          db.Store
 	tokenGenerator token.Maker
-	router         *gin.Engine
+	keyRing        *token.KeyRing
+	broker         *stream.Broker
+	ledgerMonitor  *reconcile.Monitor
+	kycProvider    kyc.Provider
+	fraudEngine    *fraud.Engine
+	access         *access.Service
+	aliasSender    alias.Sender
+	// webhookResolver resolves webhook hosts to refuse internal addresses
+	webhookResolver webhook.Resolver
+	router          *gin.Engine
 }
 
 func NewServer(config utils.Config, store db.Store) (*Server, error) {
-	tokenGenerator, err := token.NewPasetoMaker(config.TokenSymmetricKey)
-	if err != nil {
+	server := &Server{
+		config:          config,
+		store:           store,
+		broker:          stream.NewBroker(),
+		access:          access.NewService(store),
+		webhookResolver: net.DefaultResolver,
+	}
+	server.ledgerMonitor = reconcile.NewMonitor(reconcile.New(store, reconcile.Options{
+		BatchSize:     config.ReconcileBatchSize,
+		BlockAccounts: config.ReconcileAutoBlock,
+	}))
+
+	if server.config.BankCode == "" {
+		server.config.BankCode = utils.DefaultBankCode
+	}
+	if !utils.ValidBankCode(server.config.BankCode) {
+		return nil, fmt.Errorf("invalid bank code %q", server.config.BankCode)
+	}
+
+	if server.config.BeneficiaryCoolingOff <= 0 {
+		server.config.BeneficiaryCoolingOff = 24 * time.Hour
+	}
+	if server.config.BeneficiaryCoolingOffAmount <= 0 {
+		server.config.BeneficiaryCoolingOffAmount = 100_000
+	}
+
+	if err := server.setupTokenMaker(); err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
-	server := &Server{
-		config:         config,
-		store:          store,
-		tokenGenerator: tokenGenerator,
+	kycProvider := config.KYCProvider
+	if kycProvider == "" {
+		kycProvider = kyc.FakeProviderName
 	}
+	provider, err := kyc.NewProvider(kycProvider, config.KYCProviderConfig)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create kyc provider: %w", err)
+	}
+	server.kycProvider = provider
+
+	aliasSender, err := newAliasSender(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create alias sender: %w", err)
+	}
+	server.aliasSender = aliasSender
+
+	rules, err := fraud.DefaultRules(store, fraud.Config{SanctionsFile: config.FraudSanctionsFile})
+	if err != nil {
+		return nil, fmt.Errorf("cannot load fraud rules: %w", err)
+	}
+	server.fraudEngine = fraud.NewEngine(rules...)
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
 	}
 
-	server.registerRoutes()
+	if err := server.registerRoutes(); err != nil {
+		return nil, err
+	}
 
 	return server, nil
 }
 
-func (server *Server) registerRoutes() {
+// newAliasSender builds the configured sender of alias verification codes.
+// Development falls back to logging them, which is refused anywhere else as
+// anyone who can read the log could then verify any alias.
+func newAliasSender(config utils.Config) (alias.Sender, error) {
+	name := config.AliasSender
+	if name == "" && config.Environment == utils.EnvDevelopment {
+		name = alias.LogSenderName
+	}
+	if name == alias.LogSenderName && config.Environment != utils.EnvDevelopment {
+		return nil, fmt.Errorf("the %s sender is only allowed in %s", alias.LogSenderName, utils.EnvDevelopment)
+	}
+	return alias.NewSender(name, config.AliasSenderConfig)
+}
+
+func (server *Server) registerRoutes() error {
 	router := gin.Default()
+	// gin trusts X-Forwarded-For from anyone by default, which would let a
+	// caller pick the IP API key allow lists and audit events see
+	if err := router.SetTrustedProxies(server.config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(auditMiddleware())
 
 	router.POST("/users", server.createUser)
 	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login/mfa", server.loginUserMFA)
+	router.GET("/invoices/pay/:token", server.getPayableInvoice)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenGenerator, server.store))
+
+	authRoutes.POST("/accounts", requireScope(token.ScopeAccountsWrite), server.createAccountHandler)
+	authRoutes.GET("/accounts/:id", requireScope(token.ScopeAccountsRead), server.getAccountHandler)
+	authRoutes.GET("/accounts/:id/stream", requireScope(token.ScopeAccountsRead), server.streamAccount)
+	authRoutes.GET("/accounts/:id/balance", requireScope(token.ScopeAccountsRead), server.getAccountBalance)
+	authRoutes.GET("/accounts/:id/limits", requireScope(token.ScopeAccountsRead), server.getAccountLimits)
+	authRoutes.GET("/accounts", requireScope(token.ScopeAccountsRead), server.listAccountHandler)
+	authRoutes.GET("/account-products", server.listAccountProducts)
+	authRoutes.DELETE("/accounts/:id", requireScope(token.ScopeAccountsWrite), server.deleteAccount)
+	authRoutes.GET("/accounts/:id/members", requireScope(token.ScopeAccountsRead), server.listAccountMembers)
+	authRoutes.POST("/accounts/:id/members", requireUserSession(), server.inviteAccountMember)
+	authRoutes.POST("/accounts/:id/members/accept", requireUserSession(), server.acceptAccountMember)
+	authRoutes.DELETE("/accounts/:id/members/:username", requireUserSession(), server.removeAccountMember)
+	authRoutes.GET("/account-invitations", requireUserSession(), server.listAccountInvitations)
+	authRoutes.GET("/accounts/:id/pots", requireScope(token.ScopeAccountsRead), server.listPots)
+	authRoutes.POST("/accounts/:id/pots", requireScope(token.ScopeAccountsWrite), server.createPot)
+	authRoutes.PUT("/pots/:id", requireScope(token.ScopeAccountsWrite), server.updatePot)
+	authRoutes.POST("/pots/:id/deposit", requireScope(token.ScopeTransfersCreate), server.depositToPot)
+	authRoutes.POST("/pots/:id/withdraw", requireScope(token.ScopeTransfersCreate), server.withdrawFromPot)
+	authRoutes.POST("/transfers", requireScope(token.ScopeTransfersCreate), server.createTransfer)
+	authRoutes.GET("/transfers/held/:id", requireScope(token.ScopeTransfersCreate), server.getHeldTransfer)
+	authRoutes.GET("/beneficiaries", requireUserSession(), server.listBeneficiaries)
+	authRoutes.POST("/beneficiaries", requireUserSession(), server.createBeneficiary)
+	authRoutes.PUT("/beneficiaries/:id", requireUserSession(), server.renameBeneficiary)
+	authRoutes.DELETE("/beneficiaries/:id", requireUserSession(), server.deleteBeneficiary)
+	authRoutes.GET("/payment-requests", requireScope(token.ScopeAccountsRead), server.listPaymentRequests)
+	authRoutes.POST("/payment-requests", requireUserSession(), server.createPaymentRequest)
+	authRoutes.POST("/payment-requests/:id/pay", requireScope(token.ScopeTransfersCreate), server.payPaymentRequest)
+	authRoutes.POST("/payment-requests/:id/decline", requireUserSession(), server.declinePaymentRequest)
+	authRoutes.POST("/payment-requests/:id/cancel", requireUserSession(), server.cancelPaymentRequest)
+	authRoutes.GET("/invoices", requireScope(token.ScopeAccountsRead), server.listInvoices)
+	authRoutes.GET("/invoices/aging", requireScope(token.ScopeAccountsRead), server.getInvoiceAging)
+	authRoutes.GET("/invoices/:id", requireScope(token.ScopeAccountsRead), server.getInvoice)
+	authRoutes.POST("/invoices", requireUserSession(), server.createInvoice)
+	authRoutes.POST("/invoices/:id/send", requireUserSession(), server.sendInvoice)
+	authRoutes.POST("/invoices/:id/void", requireUserSession(), server.voidInvoice)
+	authRoutes.POST("/invoices/pay/:token", requireScope(token.ScopeTransfersCreate), server.payInvoice)
+	authRoutes.GET("/escrow", requireScope(token.ScopeAccountsRead), server.listEscrow)
+	authRoutes.GET("/escrow/:id", requireScope(token.ScopeAccountsRead), server.getEscrow)
+	authRoutes.POST("/escrow", requireScope(token.ScopeTransfersCreate), server.createEscrow)
+	authRoutes.POST("/escrow/:id/confirm", requireUserSession(), server.confirmEscrow)
+	authRoutes.POST("/escrow/:id/dispute", requireUserSession(), server.disputeEscrow)
+	authRoutes.GET("/aliases/lookup", requireScope(token.ScopeTransfersCreate), server.lookupAlias)
+
+	authRoutes.POST("/aliases", requireUserSession(), server.createAlias)
+	authRoutes.POST("/aliases/verify", requireUserSession(), server.verifyAlias)
+	authRoutes.GET("/aliases", requireUserSession(), server.listAliases)
+	authRoutes.DELETE("/aliases/:alias", requireUserSession(), server.deleteAlias)
+
+	authRoutes.GET("/pending-transfers", requireUserSession(), server.listApprovableTransfers)
+	authRoutes.GET("/pending-transfers/:id", requireUserSession(), server.getPendingTransfer)
+	authRoutes.POST("/pending-transfers/:id/approve", requireUserSession(), server.approveTransfer)
+	authRoutes.POST("/pending-transfers/:id/reject", requireUserSession(), server.rejectTransfer)
+
+	authRoutes.POST("/users/mfa/enroll", requireUserSession(), server.enrollMFA)
+	authRoutes.POST("/users/mfa/confirm", requireUserSession(), server.confirmMFA)
+	authRoutes.POST("/users/mfa/recovery-codes", requireUserSession(), server.regenerateRecoveryCodes)
+	authRoutes.POST("/users/mfa/disable", requireUserSession(), server.disableMFA)
+
+	authRoutes.POST("/api-keys", requireUserSession(), server.createAPIKey)
+	authRoutes.GET("/api-keys", requireUserSession(), server.listAPIKeys)
+	authRoutes.DELETE("/api-keys/:id", requireUserSession(), server.revokeAPIKey)
+
+	authRoutes.GET("/kyc", requireUserSession(), server.getKYC)
+	authRoutes.GET("/kyc/tiers", requireUserSession(), server.listKYCTiers)
+	authRoutes.POST("/kyc/documents", requireUserSession(), server.createKYCDocument)
+	authRoutes.POST("/kyc/submit", requireUserSession(), server.submitKYC)
+
+	authRoutes.POST("/webhooks", requireUserSession(), server.createWebhook)
+	authRoutes.GET("/webhooks", requireUserSession(), server.listWebhooks)
+	authRoutes.DELETE("/webhooks/:id", requireUserSession(), server.deleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", requireUserSession(), server.listWebhookDeliveries)
+	authRoutes.GET("/webhooks/:id/deliveries/:delivery_id", requireUserSession(), server.getWebhookDelivery)
+	authRoutes.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", requireUserSession(), server.redeliverWebhook)
 
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenGenerator))
+	authRoutes.POST("/oauth/clients", requireUserSession(), server.createOAuthClient)
+	authRoutes.GET("/oauth/authorize", requireUserSession(), server.getConsent)
+	authRoutes.POST("/oauth/authorize", requireUserSession(), server.submitConsent)
 
-	authRoutes.POST("/accounts", server.createAccountHandler)
-	authRoutes.GET("/accounts/:id", server.getAccountHandler)
-	authRoutes.GET("/accounts", server.listAccountHandler)
-	authRoutes.DELETE("/accounts/:id", server.deleteAccount)
-	authRoutes.POST("/transfers", server.createTransfer)
+	adminRoutes := router.Group("/admin").Use(
+		authMiddleware(server.tokenGenerator, server.store),
+		requireUserSession(),
+		server.requireAdmin(),
+	)
+	adminRoutes.GET("/audit-events", server.listAuditEvents)
+	adminRoutes.GET("/audit-events/verify", server.verifyAuditChain)
+	adminRoutes.GET("/reconciliation", server.getReconciliation)
+	adminRoutes.POST("/accounts/:id/unblock", server.unblockAccount)
+	adminRoutes.GET("/accounts/:id/balance", server.adminGetAccountBalance)
+	adminRoutes.GET("/transfer-limits", server.listTransferLimits)
+	adminRoutes.PUT("/transfer-limits", server.upsertTransferLimit)
+	adminRoutes.DELETE("/transfer-limits/:id", server.deleteTransferLimit)
+	adminRoutes.GET("/accounts/:id/approval-policies", server.listApprovalPolicies)
+	adminRoutes.PUT("/accounts/:id/approval-policies", server.upsertApprovalPolicy)
+	adminRoutes.DELETE("/approval-policies/:id", server.deleteApprovalPolicy)
+	adminRoutes.PUT("/users/:username/tier", server.setUserTier)
+	adminRoutes.GET("/kyc", server.listKYCProfiles)
+	adminRoutes.GET("/kyc/:username", server.adminGetKYC)
+	adminRoutes.POST("/kyc/:username/review", server.reviewKYC)
+	adminRoutes.GET("/fraud/cases", server.listFraudCases)
+	adminRoutes.GET("/fraud/cases/:id", server.getFraudCase)
+	adminRoutes.POST("/fraud/cases/:id/approve", server.approveFraudCase)
+	adminRoutes.POST("/fraud/cases/:id/reject", server.rejectFraudCase)
+	adminRoutes.GET("/escrow", server.listEscrowDisputes)
+	adminRoutes.POST("/escrow/:id/resolve", server.resolveEscrow)
+
+	router.POST("/oauth/token", server.issueOAuthToken)
+	router.POST("/oauth/introspect", server.introspectOAuthToken)
+	router.POST("/oauth/revoke", server.revokeOAuthToken)
+
+	router.GET("/.well-known/jwks.json", server.getJWKS)
+	router.GET("/health/ledger", server.ledgerHealth)
 
 	server.router = router
+	return nil
+
+}
+
+// setupTokenMaker creates the maker selected by TOKEN_TYPE. When no type is
+// configured it signs with Ed25519 if a signing key is present, so other
+// services can verify our tokens from the JWKS endpoint, and falls back to
+// the shared symmetric key otherwise.
+func (server *Server) setupTokenMaker() error {
+	var err error
+
+	if server.config.TokenSigningKey != "" {
+		server.keyRing, err = token.ParseKeyRing(
+			server.config.TokenSigningKeyID,
+			server.config.TokenSigningKey,
+			server.config.TokenVerificationKeys,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	makerType := server.config.TokenType
+	if makerType == "" {
+		makerType = token.TypePasetoLocal
+		if server.keyRing != nil {
+			makerType = token.TypePasetoPublic
+		}
+	}
+
+	server.tokenGenerator, err = token.NewMaker(
+		makerType,
+		server.config.TokenSymmetricKey,
+		server.keyRing,
+		token.WithIssuer(server.config.TokenIssuer),
+		token.WithAudience(server.config.TokenAudience),
+	)
+	return err
+}
 
+// ListenAccountEvents feeds account streams from Postgres notifications until ctx is cancelled
+func (server *Server) ListenAccountEvents(ctx context.Context, dataSource string) error {
+	return server.broker.Listen(ctx, dataSource)
 }
 
 func (server *Server) Start(address string) error {
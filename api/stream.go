@@ -0,0 +1,80 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const streamHeartbeatInterval = 15 * time.Second
+
+// streamAccount pushes the account's balance and every new entry to the client
+// as server-sent events. It starts with a balance event so clients don't have
+// to fetch the account separately, and ends the stream whenever events might
+// have been missed so the client reconnects and starts from a fresh snapshot.
+func (server *Server) streamAccount(ctx *gin.Context) {
+	var request getAccountRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !authPayload.CanAccessAccount(request.ID) {
+		err := errors.New("account was not shared with this application")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
+	// subscribe before reading the snapshot so nothing committed in between is lost
+	events, unsubscribe := server.broker.Subscribe(request.ID)
+	defer unsubscribe()
+
+	account, err := server.store.GetAccount(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.authorizeAccount(ctx, account, access.PermissionView); !ok {
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("balance", account)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case notification, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("entry", notification.Entry)
+			ctx.SSEvent("balance", notification.Account)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent reads one server-sent event, skipping comments
+func readEvent(t *testing.T, reader *bufio.Reader) (event string, data string) {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case line == "" && event != "":
+			return
+		}
+	}
+}
+
+func Test_StreamAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+	httpServer := httptest.NewServer(server.router)
+	defer httpServer.Close()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/accounts/%d/stream", httpServer.URL, account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Contains(t, response.Header.Get("Content-Type"), "text/event-stream")
+
+	reader := bufio.NewReader(response.Body)
+	event, data := readEvent(t, reader)
+	require.Equal(t, "balance", event)
+	require.Contains(t, data, fmt.Sprintf(`"balance":%d`, account.Balance))
+
+	updated := account
+	updated.Balance += 25
+	server.broker.Publish(db.AccountNotification{
+		AccountID: account.ID,
+		Account:   updated,
+		Entry:     db.Entry{ID: 9, AccountID: account.ID, Amount: 25},
+	})
+
+	event, data = readEvent(t, reader)
+	require.Equal(t, "entry", event)
+	require.Contains(t, data, `"amount":25`)
+
+	event, data = readEvent(t, reader)
+	require.Equal(t, "balance", event)
+	require.Contains(t, data, fmt.Sprintf(`"balance":%d`, updated.Balance))
+
+	// a reset ends the stream so the client resubscribes from a fresh snapshot
+	server.broker.Reset()
+	_, err = reader.ReadString('\n')
+	require.Error(t, err)
+}
+
+func Test_StreamAccountAPIAccess(t *testing.T) {
+	user, _ := randomUser(t)
+	account := generateRandomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "NotOwner",
+			username: "intruder",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "event:")
+			},
+		},
+		{
+			name:     "NotFound",
+			username: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%d/stream", account.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -7,15 +7,24 @@ import (
 	"net/http"
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/access"
+	"github.com/caleberi/simple-bank/pkg/utils"
 	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
 )
 
+// transferRequest names each account by its id or by its account number, the
+// recipient can also be named by an alias or one of the user's beneficiaries
 type transferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	CurrencyCode  string `json:"currency_code" binding:"required,currency"`
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,excluded_with=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"omitempty,numeric"`
+	ToAccountID       int64  `json:"to_account_id" binding:"required_without_all=ToAccountNumber ToAlias BeneficiaryID,excluded_with=ToAccountNumber ToAlias BeneficiaryID,omitempty,min=1"`
+	ToAccountNumber   string `json:"to_account_number" binding:"excluded_with=ToAlias BeneficiaryID,omitempty,numeric"`
+	ToAlias           string `json:"to_alias" binding:"excluded_with=BeneficiaryID,omitempty,max=254"`
+	BeneficiaryID     int64  `json:"beneficiary_id" binding:"omitempty,min=1"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	CurrencyCode      string `json:"currency_code" binding:"required,currency"`
+	TOTPCode          string `json:"totp_code"`
 }
 
 func (server *Server) createTransfer(ctx *gin.Context) {
@@ -25,31 +34,62 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	fromAccount, valid := server.validAccount(ctx, request.FromAccountID, request.CurrencyCode)
+	fromAccount, valid := server.transferAccount(ctx, request.FromAccountID, request.FromAccountNumber, request.CurrencyCode)
 	if !valid {
 		return
 	}
 
+	grant, ok := server.authorizeAccount(ctx, fromAccount, access.PermissionTransfer)
+	if !ok {
+		return
+	}
+
+	if err := grant.CanSpend(request.Amount); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+
+	if !server.requireTransferMFA(ctx, authPayload.Username, request.Amount, request.TOTPCode) {
 		return
 	}
 
-	_, valid = server.validAccount(ctx, request.ToAccountID, request.CurrencyCode)
+	toAccount, valid := server.recipientAccount(ctx, request)
 	if !valid {
 		return
 	}
 
-	arg := db.TransferTxnParams{
-		FromAccountID: request.FromAccountID,
-		ToAccountID:   request.ToAccountID,
+	server.sendTransfer(ctx, fromAccount, toAccount, db.TransferTxnParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
 		Amount:        request.Amount,
+	}, authPayload.Username)
+}
+
+// sendTransfer makes a transfer the initiator was already authorized and
+// MFA checked for, once it clears the transfer controls
+func (server *Server) sendTransfer(ctx *gin.Context, from, to db.Account, arg db.TransferTxnParams, initiatedBy string) {
+	if !server.clearTransfer(ctx, from, to, arg, initiatedBy) {
+		return
 	}
 
+	arg.InitiatedBy = initiatedBy
 	result, err := server.store.PerformTransactionTrxn(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrAccountBlocked) || errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrPotTransfer) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if settlementConflict(err) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		var limitErr *db.LimitExceededError
+		if errors.As(err, &limitErr) {
+			ctx.JSON(http.StatusForbidden, limitErrorResponse(limitErr))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
@@ -57,22 +97,106 @@ func (server *Server) createTransfer(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, successResponse("transaction initiated successfully", result))
 }
 
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currencyCode string) (db.Account, bool) {
-	account, err := server.store.GetAccount(ctx, accountID)
+// clearTransfer runs a transfer past the beneficiary cooling-off, the fraud
+// rules and the source account's approval policies. It answers and returns
+// false when the transfer is refused or held, a held transfer is made once a
+// reviewer or the approvers let it through.
+func (server *Server) clearTransfer(ctx *gin.Context, from, to db.Account, arg db.TransferTxnParams, initiatedBy string) bool {
+	if !server.checkCoolingOff(ctx, initiatedBy, to, arg.Amount) {
+		return false
+	}
+
+	if !server.screenTransfer(ctx, from, to, arg, initiatedBy) {
+		return false
+	}
+
+	return server.requireApproval(ctx, from, to, arg, initiatedBy)
+}
+
+// settlementConflict reports whether a transfer failed because the payment
+// request or invoice it pays was settled, closed or expired in the meantime
+func settlementConflict(err error) bool {
+	return errors.Is(err, db.ErrPaymentRequestClosed) ||
+		errors.Is(err, db.ErrPaymentRequestExpired) ||
+		errors.Is(err, db.ErrInvoiceNotPayable) ||
+		errors.Is(err, db.ErrInvoiceOverpaid)
+}
+
+// recipientAccount loads the account a transfer pays, an alias pays its owner's
+// primary account in the transfer's currency
+func (server *Server) recipientAccount(ctx *gin.Context, request transferRequest) (db.Account, bool) {
+	switch {
+	case request.BeneficiaryID != 0:
+		beneficiary, ok := server.userBeneficiary(ctx, request.BeneficiaryID)
+		if !ok {
+			return db.Account{}, false
+		}
+		return server.transferAccount(ctx, beneficiary.AccountID, "", request.CurrencyCode)
+	case request.ToAlias != "":
+		_, account, ok := server.resolveAlias(ctx, request.ToAlias, request.CurrencyCode)
+		if !ok {
+			return account, false
+		}
+		return checkTransferAccount(ctx, account, request.CurrencyCode)
+	}
+	return server.transferAccount(ctx, request.ToAccountID, request.ToAccountNumber, request.CurrencyCode)
+}
+
+// transferAccount loads one side of a transfer by its account number when it
+// was given one and by its id otherwise
+func (server *Server) transferAccount(ctx *gin.Context, accountID int64, accountNumber, currencyCode string) (db.Account, bool) {
+	account, ok := server.lookupAccount(ctx, accountID, accountNumber)
+	if !ok {
+		return account, false
+	}
+	return checkTransferAccount(ctx, account, currencyCode)
+}
+
+// lookupAccount loads an account by its account number when it was given one
+// and by its id otherwise
+func (server *Server) lookupAccount(ctx *gin.Context, accountID int64, accountNumber string) (db.Account, bool) {
+	if accountNumber == "" {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return account, false
+		}
+		return account, true
+	}
+
+	if !utils.ValidAccountNumber(server.config.BankCode, accountNumber) {
+		err := fmt.Errorf("%s is not a valid account number", accountNumber)
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Account{}, false
+	}
+
+	account, err := server.store.GetAccountByNumber(ctx, nullString(accountNumber))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("account %s does not exist", accountNumber)
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return account, false
 		}
-		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return account, false
 	}
 
+	return account, true
+}
+
+// checkTransferAccount checks money can move in or out of account in currencyCode
+func checkTransferAccount(ctx *gin.Context, account db.Account, currencyCode string) (db.Account, bool) {
 	if account.CurrencyCode != currencyCode {
 		err := fmt.Errorf("account [%d] currency mismatch: %v vs %s", account.ID, account.CurrencyCode, currencyCode)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return account, false
 	}
 
+	if account.Status == db.AccountStatusBlocked {
+		err := fmt.Errorf("account [%d] is blocked", account.ID)
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return account, false
+	}
+
 	return account, true
 }
@@ -7,6 +7,7 @@ import (
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
 	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/token"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
@@ -28,6 +29,8 @@ type userResponse struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	MFAEnabled        bool      `json:"mfa_enabled"`
+	Tier              string    `json:"tier"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -37,6 +40,8 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		MFAEnabled:        user.TotpEnabled,
+		Tier:              user.Tier,
 	}
 }
 
@@ -109,9 +114,35 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	err = hasher.CheckPassword(request.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
 	}
 
+	if user.TotpEnabled {
+		payload, err := token.NewPayload(user.Username, server.mfaTokenDuration())
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		payload.Purpose = token.PurposeMFA
+
+		mfaToken, err := server.tokenGenerator.CreateTokenFromPayload(payload)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, loginMFARequiredResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		})
+		return
+	}
+
+	server.respondWithAccessToken(ctx, user)
+}
+
+func (server *Server) respondWithAccessToken(ctx *gin.Context, user db.User) {
 	accessToken, err := server.tokenGenerator.CreateToken(user.Username, server.config.AccessTokenDuration)
 
 	if err != nil {
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/pkg/webhook"
+	"github.com/caleberi/simple-bank/token"
+	"github.com/gin-gonic/gin"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,startswith=https://"`
+	EventTypes []string `json:"event_types" binding:"omitempty,dive,oneof=transfer.completed account.credited account.debited payment_request.created payment_request.paid payment_request.declined payment_request.cancelled payment_request.expired invoice.payment_received invoice.paid invoice.overdue escrow.funded escrow.disputed escrow.released escrow.refunded"`
+}
+
+type webhookResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type createWebhookResponse struct {
+	Secret  string          `json:"secret"`
+	Webhook webhookResponse `json:"webhook"`
+}
+
+func newWebhookResponse(endpoint db.WebhookEndpoint) webhookResponse {
+	return webhookResponse{
+		ID:         endpoint.ID,
+		URL:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		Active:     endpoint.Active,
+		CreatedAt:  endpoint.CreatedAt,
+	}
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var request createWebhookRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := webhook.CheckURL(ctx, server.webhookResolver, request.URL); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	secret, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	eventTypes := request.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:      authPayload.Username,
+		Url:        request.URL,
+		Secret:     webhookSecretPrefix + secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("webhook registered, use the secret to verify signatures", createWebhookResponse{
+		Secret:  endpoint.Secret,
+		Webhook: newWebhookResponse(endpoint),
+	}))
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoints, err := server.store.ListWebhookEndpoints(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]webhookResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		response[i] = newWebhookResponse(endpoint)
+	}
+	ctx.JSON(http.StatusOK, successResponse("webhooks retrieved successfully", response))
+}
+
+type webhookURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var request webhookURIRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoint, err := server.store.DeactivateWebhookEndpoint(ctx, db.DeactivateWebhookEndpointParams{
+		ID:    request.ID,
+		Owner: authPayload.Username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err := fmt.Errorf("webhook with ID [%d] does not exist", request.ID)
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("webhook deactivated successfully", newWebhookResponse(endpoint)))
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var request listWebhookDeliveriesRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.ownedWebhook(ctx, uri.ID); !ok {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		EndpointID: uri.ID,
+		Limit:      request.PageSize,
+		Offset:     (request.PageID - 1) * request.PageSize,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("webhook deliveries retrieved successfully", deliveries))
+}
+
+type webhookDeliveryURIRequest struct {
+	ID         int64 `uri:"id" binding:"required,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,min=1"`
+}
+
+type webhookDeliveryResponse struct {
+	Delivery db.WebhookDelivery  `json:"delivery"`
+	Attempts []db.WebhookAttempt `json:"attempts"`
+}
+
+func (server *Server) getWebhookDelivery(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	attempts, err := server.store.ListWebhookAttempts(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("webhook delivery retrieved successfully", webhookDeliveryResponse{
+		Delivery: delivery,
+		Attempts: attempts,
+	}))
+}
+
+// redeliverWebhook queues a delivery to be sent again on the dispatcher's next pass,
+// whether it failed for good or already succeeded
+func (server *Server) redeliverWebhook(ctx *gin.Context) {
+	delivery, ok := server.ownedWebhookDelivery(ctx)
+	if !ok {
+		return
+	}
+
+	delivery, err := server.store.RedeliverWebhook(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse("webhook delivery queued", delivery))
+}
+
+func (server *Server) ownedWebhookDelivery(ctx *gin.Context) (db.WebhookDelivery, bool) {
+	var request webhookDeliveryURIRequest
+	if err := ctx.ShouldBindUri(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.WebhookDelivery{}, false
+	}
+
+	if _, ok := server.ownedWebhook(ctx, request.ID); !ok {
+		return db.WebhookDelivery{}, false
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, request.DeliveryID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return delivery, false
+	}
+
+	if err != nil || delivery.EndpointID != request.ID {
+		err := fmt.Errorf("webhook delivery with ID [%d] does not exist", request.DeliveryID)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return delivery, false
+	}
+	return delivery, true
+}
+
+func (server *Server) ownedWebhook(ctx *gin.Context, id int64) (db.WebhookEndpoint, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return endpoint, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err != nil || endpoint.Owner != authPayload.Username {
+		err := fmt.Errorf("webhook with ID [%d] does not exist", id)
+		ctx.JSON(http.StatusNotFound, errorResponse(err))
+		return endpoint, false
+	}
+	return endpoint, true
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhookEndpoint(owner string) db.WebhookEndpoint {
+	return db.WebhookEndpoint{
+		ID:         utils.RandomInt(1, 1000),
+		Owner:      owner,
+		Url:        "https://hooks.example.com/" + utils.RandomString(6),
+		Secret:     webhookSecretPrefix + utils.RandomString(32),
+		EventTypes: []string{},
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// staticResolver resolves hosts to fixed addresses instead of asking DNS
+type staticResolver map[string]string
+
+func (r staticResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+	ip, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func Test_CreateWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	resolver := staticResolver{
+		"hooks.example.com": "93.184.216.34",
+		"hooks.internal":    "10.0.0.5",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":         "https://hooks.example.com/bank",
+				"event_types": []string{db.EventTransferCompleted},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.True(t, strings.HasPrefix(arg.Secret, webhookSecretPrefix))
+						require.Equal(t, []string{db.EventTransferCompleted}, arg.EventTypes)
+						return db.WebhookEndpoint{
+							ID:         1,
+							Owner:      arg.Owner,
+							Url:        arg.Url,
+							Secret:     arg.Secret,
+							EventTypes: arg.EventTypes,
+							Active:     true,
+							CreatedAt:  time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var body struct {
+					Data createWebhookResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+				require.True(t, strings.HasPrefix(body.Data.Secret, webhookSecretPrefix))
+				require.Equal(t, "https://hooks.example.com/bank", body.Data.Webhook.URL)
+			},
+		},
+		{
+			name: "InsecureURL",
+			body: gin.H{
+				"url": "http://hooks.example.com/bank",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalHost",
+			body: gin.H{
+				"url": "https://hooks.internal/bank",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MetadataAddress",
+			body: gin.H{
+				"url": "https://169.254.169.254/latest/meta-data",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnresolvableHost",
+			body: gin.H{
+				"url": "https://hooks.example.org/bank",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownEventType",
+			body: gin.H{
+				"url":         "https://hooks.example.com/bank",
+				"event_types": []string{"account.deleted"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.webhookResolver = resolver
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func Test_RedeliverWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	endpoint := randomWebhookEndpoint(user.Username)
+	delivery := db.WebhookDelivery{
+		ID:         utils.RandomInt(1, 1000),
+		EventID:    utils.RandomInt(1, 1000),
+		EndpointID: endpoint.ID,
+		Status:     db.WebhookDeliveryFailed,
+		Attempts:   10,
+		LastError:  "webhook endpoint responded with 500",
+		CreatedAt:  time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				queued := delivery
+				queued.Status = db.WebhookDeliveryPending
+				queued.Attempts = 0
+
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(delivery, nil)
+				store.EXPECT().RedeliverWebhook(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(queued, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EndpointOfAnotherUser",
+			buildStubs: func(store *mockdb.MockStore) {
+				other := endpoint
+				other.Owner = utils.RandomOwner()
+
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(other, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().RedeliverWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "DeliveryOfAnotherEndpoint",
+			buildStubs: func(store *mockdb.MockStore) {
+				other := delivery
+				other.EndpointID = endpoint.ID + 1
+
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(other, nil)
+				store.EXPECT().RedeliverWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "DeliveryNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).Times(1).Return(db.WebhookDelivery{}, sql.ErrNoRows)
+				store.EXPECT().RedeliverWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%d/deliveries/%d/redeliver", endpoint.ID, delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenGenerator, authorizationBearerType, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "mfa_recovery_codes";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_last_counter";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_enabled";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_secret";
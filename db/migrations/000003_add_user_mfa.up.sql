@@ -0,0 +1,19 @@
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "users" ADD COLUMN "totp_enabled" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "users" ADD COLUMN "totp_last_counter" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "mfa_recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "hashed_code" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "mfa_recovery_codes" ("username");
+
+COMMENT ON COLUMN "users"."totp_last_counter" IS 'last accepted TOTP time step, guards against code replay';
+
+ALTER TABLE "mfa_recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
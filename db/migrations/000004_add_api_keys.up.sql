@@ -0,0 +1,23 @@
+CREATE TABLE "api_keys" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "prefix" varchar UNIQUE NOT NULL,
+  "hashed_key" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "allowed_ips" varchar[] NOT NULL,
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "api_keys" ("owner");
+
+COMMENT ON COLUMN "api_keys"."prefix" IS 'public part of the key used to look it up';
+
+COMMENT ON COLUMN "api_keys"."hashed_key" IS 'sha256 of the full key, the key itself is never stored';
+
+COMMENT ON COLUMN "api_keys"."allowed_ips" IS 'IPs or CIDR ranges the key may be used from, empty allows any';
+
+ALTER TABLE "api_keys" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "oauth_revoked_access_tokens";
+DROP TABLE IF EXISTS "oauth_refresh_tokens";
+DROP TABLE IF EXISTS "oauth_authorization_codes";
+DROP TABLE IF EXISTS "oauth_clients";
@@ -0,0 +1,59 @@
+CREATE TABLE "oauth_clients" (
+  "client_id" varchar PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "hashed_secret" varchar NOT NULL DEFAULT '',
+  "redirect_uris" varchar[] NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_authorization_codes" (
+  "code_hash" varchar PRIMARY KEY,
+  "client_id" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "redirect_uri" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "account_ids" bigint[] NOT NULL,
+  "code_challenge" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_refresh_tokens" (
+  "token_hash" varchar PRIMARY KEY,
+  "client_id" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "account_ids" bigint[] NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "oauth_revoked_access_tokens" (
+  "token_id" varchar PRIMARY KEY,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "oauth_clients" ("owner");
+
+CREATE INDEX ON "oauth_refresh_tokens" ("client_id", "username");
+
+COMMENT ON COLUMN "oauth_clients"."hashed_secret" IS 'empty for public clients which must rely on PKCE alone';
+
+COMMENT ON COLUMN "oauth_authorization_codes"."code_challenge" IS 'S256 PKCE challenge';
+
+COMMENT ON COLUMN "oauth_revoked_access_tokens"."expires_at" IS 'rows can be purged once the token would have expired anyway';
+
+ALTER TABLE "oauth_clients" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_authorization_codes" ADD FOREIGN KEY ("client_id") REFERENCES "oauth_clients" ("client_id") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_authorization_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_refresh_tokens" ADD FOREIGN KEY ("client_id") REFERENCES "oauth_clients" ("client_id") ON DELETE CASCADE;
+
+ALTER TABLE "oauth_refresh_tokens" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
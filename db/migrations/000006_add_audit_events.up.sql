@@ -0,0 +1,36 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "resource_type" varchar NOT NULL,
+  "resource_id" varchar NOT NULL,
+  "before" json NOT NULL,
+  "after" json NOT NULL,
+  "ip" varchar NOT NULL,
+  "request_id" varchar NOT NULL,
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "audit_events" ("actor");
+
+CREATE INDEX ON "audit_events" ("resource_type", "resource_id");
+
+CREATE INDEX ON "audit_events" ("created_at");
+
+COMMENT ON COLUMN "audit_events"."before" IS 'json rather than jsonb so the stored text is byte for byte what was hashed';
+
+COMMENT ON COLUMN "audit_events"."hash" IS 'sha256 over prev_hash and the event, chaining every row to the one before it';
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "webhook_attempts";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_endpoints";
+DROP TABLE IF EXISTS "outbox_events";
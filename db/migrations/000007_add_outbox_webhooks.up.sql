@@ -0,0 +1,69 @@
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "owner" varchar NOT NULL,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "dispatched_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_endpoints" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "event_id" bigint NOT NULL,
+  "endpoint_id" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_error" varchar NOT NULL DEFAULT '',
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_attempts" (
+  "id" bigserial PRIMARY KEY,
+  "delivery_id" bigint NOT NULL,
+  "response_status" integer NOT NULL,
+  "error" varchar NOT NULL,
+  "duration_ms" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "outbox_events" ("id") WHERE "dispatched_at" IS NULL;
+
+CREATE INDEX ON "webhook_endpoints" ("owner");
+
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("event_id", "endpoint_id");
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX ON "webhook_attempts" ("delivery_id");
+
+COMMENT ON COLUMN "outbox_events"."owner" IS 'user whose webhook endpoints receive the event';
+
+COMMENT ON COLUMN "outbox_events"."dispatched_at" IS 'set once a delivery row exists for every matching endpoint';
+
+COMMENT ON COLUMN "webhook_endpoints"."secret" IS 'HMAC-SHA256 signing key, kept in clear because it signs every delivery';
+
+COMMENT ON COLUMN "webhook_endpoints"."event_types" IS 'empty subscribes to every event type';
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'pending, succeeded or failed';
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox_events" ("id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webhook_attempts" ADD FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries" ("id") ON DELETE CASCADE;
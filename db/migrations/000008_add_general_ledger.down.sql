@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "postings";
+DROP TABLE IF EXISTS "journals";
+DROP TABLE IF EXISTS "ledger_accounts";
+DROP FUNCTION IF EXISTS postings_balanced();
+DROP FUNCTION IF EXISTS postings_append_only();
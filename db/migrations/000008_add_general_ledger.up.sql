@@ -0,0 +1,95 @@
+CREATE TABLE "ledger_accounts" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar NOT NULL,
+  "name" varchar NOT NULL,
+  "type" varchar NOT NULL,
+  "currency_code" varchar(3) NOT NULL,
+  "balance" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("code", "currency_code")
+);
+
+CREATE TABLE "journals" (
+  "id" bigserial PRIMARY KEY,
+  "description" varchar NOT NULL,
+  "transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "postings" (
+  "id" bigserial PRIMARY KEY,
+  "journal_id" bigint NOT NULL,
+  "account_id" bigint,
+  "ledger_account_id" bigint,
+  "entry_id" bigint,
+  "currency_code" varchar(3) NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK (("account_id" IS NULL) <> ("ledger_account_id" IS NULL)),
+  CHECK ("amount" <> 0)
+);
+
+CREATE INDEX ON "journals" ("transfer_id");
+
+CREATE INDEX ON "postings" ("journal_id");
+
+CREATE INDEX ON "postings" ("account_id");
+
+CREATE INDEX ON "postings" ("ledger_account_id");
+
+COMMENT ON COLUMN "ledger_accounts"."balance" IS 'credits are positive and debits negative, the same sign convention as customer account balances';
+
+COMMENT ON COLUMN "postings"."account_id" IS 'customer account posted to, mutually exclusive with ledger_account_id';
+
+COMMENT ON COLUMN "postings"."entry_id" IS 'statement line written for postings to customer accounts';
+
+ALTER TABLE "journals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("ledger_account_id") REFERENCES "ledger_accounts" ("id");
+
+ALTER TABLE "postings" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+-- the chart of accounts: every system ledger account exists once per supported currency
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency_code")
+SELECT chart.code, chart.name, chart.type, currencies.currency_code
+FROM (VALUES
+  ('cash', 'Cash', 'asset'),
+  ('fees_revenue', 'Fees revenue', 'revenue'),
+  ('fx_gain_loss', 'FX gain/loss', 'revenue'),
+  ('suspense', 'Suspense', 'liability')
+) AS chart (code, name, type)
+CROSS JOIN (VALUES ('USD'), ('EUR'), ('GBP'), ('NGN'), ('AUD'), ('CAD'), ('CDF')) AS currencies (currency_code);
+
+-- journals must balance per currency once the transaction that wrote them commits
+CREATE FUNCTION postings_balanced() RETURNS trigger AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM "postings"
+    WHERE "journal_id" = NEW."journal_id"
+    GROUP BY "currency_code"
+    HAVING SUM("amount") <> 0
+  ) THEN
+    RAISE EXCEPTION 'journal % does not balance', NEW."journal_id" USING ERRCODE = 'check_violation';
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER postings_balanced
+AFTER INSERT ON "postings"
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW EXECUTE FUNCTION postings_balanced();
+
+CREATE FUNCTION postings_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'postings is append-only, post a reversing journal instead';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER postings_append_only
+BEFORE UPDATE OR DELETE ON "postings"
+FOR EACH ROW EXECUTE FUNCTION postings_append_only();
@@ -0,0 +1,13 @@
+CREATE TABLE "balance_snapshots" (
+  "account_id" bigint NOT NULL,
+  "as_of" timestamptz NOT NULL,
+  "balance" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "as_of")
+);
+
+COMMENT ON COLUMN "balance_snapshots"."as_of" IS 'midnight UTC ending the day, the snapshot covers entries created before it';
+
+COMMENT ON COLUMN "balance_snapshots"."balance" IS 'sum of the account''s entries created before as_of';
+
+ALTER TABLE "balance_snapshots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,5 @@
+DELETE FROM "ledger_accounts" WHERE "code" = 'interest_expense';
+DROP TABLE IF EXISTS "interest_postings";
+DROP TABLE IF EXISTS "interest_accruals";
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "product_code";
+DROP TABLE IF EXISTS "account_products";
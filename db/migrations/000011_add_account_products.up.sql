@@ -0,0 +1,57 @@
+CREATE TABLE "account_products" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "interest_rate_bps" bigint NOT NULL DEFAULT 0,
+  "day_count" varchar NOT NULL DEFAULT 'ACT/365',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "account_products"."interest_rate_bps" IS 'annual interest rate in basis points, 250 is 2.50%';
+
+COMMENT ON COLUMN "account_products"."day_count" IS 'day-count convention used to turn the annual rate into a daily one: ACT/365, ACT/360 or ACT/ACT';
+
+INSERT INTO "account_products" ("code", "name", "interest_rate_bps") VALUES
+  ('current', 'Current account', 0),
+  ('savings', 'Savings account', 250);
+
+ALTER TABLE "accounts" ADD COLUMN "product_code" varchar NOT NULL DEFAULT 'current';
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("product_code") REFERENCES "account_products" ("code");
+
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" bigint NOT NULL,
+  "rate_bps" bigint NOT NULL,
+  "day_count" varchar NOT NULL,
+  "amount_micros" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end-of-day balance interest was computed on';
+
+COMMENT ON COLUMN "interest_accruals"."amount_micros" IS 'interest in millionths of the currency''s minor unit, rounded half to even';
+
+CREATE TABLE "interest_postings" (
+  "account_id" bigint NOT NULL,
+  "period_start" date NOT NULL,
+  "period_end" date NOT NULL,
+  "accrued_micros" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "journal_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "period_start")
+);
+
+COMMENT ON COLUMN "interest_postings"."amount" IS 'accrued_micros rounded half to even to the minor unit and paid to the account';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_postings" ADD FOREIGN KEY ("journal_id") REFERENCES "journals" ("id");
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency_code")
+SELECT 'interest_expense', 'Interest expense', 'expense', currencies.currency_code
+FROM (VALUES ('USD'), ('EUR'), ('GBP'), ('NGN'), ('AUD'), ('CAD'), ('CDF')) AS currencies (currency_code);
@@ -0,0 +1,31 @@
+ALTER TABLE "users" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint,
+  "tier" varchar,
+  "currency_code" varchar,
+  "max_amount" bigint,
+  "daily_amount" bigint,
+  "monthly_amount" bigint,
+  "daily_count" bigint,
+  "monthly_count" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("account_id" IS NULL OR ("tier" IS NULL AND "currency_code" IS NULL)),
+  CHECK ("account_id" IS NOT NULL OR "tier" IS NOT NULL OR "currency_code" IS NOT NULL)
+);
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'limits for a single account, they take precedence over tier and currency limits';
+
+COMMENT ON COLUMN "transfer_limits"."tier" IS 'limits for accounts of users in this tier, optionally narrowed to currency_code';
+
+COMMENT ON COLUMN "transfer_limits"."daily_amount" IS 'outgoing total over a rolling 24 hours, a null limit is not enforced';
+
+COMMENT ON COLUMN "transfer_limits"."monthly_amount" IS 'outgoing total over a rolling 30 days, a null limit is not enforced';
+
+CREATE UNIQUE INDEX "transfer_limits_scope_key" ON "transfer_limits" (COALESCE("account_id", 0), COALESCE("tier", ''), COALESCE("currency_code", ''));
+
+CREATE INDEX ON "transfers" ("from_account_id", "created_at");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "kyc_documents";
+DROP TABLE IF EXISTS "kyc_profiles";
+DELETE FROM "transfer_limits" WHERE "tier" IN ('standard', 'verified', 'enhanced') AND "currency_code" IS NULL;
+DROP TABLE IF EXISTS "kyc_tiers";
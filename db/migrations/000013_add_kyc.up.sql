@@ -0,0 +1,71 @@
+CREATE TABLE "kyc_tiers" (
+  "tier" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "level" int NOT NULL,
+  "allowed_products" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "kyc_tiers"."allowed_products" IS 'account products users in this tier may open';
+
+INSERT INTO "kyc_tiers" ("tier", "name", "level", "allowed_products") VALUES
+  ('standard', 'Unverified', 0, '{current}'),
+  ('verified', 'Verified identity', 1, '{current,savings}'),
+  ('enhanced', 'Enhanced due diligence', 2, '{current,savings}');
+
+INSERT INTO "transfer_limits" ("tier", "max_amount", "daily_amount", "monthly_amount", "daily_count", "monthly_count") VALUES
+  ('standard', 50000, 100000, 500000, 20, 200),
+  ('verified', 500000, 1000000, 10000000, 100, 1000),
+  ('enhanced', 5000000, 20000000, 200000000, NULL, NULL);
+
+CREATE TABLE "kyc_profiles" (
+  "username" varchar PRIMARY KEY,
+  "status" varchar NOT NULL,
+  "requested_tier" varchar NOT NULL,
+  "date_of_birth" date NOT NULL,
+  "phone_number" varchar NOT NULL,
+  "address_line1" varchar NOT NULL,
+  "address_line2" varchar NOT NULL DEFAULT '',
+  "city" varchar NOT NULL,
+  "postal_code" varchar NOT NULL,
+  "country_code" varchar NOT NULL,
+  "nationality" varchar NOT NULL,
+  "id_number" varchar NOT NULL,
+  "provider" varchar NOT NULL,
+  "provider_reference" varchar NOT NULL,
+  "provider_outcome" varchar NOT NULL,
+  "provider_reasons" varchar[] NOT NULL,
+  "reviewer" varchar,
+  "review_note" varchar NOT NULL DEFAULT '',
+  "submitted_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "kyc_profiles"."status" IS 'pending, approved or rejected, a submission moves it to pending and a review out of it';
+
+COMMENT ON COLUMN "kyc_profiles"."provider_outcome" IS 'clear or consider, the identity check is advice for the reviewer and never decides on its own';
+
+CREATE INDEX ON "kyc_profiles" ("status", "submitted_at");
+
+CREATE TABLE "kyc_documents" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "document_type" varchar NOT NULL,
+  "file_name" varchar NOT NULL,
+  "content_type" varchar NOT NULL,
+  "size_bytes" bigint NOT NULL,
+  "sha256" varchar NOT NULL,
+  "storage_key" varchar UNIQUE NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "kyc_documents"."storage_key" IS 'where the file lives in document storage, only its metadata is kept here';
+
+CREATE INDEX ON "kyc_documents" ("username");
+
+ALTER TABLE "kyc_profiles" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "kyc_profiles" ADD FOREIGN KEY ("requested_tier") REFERENCES "kyc_tiers" ("tier");
+
+ALTER TABLE "kyc_documents" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
@@ -0,0 +1,31 @@
+CREATE TABLE "fraud_cases" (
+  "id" bigserial PRIMARY KEY,
+  "status" varchar NOT NULL,
+  "decision" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "initiated_by" varchar NOT NULL,
+  "verdicts" jsonb NOT NULL,
+  "transfer_id" bigint,
+  "reviewer" varchar,
+  "review_note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz
+);
+
+COMMENT ON COLUMN "fraud_cases"."status" IS 'open while held for review, then approved or rejected, blocked transfers are recorded as blocked';
+
+COMMENT ON COLUMN "fraud_cases"."verdicts" IS 'the rules that held or blocked the transfer and why';
+
+COMMENT ON COLUMN "fraud_cases"."transfer_id" IS 'the transfer made once the case was approved';
+
+CREATE INDEX ON "fraud_cases" ("status", "created_at");
+
+CREATE INDEX ON "fraud_cases" ("initiated_by");
+
+ALTER TABLE "fraud_cases" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "fraud_cases" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "fraud_cases" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
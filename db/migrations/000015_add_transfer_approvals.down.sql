@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "transfer_approvals";
+DROP TABLE IF EXISTS "pending_transfers";
+DROP TABLE IF EXISTS "approval_policies";
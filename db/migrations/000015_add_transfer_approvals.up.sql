@@ -0,0 +1,70 @@
+CREATE TABLE "approval_policies" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "min_amount" bigint NOT NULL,
+  "required_approvals" integer NOT NULL,
+  "approver_role" varchar,
+  "approvers" varchar[] NOT NULL DEFAULT '{}',
+  "expiry_seconds" integer NOT NULL DEFAULT 86400,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "approval_policies_min_amount_check" CHECK ("min_amount" > 0),
+  CONSTRAINT "approval_policies_required_approvals_check" CHECK ("required_approvals" > 0),
+  CONSTRAINT "approval_policies_expiry_seconds_check" CHECK ("expiry_seconds" > 0)
+);
+
+COMMENT ON COLUMN "approval_policies"."min_amount" IS 'transfers of at least this amount need approval, the policy with the highest threshold applies';
+
+COMMENT ON COLUMN "approval_policies"."approver_role" IS 'users with this role may approve, along with the named approvers';
+
+CREATE UNIQUE INDEX ON "approval_policies" ("account_id", "min_amount");
+
+ALTER TABLE "approval_policies" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+CREATE TABLE "pending_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "initiated_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "policy_id" bigint,
+  "required_approvals" integer NOT NULL,
+  "approver_role" varchar,
+  "approvers" varchar[] NOT NULL DEFAULT '{}',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "decided_at" timestamptz
+);
+
+COMMENT ON COLUMN "pending_transfers"."status" IS 'pending until the quorum executes it, a rejection or expiry';
+
+COMMENT ON COLUMN "pending_transfers"."required_approvals" IS 'copied from the policy, so changing a policy leaves pending transfers alone';
+
+CREATE INDEX ON "pending_transfers" ("from_account_id", "status");
+
+CREATE INDEX ON "pending_transfers" ("status", "expires_at");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("policy_id") REFERENCES "approval_policies" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+CREATE TABLE "transfer_approvals" (
+  "pending_transfer_id" bigint NOT NULL,
+  "approver" varchar NOT NULL,
+  "decision" varchar NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("pending_transfer_id", "approver")
+);
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("pending_transfer_id") REFERENCES "pending_transfers" ("id");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("approver") REFERENCES "users" ("username");
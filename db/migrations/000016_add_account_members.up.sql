@@ -0,0 +1,26 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "spend_limit" bigint,
+  "status" varchar NOT NULL DEFAULT 'invited',
+  "invited_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "accepted_at" timestamptz,
+  PRIMARY KEY ("account_id", "username"),
+  CONSTRAINT "account_members_spend_limit_check" CHECK ("spend_limit" > 0)
+);
+
+COMMENT ON COLUMN "account_members"."role" IS 'co_owner, spender or viewer, the account owner is accounts.owner';
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'the most a spender can send in one transfer';
+
+COMMENT ON COLUMN "account_members"."status" IS 'invited until the user accepts, then active';
+
+CREATE INDEX ON "account_members" ("username", "status");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "pots";
+
+DROP INDEX IF EXISTS "owner_currency_code_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_code_key" UNIQUE ("owner", "currency_code");
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "parent_account_id";
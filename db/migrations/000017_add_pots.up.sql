@@ -0,0 +1,34 @@
+ALTER TABLE "accounts" ADD COLUMN "parent_account_id" bigint;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("parent_account_id") REFERENCES "accounts" ("id");
+
+COMMENT ON COLUMN "accounts"."parent_account_id" IS 'set on the accounts that hold a pot''s money';
+
+-- pots share their parent's owner and currency, so only top level accounts are unique per currency
+ALTER TABLE "accounts" DROP CONSTRAINT "owner_currency_code_key";
+
+CREATE UNIQUE INDEX "owner_currency_code_key" ON "accounts" ("owner", "currency_code") WHERE "parent_account_id" IS NULL;
+
+CREATE TABLE "pots" (
+  "account_id" bigint PRIMARY KEY,
+  "parent_account_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "target_amount" bigint,
+  "target_date" date,
+  "round_up_unit" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "pots_target_amount_check" CHECK ("target_amount" > 0),
+  CONSTRAINT "pots_round_up_unit_check" CHECK ("round_up_unit" > 0)
+);
+
+COMMENT ON COLUMN "pots"."round_up_unit" IS 'outgoing transfers from the parent are rounded up to a multiple of this and the difference swept into the pot';
+
+CREATE UNIQUE INDEX ON "pots" ("parent_account_id", "name");
+
+-- spare change goes to a single pot
+CREATE UNIQUE INDEX "pots_round_up_key" ON "pots" ("parent_account_id") WHERE "round_up_unit" IS NOT NULL;
+
+ALTER TABLE "pots" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pots" ADD FOREIGN KEY ("parent_account_id") REFERENCES "accounts" ("id");
@@ -0,0 +1,37 @@
+DELETE FROM "transfer_limits" WHERE "product_code" IS NOT NULL;
+
+DROP INDEX IF EXISTS "transfer_limits_scope_key";
+
+CREATE UNIQUE INDEX "transfer_limits_scope_key" ON "transfer_limits" (COALESCE("account_id", 0), COALESCE("tier", ''), COALESCE("currency_code", ''));
+
+ALTER TABLE "transfer_limits" DROP CONSTRAINT IF EXISTS "transfer_limits_check";
+
+ALTER TABLE "transfer_limits" DROP CONSTRAINT IF EXISTS "transfer_limits_check1";
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_check"
+  CHECK ("account_id" IS NULL OR ("tier" IS NULL AND "currency_code" IS NULL));
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_check1"
+  CHECK ("account_id" IS NOT NULL OR "tier" IS NOT NULL OR "currency_code" IS NOT NULL);
+
+ALTER TABLE "transfer_limits" DROP COLUMN IF EXISTS "product_code";
+
+DROP INDEX IF EXISTS "accounts_owner_idx";
+
+CREATE UNIQUE INDEX "owner_currency_code_key" ON "accounts" ("owner", "currency_code") WHERE "parent_account_id" IS NULL;
+
+INSERT INTO "account_products" ("code", "name", "interest_rate_bps") VALUES
+  ('current', 'Current account', 0);
+
+UPDATE "accounts" SET "product_code" = 'current' WHERE "product_code" IN ('checking', 'escrow', 'business');
+
+ALTER TABLE "accounts" ALTER COLUMN "product_code" SET DEFAULT 'current';
+
+UPDATE "kyc_tiers" SET "allowed_products" = CASE "tier"
+  WHEN 'standard' THEN '{current}'::varchar[]
+  ELSE '{current,savings}'::varchar[]
+END;
+
+DELETE FROM "account_products" WHERE "code" IN ('checking', 'escrow', 'business');
+
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "overdraft_limit";
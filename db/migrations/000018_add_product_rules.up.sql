@@ -0,0 +1,48 @@
+ALTER TABLE "account_products" ADD COLUMN "overdraft_limit" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "account_products"."overdraft_limit" IS 'how far below zero a transfer may take the balance';
+
+INSERT INTO "account_products" ("code", "name", "interest_rate_bps", "overdraft_limit") VALUES
+  ('checking', 'Checking account', 0, 50000),
+  ('escrow', 'Escrow account', 0, 0),
+  ('business', 'Business account', 0, 500000);
+
+UPDATE "accounts" SET "product_code" = 'checking' WHERE "product_code" = 'current';
+
+ALTER TABLE "accounts" ALTER COLUMN "product_code" DROP DEFAULT;
+
+UPDATE "kyc_tiers" SET "allowed_products" = CASE "tier"
+  WHEN 'standard' THEN '{checking}'::varchar[]
+  WHEN 'verified' THEN '{checking,savings,escrow}'::varchar[]
+  ELSE '{checking,savings,escrow,business}'::varchar[]
+END;
+
+DELETE FROM "account_products" WHERE "code" = 'current';
+
+-- users can hold any number of accounts in a currency, one per product or more
+DROP INDEX IF EXISTS "owner_currency_code_key";
+
+CREATE INDEX ON "accounts" ("owner");
+
+ALTER TABLE "transfer_limits" ADD COLUMN "product_code" varchar;
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("product_code") REFERENCES "account_products" ("code");
+
+COMMENT ON COLUMN "transfer_limits"."product_code" IS 'limits for accounts of this product, they take precedence over tier and currency limits';
+
+ALTER TABLE "transfer_limits" DROP CONSTRAINT "transfer_limits_check";
+
+ALTER TABLE "transfer_limits" DROP CONSTRAINT "transfer_limits_check1";
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_check"
+  CHECK ("account_id" IS NULL OR ("product_code" IS NULL AND "tier" IS NULL AND "currency_code" IS NULL));
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limits_check1"
+  CHECK ("account_id" IS NOT NULL OR "product_code" IS NOT NULL OR "tier" IS NOT NULL OR "currency_code" IS NOT NULL);
+
+DROP INDEX "transfer_limits_scope_key";
+
+CREATE UNIQUE INDEX "transfer_limits_scope_key" ON "transfer_limits" (COALESCE("account_id", 0), COALESCE("product_code", ''), COALESCE("tier", ''), COALESCE("currency_code", ''));
+
+INSERT INTO "transfer_limits" ("product_code", "monthly_count") VALUES
+  ('savings', 6);
@@ -0,0 +1,3 @@
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "account_number";
+
+DROP SEQUENCE IF EXISTS "account_number_seq";
@@ -0,0 +1,7 @@
+CREATE SEQUENCE "account_number_seq";
+
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar;
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'external account number, the bank code, a sequence and check digits; accounts opened earlier get one from `simple-bank account-numbers`';
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
@@ -0,0 +1,20 @@
+CREATE TABLE "aliases" (
+  "alias" varchar PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "code_expires_at" timestamptz NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "verified_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "aliases"."alias" IS 'normalized email address or phone number, payments to it go to username';
+
+COMMENT ON COLUMN "aliases"."kind" IS 'email or phone';
+
+COMMENT ON COLUMN "aliases"."verified_at" IS 'aliases only resolve once verified, until then anyone can claim them again';
+
+CREATE INDEX ON "aliases" ("username");
+
+ALTER TABLE "aliases" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON DELETE CASCADE;
@@ -0,0 +1,20 @@
+CREATE TABLE "beneficiaries" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "nickname" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "beneficiaries"."owner" IS 'the user who saved the payee';
+
+COMMENT ON COLUMN "beneficiaries"."created_at" IS 'large transfers to the payee are refused until the cooling-off period after this has passed';
+
+CREATE UNIQUE INDEX ON "beneficiaries" ("owner", "account_id");
+
+CREATE INDEX ON "beneficiaries" ("owner", "created_at");
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username") ON DELETE CASCADE;
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
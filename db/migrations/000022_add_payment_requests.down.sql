@@ -0,0 +1,5 @@
+ALTER TABLE "fraud_cases" DROP COLUMN IF EXISTS "payment_request_id";
+
+ALTER TABLE "pending_transfers" DROP COLUMN IF EXISTS "payment_request_id";
+
+DROP TABLE IF EXISTS "payment_requests";
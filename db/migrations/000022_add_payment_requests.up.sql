@@ -0,0 +1,47 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency_code" varchar NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz,
+  CONSTRAINT "payment_requests_amount_check" CHECK ("amount" > 0)
+);
+
+COMMENT ON COLUMN "payment_requests"."to_account_id" IS 'the requester''s account the payment goes to';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending until paid, declined by the payer, cancelled by the requester or expired';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'the transfer that paid the request';
+
+CREATE INDEX ON "payment_requests" ("payer", "created_at");
+
+CREATE INDEX ON "payment_requests" ("requester", "created_at");
+
+CREATE INDEX ON "payment_requests" ("status", "expires_at");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "pending_transfers" ADD COLUMN "payment_request_id" bigint;
+
+COMMENT ON COLUMN "pending_transfers"."payment_request_id" IS 'the payment request the transfer pays, settled when the transfer is made';
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("payment_request_id") REFERENCES "payment_requests" ("id");
+
+ALTER TABLE "fraud_cases" ADD COLUMN "payment_request_id" bigint;
+
+COMMENT ON COLUMN "fraud_cases"."payment_request_id" IS 'the payment request the transfer pays, settled when the transfer is made';
+
+ALTER TABLE "fraud_cases" ADD FOREIGN KEY ("payment_request_id") REFERENCES "payment_requests" ("id");
@@ -0,0 +1,9 @@
+ALTER TABLE "fraud_cases" DROP COLUMN IF EXISTS "invoice_id";
+
+ALTER TABLE "pending_transfers" DROP COLUMN IF EXISTS "invoice_id";
+
+DROP TABLE IF EXISTS "invoice_payments";
+
+DROP TABLE IF EXISTS "invoice_items";
+
+DROP TABLE IF EXISTS "invoices";
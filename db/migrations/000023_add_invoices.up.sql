@@ -0,0 +1,81 @@
+CREATE TABLE "invoices" (
+  "id" bigserial PRIMARY KEY,
+  "merchant" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "customer" varchar NOT NULL DEFAULT '',
+  "currency_code" varchar NOT NULL,
+  "subtotal" bigint NOT NULL,
+  "tax_rate_bps" integer NOT NULL DEFAULT 0,
+  "tax_amount" bigint NOT NULL DEFAULT 0,
+  "total" bigint NOT NULL,
+  "amount_paid" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'draft',
+  "pay_token" varchar UNIQUE NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "due_date" date NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "sent_at" timestamptz,
+  "paid_at" timestamptz,
+  "voided_at" timestamptz,
+  CONSTRAINT "invoices_total_check" CHECK ("total" > 0),
+  CONSTRAINT "invoices_tax_rate_bps_check" CHECK ("tax_rate_bps" BETWEEN 0 AND 10000),
+  CONSTRAINT "invoices_amount_paid_check" CHECK ("amount_paid" BETWEEN 0 AND "total")
+);
+
+CREATE TABLE "invoice_items" (
+  "id" bigserial PRIMARY KEY,
+  "invoice_id" bigint NOT NULL,
+  "description" varchar NOT NULL,
+  "quantity" bigint NOT NULL,
+  "unit_amount" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  CONSTRAINT "invoice_items_quantity_check" CHECK ("quantity" > 0),
+  CONSTRAINT "invoice_items_unit_amount_check" CHECK ("unit_amount" >= 0)
+);
+
+CREATE TABLE "invoice_payments" (
+  "id" bigserial PRIMARY KEY,
+  "invoice_id" bigint NOT NULL,
+  "transfer_id" bigint UNIQUE NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "invoices"."to_account_id" IS 'the merchant''s account payments go to';
+
+COMMENT ON COLUMN "invoices"."tax_rate_bps" IS 'tax charged on the subtotal in basis points, 750 is 7.5%';
+
+COMMENT ON COLUMN "invoices"."status" IS 'draft, sent, paid, overdue or void. Partly paid invoices stay sent or overdue until paid in full';
+
+COMMENT ON COLUMN "invoices"."pay_token" IS 'the secret in the invoice''s pay link';
+
+CREATE INDEX ON "invoices" ("merchant", "created_at");
+
+CREATE INDEX ON "invoices" ("status", "due_date");
+
+CREATE INDEX ON "invoice_items" ("invoice_id");
+
+CREATE INDEX ON "invoice_payments" ("invoice_id");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("merchant") REFERENCES "users" ("username");
+
+ALTER TABLE "invoices" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "invoice_items" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "invoice_payments" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id");
+
+ALTER TABLE "invoice_payments" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "pending_transfers" ADD COLUMN "invoice_id" bigint;
+
+COMMENT ON COLUMN "pending_transfers"."invoice_id" IS 'the invoice the transfer pays, settled when the transfer is made';
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id");
+
+ALTER TABLE "fraud_cases" ADD COLUMN "invoice_id" bigint;
+
+COMMENT ON COLUMN "fraud_cases"."invoice_id" IS 'the invoice the transfer pays, settled when the transfer is made';
+
+ALTER TABLE "fraud_cases" ADD FOREIGN KEY ("invoice_id") REFERENCES "invoices" ("id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "escrow_contracts";
+
+DELETE FROM "ledger_accounts" WHERE "code" = 'escrow';
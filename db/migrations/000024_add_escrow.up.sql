@@ -0,0 +1,56 @@
+CREATE TABLE "escrow_contracts" (
+  "id" bigserial PRIMARY KEY,
+  "buyer" varchar NOT NULL,
+  "seller" varchar NOT NULL,
+  "buyer_account_id" bigint NOT NULL,
+  "seller_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency_code" varchar NOT NULL,
+  "description" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'funded',
+  "release_at" timestamptz NOT NULL,
+  "fund_journal_id" bigint,
+  "settle_journal_id" bigint,
+  "disputed_by" varchar,
+  "dispute_reason" varchar NOT NULL DEFAULT '',
+  "disputed_at" timestamptz,
+  "resolved_by" varchar,
+  "resolution_note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz,
+  CONSTRAINT "escrow_contracts_amount_check" CHECK ("amount" > 0)
+);
+
+COMMENT ON COLUMN "escrow_contracts"."status" IS 'funded until the buyer confirms delivery or release_at passes, disputed until an admin releases or refunds it';
+
+COMMENT ON COLUMN "escrow_contracts"."release_at" IS 'when the funds go to the seller if the buyer neither confirms nor disputes';
+
+COMMENT ON COLUMN "escrow_contracts"."fund_journal_id" IS 'the journal that moved the buyer''s money into the escrow ledger account';
+
+COMMENT ON COLUMN "escrow_contracts"."settle_journal_id" IS 'the journal that paid the seller or refunded the buyer';
+
+CREATE INDEX ON "escrow_contracts" ("buyer", "created_at");
+
+CREATE INDEX ON "escrow_contracts" ("seller", "created_at");
+
+CREATE INDEX ON "escrow_contracts" ("status", "release_at");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("buyer") REFERENCES "users" ("username");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("seller") REFERENCES "users" ("username");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("buyer_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("seller_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("fund_journal_id") REFERENCES "journals" ("id");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("settle_journal_id") REFERENCES "journals" ("id");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("disputed_by") REFERENCES "users" ("username");
+
+ALTER TABLE "escrow_contracts" ADD FOREIGN KEY ("resolved_by") REFERENCES "users" ("username");
+
+INSERT INTO "ledger_accounts" ("code", "name", "type", "currency_code")
+SELECT 'escrow', 'Escrow', 'liability', currencies.currency_code
+FROM (VALUES ('USD'), ('EUR'), ('GBP'), ('NGN'), ('AUD'), ('CAD'), ('CDF')) AS currencies (currency_code);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "escrow_contracts_buyer_account_id_created_at_idx";
+
+ALTER TABLE "fraud_cases" DROP COLUMN IF EXISTS "escrow_description";
+
+ALTER TABLE "fraud_cases" DROP COLUMN IF EXISTS "escrow_release_seconds";
+
+ALTER TABLE "pending_transfers" DROP COLUMN IF EXISTS "escrow_description";
+
+ALTER TABLE "pending_transfers" DROP COLUMN IF EXISTS "escrow_release_seconds";
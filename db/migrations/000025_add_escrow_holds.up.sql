@@ -0,0 +1,13 @@
+ALTER TABLE "pending_transfers" ADD COLUMN "escrow_release_seconds" bigint;
+
+ALTER TABLE "pending_transfers" ADD COLUMN "escrow_description" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "pending_transfers"."escrow_release_seconds" IS 'set when the transfer funds an escrow contract, which releases this long after it is funded';
+
+ALTER TABLE "fraud_cases" ADD COLUMN "escrow_release_seconds" bigint;
+
+ALTER TABLE "fraud_cases" ADD COLUMN "escrow_description" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "fraud_cases"."escrow_release_seconds" IS 'set when the transfer funds an escrow contract, which releases this long after it is funded';
+
+CREATE INDEX ON "escrow_contracts" ("buyer_account_id", "created_at");
@@ -0,0 +1,7 @@
+ALTER TABLE "users" ADD COLUMN "totp_failed_attempts" integer NOT NULL DEFAULT 0;
+
+ALTER TABLE "users" ADD COLUMN "totp_locked_until" timestamptz;
+
+COMMENT ON COLUMN "users"."totp_failed_attempts" IS 'invalid second factor codes since the last accepted one or lockout';
+
+COMMENT ON COLUMN "users"."totp_locked_until" IS 'second factor codes are refused until then after too many invalid ones';
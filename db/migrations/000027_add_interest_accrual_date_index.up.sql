@@ -0,0 +1 @@
+CREATE INDEX "interest_accruals_accrual_date_idx" ON "interest_accruals" ("accrual_date");
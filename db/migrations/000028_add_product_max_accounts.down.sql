@@ -0,0 +1 @@
+ALTER TABLE "account_products" DROP COLUMN IF EXISTS "max_accounts";
@@ -0,0 +1,10 @@
+ALTER TABLE "account_products" ADD COLUMN "max_accounts" integer;
+
+COMMENT ON COLUMN "account_products"."max_accounts" IS 'how many accounts of the product, pots aside, a user may hold in each currency, no limit when NULL';
+
+UPDATE "account_products" SET "max_accounts" = CASE "code"
+  WHEN 'checking' THEN 1
+  WHEN 'savings' THEN 5
+  WHEN 'escrow' THEN 1
+  WHEN 'business' THEN 3
+END;
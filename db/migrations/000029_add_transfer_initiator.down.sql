@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'the most a spender can send in one transfer';
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "initiated_by";
@@ -0,0 +1,9 @@
+ALTER TABLE "transfers" ADD COLUMN "initiated_by" varchar;
+
+COMMENT ON COLUMN "transfers"."initiated_by" IS 'the user who sent the transfer, NULL for transfers the bank made itself';
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("initiated_by") REFERENCES "users" ("username");
+
+CREATE INDEX ON "transfers" ("from_account_id", "initiated_by", "created_at");
+
+COMMENT ON COLUMN "account_members"."spend_limit" IS 'the most a spender can send from the account in a rolling 24 hours';
@@ -0,0 +1,3 @@
+COMMENT ON COLUMN "audit_events"."hash" IS 'sha256 over prev_hash and the event, chaining every row to the one before it';
+
+ALTER TABLE "audit_events" DROP COLUMN IF EXISTS "chain";
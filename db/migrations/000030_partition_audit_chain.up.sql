@@ -0,0 +1,7 @@
+ALTER TABLE "audit_events" ADD COLUMN "chain" integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "audit_events"."chain" IS 'which of the hash chains the event links into, picked from its resource so unrelated writes don''t wait on each other';
+
+COMMENT ON COLUMN "audit_events"."hash" IS 'sha256 over prev_hash and the event, chaining every row to the one before it in its chain';
+
+CREATE INDEX ON "audit_events" ("chain", "id");
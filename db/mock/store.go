@@ -6,7 +6,9 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/caleberi/simple-bank/db/sqlc"
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +37,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptAccountMember mocks base method.
+func (m *MockStore) AcceptAccountMember(arg0 context.Context, arg1 db.AcceptAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptAccountMember indicates an expected call of AcceptAccountMember.
+func (mr *MockStoreMockRecorder) AcceptAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAccountMember", reflect.TypeOf((*MockStore)(nil).AcceptAccountMember), arg0, arg1)
+}
+
 // AddAccountBalance mocks base method.
 func (m *MockStore) AddAccountBalance(arg0 context.Context, arg1 db.AddAccountBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +67,201 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
+// AddLedgerAccountBalance mocks base method.
+func (m *MockStore) AddLedgerAccountBalance(arg0 context.Context, arg1 db.AddLedgerAccountBalanceParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLedgerAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLedgerAccountBalance indicates an expected call of AddLedgerAccountBalance.
+func (mr *MockStoreMockRecorder) AddLedgerAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLedgerAccountBalance", reflect.TypeOf((*MockStore)(nil).AddLedgerAccountBalance), arg0, arg1)
+}
+
+// ApproveFraudCaseTrxn mocks base method.
+func (m *MockStore) ApproveFraudCaseTrxn(arg0 context.Context, arg1 db.ApproveFraudCaseTxnParams) (db.ApproveFraudCaseTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveFraudCaseTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.ApproveFraudCaseTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveFraudCaseTrxn indicates an expected call of ApproveFraudCaseTrxn.
+func (mr *MockStoreMockRecorder) ApproveFraudCaseTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveFraudCaseTrxn", reflect.TypeOf((*MockStore)(nil).ApproveFraudCaseTrxn), arg0, arg1)
+}
+
+// ClaimAlias mocks base method.
+func (m *MockStore) ClaimAlias(arg0 context.Context, arg1 db.ClaimAliasParams) (db.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimAlias", arg0, arg1)
+	ret0, _ := ret[0].(db.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimAlias indicates an expected call of ClaimAlias.
+func (mr *MockStoreMockRecorder) ClaimAlias(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimAlias", reflect.TypeOf((*MockStore)(nil).ClaimAlias), arg0, arg1)
+}
+
+// ClaimDueWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimDueWebhookDeliveries(arg0 context.Context, arg1 db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueWebhookDeliveries indicates an expected call of ClaimDueWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimDueWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimDueWebhookDeliveries), arg0, arg1)
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockStore) ClaimOutboxEvents(arg0 context.Context, arg1 int32) ([]db.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockStoreMockRecorder) ClaimOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockStore)(nil).ClaimOutboxEvents), arg0, arg1)
+}
+
+// CloseEscrowContract mocks base method.
+func (m *MockStore) CloseEscrowContract(arg0 context.Context, arg1 db.CloseEscrowContractParams) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseEscrowContract", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseEscrowContract indicates an expected call of CloseEscrowContract.
+func (mr *MockStoreMockRecorder) CloseEscrowContract(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseEscrowContract", reflect.TypeOf((*MockStore)(nil).CloseEscrowContract), arg0, arg1)
+}
+
+// CloseFraudCase mocks base method.
+func (m *MockStore) CloseFraudCase(arg0 context.Context, arg1 db.CloseFraudCaseParams) (db.FraudCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseFraudCase", arg0, arg1)
+	ret0, _ := ret[0].(db.FraudCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseFraudCase indicates an expected call of CloseFraudCase.
+func (mr *MockStoreMockRecorder) CloseFraudCase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseFraudCase", reflect.TypeOf((*MockStore)(nil).CloseFraudCase), arg0, arg1)
+}
+
+// ClosePaymentRequest mocks base method.
+func (m *MockStore) ClosePaymentRequest(arg0 context.Context, arg1 db.ClosePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePaymentRequest indicates an expected call of ClosePaymentRequest.
+func (mr *MockStoreMockRecorder) ClosePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePaymentRequest", reflect.TypeOf((*MockStore)(nil).ClosePaymentRequest), arg0, arg1)
+}
+
+// ClosePaymentRequestTrxn mocks base method.
+func (m *MockStore) ClosePaymentRequestTrxn(arg0 context.Context, arg1 db.ClosePaymentRequestTxnParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePaymentRequestTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePaymentRequestTrxn indicates an expected call of ClosePaymentRequestTrxn.
+func (mr *MockStoreMockRecorder) ClosePaymentRequestTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePaymentRequestTrxn", reflect.TypeOf((*MockStore)(nil).ClosePaymentRequestTrxn), arg0, arg1)
+}
+
+// ClosePendingTransfer mocks base method.
+func (m *MockStore) ClosePendingTransfer(arg0 context.Context, arg1 db.ClosePendingTransferParams) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosePendingTransfer indicates an expected call of ClosePendingTransfer.
+func (mr *MockStoreMockRecorder) ClosePendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePendingTransfer", reflect.TypeOf((*MockStore)(nil).ClosePendingTransfer), arg0, arg1)
+}
+
+// CountBeneficiariesAddedSince mocks base method.
+func (m *MockStore) CountBeneficiariesAddedSince(arg0 context.Context, arg1 db.CountBeneficiariesAddedSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBeneficiariesAddedSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBeneficiariesAddedSince indicates an expected call of CountBeneficiariesAddedSince.
+func (mr *MockStoreMockRecorder) CountBeneficiariesAddedSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBeneficiariesAddedSince", reflect.TypeOf((*MockStore)(nil).CountBeneficiariesAddedSince), arg0, arg1)
+}
+
+// CountOwnerProductAccounts mocks base method.
+func (m *MockStore) CountOwnerProductAccounts(arg0 context.Context, arg1 db.CountOwnerProductAccountsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOwnerProductAccounts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOwnerProductAccounts indicates an expected call of CountOwnerProductAccounts.
+func (mr *MockStoreMockRecorder) CountOwnerProductAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOwnerProductAccounts", reflect.TypeOf((*MockStore)(nil).CountOwnerProductAccounts), arg0, arg1)
+}
+
+// CountTransfersBetween mocks base method.
+func (m *MockStore) CountTransfersBetween(arg0 context.Context, arg1 db.CountTransfersBetweenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransfersBetween", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransfersBetween indicates an expected call of CountTransfersBetween.
+func (mr *MockStoreMockRecorder) CountTransfersBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransfersBetween", reflect.TypeOf((*MockStore)(nil).CountTransfersBetween), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +277,95 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateAccountMember mocks base method.
+func (m *MockStore) CreateAccountMember(arg0 context.Context, arg1 db.CreateAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountMember indicates an expected call of CreateAccountMember.
+func (mr *MockStoreMockRecorder) CreateAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountMember", reflect.TypeOf((*MockStore)(nil).CreateAccountMember), arg0, arg1)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockStore) CreateApiKey(arg0 context.Context, arg1 db.CreateApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockStoreMockRecorder) CreateApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockStore)(nil).CreateApiKey), arg0, arg1)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(arg0 context.Context, arg1 db.CreateAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), arg0, arg1)
+}
+
+// CreateAuthorizationCode mocks base method.
+func (m *MockStore) CreateAuthorizationCode(arg0 context.Context, arg1 db.CreateAuthorizationCodeParams) (db.OauthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthorizationCode indicates an expected call of CreateAuthorizationCode.
+func (mr *MockStoreMockRecorder) CreateAuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthorizationCode", reflect.TypeOf((*MockStore)(nil).CreateAuthorizationCode), arg0, arg1)
+}
+
+// CreateBalanceSnapshots mocks base method.
+func (m *MockStore) CreateBalanceSnapshots(arg0 context.Context, arg1 db.CreateBalanceSnapshotsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBalanceSnapshots indicates an expected call of CreateBalanceSnapshots.
+func (mr *MockStoreMockRecorder) CreateBalanceSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceSnapshots", reflect.TypeOf((*MockStore)(nil).CreateBalanceSnapshots), arg0, arg1)
+}
+
+// CreateBeneficiary mocks base method.
+func (m *MockStore) CreateBeneficiary(arg0 context.Context, arg1 db.CreateBeneficiaryParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBeneficiary indicates an expected call of CreateBeneficiary.
+func (mr *MockStoreMockRecorder) CreateBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBeneficiary", reflect.TypeOf((*MockStore)(nil).CreateBeneficiary), arg0, arg1)
+}
+
 // CreateEntry mocks base method.
 func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
 	m.ctrl.T.Helper()
@@ -80,196 +381,2912 @@ func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// CreateEscrowContract mocks base method.
+func (m *MockStore) CreateEscrowContract(arg0 context.Context, arg1 db.CreateEscrowContractParams) (db.EscrowContract, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "CreateEscrowContract", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CreateEscrowContract indicates an expected call of CreateEscrowContract.
+func (mr *MockStoreMockRecorder) CreateEscrowContract(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrowContract", reflect.TypeOf((*MockStore)(nil).CreateEscrowContract), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// CreateEscrowTrxn mocks base method.
+func (m *MockStore) CreateEscrowTrxn(arg0 context.Context, arg1 db.CreateEscrowContractParams) (db.EscrowTxnResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CreateEscrowTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxnResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateEscrowTrxn indicates an expected call of CreateEscrowTrxn.
+func (mr *MockStoreMockRecorder) CreateEscrowTrxn(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEscrowTrxn", reflect.TypeOf((*MockStore)(nil).CreateEscrowTrxn), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// CreateFraudCase mocks base method.
+func (m *MockStore) CreateFraudCase(arg0 context.Context, arg1 db.CreateFraudCaseParams) (db.FraudCase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateFraudCase", arg0, arg1)
+	ret0, _ := ret[0].(db.FraudCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFraudCase indicates an expected call of CreateFraudCase.
+func (mr *MockStoreMockRecorder) CreateFraudCase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFraudCase", reflect.TypeOf((*MockStore)(nil).CreateFraudCase), arg0, arg1)
+}
+
+// CreateInterestAccrual mocks base method.
+func (m *MockStore) CreateInterestAccrual(arg0 context.Context, arg1 db.CreateInterestAccrualParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrual", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateInterestAccrual indicates an expected call of CreateInterestAccrual.
+func (mr *MockStoreMockRecorder) CreateInterestAccrual(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrual", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrual), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// CreateInterestPosting mocks base method.
+func (m *MockStore) CreateInterestPosting(arg0 context.Context, arg1 db.CreateInterestPostingParams) (db.InterestPosting, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CreateInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateInterestPosting indicates an expected call of CreateInterestPosting.
+func (mr *MockStoreMockRecorder) CreateInterestPosting(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestPosting", reflect.TypeOf((*MockStore)(nil).CreateInterestPosting), arg0, arg1)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// CreateInvoice mocks base method.
+func (m *MockStore) CreateInvoice(arg0 context.Context, arg1 db.CreateInvoiceParams) (db.Invoice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CreateInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// CreateInvoice indicates an expected call of CreateInvoice.
+func (mr *MockStoreMockRecorder) CreateInvoice(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoice", reflect.TypeOf((*MockStore)(nil).CreateInvoice), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// CreateInvoiceItem mocks base method.
+func (m *MockStore) CreateInvoiceItem(arg0 context.Context, arg1 db.CreateInvoiceItemParams) (db.InvoiceItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "CreateInvoiceItem", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoiceItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// CreateInvoiceItem indicates an expected call of CreateInvoiceItem.
+func (mr *MockStoreMockRecorder) CreateInvoiceItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoiceItem", reflect.TypeOf((*MockStore)(nil).CreateInvoiceItem), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// CreateInvoicePayment mocks base method.
+func (m *MockStore) CreateInvoicePayment(arg0 context.Context, arg1 db.CreateInvoicePaymentParams) (db.InvoicePayment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "CreateInvoicePayment", arg0, arg1)
+	ret0, _ := ret[0].(db.InvoicePayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CreateInvoicePayment indicates an expected call of CreateInvoicePayment.
+func (mr *MockStoreMockRecorder) CreateInvoicePayment(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoicePayment", reflect.TypeOf((*MockStore)(nil).CreateInvoicePayment), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// CreateInvoiceTrxn mocks base method.
+func (m *MockStore) CreateInvoiceTrxn(arg0 context.Context, arg1 db.CreateInvoiceTxnParams) (db.CreateInvoiceTxnResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "CreateInvoiceTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateInvoiceTxnResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateInvoiceTrxn indicates an expected call of CreateInvoiceTrxn.
+func (mr *MockStoreMockRecorder) CreateInvoiceTrxn(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvoiceTrxn", reflect.TypeOf((*MockStore)(nil).CreateInvoiceTrxn), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// CreateJournal mocks base method.
+func (m *MockStore) CreateJournal(arg0 context.Context, arg1 db.CreateJournalParams) (db.Journal, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "CreateJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.Journal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// CreateJournal indicates an expected call of CreateJournal.
+func (mr *MockStoreMockRecorder) CreateJournal(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJournal", reflect.TypeOf((*MockStore)(nil).CreateJournal), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// CreateKycDocument mocks base method.
+func (m *MockStore) CreateKycDocument(arg0 context.Context, arg1 db.CreateKycDocumentParams) (db.KycDocument, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "CreateKycDocument", arg0, arg1)
+	ret0, _ := ret[0].(db.KycDocument)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// CreateKycDocument indicates an expected call of CreateKycDocument.
+func (mr *MockStoreMockRecorder) CreateKycDocument(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKycDocument", reflect.TypeOf((*MockStore)(nil).CreateKycDocument), arg0, arg1)
 }
 
-// ListTransfer mocks base method.
-func (m *MockStore) ListTransfer(arg0 context.Context, arg1 db.ListTransferParams) ([]db.Transfer, error) {
+// CreateOAuthClient mocks base method.
+func (m *MockStore) CreateOAuthClient(arg0 context.Context, arg1 db.CreateOAuthClientParams) (db.OauthClient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfer", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "CreateOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfer indicates an expected call of ListTransfer.
-func (mr *MockStoreMockRecorder) ListTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CreateOAuthClient indicates an expected call of CreateOAuthClient.
+func (mr *MockStoreMockRecorder) CreateOAuthClient(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfer", reflect.TypeOf((*MockStore)(nil).ListTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthClient", reflect.TypeOf((*MockStore)(nil).CreateOAuthClient), arg0, arg1)
 }
 
-// PerformTransactionTrxn mocks base method.
-func (m *MockStore) PerformTransactionTrxn(arg0 context.Context, arg1 db.TransferTxnParams) (db.TransferTrxResult, error) {
+// CreateOutboxEvent mocks base method.
+func (m *MockStore) CreateOutboxEvent(arg0 context.Context, arg1 db.CreateOutboxEventParams) (db.OutboxEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PerformTransactionTrxn", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTrxResult)
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.OutboxEvent)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// PerformTransactionTrxn indicates an expected call of PerformTransactionTrxn.
-func (mr *MockStoreMockRecorder) PerformTransactionTrxn(arg0, arg1 interface{}) *gomock.Call {
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockStoreMockRecorder) CreateOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PerformTransactionTrxn", reflect.TypeOf((*MockStore)(nil).PerformTransactionTrxn), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockStore)(nil).CreateOutboxEvent), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// CreatePaymentRequest mocks base method.
+func (m *MockStore) CreatePaymentRequest(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockStoreMockRecorder) CreatePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequest), arg0, arg1)
+}
+
+// CreatePaymentRequestTrxn mocks base method.
+func (m *MockStore) CreatePaymentRequestTrxn(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequestTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequestTrxn indicates an expected call of CreatePaymentRequestTrxn.
+func (mr *MockStoreMockRecorder) CreatePaymentRequestTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequestTrxn", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequestTrxn), arg0, arg1)
+}
+
+// CreatePendingTransfer mocks base method.
+func (m *MockStore) CreatePendingTransfer(arg0 context.Context, arg1 db.CreatePendingTransferParams) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePendingTransfer indicates an expected call of CreatePendingTransfer.
+func (mr *MockStoreMockRecorder) CreatePendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransfer", reflect.TypeOf((*MockStore)(nil).CreatePendingTransfer), arg0, arg1)
+}
+
+// CreatePosting mocks base method.
+func (m *MockStore) CreatePosting(arg0 context.Context, arg1 db.CreatePostingParams) (db.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePosting", arg0, arg1)
+	ret0, _ := ret[0].(db.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePosting indicates an expected call of CreatePosting.
+func (mr *MockStoreMockRecorder) CreatePosting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePosting", reflect.TypeOf((*MockStore)(nil).CreatePosting), arg0, arg1)
+}
+
+// CreatePot mocks base method.
+func (m *MockStore) CreatePot(arg0 context.Context, arg1 db.CreatePotParams) (db.Pot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePot", arg0, arg1)
+	ret0, _ := ret[0].(db.Pot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePot indicates an expected call of CreatePot.
+func (mr *MockStoreMockRecorder) CreatePot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePot", reflect.TypeOf((*MockStore)(nil).CreatePot), arg0, arg1)
+}
+
+// CreatePotAccount mocks base method.
+func (m *MockStore) CreatePotAccount(arg0 context.Context, arg1 db.CreatePotAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePotAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePotAccount indicates an expected call of CreatePotAccount.
+func (mr *MockStoreMockRecorder) CreatePotAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePotAccount", reflect.TypeOf((*MockStore)(nil).CreatePotAccount), arg0, arg1)
+}
+
+// CreatePotTrxn mocks base method.
+func (m *MockStore) CreatePotTrxn(arg0 context.Context, arg1 db.CreatePotTxnParams) (db.CreatePotTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePotTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.CreatePotTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePotTrxn indicates an expected call of CreatePotTrxn.
+func (mr *MockStoreMockRecorder) CreatePotTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePotTrxn", reflect.TypeOf((*MockStore)(nil).CreatePotTrxn), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) (db.MfaRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockStore) CreateRefreshToken(arg0 context.Context, arg1 db.CreateRefreshTokenParams) (db.OauthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockStoreMockRecorder) CreateRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockStore)(nil).CreateRefreshToken), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferApproval mocks base method.
+func (m *MockStore) CreateTransferApproval(arg0 context.Context, arg1 db.CreateTransferApprovalParams) (db.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferApproval", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferApproval indicates an expected call of CreateTransferApproval.
+func (mr *MockStoreMockRecorder) CreateTransferApproval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferApproval", reflect.TypeOf((*MockStore)(nil).CreateTransferApproval), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhookAttempt mocks base method.
+func (m *MockStore) CreateWebhookAttempt(arg0 context.Context, arg1 db.CreateWebhookAttemptParams) (db.WebhookAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookAttempt indicates an expected call of CreateWebhookAttempt.
+func (mr *MockStoreMockRecorder) CreateWebhookAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookAttempt", reflect.TypeOf((*MockStore)(nil).CreateWebhookAttempt), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStore) CreateWebhookEndpoint(arg0 context.Context, arg1 db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) CreateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).CreateWebhookEndpoint), arg0, arg1)
+}
+
+// DeactivateWebhookEndpoint mocks base method.
+func (m *MockStore) DeactivateWebhookEndpoint(arg0 context.Context, arg1 db.DeactivateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateWebhookEndpoint indicates an expected call of DeactivateWebhookEndpoint.
+func (mr *MockStoreMockRecorder) DeactivateWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).DeactivateWebhookEndpoint), arg0, arg1)
+}
+
+// DecideTransferTrxn mocks base method.
+func (m *MockStore) DecideTransferTrxn(arg0 context.Context, arg1 db.DecideTransferTxnParams) (db.DecideTransferTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.DecideTransferTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferTrxn indicates an expected call of DecideTransferTrxn.
+func (mr *MockStoreMockRecorder) DecideTransferTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferTrxn", reflect.TypeOf((*MockStore)(nil).DecideTransferTrxn), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteAccountMember mocks base method.
+func (m *MockStore) DeleteAccountMember(arg0 context.Context, arg1 db.DeleteAccountMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountMember indicates an expected call of DeleteAccountMember.
+func (mr *MockStoreMockRecorder) DeleteAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountMember", reflect.TypeOf((*MockStore)(nil).DeleteAccountMember), arg0, arg1)
+}
+
+// DeleteAlias mocks base method.
+func (m *MockStore) DeleteAlias(arg0 context.Context, arg1 db.DeleteAliasParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAlias", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAlias indicates an expected call of DeleteAlias.
+func (mr *MockStoreMockRecorder) DeleteAlias(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAlias", reflect.TypeOf((*MockStore)(nil).DeleteAlias), arg0, arg1)
+}
+
+// DeleteApprovalPolicy mocks base method.
+func (m *MockStore) DeleteApprovalPolicy(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteApprovalPolicy indicates an expected call of DeleteApprovalPolicy.
+func (mr *MockStoreMockRecorder) DeleteApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApprovalPolicy", reflect.TypeOf((*MockStore)(nil).DeleteApprovalPolicy), arg0, arg1)
+}
+
+// DeleteBeneficiary mocks base method.
+func (m *MockStore) DeleteBeneficiary(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBeneficiary indicates an expected call of DeleteBeneficiary.
+func (mr *MockStoreMockRecorder) DeleteBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBeneficiary", reflect.TypeOf((*MockStore)(nil).DeleteBeneficiary), arg0, arg1)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteTransferLimit mocks base method.
+func (m *MockStore) DeleteTransferLimit(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransferLimit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransferLimit indicates an expected call of DeleteTransferLimit.
+func (mr *MockStoreMockRecorder) DeleteTransferLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransferLimit", reflect.TypeOf((*MockStore)(nil).DeleteTransferLimit), arg0, arg1)
+}
+
+// DisableMFATrxn mocks base method.
+func (m *MockStore) DisableMFATrxn(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFATrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableMFATrxn indicates an expected call of DisableMFATrxn.
+func (mr *MockStoreMockRecorder) DisableMFATrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFATrxn", reflect.TypeOf((*MockStore)(nil).DisableMFATrxn), arg0, arg1)
+}
+
+// DisableUserTOTP mocks base method.
+func (m *MockStore) DisableUserTOTP(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUserTOTP indicates an expected call of DisableUserTOTP.
+func (mr *MockStoreMockRecorder) DisableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUserTOTP", reflect.TypeOf((*MockStore)(nil).DisableUserTOTP), arg0, arg1)
+}
+
+// DispatchOutboxTrxn mocks base method.
+func (m *MockStore) DispatchOutboxTrxn(arg0 context.Context, arg1 int32) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchOutboxTrxn", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchOutboxTrxn indicates an expected call of DispatchOutboxTrxn.
+func (mr *MockStoreMockRecorder) DispatchOutboxTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchOutboxTrxn", reflect.TypeOf((*MockStore)(nil).DispatchOutboxTrxn), arg0, arg1)
+}
+
+// DisputeEscrowContract mocks base method.
+func (m *MockStore) DisputeEscrowContract(arg0 context.Context, arg1 db.DisputeEscrowContractParams) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrowContract", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrowContract indicates an expected call of DisputeEscrowContract.
+func (mr *MockStoreMockRecorder) DisputeEscrowContract(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrowContract", reflect.TypeOf((*MockStore)(nil).DisputeEscrowContract), arg0, arg1)
+}
+
+// DisputeEscrowTrxn mocks base method.
+func (m *MockStore) DisputeEscrowTrxn(arg0 context.Context, arg1 db.DisputeEscrowContractParams) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisputeEscrowTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisputeEscrowTrxn indicates an expected call of DisputeEscrowTrxn.
+func (mr *MockStoreMockRecorder) DisputeEscrowTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisputeEscrowTrxn", reflect.TypeOf((*MockStore)(nil).DisputeEscrowTrxn), arg0, arg1)
+}
+
+// EnableUserTOTP mocks base method.
+func (m *MockStore) EnableUserTOTP(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTOTP indicates an expected call of EnableUserTOTP.
+func (mr *MockStoreMockRecorder) EnableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTOTP", reflect.TypeOf((*MockStore)(nil).EnableUserTOTP), arg0, arg1)
+}
+
+// ExpirePaymentRequests mocks base method.
+func (m *MockStore) ExpirePaymentRequests(arg0 context.Context, arg1 time.Time) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequests indicates an expected call of ExpirePaymentRequests.
+func (mr *MockStoreMockRecorder) ExpirePaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequests", reflect.TypeOf((*MockStore)(nil).ExpirePaymentRequests), arg0, arg1)
+}
+
+// ExpirePaymentRequestsTrxn mocks base method.
+func (m *MockStore) ExpirePaymentRequestsTrxn(arg0 context.Context, arg1 time.Time) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequestsTrxn", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequestsTrxn indicates an expected call of ExpirePaymentRequestsTrxn.
+func (mr *MockStoreMockRecorder) ExpirePaymentRequestsTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequestsTrxn", reflect.TypeOf((*MockStore)(nil).ExpirePaymentRequestsTrxn), arg0, arg1)
+}
+
+// ExpirePendingTransfers mocks base method.
+func (m *MockStore) ExpirePendingTransfers(arg0 context.Context, arg1 time.Time) ([]db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePendingTransfers indicates an expected call of ExpirePendingTransfers.
+func (mr *MockStoreMockRecorder) ExpirePendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePendingTransfers", reflect.TypeOf((*MockStore)(nil).ExpirePendingTransfers), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(arg0 context.Context, arg1 sql.NullString) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountLimits mocks base method.
+func (m *MockStore) GetAccountLimits(arg0 context.Context, arg1 int64) (db.AccountLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountLimits indicates an expected call of GetAccountLimits.
+func (mr *MockStoreMockRecorder) GetAccountLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountLimits", reflect.TypeOf((*MockStore)(nil).GetAccountLimits), arg0, arg1)
+}
+
+// GetAccountMember mocks base method.
+func (m *MockStore) GetAccountMember(arg0 context.Context, arg1 db.GetAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountMember indicates an expected call of GetAccountMember.
+func (mr *MockStoreMockRecorder) GetAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMember", reflect.TypeOf((*MockStore)(nil).GetAccountMember), arg0, arg1)
+}
+
+// GetAccountOwnerTier mocks base method.
+func (m *MockStore) GetAccountOwnerTier(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountOwnerTier", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountOwnerTier indicates an expected call of GetAccountOwnerTier.
+func (mr *MockStoreMockRecorder) GetAccountOwnerTier(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountOwnerTier", reflect.TypeOf((*MockStore)(nil).GetAccountOwnerTier), arg0, arg1)
+}
+
+// GetAccountProduct mocks base method.
+func (m *MockStore) GetAccountProduct(arg0 context.Context, arg1 string) (db.AccountProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountProduct", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountProduct indicates an expected call of GetAccountProduct.
+func (mr *MockStoreMockRecorder) GetAccountProduct(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountProduct", reflect.TypeOf((*MockStore)(nil).GetAccountProduct), arg0, arg1)
+}
+
+// GetAlias mocks base method.
+func (m *MockStore) GetAlias(arg0 context.Context, arg1 string) (db.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAlias", arg0, arg1)
+	ret0, _ := ret[0].(db.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAlias indicates an expected call of GetAlias.
+func (mr *MockStoreMockRecorder) GetAlias(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAlias", reflect.TypeOf((*MockStore)(nil).GetAlias), arg0, arg1)
+}
+
+// GetApiKeyByPrefix mocks base method.
+func (m *MockStore) GetApiKeyByPrefix(arg0 context.Context, arg1 string) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeyByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeyByPrefix indicates an expected call of GetApiKeyByPrefix.
+func (mr *MockStoreMockRecorder) GetApiKeyByPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeyByPrefix", reflect.TypeOf((*MockStore)(nil).GetApiKeyByPrefix), arg0, arg1)
+}
+
+// GetApplicableApprovalPolicy mocks base method.
+func (m *MockStore) GetApplicableApprovalPolicy(arg0 context.Context, arg1 db.GetApplicableApprovalPolicyParams) (db.ApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicableApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicableApprovalPolicy indicates an expected call of GetApplicableApprovalPolicy.
+func (mr *MockStoreMockRecorder) GetApplicableApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicableApprovalPolicy", reflect.TypeOf((*MockStore)(nil).GetApplicableApprovalPolicy), arg0, arg1)
+}
+
+// GetApprovalPolicy mocks base method.
+func (m *MockStore) GetApprovalPolicy(arg0 context.Context, arg1 int64) (db.ApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovalPolicy indicates an expected call of GetApprovalPolicy.
+func (mr *MockStoreMockRecorder) GetApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalPolicy", reflect.TypeOf((*MockStore)(nil).GetApprovalPolicy), arg0, arg1)
+}
+
+// GetBalanceAt mocks base method.
+func (m *MockStore) GetBalanceAt(arg0 context.Context, arg1 db.BalanceAtParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceAt", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceAt indicates an expected call of GetBalanceAt.
+func (mr *MockStoreMockRecorder) GetBalanceAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceAt", reflect.TypeOf((*MockStore)(nil).GetBalanceAt), arg0, arg1)
+}
+
+// GetBeneficiary mocks base method.
+func (m *MockStore) GetBeneficiary(arg0 context.Context, arg1 int64) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiary indicates an expected call of GetBeneficiary.
+func (mr *MockStoreMockRecorder) GetBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiary", reflect.TypeOf((*MockStore)(nil).GetBeneficiary), arg0, arg1)
+}
+
+// GetBeneficiaryByAccount mocks base method.
+func (m *MockStore) GetBeneficiaryByAccount(arg0 context.Context, arg1 db.GetBeneficiaryByAccountParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBeneficiaryByAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiaryByAccount indicates an expected call of GetBeneficiaryByAccount.
+func (mr *MockStoreMockRecorder) GetBeneficiaryByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiaryByAccount", reflect.TypeOf((*MockStore)(nil).GetBeneficiaryByAccount), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetEscrowContract mocks base method.
+func (m *MockStore) GetEscrowContract(arg0 context.Context, arg1 int64) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrowContract", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrowContract indicates an expected call of GetEscrowContract.
+func (mr *MockStoreMockRecorder) GetEscrowContract(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrowContract", reflect.TypeOf((*MockStore)(nil).GetEscrowContract), arg0, arg1)
+}
+
+// GetEscrowContractForUpdate mocks base method.
+func (m *MockStore) GetEscrowContractForUpdate(arg0 context.Context, arg1 int64) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEscrowContractForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEscrowContractForUpdate indicates an expected call of GetEscrowContractForUpdate.
+func (mr *MockStoreMockRecorder) GetEscrowContractForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEscrowContractForUpdate", reflect.TypeOf((*MockStore)(nil).GetEscrowContractForUpdate), arg0, arg1)
+}
+
+// GetFraudCase mocks base method.
+func (m *MockStore) GetFraudCase(arg0 context.Context, arg1 int64) (db.FraudCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFraudCase", arg0, arg1)
+	ret0, _ := ret[0].(db.FraudCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFraudCase indicates an expected call of GetFraudCase.
+func (mr *MockStoreMockRecorder) GetFraudCase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFraudCase", reflect.TypeOf((*MockStore)(nil).GetFraudCase), arg0, arg1)
+}
+
+// GetFraudCaseForUpdate mocks base method.
+func (m *MockStore) GetFraudCaseForUpdate(arg0 context.Context, arg1 int64) (db.FraudCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFraudCaseForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.FraudCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFraudCaseForUpdate indicates an expected call of GetFraudCaseForUpdate.
+func (mr *MockStoreMockRecorder) GetFraudCaseForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFraudCaseForUpdate", reflect.TypeOf((*MockStore)(nil).GetFraudCaseForUpdate), arg0, arg1)
+}
+
+// GetInterestPosting mocks base method.
+func (m *MockStore) GetInterestPosting(arg0 context.Context, arg1 db.GetInterestPostingParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestPosting", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestPosting indicates an expected call of GetInterestPosting.
+func (mr *MockStoreMockRecorder) GetInterestPosting(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestPosting", reflect.TypeOf((*MockStore)(nil).GetInterestPosting), arg0, arg1)
+}
+
+// GetInvoice mocks base method.
+func (m *MockStore) GetInvoice(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoice indicates an expected call of GetInvoice.
+func (mr *MockStoreMockRecorder) GetInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoice", reflect.TypeOf((*MockStore)(nil).GetInvoice), arg0, arg1)
+}
+
+// GetInvoiceByPayToken mocks base method.
+func (m *MockStore) GetInvoiceByPayToken(arg0 context.Context, arg1 string) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceByPayToken", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceByPayToken indicates an expected call of GetInvoiceByPayToken.
+func (mr *MockStoreMockRecorder) GetInvoiceByPayToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceByPayToken", reflect.TypeOf((*MockStore)(nil).GetInvoiceByPayToken), arg0, arg1)
+}
+
+// GetInvoiceForUpdate mocks base method.
+func (m *MockStore) GetInvoiceForUpdate(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvoiceForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvoiceForUpdate indicates an expected call of GetInvoiceForUpdate.
+func (mr *MockStoreMockRecorder) GetInvoiceForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvoiceForUpdate", reflect.TypeOf((*MockStore)(nil).GetInvoiceForUpdate), arg0, arg1)
+}
+
+// GetJournal mocks base method.
+func (m *MockStore) GetJournal(arg0 context.Context, arg1 int64) (db.Journal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.Journal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournal indicates an expected call of GetJournal.
+func (mr *MockStoreMockRecorder) GetJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournal", reflect.TypeOf((*MockStore)(nil).GetJournal), arg0, arg1)
+}
+
+// GetJournalByTransfer mocks base method.
+func (m *MockStore) GetJournalByTransfer(arg0 context.Context, arg1 sql.NullInt64) (db.Journal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJournalByTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Journal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJournalByTransfer indicates an expected call of GetJournalByTransfer.
+func (mr *MockStoreMockRecorder) GetJournalByTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJournalByTransfer", reflect.TypeOf((*MockStore)(nil).GetJournalByTransfer), arg0, arg1)
+}
+
+// GetKycProfile mocks base method.
+func (m *MockStore) GetKycProfile(arg0 context.Context, arg1 string) (db.KycProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKycProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.KycProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKycProfile indicates an expected call of GetKycProfile.
+func (mr *MockStoreMockRecorder) GetKycProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKycProfile", reflect.TypeOf((*MockStore)(nil).GetKycProfile), arg0, arg1)
+}
+
+// GetKycTier mocks base method.
+func (m *MockStore) GetKycTier(arg0 context.Context, arg1 string) (db.KycTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKycTier", arg0, arg1)
+	ret0, _ := ret[0].(db.KycTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKycTier indicates an expected call of GetKycTier.
+func (mr *MockStoreMockRecorder) GetKycTier(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKycTier", reflect.TypeOf((*MockStore)(nil).GetKycTier), arg0, arg1)
+}
+
+// GetKycTierForUser mocks base method.
+func (m *MockStore) GetKycTierForUser(arg0 context.Context, arg1 string) (db.KycTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKycTierForUser", arg0, arg1)
+	ret0, _ := ret[0].(db.KycTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKycTierForUser indicates an expected call of GetKycTierForUser.
+func (mr *MockStoreMockRecorder) GetKycTierForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKycTierForUser", reflect.TypeOf((*MockStore)(nil).GetKycTierForUser), arg0, arg1)
+}
+
+// GetLastAuditEvent mocks base method.
+func (m *MockStore) GetLastAuditEvent(arg0 context.Context, arg1 int32) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAuditEvent indicates an expected call of GetLastAuditEvent.
+func (mr *MockStoreMockRecorder) GetLastAuditEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAuditEvent", reflect.TypeOf((*MockStore)(nil).GetLastAuditEvent), arg0, arg1)
+}
+
+// GetLastInterestAccrualDate mocks base method.
+func (m *MockStore) GetLastInterestAccrualDate(arg0 context.Context) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastInterestAccrualDate", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastInterestAccrualDate indicates an expected call of GetLastInterestAccrualDate.
+func (mr *MockStoreMockRecorder) GetLastInterestAccrualDate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastInterestAccrualDate", reflect.TypeOf((*MockStore)(nil).GetLastInterestAccrualDate), arg0)
+}
+
+// GetLatestBalanceSnapshot mocks base method.
+func (m *MockStore) GetLatestBalanceSnapshot(arg0 context.Context, arg1 db.GetLatestBalanceSnapshotParams) (db.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestBalanceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(db.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestBalanceSnapshot indicates an expected call of GetLatestBalanceSnapshot.
+func (mr *MockStoreMockRecorder) GetLatestBalanceSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestBalanceSnapshot", reflect.TypeOf((*MockStore)(nil).GetLatestBalanceSnapshot), arg0, arg1)
+}
+
+// GetLedgerAccountByCode mocks base method.
+func (m *MockStore) GetLedgerAccountByCode(arg0 context.Context, arg1 db.GetLedgerAccountByCodeParams) (db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerAccountByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerAccountByCode indicates an expected call of GetLedgerAccountByCode.
+func (mr *MockStoreMockRecorder) GetLedgerAccountByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerAccountByCode", reflect.TypeOf((*MockStore)(nil).GetLedgerAccountByCode), arg0, arg1)
+}
+
+// GetOAuthClient mocks base method.
+func (m *MockStore) GetOAuthClient(arg0 context.Context, arg1 string) (db.OauthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthClient", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthClient indicates an expected call of GetOAuthClient.
+func (mr *MockStoreMockRecorder) GetOAuthClient(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthClient", reflect.TypeOf((*MockStore)(nil).GetOAuthClient), arg0, arg1)
+}
+
+// GetOutboxEvent mocks base method.
+func (m *MockStore) GetOutboxEvent(arg0 context.Context, arg1 int64) (db.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxEvent indicates an expected call of GetOutboxEvent.
+func (mr *MockStoreMockRecorder) GetOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxEvent", reflect.TypeOf((*MockStore)(nil).GetOutboxEvent), arg0, arg1)
+}
+
+// GetOutgoingTransferTotals mocks base method.
+func (m *MockStore) GetOutgoingTransferTotals(arg0 context.Context, arg1 db.GetOutgoingTransferTotalsParams) (db.GetOutgoingTransferTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingTransferTotals", arg0, arg1)
+	ret0, _ := ret[0].(db.GetOutgoingTransferTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingTransferTotals indicates an expected call of GetOutgoingTransferTotals.
+func (mr *MockStoreMockRecorder) GetOutgoingTransferTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingTransferTotals", reflect.TypeOf((*MockStore)(nil).GetOutgoingTransferTotals), arg0, arg1)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockStore) GetPaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockStoreMockRecorder) GetPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockStore)(nil).GetPaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockStore) GetPaymentRequestForUpdate(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestForUpdate), arg0, arg1)
+}
+
+// GetPendingTransfer mocks base method.
+func (m *MockStore) GetPendingTransfer(arg0 context.Context, arg1 int64) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransfer indicates an expected call of GetPendingTransfer.
+func (mr *MockStoreMockRecorder) GetPendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfer", reflect.TypeOf((*MockStore)(nil).GetPendingTransfer), arg0, arg1)
+}
+
+// GetPendingTransferForUpdate mocks base method.
+func (m *MockStore) GetPendingTransferForUpdate(arg0 context.Context, arg1 int64) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransferForUpdate indicates an expected call of GetPendingTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetPendingTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetPendingTransferForUpdate), arg0, arg1)
+}
+
+// GetPot mocks base method.
+func (m *MockStore) GetPot(arg0 context.Context, arg1 int64) (db.Pot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPot", arg0, arg1)
+	ret0, _ := ret[0].(db.Pot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPot indicates an expected call of GetPot.
+func (mr *MockStoreMockRecorder) GetPot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPot", reflect.TypeOf((*MockStore)(nil).GetPot), arg0, arg1)
+}
+
+// GetPrimaryAccount mocks base method.
+func (m *MockStore) GetPrimaryAccount(arg0 context.Context, arg1 db.GetPrimaryAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrimaryAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrimaryAccount indicates an expected call of GetPrimaryAccount.
+func (mr *MockStoreMockRecorder) GetPrimaryAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrimaryAccount", reflect.TypeOf((*MockStore)(nil).GetPrimaryAccount), arg0, arg1)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockStore) GetRefreshToken(arg0 context.Context, arg1 string) (db.OauthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockStoreMockRecorder) GetRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockStore)(nil).GetRefreshToken), arg0, arg1)
+}
+
+// GetRevokedAccessToken mocks base method.
+func (m *MockStore) GetRevokedAccessToken(arg0 context.Context, arg1 string) (db.OauthRevokedAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevokedAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthRevokedAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevokedAccessToken indicates an expected call of GetRevokedAccessToken.
+func (mr *MockStoreMockRecorder) GetRevokedAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevokedAccessToken", reflect.TypeOf((*MockStore)(nil).GetRevokedAccessToken), arg0, arg1)
+}
+
+// GetRoundUpPot mocks base method.
+func (m *MockStore) GetRoundUpPot(arg0 context.Context, arg1 int64) (db.Pot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoundUpPot", arg0, arg1)
+	ret0, _ := ret[0].(db.Pot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoundUpPot indicates an expected call of GetRoundUpPot.
+func (mr *MockStoreMockRecorder) GetRoundUpPot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoundUpPot", reflect.TypeOf((*MockStore)(nil).GetRoundUpPot), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferLimit mocks base method.
+func (m *MockStore) GetTransferLimit(arg0 context.Context, arg1 int64) (db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferLimit indicates an expected call of GetTransferLimit.
+func (mr *MockStoreMockRecorder) GetTransferLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferLimit", reflect.TypeOf((*MockStore)(nil).GetTransferLimit), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhookEndpoint mocks base method.
+func (m *MockStore) GetWebhookEndpoint(arg0 context.Context, arg1 int64) (db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoint", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoint indicates an expected call of GetWebhookEndpoint.
+func (mr *MockStoreMockRecorder) GetWebhookEndpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoint", reflect.TypeOf((*MockStore)(nil).GetWebhookEndpoint), arg0, arg1)
+}
+
+// IncrementAliasAttempts mocks base method.
+func (m *MockStore) IncrementAliasAttempts(arg0 context.Context, arg1 string) (db.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAliasAttempts", arg0, arg1)
+	ret0, _ := ret[0].(db.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAliasAttempts indicates an expected call of IncrementAliasAttempts.
+func (mr *MockStoreMockRecorder) IncrementAliasAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAliasAttempts", reflect.TypeOf((*MockStore)(nil).IncrementAliasAttempts), arg0, arg1)
+}
+
+// ListAccountEntryTotals mocks base method.
+func (m *MockStore) ListAccountEntryTotals(arg0 context.Context, arg1 db.ListAccountEntryTotalsParams) ([]db.ListAccountEntryTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEntryTotals", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAccountEntryTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEntryTotals indicates an expected call of ListAccountEntryTotals.
+func (mr *MockStoreMockRecorder) ListAccountEntryTotals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEntryTotals", reflect.TypeOf((*MockStore)(nil).ListAccountEntryTotals), arg0, arg1)
+}
+
+// ListAccountIDs mocks base method.
+func (m *MockStore) ListAccountIDs(arg0 context.Context, arg1 db.ListAccountIDsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountIDs", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountIDs indicates an expected call of ListAccountIDs.
+func (mr *MockStoreMockRecorder) ListAccountIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountIDs", reflect.TypeOf((*MockStore)(nil).ListAccountIDs), arg0, arg1)
+}
+
+// ListAccountInvitations mocks base method.
+func (m *MockStore) ListAccountInvitations(arg0 context.Context, arg1 string) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountInvitations indicates an expected call of ListAccountInvitations.
+func (mr *MockStoreMockRecorder) ListAccountInvitations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountInvitations", reflect.TypeOf((*MockStore)(nil).ListAccountInvitations), arg0, arg1)
+}
+
+// ListAccountMembers mocks base method.
+func (m *MockStore) ListAccountMembers(arg0 context.Context, arg1 int64) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountMembers", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountMembers indicates an expected call of ListAccountMembers.
+func (mr *MockStoreMockRecorder) ListAccountMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountMembers", reflect.TypeOf((*MockStore)(nil).ListAccountMembers), arg0, arg1)
+}
+
+// ListAccountPendingTransfers mocks base method.
+func (m *MockStore) ListAccountPendingTransfers(arg0 context.Context, arg1 db.ListAccountPendingTransfersParams) ([]db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountPendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountPendingTransfers indicates an expected call of ListAccountPendingTransfers.
+func (mr *MockStoreMockRecorder) ListAccountPendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountPendingTransfers", reflect.TypeOf((*MockStore)(nil).ListAccountPendingTransfers), arg0, arg1)
+}
+
+// ListAccountProducts mocks base method.
+func (m *MockStore) ListAccountProducts(arg0 context.Context) ([]db.AccountProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountProducts", arg0)
+	ret0, _ := ret[0].([]db.AccountProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountProducts indicates an expected call of ListAccountProducts.
+func (mr *MockStoreMockRecorder) ListAccountProducts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountProducts", reflect.TypeOf((*MockStore)(nil).ListAccountProducts), arg0)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccountsWithoutNumber mocks base method.
+func (m *MockStore) ListAccountsWithoutNumber(arg0 context.Context, arg1 int32) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsWithoutNumber", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsWithoutNumber indicates an expected call of ListAccountsWithoutNumber.
+func (mr *MockStoreMockRecorder) ListAccountsWithoutNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsWithoutNumber", reflect.TypeOf((*MockStore)(nil).ListAccountsWithoutNumber), arg0, arg1)
+}
+
+// ListApiKeys mocks base method.
+func (m *MockStore) ListApiKeys(arg0 context.Context, arg1 db.ListApiKeysParams) ([]db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApiKeys", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApiKeys indicates an expected call of ListApiKeys.
+func (mr *MockStoreMockRecorder) ListApiKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApiKeys", reflect.TypeOf((*MockStore)(nil).ListApiKeys), arg0, arg1)
+}
+
+// ListApplicableTransferLimits mocks base method.
+func (m *MockStore) ListApplicableTransferLimits(arg0 context.Context, arg1 db.ListApplicableTransferLimitsParams) ([]db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicableTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicableTransferLimits indicates an expected call of ListApplicableTransferLimits.
+func (mr *MockStoreMockRecorder) ListApplicableTransferLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicableTransferLimits", reflect.TypeOf((*MockStore)(nil).ListApplicableTransferLimits), arg0, arg1)
+}
+
+// ListApprovableTransfers mocks base method.
+func (m *MockStore) ListApprovableTransfers(arg0 context.Context, arg1 db.ListApprovableTransfersParams) ([]db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApprovableTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApprovableTransfers indicates an expected call of ListApprovableTransfers.
+func (mr *MockStoreMockRecorder) ListApprovableTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApprovableTransfers", reflect.TypeOf((*MockStore)(nil).ListApprovableTransfers), arg0, arg1)
+}
+
+// ListApprovalPolicies mocks base method.
+func (m *MockStore) ListApprovalPolicies(arg0 context.Context, arg1 int64) ([]db.ApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApprovalPolicies", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApprovalPolicies indicates an expected call of ListApprovalPolicies.
+func (mr *MockStoreMockRecorder) ListApprovalPolicies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApprovalPolicies", reflect.TypeOf((*MockStore)(nil).ListApprovalPolicies), arg0, arg1)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(arg0 context.Context, arg1 db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), arg0, arg1)
+}
+
+// ListAuditEventsAfter mocks base method.
+func (m *MockStore) ListAuditEventsAfter(arg0 context.Context, arg1 db.ListAuditEventsAfterParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEventsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEventsAfter indicates an expected call of ListAuditEventsAfter.
+func (mr *MockStoreMockRecorder) ListAuditEventsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEventsAfter", reflect.TypeOf((*MockStore)(nil).ListAuditEventsAfter), arg0, arg1)
+}
+
+// ListBeneficiaries mocks base method.
+func (m *MockStore) ListBeneficiaries(arg0 context.Context, arg1 string) ([]db.ListBeneficiariesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBeneficiaries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBeneficiariesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBeneficiaries indicates an expected call of ListBeneficiaries.
+func (mr *MockStoreMockRecorder) ListBeneficiaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBeneficiaries", reflect.TypeOf((*MockStore)(nil).ListBeneficiaries), arg0, arg1)
+}
+
+// ListDueEscrowContracts mocks base method.
+func (m *MockStore) ListDueEscrowContracts(arg0 context.Context, arg1 db.ListDueEscrowContractsParams) ([]db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueEscrowContracts", arg0, arg1)
+	ret0, _ := ret[0].([]db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueEscrowContracts indicates an expected call of ListDueEscrowContracts.
+func (mr *MockStoreMockRecorder) ListDueEscrowContracts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueEscrowContracts", reflect.TypeOf((*MockStore)(nil).ListDueEscrowContracts), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEscrowContracts mocks base method.
+func (m *MockStore) ListEscrowContracts(arg0 context.Context, arg1 db.ListEscrowContractsParams) ([]db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscrowContracts", arg0, arg1)
+	ret0, _ := ret[0].([]db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscrowContracts indicates an expected call of ListEscrowContracts.
+func (mr *MockStoreMockRecorder) ListEscrowContracts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscrowContracts", reflect.TypeOf((*MockStore)(nil).ListEscrowContracts), arg0, arg1)
+}
+
+// ListEscrowContractsByStatus mocks base method.
+func (m *MockStore) ListEscrowContractsByStatus(arg0 context.Context, arg1 db.ListEscrowContractsByStatusParams) ([]db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscrowContractsByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscrowContractsByStatus indicates an expected call of ListEscrowContractsByStatus.
+func (mr *MockStoreMockRecorder) ListEscrowContractsByStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscrowContractsByStatus", reflect.TypeOf((*MockStore)(nil).ListEscrowContractsByStatus), arg0, arg1)
+}
+
+// ListEscrowContractsFundedSince mocks base method.
+func (m *MockStore) ListEscrowContractsFundedSince(arg0 context.Context, arg1 db.ListEscrowContractsFundedSinceParams) ([]db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEscrowContractsFundedSince", arg0, arg1)
+	ret0, _ := ret[0].([]db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEscrowContractsFundedSince indicates an expected call of ListEscrowContractsFundedSince.
+func (mr *MockStoreMockRecorder) ListEscrowContractsFundedSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEscrowContractsFundedSince", reflect.TypeOf((*MockStore)(nil).ListEscrowContractsFundedSince), arg0, arg1)
+}
+
+// ListFraudCases mocks base method.
+func (m *MockStore) ListFraudCases(arg0 context.Context, arg1 db.ListFraudCasesParams) ([]db.FraudCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFraudCases", arg0, arg1)
+	ret0, _ := ret[0].([]db.FraudCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFraudCases indicates an expected call of ListFraudCases.
+func (mr *MockStoreMockRecorder) ListFraudCases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFraudCases", reflect.TypeOf((*MockStore)(nil).ListFraudCases), arg0, arg1)
+}
+
+// ListIncomingPaymentRequests mocks base method.
+func (m *MockStore) ListIncomingPaymentRequests(arg0 context.Context, arg1 db.ListIncomingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIncomingPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIncomingPaymentRequests indicates an expected call of ListIncomingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListIncomingPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIncomingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListIncomingPaymentRequests), arg0, arg1)
+}
+
+// ListInterestAccruals mocks base method.
+func (m *MockStore) ListInterestAccruals(arg0 context.Context, arg1 db.ListInterestAccrualsParams) ([]db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInterestAccruals", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInterestAccruals indicates an expected call of ListInterestAccruals.
+func (mr *MockStoreMockRecorder) ListInterestAccruals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInterestAccruals", reflect.TypeOf((*MockStore)(nil).ListInterestAccruals), arg0, arg1)
+}
+
+// ListInterestBearingAccounts mocks base method.
+func (m *MockStore) ListInterestBearingAccounts(arg0 context.Context, arg1 db.ListInterestBearingAccountsParams) ([]db.ListInterestBearingAccountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInterestBearingAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListInterestBearingAccountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInterestBearingAccounts indicates an expected call of ListInterestBearingAccounts.
+func (mr *MockStoreMockRecorder) ListInterestBearingAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInterestBearingAccounts", reflect.TypeOf((*MockStore)(nil).ListInterestBearingAccounts), arg0, arg1)
+}
+
+// ListInvoiceItems mocks base method.
+func (m *MockStore) ListInvoiceItems(arg0 context.Context, arg1 int64) ([]db.InvoiceItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoiceItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.InvoiceItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoiceItems indicates an expected call of ListInvoiceItems.
+func (mr *MockStoreMockRecorder) ListInvoiceItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoiceItems", reflect.TypeOf((*MockStore)(nil).ListInvoiceItems), arg0, arg1)
+}
+
+// ListInvoicePayments mocks base method.
+func (m *MockStore) ListInvoicePayments(arg0 context.Context, arg1 int64) ([]db.InvoicePayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoicePayments", arg0, arg1)
+	ret0, _ := ret[0].([]db.InvoicePayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoicePayments indicates an expected call of ListInvoicePayments.
+func (mr *MockStoreMockRecorder) ListInvoicePayments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoicePayments", reflect.TypeOf((*MockStore)(nil).ListInvoicePayments), arg0, arg1)
+}
+
+// ListInvoices mocks base method.
+func (m *MockStore) ListInvoices(arg0 context.Context, arg1 db.ListInvoicesParams) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvoices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvoices indicates an expected call of ListInvoices.
+func (mr *MockStoreMockRecorder) ListInvoices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvoices", reflect.TypeOf((*MockStore)(nil).ListInvoices), arg0, arg1)
+}
+
+// ListJournalPostings mocks base method.
+func (m *MockStore) ListJournalPostings(arg0 context.Context, arg1 int64) ([]db.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJournalPostings", arg0, arg1)
+	ret0, _ := ret[0].([]db.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJournalPostings indicates an expected call of ListJournalPostings.
+func (mr *MockStoreMockRecorder) ListJournalPostings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJournalPostings", reflect.TypeOf((*MockStore)(nil).ListJournalPostings), arg0, arg1)
+}
+
+// ListKycDocuments mocks base method.
+func (m *MockStore) ListKycDocuments(arg0 context.Context, arg1 string) ([]db.KycDocument, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKycDocuments", arg0, arg1)
+	ret0, _ := ret[0].([]db.KycDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKycDocuments indicates an expected call of ListKycDocuments.
+func (mr *MockStoreMockRecorder) ListKycDocuments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKycDocuments", reflect.TypeOf((*MockStore)(nil).ListKycDocuments), arg0, arg1)
+}
+
+// ListKycProfilesByStatus mocks base method.
+func (m *MockStore) ListKycProfilesByStatus(arg0 context.Context, arg1 db.ListKycProfilesByStatusParams) ([]db.KycProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKycProfilesByStatus", arg0, arg1)
+	ret0, _ := ret[0].([]db.KycProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKycProfilesByStatus indicates an expected call of ListKycProfilesByStatus.
+func (mr *MockStoreMockRecorder) ListKycProfilesByStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKycProfilesByStatus", reflect.TypeOf((*MockStore)(nil).ListKycProfilesByStatus), arg0, arg1)
+}
+
+// ListKycTiers mocks base method.
+func (m *MockStore) ListKycTiers(arg0 context.Context) ([]db.KycTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListKycTiers", arg0)
+	ret0, _ := ret[0].([]db.KycTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListKycTiers indicates an expected call of ListKycTiers.
+func (mr *MockStoreMockRecorder) ListKycTiers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListKycTiers", reflect.TypeOf((*MockStore)(nil).ListKycTiers), arg0)
+}
+
+// ListLedgerAccounts mocks base method.
+func (m *MockStore) ListLedgerAccounts(arg0 context.Context) ([]db.LedgerAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerAccounts", arg0)
+	ret0, _ := ret[0].([]db.LedgerAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerAccounts indicates an expected call of ListLedgerAccounts.
+func (mr *MockStoreMockRecorder) ListLedgerAccounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerAccounts", reflect.TypeOf((*MockStore)(nil).ListLedgerAccounts), arg0)
+}
+
+// ListMemberSpendsSince mocks base method.
+func (m *MockStore) ListMemberSpendsSince(arg0 context.Context, arg1 db.ListMemberSpendsSinceParams) ([]db.ListMemberSpendsSinceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMemberSpendsSince", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListMemberSpendsSinceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMemberSpendsSince indicates an expected call of ListMemberSpendsSince.
+func (mr *MockStoreMockRecorder) ListMemberSpendsSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMemberSpendsSince", reflect.TypeOf((*MockStore)(nil).ListMemberSpendsSince), arg0, arg1)
+}
+
+// ListOutgoingPaymentRequests mocks base method.
+func (m *MockStore) ListOutgoingPaymentRequests(arg0 context.Context, arg1 db.ListOutgoingPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingPaymentRequests indicates an expected call of ListOutgoingPaymentRequests.
+func (mr *MockStoreMockRecorder) ListOutgoingPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListOutgoingPaymentRequests), arg0, arg1)
+}
+
+// ListOutgoingTransfersSince mocks base method.
+func (m *MockStore) ListOutgoingTransfersSince(arg0 context.Context, arg1 db.ListOutgoingTransfersSinceParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutgoingTransfersSince", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutgoingTransfersSince indicates an expected call of ListOutgoingTransfersSince.
+func (mr *MockStoreMockRecorder) ListOutgoingTransfersSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutgoingTransfersSince", reflect.TypeOf((*MockStore)(nil).ListOutgoingTransfersSince), arg0, arg1)
+}
+
+// ListOutstandingInvoices mocks base method.
+func (m *MockStore) ListOutstandingInvoices(arg0 context.Context, arg1 string) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutstandingInvoices", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutstandingInvoices indicates an expected call of ListOutstandingInvoices.
+func (mr *MockStoreMockRecorder) ListOutstandingInvoices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutstandingInvoices", reflect.TypeOf((*MockStore)(nil).ListOutstandingInvoices), arg0, arg1)
+}
+
+// ListOwnerLimitAccounts mocks base method.
+func (m *MockStore) ListOwnerLimitAccounts(arg0 context.Context, arg1 db.ListOwnerLimitAccountsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerLimitAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerLimitAccounts indicates an expected call of ListOwnerLimitAccounts.
+func (mr *MockStoreMockRecorder) ListOwnerLimitAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerLimitAccounts", reflect.TypeOf((*MockStore)(nil).ListOwnerLimitAccounts), arg0, arg1)
+}
+
+// ListPots mocks base method.
+func (m *MockStore) ListPots(arg0 context.Context, arg1 int64) ([]db.ListPotsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPots", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListPotsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPots indicates an expected call of ListPots.
+func (mr *MockStoreMockRecorder) ListPots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPots", reflect.TypeOf((*MockStore)(nil).ListPots), arg0, arg1)
+}
+
+// ListSubscribedWebhookEndpoints mocks base method.
+func (m *MockStore) ListSubscribedWebhookEndpoints(arg0 context.Context, arg1 db.ListSubscribedWebhookEndpointsParams) ([]db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscribedWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscribedWebhookEndpoints indicates an expected call of ListSubscribedWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListSubscribedWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscribedWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListSubscribedWebhookEndpoints), arg0, arg1)
+}
+
+// ListTransfer mocks base method.
+func (m *MockStore) ListTransfer(arg0 context.Context, arg1 db.ListTransferParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfer", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfer indicates an expected call of ListTransfer.
+func (mr *MockStoreMockRecorder) ListTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfer", reflect.TypeOf((*MockStore)(nil).ListTransfer), arg0, arg1)
+}
+
+// ListTransferApprovals mocks base method.
+func (m *MockStore) ListTransferApprovals(arg0 context.Context, arg1 int64) ([]db.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferApprovals indicates an expected call of ListTransferApprovals.
+func (mr *MockStoreMockRecorder) ListTransferApprovals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferApprovals", reflect.TypeOf((*MockStore)(nil).ListTransferApprovals), arg0, arg1)
+}
+
+// ListTransferEntryCounts mocks base method.
+func (m *MockStore) ListTransferEntryCounts(arg0 context.Context, arg1 db.ListTransferEntryCountsParams) ([]db.ListTransferEntryCountsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferEntryCounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListTransferEntryCountsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferEntryCounts indicates an expected call of ListTransferEntryCounts.
+func (mr *MockStoreMockRecorder) ListTransferEntryCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferEntryCounts", reflect.TypeOf((*MockStore)(nil).ListTransferEntryCounts), arg0, arg1)
+}
+
+// ListTransferLimits mocks base method.
+func (m *MockStore) ListTransferLimits(arg0 context.Context, arg1 db.ListTransferLimitsParams) ([]db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferLimits", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferLimits indicates an expected call of ListTransferLimits.
+func (mr *MockStoreMockRecorder) ListTransferLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferLimits", reflect.TypeOf((*MockStore)(nil).ListTransferLimits), arg0, arg1)
+}
+
+// ListUnpostedInterestAccounts mocks base method.
+func (m *MockStore) ListUnpostedInterestAccounts(arg0 context.Context, arg1 db.ListUnpostedInterestAccountsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnpostedInterestAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnpostedInterestAccounts indicates an expected call of ListUnpostedInterestAccounts.
+func (mr *MockStoreMockRecorder) ListUnpostedInterestAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnpostedInterestAccounts", reflect.TypeOf((*MockStore)(nil).ListUnpostedInterestAccounts), arg0, arg1)
+}
+
+// ListUnusedRecoveryCodes mocks base method.
+func (m *MockStore) ListUnusedRecoveryCodes(arg0 context.Context, arg1 string) ([]db.MfaRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnusedRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]db.MfaRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnusedRecoveryCodes indicates an expected call of ListUnusedRecoveryCodes.
+func (mr *MockStoreMockRecorder) ListUnusedRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnusedRecoveryCodes", reflect.TypeOf((*MockStore)(nil).ListUnusedRecoveryCodes), arg0, arg1)
+}
+
+// ListUserAliases mocks base method.
+func (m *MockStore) ListUserAliases(arg0 context.Context, arg1 string) ([]db.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserAliases", arg0, arg1)
+	ret0, _ := ret[0].([]db.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserAliases indicates an expected call of ListUserAliases.
+func (mr *MockStoreMockRecorder) ListUserAliases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAliases", reflect.TypeOf((*MockStore)(nil).ListUserAliases), arg0, arg1)
+}
+
+// ListWebhookAttempts mocks base method.
+func (m *MockStore) ListWebhookAttempts(arg0 context.Context, arg1 int64) ([]db.WebhookAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookAttempts", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookAttempts indicates an expected call of ListWebhookAttempts.
+func (mr *MockStoreMockRecorder) ListWebhookAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookAttempts", reflect.TypeOf((*MockStore)(nil).ListWebhookAttempts), arg0, arg1)
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
+}
+
+// ListWebhookEndpoints mocks base method.
+func (m *MockStore) ListWebhookEndpoints(arg0 context.Context, arg1 string) ([]db.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookEndpoints indicates an expected call of ListWebhookEndpoints.
+func (mr *MockStoreMockRecorder) ListWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookEndpoints", reflect.TypeOf((*MockStore)(nil).ListWebhookEndpoints), arg0, arg1)
+}
+
+// LockAccounts mocks base method.
+func (m *MockStore) LockAccounts(arg0 context.Context, arg1 []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockAccounts indicates an expected call of LockAccounts.
+func (mr *MockStoreMockRecorder) LockAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccounts", reflect.TypeOf((*MockStore)(nil).LockAccounts), arg0, arg1)
+}
+
+// LockAuditChain mocks base method.
+func (m *MockStore) LockAuditChain(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAuditChain", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockAuditChain indicates an expected call of LockAuditChain.
+func (mr *MockStoreMockRecorder) LockAuditChain(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAuditChain", reflect.TypeOf((*MockStore)(nil).LockAuditChain), arg0, arg1)
+}
+
+// LockUser mocks base method.
+func (m *MockStore) LockUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUser indicates an expected call of LockUser.
+func (mr *MockStoreMockRecorder) LockUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUser", reflect.TypeOf((*MockStore)(nil).LockUser), arg0, arg1)
+}
+
+// MarkInvoicesOverdue mocks base method.
+func (m *MockStore) MarkInvoicesOverdue(arg0 context.Context, arg1 time.Time) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInvoicesOverdue", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkInvoicesOverdue indicates an expected call of MarkInvoicesOverdue.
+func (mr *MockStoreMockRecorder) MarkInvoicesOverdue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInvoicesOverdue", reflect.TypeOf((*MockStore)(nil).MarkInvoicesOverdue), arg0, arg1)
+}
+
+// MarkInvoicesOverdueTrxn mocks base method.
+func (m *MockStore) MarkInvoicesOverdueTrxn(arg0 context.Context, arg1 time.Time) ([]db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInvoicesOverdueTrxn", arg0, arg1)
+	ret0, _ := ret[0].([]db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkInvoicesOverdueTrxn indicates an expected call of MarkInvoicesOverdueTrxn.
+func (mr *MockStoreMockRecorder) MarkInvoicesOverdueTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInvoicesOverdueTrxn", reflect.TypeOf((*MockStore)(nil).MarkInvoicesOverdueTrxn), arg0, arg1)
+}
+
+// MarkOutboxEventDispatched mocks base method.
+func (m *MockStore) MarkOutboxEventDispatched(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventDispatched", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventDispatched indicates an expected call of MarkOutboxEventDispatched.
+func (mr *MockStoreMockRecorder) MarkOutboxEventDispatched(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventDispatched", reflect.TypeOf((*MockStore)(nil).MarkOutboxEventDispatched), arg0, arg1)
+}
+
+// MarkRecoveryCodeUsed mocks base method.
+func (m *MockStore) MarkRecoveryCodeUsed(arg0 context.Context, arg1 int64) (db.MfaRecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecoveryCodeUsed", arg0, arg1)
+	ret0, _ := ret[0].(db.MfaRecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecoveryCodeUsed indicates an expected call of MarkRecoveryCodeUsed.
+func (mr *MockStoreMockRecorder) MarkRecoveryCodeUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecoveryCodeUsed", reflect.TypeOf((*MockStore)(nil).MarkRecoveryCodeUsed), arg0, arg1)
+}
+
+// MovePotTrxn mocks base method.
+func (m *MockStore) MovePotTrxn(arg0 context.Context, arg1 db.MovePotTxnParams) (db.TransferTrxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MovePotTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTrxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MovePotTrxn indicates an expected call of MovePotTrxn.
+func (mr *MockStoreMockRecorder) MovePotTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MovePotTrxn", reflect.TypeOf((*MockStore)(nil).MovePotTrxn), arg0, arg1)
+}
+
+// NextAccountSequence mocks base method.
+func (m *MockStore) NextAccountSequence(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextAccountSequence", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextAccountSequence indicates an expected call of NextAccountSequence.
+func (mr *MockStoreMockRecorder) NextAccountSequence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextAccountSequence", reflect.TypeOf((*MockStore)(nil).NextAccountSequence), arg0)
+}
+
+// NotifyAccountEvent mocks base method.
+func (m *MockStore) NotifyAccountEvent(arg0 context.Context, arg1 db.NotifyAccountEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyAccountEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyAccountEvent indicates an expected call of NotifyAccountEvent.
+func (mr *MockStoreMockRecorder) NotifyAccountEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyAccountEvent", reflect.TypeOf((*MockStore)(nil).NotifyAccountEvent), arg0, arg1)
+}
+
+// OpenAccountTrxn mocks base method.
+func (m *MockStore) OpenAccountTrxn(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAccountTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenAccountTrxn indicates an expected call of OpenAccountTrxn.
+func (mr *MockStoreMockRecorder) OpenAccountTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAccountTrxn", reflect.TypeOf((*MockStore)(nil).OpenAccountTrxn), arg0, arg1)
+}
+
+// PerformTransactionTrxn mocks base method.
+func (m *MockStore) PerformTransactionTrxn(arg0 context.Context, arg1 db.TransferTxnParams) (db.TransferTrxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PerformTransactionTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTrxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PerformTransactionTrxn indicates an expected call of PerformTransactionTrxn.
+func (mr *MockStoreMockRecorder) PerformTransactionTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PerformTransactionTrxn", reflect.TypeOf((*MockStore)(nil).PerformTransactionTrxn), arg0, arg1)
+}
+
+// PostInterestTrxn mocks base method.
+func (m *MockStore) PostInterestTrxn(arg0 context.Context, arg1 db.PostInterestTxnParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostInterestTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostInterestTrxn indicates an expected call of PostInterestTrxn.
+func (mr *MockStoreMockRecorder) PostInterestTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterestTrxn", reflect.TypeOf((*MockStore)(nil).PostInterestTrxn), arg0, arg1)
+}
+
+// PostJournalTrxn mocks base method.
+func (m *MockStore) PostJournalTrxn(arg0 context.Context, arg1 db.PostJournalTxnParams) (db.PostJournalTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostJournalTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.PostJournalTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostJournalTrxn indicates an expected call of PostJournalTrxn.
+func (mr *MockStoreMockRecorder) PostJournalTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostJournalTrxn", reflect.TypeOf((*MockStore)(nil).PostJournalTrxn), arg0, arg1)
+}
+
+// RecordAuditEventTrxn mocks base method.
+func (m *MockStore) RecordAuditEventTrxn(arg0 context.Context, arg1 db.RecordAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAuditEventTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordAuditEventTrxn indicates an expected call of RecordAuditEventTrxn.
+func (mr *MockStoreMockRecorder) RecordAuditEventTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAuditEventTrxn", reflect.TypeOf((*MockStore)(nil).RecordAuditEventTrxn), arg0, arg1)
+}
+
+// RecordInvoicePayment mocks base method.
+func (m *MockStore) RecordInvoicePayment(arg0 context.Context, arg1 db.RecordInvoicePaymentParams) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordInvoicePayment", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordInvoicePayment indicates an expected call of RecordInvoicePayment.
+func (mr *MockStoreMockRecorder) RecordInvoicePayment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordInvoicePayment", reflect.TypeOf((*MockStore)(nil).RecordInvoicePayment), arg0, arg1)
+}
+
+// RecordTOTPFailure mocks base method.
+func (m *MockStore) RecordTOTPFailure(arg0 context.Context, arg1 db.RecordTOTPFailureParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTOTPFailure", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordTOTPFailure indicates an expected call of RecordTOTPFailure.
+func (mr *MockStoreMockRecorder) RecordTOTPFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTOTPFailure", reflect.TypeOf((*MockStore)(nil).RecordTOTPFailure), arg0, arg1)
+}
+
+// RecordWebhookAttemptTrxn mocks base method.
+func (m *MockStore) RecordWebhookAttemptTrxn(arg0 context.Context, arg1 db.RecordWebhookAttemptTxnParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookAttemptTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookAttemptTrxn indicates an expected call of RecordWebhookAttemptTrxn.
+func (mr *MockStoreMockRecorder) RecordWebhookAttemptTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttemptTrxn", reflect.TypeOf((*MockStore)(nil).RecordWebhookAttemptTrxn), arg0, arg1)
+}
+
+// RedeliverWebhook mocks base method.
+func (m *MockStore) RedeliverWebhook(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverWebhook indicates an expected call of RedeliverWebhook.
+func (mr *MockStoreMockRecorder) RedeliverWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhook", reflect.TypeOf((*MockStore)(nil).RedeliverWebhook), arg0, arg1)
+}
+
+// ReleaseEscrowTrxn mocks base method.
+func (m *MockStore) ReleaseEscrowTrxn(arg0 context.Context, arg1 int64) (db.EscrowTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseEscrowTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseEscrowTrxn indicates an expected call of ReleaseEscrowTrxn.
+func (mr *MockStoreMockRecorder) ReleaseEscrowTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseEscrowTrxn", reflect.TypeOf((*MockStore)(nil).ReleaseEscrowTrxn), arg0, arg1)
+}
+
+// RenameBeneficiary mocks base method.
+func (m *MockStore) RenameBeneficiary(arg0 context.Context, arg1 db.RenameBeneficiaryParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameBeneficiary indicates an expected call of RenameBeneficiary.
+func (mr *MockStoreMockRecorder) RenameBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameBeneficiary", reflect.TypeOf((*MockStore)(nil).RenameBeneficiary), arg0, arg1)
+}
+
+// ReplaceRecoveryCodesTrxn mocks base method.
+func (m *MockStore) ReplaceRecoveryCodesTrxn(arg0 context.Context, arg1 db.ReplaceRecoveryCodesTxnParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodesTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceRecoveryCodesTrxn indicates an expected call of ReplaceRecoveryCodesTrxn.
+func (mr *MockStoreMockRecorder) ReplaceRecoveryCodesTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodesTrxn", reflect.TypeOf((*MockStore)(nil).ReplaceRecoveryCodesTrxn), arg0, arg1)
+}
+
+// ResolveEscrowTrxn mocks base method.
+func (m *MockStore) ResolveEscrowTrxn(arg0 context.Context, arg1 db.ResolveEscrowTxnParams) (db.EscrowTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveEscrowTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveEscrowTrxn indicates an expected call of ResolveEscrowTrxn.
+func (mr *MockStoreMockRecorder) ResolveEscrowTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveEscrowTrxn", reflect.TypeOf((*MockStore)(nil).ResolveEscrowTrxn), arg0, arg1)
+}
+
+// ReviewKYCTrxn mocks base method.
+func (m *MockStore) ReviewKYCTrxn(arg0 context.Context, arg1 db.ReviewKYCTxnParams) (db.ReviewKYCTxnResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewKYCTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.ReviewKYCTxnResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewKYCTrxn indicates an expected call of ReviewKYCTrxn.
+func (mr *MockStoreMockRecorder) ReviewKYCTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewKYCTrxn", reflect.TypeOf((*MockStore)(nil).ReviewKYCTrxn), arg0, arg1)
+}
+
+// ReviewKycProfile mocks base method.
+func (m *MockStore) ReviewKycProfile(arg0 context.Context, arg1 db.ReviewKycProfileParams) (db.KycProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewKycProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.KycProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewKycProfile indicates an expected call of ReviewKycProfile.
+func (mr *MockStoreMockRecorder) ReviewKycProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewKycProfile", reflect.TypeOf((*MockStore)(nil).ReviewKycProfile), arg0, arg1)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockStore) RevokeAccessToken(arg0 context.Context, arg1 db.RevokeAccessTokenParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockStoreMockRecorder) RevokeAccessToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockStore)(nil).RevokeAccessToken), arg0, arg1)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockStore) RevokeApiKey(arg0 context.Context, arg1 db.RevokeApiKeyParams) (db.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", arg0, arg1)
+	ret0, _ := ret[0].(db.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockStoreMockRecorder) RevokeApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockStore)(nil).RevokeApiKey), arg0, arg1)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockStore) RevokeRefreshToken(arg0 context.Context, arg1 string) (db.OauthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockStoreMockRecorder) RevokeRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockStore)(nil).RevokeRefreshToken), arg0, arg1)
+}
+
+// RotateRefreshTokenTrxn mocks base method.
+func (m *MockStore) RotateRefreshTokenTrxn(arg0 context.Context, arg1 db.RotateRefreshTokenTxnParams) (db.OauthRefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshTokenTrxn", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthRefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshTokenTrxn indicates an expected call of RotateRefreshTokenTrxn.
+func (mr *MockStoreMockRecorder) RotateRefreshTokenTrxn(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshTokenTrxn", reflect.TypeOf((*MockStore)(nil).RotateRefreshTokenTrxn), arg0, arg1)
+}
+
+// SendInvoice mocks base method.
+func (m *MockStore) SendInvoice(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendInvoice indicates an expected call of SendInvoice.
+func (mr *MockStoreMockRecorder) SendInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendInvoice", reflect.TypeOf((*MockStore)(nil).SendInvoice), arg0, arg1)
+}
+
+// SetAccountNumber mocks base method.
+func (m *MockStore) SetAccountNumber(arg0 context.Context, arg1 db.SetAccountNumberParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAccountNumber indicates an expected call of SetAccountNumber.
+func (mr *MockStoreMockRecorder) SetAccountNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountNumber", reflect.TypeOf((*MockStore)(nil).SetAccountNumber), arg0, arg1)
+}
+
+// SetAccountStatus mocks base method.
+func (m *MockStore) SetAccountStatus(arg0 context.Context, arg1 db.SetAccountStatusParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAccountStatus indicates an expected call of SetAccountStatus.
+func (mr *MockStoreMockRecorder) SetAccountStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountStatus", reflect.TypeOf((*MockStore)(nil).SetAccountStatus), arg0, arg1)
+}
+
+// SetEscrowFundJournal mocks base method.
+func (m *MockStore) SetEscrowFundJournal(arg0 context.Context, arg1 db.SetEscrowFundJournalParams) (db.EscrowContract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEscrowFundJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.EscrowContract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEscrowFundJournal indicates an expected call of SetEscrowFundJournal.
+func (mr *MockStoreMockRecorder) SetEscrowFundJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEscrowFundJournal", reflect.TypeOf((*MockStore)(nil).SetEscrowFundJournal), arg0, arg1)
+}
+
+// SetInterestPostingJournal mocks base method.
+func (m *MockStore) SetInterestPostingJournal(arg0 context.Context, arg1 db.SetInterestPostingJournalParams) (db.InterestPosting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInterestPostingJournal", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestPosting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetInterestPostingJournal indicates an expected call of SetInterestPostingJournal.
+func (mr *MockStoreMockRecorder) SetInterestPostingJournal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterestPostingJournal", reflect.TypeOf((*MockStore)(nil).SetInterestPostingJournal), arg0, arg1)
+}
+
+// SetUserTier mocks base method.
+func (m *MockStore) SetUserTier(arg0 context.Context, arg1 db.SetUserTierParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTier", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserTier indicates an expected call of SetUserTier.
+func (mr *MockStoreMockRecorder) SetUserTier(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTier", reflect.TypeOf((*MockStore)(nil).SetUserTier), arg0, arg1)
+}
+
+// SubmitKycProfile mocks base method.
+func (m *MockStore) SubmitKycProfile(arg0 context.Context, arg1 db.SubmitKycProfileParams) (db.KycProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitKycProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.KycProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitKycProfile indicates an expected call of SubmitKycProfile.
+func (mr *MockStoreMockRecorder) SubmitKycProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitKycProfile", reflect.TypeOf((*MockStore)(nil).SubmitKycProfile), arg0, arg1)
+}
+
+// SumEntriesBetween mocks base method.
+func (m *MockStore) SumEntriesBetween(arg0 context.Context, arg1 db.SumEntriesBetweenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesBetween", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesBetween indicates an expected call of SumEntriesBetween.
+func (mr *MockStoreMockRecorder) SumEntriesBetween(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesBetween", reflect.TypeOf((*MockStore)(nil).SumEntriesBetween), arg0, arg1)
+}
+
+// SumInterestAccruals mocks base method.
+func (m *MockStore) SumInterestAccruals(arg0 context.Context, arg1 db.SumInterestAccrualsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumInterestAccruals", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumInterestAccruals indicates an expected call of SumInterestAccruals.
+func (mr *MockStoreMockRecorder) SumInterestAccruals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumInterestAccruals", reflect.TypeOf((*MockStore)(nil).SumInterestAccruals), arg0, arg1)
+}
+
+// SumPostingsByCurrency mocks base method.
+func (m *MockStore) SumPostingsByCurrency(arg0 context.Context) ([]db.SumPostingsByCurrencyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumPostingsByCurrency", arg0)
+	ret0, _ := ret[0].([]db.SumPostingsByCurrencyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumPostingsByCurrency indicates an expected call of SumPostingsByCurrency.
+func (mr *MockStoreMockRecorder) SumPostingsByCurrency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumPostingsByCurrency", reflect.TypeOf((*MockStore)(nil).SumPostingsByCurrency), arg0)
+}
+
+// TouchApiKey mocks base method.
+func (m *MockStore) TouchApiKey(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchApiKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchApiKey indicates an expected call of TouchApiKey.
+func (mr *MockStoreMockRecorder) TouchApiKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchApiKey", reflect.TypeOf((*MockStore)(nil).TouchApiKey), arg0, arg1)
+}
+
+// UpdateAccount mocks base method.
+func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccount indicates an expected call of UpdateAccount.
 func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
 }
+
+// UpdatePot mocks base method.
+func (m *MockStore) UpdatePot(arg0 context.Context, arg1 db.UpdatePotParams) (db.Pot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePot", arg0, arg1)
+	ret0, _ := ret[0].(db.Pot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePot indicates an expected call of UpdatePot.
+func (mr *MockStoreMockRecorder) UpdatePot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePot", reflect.TypeOf((*MockStore)(nil).UpdatePot), arg0, arg1)
+}
+
+// UpdateUserTOTPCounter mocks base method.
+func (m *MockStore) UpdateUserTOTPCounter(arg0 context.Context, arg1 db.UpdateUserTOTPCounterParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPCounter", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTOTPCounter indicates an expected call of UpdateUserTOTPCounter.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPCounter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPCounter", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPCounter), arg0, arg1)
+}
+
+// UpdateUserTOTPSecret mocks base method.
+func (m *MockStore) UpdateUserTOTPSecret(arg0 context.Context, arg1 db.UpdateUserTOTPSecretParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTOTPSecret indicates an expected call of UpdateUserTOTPSecret.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPSecret", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPSecret), arg0, arg1)
+}
+
+// UpdateWebhookDelivery mocks base method.
+func (m *MockStore) UpdateWebhookDelivery(arg0 context.Context, arg1 db.UpdateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhookDelivery indicates an expected call of UpdateWebhookDelivery.
+func (mr *MockStoreMockRecorder) UpdateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).UpdateWebhookDelivery), arg0, arg1)
+}
+
+// UpsertApprovalPolicy mocks base method.
+func (m *MockStore) UpsertApprovalPolicy(arg0 context.Context, arg1 db.UpsertApprovalPolicyParams) (db.ApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertApprovalPolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertApprovalPolicy indicates an expected call of UpsertApprovalPolicy.
+func (mr *MockStoreMockRecorder) UpsertApprovalPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertApprovalPolicy", reflect.TypeOf((*MockStore)(nil).UpsertApprovalPolicy), arg0, arg1)
+}
+
+// UpsertTransferLimit mocks base method.
+func (m *MockStore) UpsertTransferLimit(arg0 context.Context, arg1 db.UpsertTransferLimitParams) (db.TransferLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTransferLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTransferLimit indicates an expected call of UpsertTransferLimit.
+func (mr *MockStoreMockRecorder) UpsertTransferLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTransferLimit", reflect.TypeOf((*MockStore)(nil).UpsertTransferLimit), arg0, arg1)
+}
+
+// UseAuthorizationCode mocks base method.
+func (m *MockStore) UseAuthorizationCode(arg0 context.Context, arg1 string) (db.OauthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseAuthorizationCode", arg0, arg1)
+	ret0, _ := ret[0].(db.OauthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseAuthorizationCode indicates an expected call of UseAuthorizationCode.
+func (mr *MockStoreMockRecorder) UseAuthorizationCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAuthorizationCode", reflect.TypeOf((*MockStore)(nil).UseAuthorizationCode), arg0, arg1)
+}
+
+// VerifyAlias mocks base method.
+func (m *MockStore) VerifyAlias(arg0 context.Context, arg1 db.VerifyAliasParams) (db.Alias, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAlias", arg0, arg1)
+	ret0, _ := ret[0].(db.Alias)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAlias indicates an expected call of VerifyAlias.
+func (mr *MockStoreMockRecorder) VerifyAlias(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAlias", reflect.TypeOf((*MockStore)(nil).VerifyAlias), arg0, arg1)
+}
+
+// VoidInvoice mocks base method.
+func (m *MockStore) VoidInvoice(arg0 context.Context, arg1 int64) (db.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidInvoice", arg0, arg1)
+	ret0, _ := ret[0].(db.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidInvoice indicates an expected call of VoidInvoice.
+func (mr *MockStoreMockRecorder) VoidInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidInvoice", reflect.TypeOf((*MockStore)(nil).VoidInvoice), arg0, arg1)
+}
@@ -2,11 +2,19 @@
 INSERT INTO accounts (
  owner,
  balance,
- currency_code
+ currency_code,
+ product_code,
+ account_number
 ) VALUES (
-    $1,$2, $3
+    $1,$2, $3, $4, $5
 ) RETURNING *;
 
+-- name: CountOwnerProductAccounts :one
+SELECT COUNT(*) FROM accounts
+WHERE owner = $1
+AND currency_code = $2
+AND product_code = $3
+AND parent_account_id IS NULL;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
@@ -18,10 +26,14 @@ WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE;
 
 -- name: ListAccounts :many
 SELECT * FROM accounts
-WHERE owner = $1 
+WHERE owner = sqlc.arg(username)
+OR id IN (
+    SELECT account_id FROM account_members
+    WHERE username = sqlc.arg(username) AND status = 'active'
+)
 ORDER BY id
-LIMIT $2
-OFFSET $3;
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
 
 -- name: AddAccountBalance :one
 UPDATE accounts 
@@ -37,4 +49,28 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+-- name: SetAccountStatus :one
+UPDATE accounts
+SET status = sqlc.arg(status)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE account_number = $1 LIMIT 1;
+
+-- name: NextAccountSequence :one
+SELECT nextval('account_number_seq')::bigint;
+
+-- name: ListAccountsWithoutNumber :many
+SELECT * FROM accounts
+WHERE account_number IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: SetAccountNumber :one
+UPDATE accounts
+SET account_number = $2
+WHERE id = $1 AND account_number IS NULL
+RETURNING *;
@@ -0,0 +1,53 @@
+-- name: ClaimAlias :one
+INSERT INTO aliases (
+    alias,
+    kind,
+    username,
+    code_hash,
+    code_expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (alias) DO UPDATE
+SET username = EXCLUDED.username,
+    code_hash = EXCLUDED.code_hash,
+    code_expires_at = EXCLUDED.code_expires_at,
+    attempts = 0,
+    created_at = now()
+WHERE aliases.verified_at IS NULL
+AND aliases.code_expires_at <= now()
+RETURNING *;
+
+-- name: GetAlias :one
+SELECT * FROM aliases
+WHERE alias = $1 LIMIT 1;
+
+-- name: ListUserAliases :many
+SELECT * FROM aliases
+WHERE username = $1
+ORDER BY created_at;
+
+-- name: IncrementAliasAttempts :one
+UPDATE aliases
+SET attempts = attempts + 1
+WHERE alias = $1 AND verified_at IS NULL
+RETURNING *;
+
+-- name: VerifyAlias :one
+UPDATE aliases
+SET verified_at = now()
+WHERE alias = $1 AND username = $2 AND verified_at IS NULL
+RETURNING *;
+
+-- name: DeleteAlias :exec
+DELETE FROM aliases
+WHERE alias = $1 AND username = $2;
+
+-- name: GetPrimaryAccount :one
+SELECT * FROM accounts
+WHERE owner = $1
+AND currency_code = $2
+AND status = 'active'
+AND parent_account_id IS NULL
+AND product_code <> 'escrow'
+ORDER BY product_code = 'checking' DESC, id
+LIMIT 1;
@@ -0,0 +1,112 @@
+-- name: UpsertApprovalPolicy :one
+INSERT INTO approval_policies (
+    account_id,
+    min_amount,
+    required_approvals,
+    approver_role,
+    approvers,
+    expiry_seconds
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, min_amount) DO UPDATE
+SET required_approvals = EXCLUDED.required_approvals,
+    approver_role = EXCLUDED.approver_role,
+    approvers = EXCLUDED.approvers,
+    expiry_seconds = EXCLUDED.expiry_seconds,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetApprovalPolicy :one
+SELECT * FROM approval_policies
+WHERE id = $1 LIMIT 1;
+
+-- name: ListApprovalPolicies :many
+SELECT * FROM approval_policies
+WHERE account_id = $1
+ORDER BY min_amount;
+
+-- name: DeleteApprovalPolicy :exec
+DELETE FROM approval_policies
+WHERE id = $1;
+
+-- name: GetApplicableApprovalPolicy :one
+SELECT * FROM approval_policies
+WHERE account_id = sqlc.arg(account_id) AND min_amount <= sqlc.arg(amount)
+ORDER BY min_amount DESC
+LIMIT 1;
+
+-- name: CreatePendingTransfer :one
+INSERT INTO pending_transfers (
+    from_account_id,
+    to_account_id,
+    amount,
+    initiated_by,
+    policy_id,
+    required_approvals,
+    approver_role,
+    approvers,
+    expires_at,
+    payment_request_id,
+    invoice_id,
+    escrow_release_seconds,
+    escrow_description
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetPendingTransfer :one
+SELECT * FROM pending_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPendingTransferForUpdate :one
+SELECT * FROM pending_transfers
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListAccountPendingTransfers :many
+SELECT * FROM pending_transfers
+WHERE from_account_id = $1 AND status = $2
+ORDER BY created_at DESC
+LIMIT $3
+OFFSET $4;
+
+-- name: ListApprovableTransfers :many
+SELECT * FROM pending_transfers
+WHERE status = 'pending'
+AND expires_at > now()
+AND initiated_by <> sqlc.arg(username)
+AND (sqlc.arg(username) = ANY(approvers) OR approver_role = sqlc.arg(role))
+ORDER BY created_at
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ClosePendingTransfer :one
+UPDATE pending_transfers
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ExpirePendingTransfers :many
+UPDATE pending_transfers
+SET status = 'expired',
+    decided_at = now()
+WHERE status = 'pending' AND expires_at <= sqlc.arg(now)
+RETURNING *;
+
+-- name: CreateTransferApproval :one
+INSERT INTO transfer_approvals (
+    pending_transfer_id,
+    approver,
+    decision,
+    note
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTransferApprovals :many
+SELECT * FROM transfer_approvals
+WHERE pending_transfer_id = $1
+ORDER BY created_at;
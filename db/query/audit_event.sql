@@ -0,0 +1,44 @@
+-- name: LockAuditChain :exec
+SELECT pg_advisory_xact_lock(hashtext('audit_events'), sqlc.arg(chain)::int);
+
+-- name: GetLastAuditEvent :one
+SELECT * FROM audit_events
+WHERE chain = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+    actor,
+    action,
+    resource_type,
+    resource_id,
+    before,
+    after,
+    ip,
+    request_id,
+    prev_hash,
+    hash,
+    created_at,
+    chain
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor))
+AND (sqlc.narg(action)::varchar IS NULL OR action = sqlc.narg(action))
+AND (sqlc.narg(resource_type)::varchar IS NULL OR resource_type = sqlc.narg(resource_type))
+AND (sqlc.narg(resource_id)::varchar IS NULL OR resource_id = sqlc.narg(resource_id))
+AND (sqlc.narg(from_time)::timestamptz IS NULL OR created_at >= sqlc.narg(from_time))
+AND (sqlc.narg(to_time)::timestamptz IS NULL OR created_at < sqlc.narg(to_time))
+ORDER BY id DESC
+LIMIT sqlc.arg(page_limit)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListAuditEventsAfter :many
+SELECT * FROM audit_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
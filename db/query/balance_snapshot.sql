@@ -0,0 +1,37 @@
+-- name: ListAccountIDs :many
+SELECT id FROM accounts
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: CreateBalanceSnapshots :exec
+INSERT INTO balance_snapshots (account_id, as_of, balance)
+SELECT a.id, sqlc.arg(as_of)::timestamptz,
+    COALESCE(prev.balance, 0) + COALESCE((
+        SELECT SUM(e.amount) FROM entries e
+        WHERE e.account_id = a.id
+        AND e.created_at >= COALESCE(prev.as_of, '-infinity')
+        AND e.created_at < sqlc.arg(as_of)::timestamptz
+    ), 0)
+FROM accounts a
+LEFT JOIN LATERAL (
+    SELECT s.balance, s.as_of FROM balance_snapshots s
+    WHERE s.account_id = a.id AND s.as_of < sqlc.arg(as_of)::timestamptz
+    ORDER BY s.as_of DESC
+    LIMIT 1
+) prev ON true
+WHERE a.id = ANY(sqlc.arg(account_ids)::bigint[])
+ON CONFLICT (account_id, as_of) DO NOTHING;
+
+-- name: GetLatestBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = sqlc.arg(account_id) AND as_of <= sqlc.arg(at)
+ORDER BY as_of DESC
+LIMIT 1;
+
+-- name: SumEntriesBetween :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total
+FROM entries
+WHERE account_id = sqlc.arg(account_id)
+AND created_at >= sqlc.arg(from_time)::timestamptz
+AND created_at <= sqlc.arg(to_time)::timestamptz;
@@ -0,0 +1,40 @@
+-- name: CreateBeneficiary :one
+INSERT INTO beneficiaries (
+    owner,
+    account_id,
+    nickname
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetBeneficiary :one
+SELECT * FROM beneficiaries
+WHERE id = $1 LIMIT 1;
+
+-- name: GetBeneficiaryByAccount :one
+SELECT * FROM beneficiaries
+WHERE owner = $1 AND account_id = $2
+LIMIT 1;
+
+-- name: ListBeneficiaries :many
+SELECT b.id, b.owner, b.account_id, b.nickname, b.created_at, b.updated_at,
+    a.account_number, a.currency_code
+FROM beneficiaries b
+JOIN accounts a ON a.id = b.account_id
+WHERE b.owner = $1
+ORDER BY b.nickname NULLS LAST, b.created_at;
+
+-- name: RenameBeneficiary :one
+UPDATE beneficiaries
+SET nickname = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBeneficiary :exec
+DELETE FROM beneficiaries
+WHERE id = $1;
+
+-- name: CountBeneficiariesAddedSince :one
+SELECT count(*) FROM beneficiaries
+WHERE owner = sqlc.arg(owner) AND created_at >= sqlc.arg(since);
@@ -0,0 +1,72 @@
+-- name: CreateEscrowContract :one
+INSERT INTO escrow_contracts (
+    buyer,
+    seller,
+    buyer_account_id,
+    seller_account_id,
+    amount,
+    currency_code,
+    description,
+    release_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: SetEscrowFundJournal :one
+UPDATE escrow_contracts
+SET fund_journal_id = sqlc.arg(fund_journal_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetEscrowContract :one
+SELECT * FROM escrow_contracts
+WHERE id = $1 LIMIT 1;
+
+-- name: GetEscrowContractForUpdate :one
+SELECT * FROM escrow_contracts
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListEscrowContracts :many
+SELECT * FROM escrow_contracts
+WHERE buyer = sqlc.arg(username) OR seller = sqlc.arg(username)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: ListEscrowContractsByStatus :many
+SELECT * FROM escrow_contracts
+WHERE status = $1
+ORDER BY created_at
+LIMIT $2
+OFFSET $3;
+
+-- name: ListDueEscrowContracts :many
+SELECT * FROM escrow_contracts
+WHERE status = 'funded' AND release_at <= sqlc.arg(now) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: DisputeEscrowContract :one
+UPDATE escrow_contracts
+SET status = 'disputed',
+    disputed_by = sqlc.arg(disputed_by),
+    dispute_reason = sqlc.arg(dispute_reason),
+    disputed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'funded'
+RETURNING *;
+
+-- name: CloseEscrowContract :one
+UPDATE escrow_contracts
+SET status = sqlc.arg(status),
+    settle_journal_id = sqlc.arg(settle_journal_id),
+    resolved_by = sqlc.narg(resolved_by),
+    resolution_note = sqlc.arg(resolution_note),
+    closed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListEscrowContractsFundedSince :many
+SELECT * FROM escrow_contracts
+WHERE buyer_account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND created_at > sqlc.arg(since)
+ORDER BY created_at, id;
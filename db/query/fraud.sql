@@ -0,0 +1,46 @@
+-- name: CreateFraudCase :one
+INSERT INTO fraud_cases (
+    status,
+    decision,
+    from_account_id,
+    to_account_id,
+    amount,
+    initiated_by,
+    verdicts,
+    payment_request_id,
+    invoice_id,
+    escrow_release_seconds,
+    escrow_description
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: GetFraudCase :one
+SELECT * FROM fraud_cases
+WHERE id = $1 LIMIT 1;
+
+-- name: GetFraudCaseForUpdate :one
+SELECT * FROM fraud_cases
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListFraudCases :many
+SELECT * FROM fraud_cases
+WHERE status = $1
+ORDER BY created_at
+LIMIT $2
+OFFSET $3;
+
+-- name: CloseFraudCase :one
+UPDATE fraud_cases
+SET status = sqlc.arg(status),
+    reviewer = sqlc.arg(reviewer),
+    review_note = sqlc.arg(review_note),
+    transfer_id = sqlc.narg(transfer_id),
+    reviewed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'open'
+RETURNING *;
+
+-- name: CountTransfersBetween :one
+SELECT COUNT(*)::bigint AS count FROM transfers
+WHERE from_account_id = $1 AND to_account_id = $2;
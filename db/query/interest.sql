@@ -0,0 +1,83 @@
+-- name: GetAccountProduct :one
+SELECT * FROM account_products
+WHERE code = $1 LIMIT 1;
+
+-- name: ListAccountProducts :many
+SELECT * FROM account_products
+ORDER BY code;
+
+-- name: ListInterestBearingAccounts :many
+SELECT a.id, a.currency_code, p.interest_rate_bps, p.day_count
+FROM accounts a
+JOIN account_products p ON p.code = a.product_code
+WHERE p.interest_rate_bps > 0 AND a.id > sqlc.arg(after_id)
+ORDER BY a.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: CreateInterestAccrual :exec
+INSERT INTO interest_accruals (
+    account_id,
+    accrual_date,
+    balance,
+    rate_bps,
+    day_count,
+    amount_micros
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) ON CONFLICT (account_id, accrual_date) DO NOTHING;
+
+-- name: GetLastInterestAccrualDate :one
+SELECT accrual_date FROM interest_accruals
+ORDER BY accrual_date DESC
+LIMIT 1;
+
+-- name: ListInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+AND accrual_date >= sqlc.arg(period_start)
+AND accrual_date <= sqlc.arg(period_end)
+ORDER BY accrual_date;
+
+-- name: ListUnpostedInterestAccounts :many
+SELECT ia.account_id
+FROM interest_accruals ia
+WHERE ia.accrual_date >= sqlc.arg(period_start)
+AND ia.accrual_date <= sqlc.arg(period_end)
+AND ia.account_id > sqlc.arg(after_id)
+AND NOT EXISTS (
+    SELECT 1 FROM interest_postings ip
+    WHERE ip.account_id = ia.account_id AND ip.period_start = sqlc.arg(period_start)
+)
+GROUP BY ia.account_id
+ORDER BY ia.account_id
+LIMIT sqlc.arg(batch_size);
+
+-- name: SumInterestAccruals :one
+SELECT COALESCE(SUM(amount_micros), 0)::bigint AS total
+FROM interest_accruals
+WHERE account_id = sqlc.arg(account_id)
+AND accrual_date >= sqlc.arg(period_start)
+AND accrual_date <= sqlc.arg(period_end);
+
+-- name: CreateInterestPosting :one
+INSERT INTO interest_postings (
+    account_id,
+    period_start,
+    period_end,
+    accrued_micros,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (account_id, period_start) DO NOTHING
+RETURNING *;
+
+-- name: SetInterestPostingJournal :one
+UPDATE interest_postings
+SET journal_id = sqlc.arg(journal_id)
+WHERE account_id = sqlc.arg(account_id) AND period_start = sqlc.arg(period_start)
+RETURNING *;
+
+-- name: GetInterestPosting :one
+SELECT * FROM interest_postings
+WHERE account_id = $1 AND period_start = $2
+LIMIT 1;
@@ -0,0 +1,103 @@
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+    merchant,
+    to_account_id,
+    customer,
+    currency_code,
+    subtotal,
+    tax_rate_bps,
+    tax_amount,
+    total,
+    pay_token,
+    memo,
+    due_date
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: CreateInvoiceItem :one
+INSERT INTO invoice_items (
+    invoice_id,
+    description,
+    quantity,
+    unit_amount,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetInvoice :one
+SELECT * FROM invoices
+WHERE id = $1 LIMIT 1;
+
+-- name: GetInvoiceByPayToken :one
+SELECT * FROM invoices
+WHERE pay_token = $1 LIMIT 1;
+
+-- name: GetInvoiceForUpdate :one
+SELECT * FROM invoices
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListInvoices :many
+SELECT * FROM invoices
+WHERE merchant = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListInvoiceItems :many
+SELECT * FROM invoice_items
+WHERE invoice_id = $1
+ORDER BY id;
+
+-- name: ListInvoicePayments :many
+SELECT * FROM invoice_payments
+WHERE invoice_id = $1
+ORDER BY id;
+
+-- name: ListOutstandingInvoices :many
+SELECT * FROM invoices
+WHERE merchant = $1 AND status IN ('sent', 'overdue')
+ORDER BY due_date;
+
+-- name: SendInvoice :one
+UPDATE invoices
+SET status = 'sent',
+    sent_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status = 'draft'
+RETURNING *;
+
+-- name: VoidInvoice :one
+UPDATE invoices
+SET status = 'void',
+    voided_at = now(),
+    updated_at = now()
+WHERE id = $1 AND status IN ('draft', 'sent', 'overdue') AND amount_paid = 0
+RETURNING *;
+
+-- name: RecordInvoicePayment :one
+UPDATE invoices
+SET amount_paid = sqlc.arg(amount_paid),
+    status = sqlc.arg(status),
+    paid_at = sqlc.narg(paid_at),
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateInvoicePayment :one
+INSERT INTO invoice_payments (
+    invoice_id,
+    transfer_id,
+    amount
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: MarkInvoicesOverdue :many
+UPDATE invoices
+SET status = 'overdue',
+    updated_at = now()
+WHERE status = 'sent' AND due_date < sqlc.arg(today)
+RETURNING *;
@@ -0,0 +1,93 @@
+-- name: GetKycTier :one
+SELECT * FROM kyc_tiers
+WHERE tier = $1 LIMIT 1;
+
+-- name: ListKycTiers :many
+SELECT * FROM kyc_tiers
+ORDER BY level;
+
+-- name: GetKycTierForUser :one
+SELECT t.* FROM kyc_tiers t
+JOIN users u ON u.tier = t.tier
+WHERE u.username = $1 LIMIT 1;
+
+-- name: CreateKycDocument :one
+INSERT INTO kyc_documents (
+    username,
+    document_type,
+    file_name,
+    content_type,
+    size_bytes,
+    sha256,
+    storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListKycDocuments :many
+SELECT * FROM kyc_documents
+WHERE username = $1
+ORDER BY id;
+
+-- name: SubmitKycProfile :one
+INSERT INTO kyc_profiles (
+    username,
+    status,
+    requested_tier,
+    date_of_birth,
+    phone_number,
+    address_line1,
+    address_line2,
+    city,
+    postal_code,
+    country_code,
+    nationality,
+    id_number,
+    provider,
+    provider_reference,
+    provider_outcome,
+    provider_reasons
+) VALUES (
+    $1, 'pending', $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) ON CONFLICT (username) DO UPDATE
+SET status = 'pending',
+    requested_tier = EXCLUDED.requested_tier,
+    date_of_birth = EXCLUDED.date_of_birth,
+    phone_number = EXCLUDED.phone_number,
+    address_line1 = EXCLUDED.address_line1,
+    address_line2 = EXCLUDED.address_line2,
+    city = EXCLUDED.city,
+    postal_code = EXCLUDED.postal_code,
+    country_code = EXCLUDED.country_code,
+    nationality = EXCLUDED.nationality,
+    id_number = EXCLUDED.id_number,
+    provider = EXCLUDED.provider,
+    provider_reference = EXCLUDED.provider_reference,
+    provider_outcome = EXCLUDED.provider_outcome,
+    provider_reasons = EXCLUDED.provider_reasons,
+    reviewer = NULL,
+    review_note = '',
+    submitted_at = now(),
+    reviewed_at = NULL
+WHERE kyc_profiles.status <> 'pending'
+RETURNING *;
+
+-- name: GetKycProfile :one
+SELECT * FROM kyc_profiles
+WHERE username = $1 LIMIT 1;
+
+-- name: ListKycProfilesByStatus :many
+SELECT * FROM kyc_profiles
+WHERE status = $1
+ORDER BY submitted_at
+LIMIT $2
+OFFSET $3;
+
+-- name: ReviewKycProfile :one
+UPDATE kyc_profiles
+SET status = sqlc.arg(status),
+    reviewer = sqlc.arg(reviewer),
+    review_note = sqlc.arg(review_note),
+    reviewed_at = now()
+WHERE username = sqlc.arg(username) AND status = 'pending'
+RETURNING *;
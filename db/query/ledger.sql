@@ -0,0 +1,47 @@
+-- name: GetLedgerAccountByCode :one
+SELECT * FROM ledger_accounts
+WHERE code = $1 AND currency_code = $2
+LIMIT 1;
+
+-- name: ListLedgerAccounts :many
+SELECT * FROM ledger_accounts
+ORDER BY code, currency_code;
+
+-- name: AddLedgerAccountBalance :one
+UPDATE ledger_accounts
+SET balance = balance + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateJournal :one
+INSERT INTO journals (
+    description,
+    transfer_id
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetJournal :one
+SELECT * FROM journals
+WHERE id = $1 LIMIT 1;
+
+-- name: CreatePosting :one
+INSERT INTO postings (
+    journal_id,
+    account_id,
+    ledger_account_id,
+    entry_id,
+    currency_code,
+    amount
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: ListJournalPostings :many
+SELECT * FROM postings
+WHERE journal_id = $1
+ORDER BY id;
+
+-- name: GetJournalByTransfer :one
+SELECT * FROM journals
+WHERE transfer_id = $1 LIMIT 1;
@@ -0,0 +1,22 @@
+-- name: CreateRecoveryCode :one
+INSERT INTO mfa_recovery_codes (
+    username,
+    hashed_code
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: ListUnusedRecoveryCodes :many
+SELECT * FROM mfa_recovery_codes
+WHERE username = $1 AND used_at IS NULL
+ORDER BY id;
+
+-- name: MarkRecoveryCodeUsed :one
+UPDATE mfa_recovery_codes
+SET used_at = now()
+WHERE id = $1 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE username = $1;
@@ -0,0 +1,69 @@
+-- name: CreateOAuthClient :one
+INSERT INTO oauth_clients (
+    client_id,
+    owner,
+    name,
+    hashed_secret,
+    redirect_uris,
+    scopes
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetOAuthClient :one
+SELECT * FROM oauth_clients
+WHERE client_id = $1 LIMIT 1;
+
+-- name: CreateAuthorizationCode :one
+INSERT INTO oauth_authorization_codes (
+    code_hash,
+    client_id,
+    username,
+    redirect_uri,
+    scopes,
+    account_ids,
+    code_challenge,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: UseAuthorizationCode :one
+UPDATE oauth_authorization_codes
+SET used_at = now()
+WHERE code_hash = $1 AND used_at IS NULL
+RETURNING *;
+
+-- name: CreateRefreshToken :one
+INSERT INTO oauth_refresh_tokens (
+    token_hash,
+    client_id,
+    username,
+    scopes,
+    account_ids,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetRefreshToken :one
+SELECT * FROM oauth_refresh_tokens
+WHERE token_hash = $1 LIMIT 1;
+
+-- name: RevokeRefreshToken :one
+UPDATE oauth_refresh_tokens
+SET revoked_at = now()
+WHERE token_hash = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAccessToken :exec
+INSERT INTO oauth_revoked_access_tokens (
+    token_id,
+    expires_at
+) VALUES (
+    $1, $2
+) ON CONFLICT (token_id) DO NOTHING;
+
+-- name: GetRevokedAccessToken :one
+SELECT * FROM oauth_revoked_access_tokens
+WHERE token_id = $1 LIMIT 1;
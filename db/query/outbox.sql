@@ -0,0 +1,26 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+    event_type,
+    owner,
+    aggregate_type,
+    aggregate_id,
+    payload
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1 LIMIT 1;
+
+-- name: ClaimOutboxEvents :many
+SELECT * FROM outbox_events
+WHERE dispatched_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventDispatched :exec
+UPDATE outbox_events
+SET dispatched_at = now()
+WHERE id = $1;
@@ -0,0 +1,50 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+    requester,
+    payer,
+    to_account_id,
+    amount,
+    currency_code,
+    note,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListIncomingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE payer = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ListOutgoingPaymentRequests :many
+SELECT * FROM payment_requests
+WHERE requester = $1
+ORDER BY created_at DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClosePaymentRequest :one
+UPDATE payment_requests
+SET status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    closed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending'
+RETURNING *;
+
+-- name: ExpirePaymentRequests :many
+UPDATE payment_requests
+SET status = 'expired',
+    closed_at = now()
+WHERE status = 'pending' AND expires_at <= sqlc.arg(now)
+RETURNING *;
@@ -0,0 +1,50 @@
+-- name: CreatePotAccount :one
+INSERT INTO accounts (
+    owner,
+    balance,
+    currency_code,
+    product_code,
+    parent_account_id,
+    account_number
+) VALUES (
+    $1, 0, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: CreatePot :one
+INSERT INTO pots (
+    account_id,
+    parent_account_id,
+    name,
+    target_amount,
+    target_date,
+    round_up_unit
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPot :one
+SELECT * FROM pots
+WHERE account_id = $1 LIMIT 1;
+
+-- name: ListPots :many
+SELECT p.account_id, p.parent_account_id, p.name, p.target_amount, p.target_date,
+    p.round_up_unit, p.created_at, p.updated_at, a.balance
+FROM pots p
+JOIN accounts a ON a.id = p.account_id
+WHERE p.parent_account_id = $1
+ORDER BY p.created_at;
+
+-- name: UpdatePot :one
+UPDATE pots
+SET name = $2,
+    target_amount = $3,
+    target_date = $4,
+    round_up_unit = $5,
+    updated_at = now()
+WHERE account_id = $1
+RETURNING *;
+
+-- name: GetRoundUpPot :one
+SELECT * FROM pots
+WHERE parent_account_id = $1 AND round_up_unit IS NOT NULL
+LIMIT 1;
@@ -0,0 +1,33 @@
+-- name: ListAccountEntryTotals :many
+SELECT a.id, a.owner, a.currency_code, a.balance, a.status,
+    COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListTransferEntryCounts :many
+SELECT t.id, t.from_account_id, t.to_account_id, t.amount,
+    COUNT(e.id) FILTER (WHERE e.account_id = t.from_account_id AND e.amount = -t.amount)::bigint AS from_entries,
+    COUNT(e.id) FILTER (WHERE e.account_id = t.to_account_id AND e.amount = t.amount)::bigint AS to_entries,
+    COUNT(e.id)::bigint AS total_entries
+FROM transfers t
+LEFT JOIN journals j ON j.transfer_id = t.id
+LEFT JOIN entries e ON e.account_id IN (t.from_account_id, t.to_account_id) AND (
+    e.id IN (SELECT p.entry_id FROM postings p WHERE p.journal_id = j.id)
+    OR (j.id IS NULL AND e.created_at = t.created_at)
+)
+WHERE t.id > sqlc.arg(after_id)
+GROUP BY t.id
+ORDER BY t.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: SumPostingsByCurrency :many
+SELECT currency_code,
+    COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)::bigint AS credits,
+    COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0)::bigint AS debits
+FROM postings
+GROUP BY currency_code
+ORDER BY currency_code;
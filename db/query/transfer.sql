@@ -2,9 +2,10 @@
 INSERT INTO transfers (
  from_account_id,
  to_account_id,
- amount
+ amount,
+ initiated_by
 ) VALUES (
-    $1,$2, $3
+    $1,$2, $3, $4
 ) RETURNING *;
 
 
@@ -0,0 +1,112 @@
+-- name: UpsertTransferLimit :one
+INSERT INTO transfer_limits (
+    account_id,
+    product_code,
+    tier,
+    currency_code,
+    max_amount,
+    daily_amount,
+    monthly_amount,
+    daily_count,
+    monthly_count
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) ON CONFLICT (COALESCE(account_id, 0), COALESCE(product_code, ''), COALESCE(tier, ''), COALESCE(currency_code, '')) DO UPDATE
+SET max_amount = EXCLUDED.max_amount,
+    daily_amount = EXCLUDED.daily_amount,
+    monthly_amount = EXCLUDED.monthly_amount,
+    daily_count = EXCLUDED.daily_count,
+    monthly_count = EXCLUDED.monthly_count,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetTransferLimit :one
+SELECT * FROM transfer_limits
+WHERE id = $1 LIMIT 1;
+
+-- name: ListTransferLimits :many
+SELECT * FROM transfer_limits
+ORDER BY id
+LIMIT $1
+OFFSET $2;
+
+-- name: DeleteTransferLimit :exec
+DELETE FROM transfer_limits
+WHERE id = $1;
+
+-- name: ListApplicableTransferLimits :many
+SELECT * FROM transfer_limits
+WHERE account_id = sqlc.arg(account_id)
+OR (
+    account_id IS NULL
+    AND (product_code IS NULL OR product_code = sqlc.arg(product_code))
+    AND (tier IS NULL OR tier = sqlc.arg(tier))
+    AND (currency_code IS NULL OR currency_code = sqlc.arg(currency_code))
+)
+ORDER BY account_id IS NOT NULL DESC, product_code IS NOT NULL DESC, tier IS NOT NULL DESC, currency_code IS NOT NULL DESC;
+
+-- name: ListOwnerLimitAccounts :many
+SELECT id FROM accounts
+WHERE owner = sqlc.arg(owner)
+AND currency_code = sqlc.arg(currency_code)
+AND (sqlc.narg(product_code)::varchar IS NULL OR product_code = sqlc.narg(product_code))
+ORDER BY id;
+
+-- name: LockUser :exec
+SELECT username FROM users
+WHERE username = $1
+FOR NO KEY UPDATE;
+
+-- name: GetAccountOwnerTier :one
+SELECT u.tier FROM accounts a
+JOIN users u ON u.username = a.owner
+WHERE a.id = $1 LIMIT 1;
+
+-- name: LockAccounts :many
+SELECT id FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id
+FOR NO KEY UPDATE;
+
+-- name: GetOutgoingTransferTotals :one
+SELECT COUNT(*)::bigint AS count, COALESCE(SUM(amount), 0)::bigint AS total
+FROM (
+    SELECT amount FROM transfers
+    WHERE from_account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND created_at > sqlc.arg(since)
+    AND NOT EXISTS (
+        -- moves between an account and its pots don't count
+        SELECT 1 FROM pots p
+        WHERE (p.account_id = transfers.to_account_id AND p.parent_account_id = transfers.from_account_id)
+        OR (p.account_id = transfers.from_account_id AND p.parent_account_id = transfers.to_account_id)
+    )
+    UNION ALL
+    -- money paid into escrow counts like a transfer to the seller
+    SELECT amount FROM escrow_contracts
+    WHERE buyer_account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND created_at > sqlc.arg(since)
+) outgoing;
+
+-- name: ListOutgoingTransfersSince :many
+SELECT * FROM transfers
+WHERE from_account_id = ANY(sqlc.arg(account_ids)::bigint[]) AND created_at > sqlc.arg(since)
+AND NOT EXISTS (
+    -- moves between an account and its pots don't count
+    SELECT 1 FROM pots p
+    WHERE (p.account_id = transfers.to_account_id AND p.parent_account_id = transfers.from_account_id)
+    OR (p.account_id = transfers.from_account_id AND p.parent_account_id = transfers.to_account_id)
+)
+ORDER BY created_at, id;
+
+-- name: ListMemberSpendsSince :many
+SELECT amount, created_at FROM transfers
+WHERE from_account_id = sqlc.arg(account_id) AND initiated_by = sqlc.arg(username)::varchar AND created_at > sqlc.arg(since)
+AND NOT EXISTS (
+    -- moves between an account and its pots don't count
+    SELECT 1 FROM pots p
+    WHERE (p.account_id = transfers.to_account_id AND p.parent_account_id = transfers.from_account_id)
+    OR (p.account_id = transfers.from_account_id AND p.parent_account_id = transfers.to_account_id)
+)
+UNION ALL
+-- money the member paid into escrow counts like a transfer to the seller
+SELECT amount, created_at FROM escrow_contracts
+WHERE buyer_account_id = sqlc.arg(account_id) AND buyer = sqlc.arg(username) AND created_at > sqlc.arg(since)
+ORDER BY created_at;
@@ -11,4 +11,53 @@ INSERT INTO users (
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: UpdateUserTOTPSecret :one
+UPDATE users
+SET totp_secret = $2,
+    totp_enabled = false,
+    totp_last_counter = 0
+WHERE username = $1
+RETURNING *;
+
+-- name: EnableUserTOTP :one
+UPDATE users
+SET totp_enabled = true
+WHERE username = $1
+RETURNING *;
+
+-- name: DisableUserTOTP :one
+UPDATE users
+SET totp_secret = '',
+    totp_enabled = false,
+    totp_last_counter = 0
+WHERE username = $1
+RETURNING *;
+
+-- name: UpdateUserTOTPCounter :one
+UPDATE users
+SET totp_last_counter = sqlc.arg(counter),
+    totp_failed_attempts = 0
+WHERE username = sqlc.arg(username) AND totp_last_counter < sqlc.arg(counter)
+  AND (totp_locked_until IS NULL OR totp_locked_until <= now())
+RETURNING *;
+
+-- name: RecordTOTPFailure :one
+UPDATE users
+SET totp_failed_attempts = CASE
+      WHEN totp_failed_attempts + 1 >= sqlc.arg(max_attempts)::int THEN 0
+      ELSE totp_failed_attempts + 1
+    END,
+    totp_locked_until = CASE
+      WHEN totp_failed_attempts + 1 >= sqlc.arg(max_attempts)::int THEN sqlc.arg(locked_until)::timestamptz
+      ELSE totp_locked_until
+    END
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: SetUserTier :one
+UPDATE users
+SET tier = $2
+WHERE username = $1
+RETURNING *;
@@ -0,0 +1,94 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+    owner,
+    url,
+    secret,
+    event_types
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id;
+
+-- name: ListSubscribedWebhookEndpoints :many
+SELECT * FROM webhook_endpoints
+WHERE owner = sqlc.arg(owner)
+AND active = true
+AND (cardinality(event_types) = 0 OR sqlc.arg(event_type)::varchar = ANY(event_types))
+ORDER BY id;
+
+-- name: DeactivateWebhookEndpoint :one
+UPDATE webhook_endpoints
+SET active = false
+WHERE id = $1 AND owner = $2
+RETURNING *;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_deliveries (
+    event_id,
+    endpoint_id
+) VALUES (
+    $1, $2
+) ON CONFLICT (event_id, endpoint_id) DO NOTHING;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_error = $5,
+    delivered_at = $6
+WHERE id = $1
+RETURNING *;
+
+-- name: RedeliverWebhook :one
+UPDATE webhook_deliveries
+SET status = 'pending',
+    next_attempt_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookAttempt :one
+INSERT INTO webhook_attempts (
+    delivery_id,
+    response_status,
+    error,
+    duration_ms
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListWebhookAttempts :many
+SELECT * FROM webhook_attempts
+WHERE delivery_id = $1
+ORDER BY id;
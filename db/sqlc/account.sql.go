@@ -7,13 +7,14 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts 
 SET balance = balance + $1
 WHERE id = $2
-RETURNING id, owner, balance, currency_code, created_at
+RETURNING id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number
 `
 
 type AddAccountBalanceParams struct {
@@ -30,28 +31,63 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.CurrencyCode,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
+const countOwnerProductAccounts = `-- name: CountOwnerProductAccounts :one
+SELECT COUNT(*) FROM accounts
+WHERE owner = $1
+AND currency_code = $2
+AND product_code = $3
+AND parent_account_id IS NULL
+`
+
+type CountOwnerProductAccountsParams struct {
+	Owner        string `json:"owner"`
+	CurrencyCode string `json:"currency_code"`
+	ProductCode  string `json:"product_code"`
+}
+
+func (q *Queries) CountOwnerProductAccounts(ctx context.Context, arg CountOwnerProductAccountsParams) (int64, error) {
+	row := q.queryRow(ctx, q.countOwnerProductAccountsStmt, countOwnerProductAccounts, arg.Owner, arg.CurrencyCode, arg.ProductCode)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
  owner,
  balance,
- currency_code
+ currency_code,
+ product_code,
+ account_number
 ) VALUES (
-    $1,$2, $3
-) RETURNING id, owner, balance, currency_code, created_at
+    $1,$2, $3, $4, $5
+) RETURNING id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number
 `
 
 type CreateAccountParams struct {
-	Owner        string `json:"owner"`
-	Balance      int64  `json:"balance"`
-	CurrencyCode string `json:"currency_code"`
+	Owner         string         `json:"owner"`
+	Balance       int64          `json:"balance"`
+	CurrencyCode  string         `json:"currency_code"`
+	ProductCode   string         `json:"product_code"`
+	AccountNumber sql.NullString `json:"account_number"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.queryRow(ctx, q.createAccountStmt, createAccount, arg.Owner, arg.Balance, arg.CurrencyCode)
+	row := q.queryRow(ctx, q.createAccountStmt, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.CurrencyCode,
+		arg.ProductCode,
+		arg.AccountNumber,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +95,10 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.CurrencyCode,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
@@ -74,7 +114,7 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency_code, created_at FROM accounts
+SELECT id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
@@ -87,12 +127,38 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.CurrencyCode,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number FROM accounts
+WHERE account_number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber sql.NullString) (Account, error) {
+	row := q.queryRow(ctx, q.getAccountByNumberStmt, getAccountByNumber, accountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency_code, created_at FROM accounts
+SELECT id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number FROM accounts
 WHERE id = $1 LIMIT 1 FOR NO KEY UPDATE
 `
 
@@ -105,26 +171,74 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.CurrencyCode,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
 
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency_code, created_at FROM accounts
-WHERE owner = $1 
+SELECT id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number FROM accounts
+WHERE owner = $1
+OR id IN (
+    SELECT account_id FROM account_members
+    WHERE username = $1 AND status = 'active'
+)
 ORDER BY id
 LIMIT $2
 OFFSET $3
 `
 
 type ListAccountsParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+	Username   string `json:"username"`
+	PageLimit  int32  `json:"page_limit"`
+	PageOffset int32  `json:"page_offset"`
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.query(ctx, q.listAccountsStmt, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.query(ctx, q.listAccountsStmt, listAccounts, arg.Username, arg.PageLimit, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.CurrencyCode,
+			&i.CreatedAt,
+			&i.Status,
+			&i.ProductCode,
+			&i.ParentAccountID,
+			&i.AccountNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsWithoutNumber = `-- name: ListAccountsWithoutNumber :many
+SELECT id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number FROM accounts
+WHERE account_number IS NULL
+ORDER BY id
+LIMIT $1
+`
+
+func (q *Queries) ListAccountsWithoutNumber(ctx context.Context, limit int32) ([]Account, error) {
+	rows, err := q.query(ctx, q.listAccountsWithoutNumberStmt, listAccountsWithoutNumber, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +252,10 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.CurrencyCode,
 			&i.CreatedAt,
+			&i.Status,
+			&i.ProductCode,
+			&i.ParentAccountID,
+			&i.AccountNumber,
 		); err != nil {
 			return nil, err
 		}
@@ -152,11 +270,80 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 	return items, nil
 }
 
+const nextAccountSequence = `-- name: NextAccountSequence :one
+SELECT nextval('account_number_seq')::bigint
+`
+
+func (q *Queries) NextAccountSequence(ctx context.Context) (int64, error) {
+	row := q.queryRow(ctx, q.nextAccountSequenceStmt, nextAccountSequence)
+	var column int64
+	err := row.Scan(&column)
+	return column, err
+}
+
+const setAccountNumber = `-- name: SetAccountNumber :one
+UPDATE accounts
+SET account_number = $2
+WHERE id = $1 AND account_number IS NULL
+RETURNING id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number
+`
+
+type SetAccountNumberParams struct {
+	ID            int64          `json:"id"`
+	AccountNumber sql.NullString `json:"account_number"`
+}
+
+func (q *Queries) SetAccountNumber(ctx context.Context, arg SetAccountNumberParams) (Account, error) {
+	row := q.queryRow(ctx, q.setAccountNumberStmt, setAccountNumber, arg.ID, arg.AccountNumber)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
+const setAccountStatus = `-- name: SetAccountStatus :one
+UPDATE accounts
+SET status = $1
+WHERE id = $2
+RETURNING id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number
+`
+
+type SetAccountStatusParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Account, error) {
+	row := q.queryRow(ctx, q.setAccountStatusStmt, setAccountStatus, arg.Status, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.CurrencyCode,
+		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
+	)
+	return i, err
+}
+
 const updateAccount = `-- name: UpdateAccount :one
 UPDATE accounts 
 SET balance = $2
 WHERE id = $1
-RETURNING id, owner, balance, currency_code, created_at
+RETURNING id, owner, balance, currency_code, created_at, status, product_code, parent_account_id, account_number
 `
 
 type UpdateAccountParams struct {
@@ -173,6 +360,10 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.CurrencyCode,
 		&i.CreatedAt,
+		&i.Status,
+		&i.ProductCode,
+		&i.ParentAccountID,
+		&i.AccountNumber,
 	)
 	return i, err
 }
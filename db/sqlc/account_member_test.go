@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMembers(t *testing.T) {
+	account := createRandomAccount(t)
+	member := createRandomUser(t)
+
+	invited, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID:  account.ID,
+		Username:   member.Username,
+		Role:       "spender",
+		SpendLimit: sql.NullInt64{Int64: 100, Valid: true},
+		InvitedBy:  account.Owner,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "invited", invited.Status)
+	require.False(t, invited.AcceptedAt.Valid)
+
+	listAccounts := func() []Account {
+		accounts, err := testQueries.ListAccounts(context.Background(), ListAccountsParams{
+			Username:  member.Username,
+			PageLimit: 10,
+		})
+		require.NoError(t, err)
+		return accounts
+	}
+
+	// an invitation doesn't make the account show up yet
+	require.Empty(t, listAccounts())
+
+	invitations, err := testQueries.ListAccountInvitations(context.Background(), member.Username)
+	require.NoError(t, err)
+	require.Len(t, invitations, 1)
+
+	accepted, err := testQueries.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  member.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "active", accepted.Status)
+	require.True(t, accepted.AcceptedAt.Valid)
+
+	_, err = testQueries.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  member.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	accounts := listAccounts()
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	err = testQueries.DeleteAccountMember(context.Background(), DeleteAccountMemberParams{
+		AccountID: account.ID,
+		Username:  member.Username,
+	})
+	require.NoError(t, err)
+	require.Empty(t, listAccounts())
+}
@@ -11,11 +11,16 @@ import (
 )
 
 func createRandomAccount(t *testing.T) Account {
+	return createRandomAccountInCurrency(t, utils.RandomCurrencyCode())
+}
+
+func createRandomAccountInCurrency(t *testing.T, currencyCode string) Account {
 	user := createRandomUser(t)
 	arg := CreateAccountParams{
 		Owner:        user.Username,
 		Balance:      utils.RandomMoney(),
-		CurrencyCode: utils.RandomCurrencyCode(),
+		CurrencyCode: currencyCode,
+		ProductCode:  AccountProductChecking,
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(60*time.Millisecond))
@@ -33,6 +38,20 @@ func createRandomAccount(t *testing.T) Account {
 	return account
 }
 
+// createFundedAccount opens a USD account holding balance for tests that need
+// to know it can pay
+func createFundedAccount(t *testing.T, balance int64) Account {
+	user := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:        user.Username,
+		Balance:      balance,
+		CurrencyCode: utils.USD,
+		ProductCode:  AccountProductChecking,
+	})
+	require.NoError(t, err)
+	return account
+}
+
 func TestCreateAccount(t *testing.T) {
 	createRandomAccount(t)
 }
@@ -88,9 +107,9 @@ func TestListAccounts(t *testing.T) {
 	}
 
 	arg := ListAccountsParams{
-		Owner:  lastListedAccount.Owner,
-		Limit:  5,
-		Offset: 0,
+		Username:   lastListedAccount.Owner,
+		PageLimit:  5,
+		PageOffset: 0,
 	}
 
 	accounts, err := testQueries.ListAccounts(context.Background(), arg)
@@ -102,3 +121,26 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastListedAccount.Owner, account.Owner)
 	}
 }
+
+func TestAccountNumbers(t *testing.T) {
+	account := createRandomAccount(t)
+	require.False(t, account.AccountNumber.Valid)
+
+	sequence, err := testQueries.NextAccountSequence(context.Background())
+	require.NoError(t, err)
+	number, err := utils.NewAccountNumber(utils.DefaultBankCode, account.CurrencyCode, sequence)
+	require.NoError(t, err)
+
+	arg := SetAccountNumberParams{ID: account.ID, AccountNumber: sql.NullString{String: number, Valid: true}}
+	numbered, err := testQueries.SetAccountNumber(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, number, numbered.AccountNumber.String)
+
+	// numbers are stable once assigned
+	_, err = testQueries.SetAccountNumber(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	found, err := testQueries.GetAccountByNumber(context.Background(), arg.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, found.ID)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2 := createRandomUser(t)
+	email := utils.RandomEmail()
+
+	claim := func(username string, expiresAt time.Time) (Alias, error) {
+		return testQueries.ClaimAlias(context.Background(), ClaimAliasParams{
+			Alias:         email,
+			Kind:          "email",
+			Username:      username,
+			CodeHash:      utils.RandomString(64),
+			CodeExpiresAt: expiresAt,
+		})
+	}
+
+	claimed, err := claim(user1.Username, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, claimed.Username)
+	require.False(t, claimed.VerifiedAt.Valid)
+
+	for i := 0; i < 5; i++ {
+		claimed, err = testQueries.IncrementAliasAttempts(context.Background(), email)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(5), claimed.Attempts)
+
+	// while its code is live the alias can't be claimed again, so the wrong
+	// guesses aren't forgotten and no new code is sent
+	_, err = claim(user2.Username, time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	claimed, err = testQueries.GetAlias(context.Background(), email)
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, claimed.Username)
+	require.Equal(t, int32(5), claimed.Attempts)
+
+	// once the code expires an unverified alias can be claimed again
+	_, err = testQueries.db.ExecContext(context.Background(),
+		"UPDATE aliases SET code_expires_at = now() - interval '1 minute' WHERE alias = $1", email)
+	require.NoError(t, err)
+
+	claimed, err = claim(user2.Username, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, user2.Username, claimed.Username)
+	require.Zero(t, claimed.Attempts)
+
+	_, err = testQueries.VerifyAlias(context.Background(), VerifyAliasParams{Alias: email, Username: user1.Username})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	verified, err := testQueries.VerifyAlias(context.Background(), VerifyAliasParams{Alias: email, Username: user2.Username})
+	require.NoError(t, err)
+	require.True(t, verified.VerifiedAt.Valid)
+
+	// once verified it stays with its owner
+	_, err = claim(user1.Username, time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	aliases, err := testQueries.ListUserAliases(context.Background(), user2.Username)
+	require.NoError(t, err)
+	require.Len(t, aliases, 1)
+
+	err = testQueries.DeleteAlias(context.Background(), DeleteAliasParams{Alias: email, Username: user2.Username})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetAlias(context.Background(), email)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetPrimaryAccount(t *testing.T) {
+	account := createRandomAccountInCurrency(t, utils.USD)
+
+	primary, err := testQueries.GetPrimaryAccount(context.Background(), GetPrimaryAccountParams{
+		Owner:        account.Owner,
+		CurrencyCode: utils.USD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.ID, primary.ID)
+
+	_, err = testQueries.GetPrimaryAccount(context.Background(), GetPrimaryAccountParams{
+		Owner:        account.Owner,
+		CurrencyCode: utils.EUR,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
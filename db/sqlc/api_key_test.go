@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomApiKey(t *testing.T, owner string) ApiKey {
+	key, prefix, err := utils.GenerateAPIKey()
+	require.NoError(t, err)
+
+	arg := CreateApiKeyParams{
+		Owner:      owner,
+		Name:       utils.RandomString(6),
+		Prefix:     prefix,
+		HashedKey:  utils.HashAPIKey(key),
+		Scopes:     []string{"accounts:read"},
+		AllowedIps: []string{"10.0.0.0/24"},
+		ExpiresAt:  sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	apiKey, err := testQueries.CreateApiKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, apiKey.ID)
+	require.Equal(t, arg.Owner, apiKey.Owner)
+	require.Equal(t, arg.Prefix, apiKey.Prefix)
+	require.Equal(t, arg.HashedKey, apiKey.HashedKey)
+	require.Equal(t, arg.Scopes, apiKey.Scopes)
+	require.Equal(t, arg.AllowedIps, apiKey.AllowedIps)
+	require.WithinDuration(t, arg.ExpiresAt.Time, apiKey.ExpiresAt.Time, time.Second)
+	require.False(t, apiKey.RevokedAt.Valid)
+
+	return apiKey
+}
+
+func Test_CreateApiKey(t *testing.T) {
+	user := createRandomUser(t)
+	createRandomApiKey(t, user.Username)
+}
+
+func Test_GetApiKeyByPrefix(t *testing.T) {
+	user := createRandomUser(t)
+	apiKey1 := createRandomApiKey(t, user.Username)
+
+	apiKey2, err := testQueries.GetApiKeyByPrefix(context.Background(), apiKey1.Prefix)
+	require.NoError(t, err)
+	require.Equal(t, apiKey1.ID, apiKey2.ID)
+	require.Equal(t, apiKey1.HashedKey, apiKey2.HashedKey)
+}
+
+func Test_RevokeApiKey(t *testing.T) {
+	user := createRandomUser(t)
+	apiKey := createRandomApiKey(t, user.Username)
+
+	_, err := testQueries.RevokeApiKey(context.Background(), RevokeApiKeyParams{
+		ID:    apiKey.ID,
+		Owner: utils.RandomOwner(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	revoked, err := testQueries.RevokeApiKey(context.Background(), RevokeApiKeyParams{
+		ID:    apiKey.ID,
+		Owner: user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	_, err = testQueries.RevokeApiKey(context.Background(), RevokeApiKeyParams{
+		ID:    apiKey.ID,
+		Owner: user.Username,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func Test_ListApiKeys(t *testing.T) {
+	user := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomApiKey(t, user.Username)
+	}
+
+	apiKeys, err := testQueries.ListApiKeys(context.Background(), ListApiKeysParams{
+		Owner:  user.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 3)
+	for _, apiKey := range apiKeys {
+		require.Equal(t, user.Username, apiKey.Owner)
+	}
+}
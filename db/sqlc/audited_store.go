@@ -0,0 +1,1059 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// AuditedStore is a Store that appends an audit event for every state-changing
+// operation in the same transaction as the change, so a change is never
+// committed without its audit record.
+type AuditedStore struct {
+	*SQLStore
+}
+
+// NewAuditedStore returns a Store that records every change in the audit log
+func NewAuditedStore(db *sql.DB) Store {
+	return &AuditedStore{
+		SQLStore: &SQLStore{
+			db:      db,
+			Queries: New(db),
+		},
+	}
+}
+
+// audited runs fn in a transaction and records the event it describes
+func (store *AuditedStore) audited(ctx context.Context, fn func(q *Queries) (RecordAuditEventParams, error)) error {
+	return store.auditedEach(ctx, func(q *Queries) ([]RecordAuditEventParams, error) {
+		arg, err := fn(q)
+		return []RecordAuditEventParams{arg}, err
+	})
+}
+
+// auditedEach runs fn in a transaction and records an event for every resource
+// it changed, for the jobs that change many at once
+func (store *AuditedStore) auditedEach(ctx context.Context, fn func(q *Queries) ([]RecordAuditEventParams, error)) error {
+	return store.executeTrxn(ctx, func(q *Queries) error {
+		args, err := fn(q)
+		if err != nil {
+			return err
+		}
+
+		metadata := AuditMetadataFromContext(ctx)
+		for _, arg := range args {
+			arg.AuditMetadata = metadata
+			if _, err = recordAuditEvent(ctx, q, arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (store *AuditedStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "user.create",
+			ResourceType: "user",
+			ResourceID:   user.Username,
+			After:        user,
+		}, err
+	})
+	return user, err
+}
+
+func (store *AuditedStore) UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) (User, error) {
+	var user User
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		user, err = q.UpdateUserTOTPSecret(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "mfa.enroll",
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			After:        user,
+		}, err
+	})
+	return user, err
+}
+
+func (store *AuditedStore) ReplaceRecoveryCodesTrxn(ctx context.Context, arg ReplaceRecoveryCodesTxnParams) (User, error) {
+	action := "mfa.recovery_codes.regenerate"
+	if arg.EnableTOTP {
+		action = "mfa.enable"
+	}
+
+	var user User
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		user, err = replaceRecoveryCodes(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       action,
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			After:        user,
+		}, err
+	})
+	return user, err
+}
+
+func (store *AuditedStore) DisableMFATrxn(ctx context.Context, username string) (User, error) {
+	var user User
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetUser(ctx, username)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		user, err = disableMFA(ctx, q, username)
+		return RecordAuditEventParams{
+			Action:       "mfa.disable",
+			ResourceType: "user",
+			ResourceID:   username,
+			Before:       before,
+			After:        user,
+		}, err
+	})
+	return user, err
+}
+
+func (store *AuditedStore) MarkRecoveryCodeUsed(ctx context.Context, id int64) (MfaRecoveryCode, error) {
+	var code MfaRecoveryCode
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		code, err = q.MarkRecoveryCodeUsed(ctx, id)
+		return RecordAuditEventParams{
+			Action:       "mfa.recovery_code.use",
+			ResourceType: "user",
+			ResourceID:   code.Username,
+			After:        code,
+		}, err
+	})
+	return code, err
+}
+
+func (store *AuditedStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		account, err = q.CreateAccount(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account.create",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(account.ID, 10),
+			After:        account,
+		}, err
+	})
+	return account, err
+}
+
+func (store *AuditedStore) OpenAccountTrxn(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		account, err = openAccount(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "account.create",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(account.ID, 10),
+			After:        account,
+		}, err
+	})
+	return account, err
+}
+
+func (store *AuditedStore) SetAccountNumber(ctx context.Context, arg SetAccountNumberParams) (Account, error) {
+	var account Account
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		account, err = q.SetAccountNumber(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account.number_assigned",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        account,
+		}, err
+	})
+	return account, err
+}
+
+func (store *AuditedStore) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
+	var account Account
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		account, err = q.UpdateAccount(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account.update",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			Before:       before,
+			After:        account,
+		}, err
+	})
+	return account, err
+}
+
+func (store *AuditedStore) SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Account, error) {
+	var account Account
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetAccountForUpdate(ctx, arg.ID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		account, err = q.SetAccountStatus(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account.set_status",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			Before:       before,
+			After:        account,
+		}, err
+	})
+	return account, err
+}
+
+// DeleteAccount keeps the plain store's behaviour of treating a missing account as deleted
+func (store *AuditedStore) DeleteAccount(ctx context.Context, id int64) error {
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "account.delete",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+		}, q.DeleteAccount(ctx, id)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+func (store *AuditedStore) PerformTransactionTrxn(ctx context.Context, arg TransferTxnParams) (TransferTrxResult, error) {
+	var result TransferTrxResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = performTransfer(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "transfer.create",
+			ResourceType: "transfer",
+			ResourceID:   strconv.FormatInt(result.Transfer.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) PostJournalTrxn(ctx context.Context, arg PostJournalTxnParams) (PostJournalTxnResult, error) {
+	var result PostJournalTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = postJournal(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "journal.post",
+			ResourceType: "journal",
+			ResourceID:   strconv.FormatInt(result.Journal.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) PostInterestTrxn(ctx context.Context, arg PostInterestTxnParams) (InterestPosting, error) {
+	var posting InterestPosting
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		posting, err = postInterest(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "interest.post",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.AccountID, 10),
+			After:        posting,
+		}, err
+	})
+	return posting, err
+}
+
+func (store *AuditedStore) CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error) {
+	var apiKey ApiKey
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		apiKey, err = q.CreateApiKey(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "api_key.create",
+			ResourceType: "api_key",
+			ResourceID:   strconv.FormatInt(apiKey.ID, 10),
+			After:        apiKey,
+		}, err
+	})
+	return apiKey, err
+}
+
+func (store *AuditedStore) RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (ApiKey, error) {
+	var apiKey ApiKey
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		apiKey, err = q.RevokeApiKey(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "api_key.revoke",
+			ResourceType: "api_key",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        apiKey,
+		}, err
+	})
+	return apiKey, err
+}
+
+func (store *AuditedStore) CreateOAuthClient(ctx context.Context, arg CreateOAuthClientParams) (OauthClient, error) {
+	var client OauthClient
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		client, err = q.CreateOAuthClient(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "oauth_client.create",
+			ResourceType: "oauth_client",
+			ResourceID:   arg.ClientID,
+			After:        client,
+		}, err
+	})
+	return client, err
+}
+
+func (store *AuditedStore) CreateAuthorizationCode(ctx context.Context, arg CreateAuthorizationCodeParams) (OauthAuthorizationCode, error) {
+	var code OauthAuthorizationCode
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		code, err = q.CreateAuthorizationCode(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "oauth.consent",
+			ResourceType: "oauth_client",
+			ResourceID:   arg.ClientID,
+			After:        code,
+		}, err
+	})
+	return code, err
+}
+
+func (store *AuditedStore) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (OauthRefreshToken, error) {
+	var refreshToken OauthRefreshToken
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		refreshToken, err = q.CreateRefreshToken(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "oauth.refresh_token.create",
+			ResourceType: "oauth_client",
+			ResourceID:   arg.ClientID,
+			After:        refreshToken,
+		}, err
+	})
+	return refreshToken, err
+}
+
+func (store *AuditedStore) RotateRefreshTokenTrxn(ctx context.Context, arg RotateRefreshTokenTxnParams) (OauthRefreshToken, error) {
+	var refreshToken OauthRefreshToken
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		refreshToken, err = rotateRefreshToken(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "oauth.refresh_token.rotate",
+			ResourceType: "oauth_client",
+			ResourceID:   refreshToken.ClientID,
+			After:        refreshToken,
+		}, err
+	})
+	return refreshToken, err
+}
+
+func (store *AuditedStore) RevokeRefreshToken(ctx context.Context, tokenHash string) (OauthRefreshToken, error) {
+	var refreshToken OauthRefreshToken
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		refreshToken, err = q.RevokeRefreshToken(ctx, tokenHash)
+		return RecordAuditEventParams{
+			Action:       "oauth.refresh_token.revoke",
+			ResourceType: "oauth_client",
+			ResourceID:   refreshToken.ClientID,
+			After:        refreshToken,
+		}, err
+	})
+	return refreshToken, err
+}
+
+func (store *AuditedStore) RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		return RecordAuditEventParams{
+			Action:       "oauth.access_token.revoke",
+			ResourceType: "access_token",
+			ResourceID:   arg.TokenID,
+			After:        arg,
+		}, q.RevokeAccessToken(ctx, arg)
+	})
+}
+
+func (store *AuditedStore) CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error) {
+	var endpoint WebhookEndpoint
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		endpoint, err = q.CreateWebhookEndpoint(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "webhook.create",
+			ResourceType: "webhook_endpoint",
+			ResourceID:   strconv.FormatInt(endpoint.ID, 10),
+			After:        endpoint,
+		}, err
+	})
+	return endpoint, err
+}
+
+func (store *AuditedStore) DeactivateWebhookEndpoint(ctx context.Context, arg DeactivateWebhookEndpointParams) (WebhookEndpoint, error) {
+	var endpoint WebhookEndpoint
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		endpoint, err = q.DeactivateWebhookEndpoint(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "webhook.deactivate",
+			ResourceType: "webhook_endpoint",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        endpoint,
+		}, err
+	})
+	return endpoint, err
+}
+
+func (store *AuditedStore) RedeliverWebhook(ctx context.Context, id int64) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		delivery, err = q.RedeliverWebhook(ctx, id)
+		return RecordAuditEventParams{
+			Action:       "webhook.redeliver",
+			ResourceType: "webhook_delivery",
+			ResourceID:   strconv.FormatInt(id, 10),
+			After:        delivery,
+		}, err
+	})
+	return delivery, err
+}
+
+func (store *AuditedStore) UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error) {
+	var limit TransferLimit
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		limit, err = q.UpsertTransferLimit(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "transfer_limit.upsert",
+			ResourceType: "transfer_limit",
+			ResourceID:   strconv.FormatInt(limit.ID, 10),
+			After:        limit,
+		}, err
+	})
+	return limit, err
+}
+
+func (store *AuditedStore) DeleteTransferLimit(ctx context.Context, id int64) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetTransferLimit(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "transfer_limit.delete",
+			ResourceType: "transfer_limit",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+		}, q.DeleteTransferLimit(ctx, id)
+	})
+}
+
+func (store *AuditedStore) SetUserTier(ctx context.Context, arg SetUserTierParams) (User, error) {
+	var user User
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetUser(ctx, arg.Username)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		user, err = q.SetUserTier(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "user.set_tier",
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			Before:       before,
+			After:        user,
+		}, err
+	})
+	return user, err
+}
+
+func (store *AuditedStore) CreateKycDocument(ctx context.Context, arg CreateKycDocumentParams) (KycDocument, error) {
+	var document KycDocument
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		document, err = q.CreateKycDocument(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "kyc.document.create",
+			ResourceType: "kyc_document",
+			ResourceID:   strconv.FormatInt(document.ID, 10),
+			After:        document,
+		}, err
+	})
+	return document, err
+}
+
+func (store *AuditedStore) SubmitKycProfile(ctx context.Context, arg SubmitKycProfileParams) (KycProfile, error) {
+	var profile KycProfile
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		profile, err = q.SubmitKycProfile(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "kyc.submit",
+			ResourceType: "kyc_profile",
+			ResourceID:   arg.Username,
+			After:        profile,
+		}, err
+	})
+	return profile, err
+}
+
+func (store *AuditedStore) ReviewKYCTrxn(ctx context.Context, arg ReviewKYCTxnParams) (ReviewKYCTxnResult, error) {
+	action := "kyc.reject"
+	if arg.Status == KYCStatusApproved {
+		action = "kyc.approve"
+	}
+
+	var result ReviewKYCTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = reviewKYC(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       action,
+			ResourceType: "kyc_profile",
+			ResourceID:   arg.Username,
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) CreateFraudCase(ctx context.Context, arg CreateFraudCaseParams) (FraudCase, error) {
+	var fraudCase FraudCase
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		fraudCase, err = q.CreateFraudCase(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "fraud_case." + fraudCase.Status,
+			ResourceType: "fraud_case",
+			ResourceID:   strconv.FormatInt(fraudCase.ID, 10),
+			After:        fraudCase,
+		}, err
+	})
+	return fraudCase, err
+}
+
+func (store *AuditedStore) CloseFraudCase(ctx context.Context, arg CloseFraudCaseParams) (FraudCase, error) {
+	var before, after FraudCase
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		before, err = q.GetFraudCase(ctx, arg.ID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+		after, err = q.CloseFraudCase(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "fraud_case." + arg.Status,
+			ResourceType: "fraud_case",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) ApproveFraudCaseTrxn(ctx context.Context, arg ApproveFraudCaseTxnParams) (ApproveFraudCaseTxnResult, error) {
+	var result ApproveFraudCaseTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = approveFraudCase(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "fraud_case.approved",
+			ResourceType: "fraud_case",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) UpsertApprovalPolicy(ctx context.Context, arg UpsertApprovalPolicyParams) (ApprovalPolicy, error) {
+	var policy ApprovalPolicy
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		policy, err = q.UpsertApprovalPolicy(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "approval_policy.upsert",
+			ResourceType: "approval_policy",
+			ResourceID:   strconv.FormatInt(policy.ID, 10),
+			After:        policy,
+		}, err
+	})
+	return policy, err
+}
+
+func (store *AuditedStore) DeleteApprovalPolicy(ctx context.Context, id int64) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetApprovalPolicy(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "approval_policy.delete",
+			ResourceType: "approval_policy",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+		}, q.DeleteApprovalPolicy(ctx, id)
+	})
+}
+
+func (store *AuditedStore) CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (PendingTransfer, error) {
+	var pending PendingTransfer
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		pending, err = q.CreatePendingTransfer(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "pending_transfer.create",
+			ResourceType: "pending_transfer",
+			ResourceID:   strconv.FormatInt(pending.ID, 10),
+			After:        pending,
+		}, err
+	})
+	return pending, err
+}
+
+func (store *AuditedStore) DecideTransferTrxn(ctx context.Context, arg DecideTransferTxnParams) (DecideTransferTxnResult, error) {
+	var result DecideTransferTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = decideTransfer(ctx, q, arg, time.Now())
+		return RecordAuditEventParams{
+			Action:       "pending_transfer." + arg.Decision,
+			ResourceType: "pending_transfer",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) ExpirePendingTransfers(ctx context.Context, now time.Time) ([]PendingTransfer, error) {
+	var expired []PendingTransfer
+	err := store.auditedEach(ctx, func(q *Queries) ([]RecordAuditEventParams, error) {
+		var err error
+		expired, err = q.ExpirePendingTransfers(ctx, now)
+		events := make([]RecordAuditEventParams, len(expired))
+		for i, pending := range expired {
+			events[i] = RecordAuditEventParams{
+				Action:       "pending_transfer.expire",
+				ResourceType: "pending_transfer",
+				ResourceID:   strconv.FormatInt(pending.ID, 10),
+				After:        pending,
+			}
+		}
+		return events, err
+	})
+	return expired, err
+}
+
+func (store *AuditedStore) CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error) {
+	var member AccountMember
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		member, err = q.CreateAccountMember(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account_member.invite",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.AccountID, 10),
+			After:        member,
+		}, err
+	})
+	return member, err
+}
+
+func (store *AuditedStore) AcceptAccountMember(ctx context.Context, arg AcceptAccountMemberParams) (AccountMember, error) {
+	var member AccountMember
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		member, err = q.AcceptAccountMember(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "account_member.accept",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.AccountID, 10),
+			After:        member,
+		}, err
+	})
+	return member, err
+}
+
+func (store *AuditedStore) DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetAccountMember(ctx, GetAccountMemberParams(arg))
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "account_member.remove",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.AccountID, 10),
+			Before:       before,
+		}, q.DeleteAccountMember(ctx, arg)
+	})
+}
+
+func (store *AuditedStore) CreatePotTrxn(ctx context.Context, arg CreatePotTxnParams) (CreatePotTxnResult, error) {
+	var result CreatePotTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = openPot(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "pot.create",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(result.Pot.AccountID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) UpdatePot(ctx context.Context, arg UpdatePotParams) (Pot, error) {
+	var after Pot
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetPot(ctx, arg.AccountID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		after, err = q.UpdatePot(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "pot.update",
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(arg.AccountID, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) MovePotTrxn(ctx context.Context, arg MovePotTxnParams) (TransferTrxResult, error) {
+	var result TransferTrxResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = movePot(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "pot." + arg.Direction,
+			ResourceType: "transfer",
+			ResourceID:   strconv.FormatInt(result.Transfer.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) ClaimAlias(ctx context.Context, arg ClaimAliasParams) (Alias, error) {
+	var alias Alias
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		alias, err = q.ClaimAlias(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "alias.claim",
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			After:        alias,
+		}, err
+	})
+	return alias, err
+}
+
+func (store *AuditedStore) VerifyAlias(ctx context.Context, arg VerifyAliasParams) (Alias, error) {
+	var alias Alias
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		alias, err = q.VerifyAlias(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "alias.verify",
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			After:        alias,
+		}, err
+	})
+	return alias, err
+}
+
+func (store *AuditedStore) DeleteAlias(ctx context.Context, arg DeleteAliasParams) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetAlias(ctx, arg.Alias)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "alias.delete",
+			ResourceType: "user",
+			ResourceID:   arg.Username,
+			Before:       before,
+		}, q.DeleteAlias(ctx, arg)
+	})
+}
+
+func (store *AuditedStore) CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error) {
+	var beneficiary Beneficiary
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		beneficiary, err = q.CreateBeneficiary(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "beneficiary.create",
+			ResourceType: "beneficiary",
+			ResourceID:   strconv.FormatInt(beneficiary.ID, 10),
+			After:        beneficiary,
+		}, err
+	})
+	return beneficiary, err
+}
+
+func (store *AuditedStore) RenameBeneficiary(ctx context.Context, arg RenameBeneficiaryParams) (Beneficiary, error) {
+	var after Beneficiary
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetBeneficiary(ctx, arg.ID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		after, err = q.RenameBeneficiary(ctx, arg)
+		return RecordAuditEventParams{
+			Action:       "beneficiary.rename",
+			ResourceType: "beneficiary",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) DeleteBeneficiary(ctx context.Context, id int64) error {
+	return store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetBeneficiary(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+
+		return RecordAuditEventParams{
+			Action:       "beneficiary.delete",
+			ResourceType: "beneficiary",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+		}, q.DeleteBeneficiary(ctx, id)
+	})
+}
+
+func (store *AuditedStore) CreatePaymentRequestTrxn(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	var request PaymentRequest
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		request, err = openPaymentRequest(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "payment_request.create",
+			ResourceType: "payment_request",
+			ResourceID:   strconv.FormatInt(request.ID, 10),
+			After:        request,
+		}, err
+	})
+	return request, err
+}
+
+func (store *AuditedStore) ClosePaymentRequestTrxn(ctx context.Context, arg ClosePaymentRequestTxnParams) (PaymentRequest, error) {
+	var request PaymentRequest
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		request, err = endPaymentRequest(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "payment_request." + arg.Status,
+			ResourceType: "payment_request",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			After:        request,
+		}, err
+	})
+	return request, err
+}
+
+func (store *AuditedStore) ExpirePaymentRequestsTrxn(ctx context.Context, now time.Time) ([]PaymentRequest, error) {
+	var expired []PaymentRequest
+	err := store.auditedEach(ctx, func(q *Queries) ([]RecordAuditEventParams, error) {
+		var err error
+		expired, err = expireOverduePaymentRequests(ctx, q, now)
+		events := make([]RecordAuditEventParams, len(expired))
+		for i, request := range expired {
+			events[i] = RecordAuditEventParams{
+				Action:       "payment_request.expire",
+				ResourceType: "payment_request",
+				ResourceID:   strconv.FormatInt(request.ID, 10),
+				After:        request,
+			}
+		}
+		return events, err
+	})
+	return expired, err
+}
+
+func (store *AuditedStore) CreateInvoiceTrxn(ctx context.Context, arg CreateInvoiceTxnParams) (CreateInvoiceTxnResult, error) {
+	var result CreateInvoiceTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = draftInvoice(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "invoice.create",
+			ResourceType: "invoice",
+			ResourceID:   strconv.FormatInt(result.Invoice.ID, 10),
+			After:        result,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) SendInvoice(ctx context.Context, id int64) (Invoice, error) {
+	var after Invoice
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetInvoiceForUpdate(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+		after, err = q.SendInvoice(ctx, id)
+		return RecordAuditEventParams{
+			Action:       "invoice.send",
+			ResourceType: "invoice",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) VoidInvoice(ctx context.Context, id int64) (Invoice, error) {
+	var after Invoice
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetInvoiceForUpdate(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+		after, err = q.VoidInvoice(ctx, id)
+		return RecordAuditEventParams{
+			Action:       "invoice.void",
+			ResourceType: "invoice",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) MarkInvoicesOverdueTrxn(ctx context.Context, today time.Time) ([]Invoice, error) {
+	var overdue []Invoice
+	err := store.auditedEach(ctx, func(q *Queries) ([]RecordAuditEventParams, error) {
+		var err error
+		overdue, err = markOverdueInvoices(ctx, q, today)
+		events := make([]RecordAuditEventParams, len(overdue))
+		for i, invoice := range overdue {
+			events[i] = RecordAuditEventParams{
+				Action:       "invoice.overdue",
+				ResourceType: "invoice",
+				ResourceID:   strconv.FormatInt(invoice.ID, 10),
+				After:        invoice,
+			}
+		}
+		return events, err
+	})
+	return overdue, err
+}
+
+func (store *AuditedStore) CreateEscrowTrxn(ctx context.Context, arg CreateEscrowContractParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		var err error
+		result, err = fundEscrow(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "escrow.fund",
+			ResourceType: "escrow_contract",
+			ResourceID:   strconv.FormatInt(result.Contract.ID, 10),
+			After:        result.Contract,
+		}, err
+	})
+	return result, err
+}
+
+func (store *AuditedStore) ReleaseEscrowTrxn(ctx context.Context, id int64) (EscrowTxnResult, error) {
+	return store.settleEscrow(ctx, id, EscrowFunded, ResolveEscrowTxnParams{Outcome: EscrowReleased})
+}
+
+func (store *AuditedStore) DisputeEscrowTrxn(ctx context.Context, arg DisputeEscrowContractParams) (EscrowContract, error) {
+	var after EscrowContract
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetEscrowContractForUpdate(ctx, arg.ID)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+		after, err = disputeEscrow(ctx, q, arg)
+		return RecordAuditEventParams{
+			Action:       "escrow.dispute",
+			ResourceType: "escrow_contract",
+			ResourceID:   strconv.FormatInt(arg.ID, 10),
+			Before:       before,
+			After:        after,
+		}, err
+	})
+	return after, err
+}
+
+func (store *AuditedStore) ResolveEscrowTrxn(ctx context.Context, arg ResolveEscrowTxnParams) (EscrowTxnResult, error) {
+	return store.settleEscrow(ctx, arg.ID, EscrowDisputed, arg)
+}
+
+// settleEscrow audits releases, refunds and dispute resolutions, the action
+// names the outcome
+func (store *AuditedStore) settleEscrow(ctx context.Context, id int64, from string, arg ResolveEscrowTxnParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+	err := store.audited(ctx, func(q *Queries) (RecordAuditEventParams, error) {
+		before, err := q.GetEscrowContractForUpdate(ctx, id)
+		if err != nil {
+			return RecordAuditEventParams{}, err
+		}
+		result, err = settleEscrow(ctx, q, id, from, arg)
+		return RecordAuditEventParams{
+			Action:       "escrow." + arg.Outcome,
+			ResourceType: "escrow_contract",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+			After:        result.Contract,
+		}, err
+	})
+	return result, err
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeneficiaries(t *testing.T) {
+	user := createRandomUser(t)
+	account := createRandomAccount(t)
+
+	beneficiary, err := testQueries.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     user.Username,
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.False(t, beneficiary.Nickname.Valid)
+
+	// an account is saved once per user
+	_, err = testQueries.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     user.Username,
+		AccountID: account.ID,
+	})
+	require.Error(t, err)
+
+	renamed, err := testQueries.RenameBeneficiary(context.Background(), RenameBeneficiaryParams{
+		ID:       beneficiary.ID,
+		Nickname: sql.NullString{String: "Landlord", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Landlord", renamed.Nickname.String)
+
+	found, err := testQueries.GetBeneficiaryByAccount(context.Background(), GetBeneficiaryByAccountParams{
+		Owner:     user.Username,
+		AccountID: account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, beneficiary.ID, found.ID)
+
+	rows, err := testQueries.ListBeneficiaries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, account.CurrencyCode, rows[0].CurrencyCode)
+
+	added, err := testQueries.CountBeneficiariesAddedSince(context.Background(), CountBeneficiariesAddedSinceParams{
+		Owner: user.Username,
+		Since: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), added)
+
+	err = testQueries.DeleteBeneficiary(context.Background(), beneficiary.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetBeneficiary(context.Background(), beneficiary.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -24,129 +24,1545 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.acceptAccountMemberStmt, err = db.PrepareContext(ctx, acceptAccountMember); err != nil {
+		return nil, fmt.Errorf("error preparing query AcceptAccountMember: %w", err)
+	}
 	if q.addAccountBalanceStmt, err = db.PrepareContext(ctx, addAccountBalance); err != nil {
 		return nil, fmt.Errorf("error preparing query AddAccountBalance: %w", err)
 	}
+	if q.addLedgerAccountBalanceStmt, err = db.PrepareContext(ctx, addLedgerAccountBalance); err != nil {
+		return nil, fmt.Errorf("error preparing query AddLedgerAccountBalance: %w", err)
+	}
+	if q.claimAliasStmt, err = db.PrepareContext(ctx, claimAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimAlias: %w", err)
+	}
+	if q.claimDueWebhookDeliveriesStmt, err = db.PrepareContext(ctx, claimDueWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimDueWebhookDeliveries: %w", err)
+	}
+	if q.claimOutboxEventsStmt, err = db.PrepareContext(ctx, claimOutboxEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimOutboxEvents: %w", err)
+	}
+	if q.closeEscrowContractStmt, err = db.PrepareContext(ctx, closeEscrowContract); err != nil {
+		return nil, fmt.Errorf("error preparing query CloseEscrowContract: %w", err)
+	}
+	if q.closeFraudCaseStmt, err = db.PrepareContext(ctx, closeFraudCase); err != nil {
+		return nil, fmt.Errorf("error preparing query CloseFraudCase: %w", err)
+	}
+	if q.closePaymentRequestStmt, err = db.PrepareContext(ctx, closePaymentRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query ClosePaymentRequest: %w", err)
+	}
+	if q.closePendingTransferStmt, err = db.PrepareContext(ctx, closePendingTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query ClosePendingTransfer: %w", err)
+	}
+	if q.countBeneficiariesAddedSinceStmt, err = db.PrepareContext(ctx, countBeneficiariesAddedSince); err != nil {
+		return nil, fmt.Errorf("error preparing query CountBeneficiariesAddedSince: %w", err)
+	}
+	if q.countOwnerProductAccountsStmt, err = db.PrepareContext(ctx, countOwnerProductAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query CountOwnerProductAccounts: %w", err)
+	}
+	if q.countTransfersBetweenStmt, err = db.PrepareContext(ctx, countTransfersBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTransfersBetween: %w", err)
+	}
 	if q.createAccountStmt, err = db.PrepareContext(ctx, createAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAccount: %w", err)
 	}
+	if q.createAccountMemberStmt, err = db.PrepareContext(ctx, createAccountMember); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAccountMember: %w", err)
+	}
+	if q.createApiKeyStmt, err = db.PrepareContext(ctx, createApiKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateApiKey: %w", err)
+	}
+	if q.createAuditEventStmt, err = db.PrepareContext(ctx, createAuditEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditEvent: %w", err)
+	}
+	if q.createAuthorizationCodeStmt, err = db.PrepareContext(ctx, createAuthorizationCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuthorizationCode: %w", err)
+	}
+	if q.createBalanceSnapshotsStmt, err = db.PrepareContext(ctx, createBalanceSnapshots); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBalanceSnapshots: %w", err)
+	}
+	if q.createBeneficiaryStmt, err = db.PrepareContext(ctx, createBeneficiary); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBeneficiary: %w", err)
+	}
 	if q.createEntryStmt, err = db.PrepareContext(ctx, createEntry); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateEntry: %w", err)
 	}
+	if q.createEscrowContractStmt, err = db.PrepareContext(ctx, createEscrowContract); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEscrowContract: %w", err)
+	}
+	if q.createFraudCaseStmt, err = db.PrepareContext(ctx, createFraudCase); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateFraudCase: %w", err)
+	}
+	if q.createInterestAccrualStmt, err = db.PrepareContext(ctx, createInterestAccrual); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInterestAccrual: %w", err)
+	}
+	if q.createInterestPostingStmt, err = db.PrepareContext(ctx, createInterestPosting); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInterestPosting: %w", err)
+	}
+	if q.createInvoiceStmt, err = db.PrepareContext(ctx, createInvoice); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInvoice: %w", err)
+	}
+	if q.createInvoiceItemStmt, err = db.PrepareContext(ctx, createInvoiceItem); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInvoiceItem: %w", err)
+	}
+	if q.createInvoicePaymentStmt, err = db.PrepareContext(ctx, createInvoicePayment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateInvoicePayment: %w", err)
+	}
+	if q.createJournalStmt, err = db.PrepareContext(ctx, createJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateJournal: %w", err)
+	}
+	if q.createKycDocumentStmt, err = db.PrepareContext(ctx, createKycDocument); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateKycDocument: %w", err)
+	}
+	if q.createOAuthClientStmt, err = db.PrepareContext(ctx, createOAuthClient); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOAuthClient: %w", err)
+	}
+	if q.createOutboxEventStmt, err = db.PrepareContext(ctx, createOutboxEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOutboxEvent: %w", err)
+	}
+	if q.createPaymentRequestStmt, err = db.PrepareContext(ctx, createPaymentRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePaymentRequest: %w", err)
+	}
+	if q.createPendingTransferStmt, err = db.PrepareContext(ctx, createPendingTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePendingTransfer: %w", err)
+	}
+	if q.createPostingStmt, err = db.PrepareContext(ctx, createPosting); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePosting: %w", err)
+	}
+	if q.createPotStmt, err = db.PrepareContext(ctx, createPot); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePot: %w", err)
+	}
+	if q.createPotAccountStmt, err = db.PrepareContext(ctx, createPotAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePotAccount: %w", err)
+	}
+	if q.createRecoveryCodeStmt, err = db.PrepareContext(ctx, createRecoveryCode); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRecoveryCode: %w", err)
+	}
+	if q.createRefreshTokenStmt, err = db.PrepareContext(ctx, createRefreshToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRefreshToken: %w", err)
+	}
 	if q.createTransferStmt, err = db.PrepareContext(ctx, createTransfer); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTransfer: %w", err)
 	}
+	if q.createTransferApprovalStmt, err = db.PrepareContext(ctx, createTransferApproval); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTransferApproval: %w", err)
+	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
+	if q.createWebhookAttemptStmt, err = db.PrepareContext(ctx, createWebhookAttempt); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookAttempt: %w", err)
+	}
+	if q.createWebhookDeliveryStmt, err = db.PrepareContext(ctx, createWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookDelivery: %w", err)
+	}
+	if q.createWebhookEndpointStmt, err = db.PrepareContext(ctx, createWebhookEndpoint); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookEndpoint: %w", err)
+	}
+	if q.deactivateWebhookEndpointStmt, err = db.PrepareContext(ctx, deactivateWebhookEndpoint); err != nil {
+		return nil, fmt.Errorf("error preparing query DeactivateWebhookEndpoint: %w", err)
+	}
 	if q.deleteAccountStmt, err = db.PrepareContext(ctx, deleteAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAccount: %w", err)
 	}
+	if q.deleteAccountMemberStmt, err = db.PrepareContext(ctx, deleteAccountMember); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAccountMember: %w", err)
+	}
+	if q.deleteAliasStmt, err = db.PrepareContext(ctx, deleteAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAlias: %w", err)
+	}
+	if q.deleteApprovalPolicyStmt, err = db.PrepareContext(ctx, deleteApprovalPolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteApprovalPolicy: %w", err)
+	}
+	if q.deleteBeneficiaryStmt, err = db.PrepareContext(ctx, deleteBeneficiary); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBeneficiary: %w", err)
+	}
+	if q.deleteRecoveryCodesStmt, err = db.PrepareContext(ctx, deleteRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteRecoveryCodes: %w", err)
+	}
+	if q.deleteTransferLimitStmt, err = db.PrepareContext(ctx, deleteTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTransferLimit: %w", err)
+	}
+	if q.disableUserTOTPStmt, err = db.PrepareContext(ctx, disableUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query DisableUserTOTP: %w", err)
+	}
+	if q.disputeEscrowContractStmt, err = db.PrepareContext(ctx, disputeEscrowContract); err != nil {
+		return nil, fmt.Errorf("error preparing query DisputeEscrowContract: %w", err)
+	}
+	if q.enableUserTOTPStmt, err = db.PrepareContext(ctx, enableUserTOTP); err != nil {
+		return nil, fmt.Errorf("error preparing query EnableUserTOTP: %w", err)
+	}
+	if q.expirePaymentRequestsStmt, err = db.PrepareContext(ctx, expirePaymentRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query ExpirePaymentRequests: %w", err)
+	}
+	if q.expirePendingTransfersStmt, err = db.PrepareContext(ctx, expirePendingTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ExpirePendingTransfers: %w", err)
+	}
 	if q.getAccountStmt, err = db.PrepareContext(ctx, getAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAccount: %w", err)
 	}
+	if q.getAccountByNumberStmt, err = db.PrepareContext(ctx, getAccountByNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountByNumber: %w", err)
+	}
 	if q.getAccountForUpdateStmt, err = db.PrepareContext(ctx, getAccountForUpdate); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAccountForUpdate: %w", err)
 	}
+	if q.getAccountMemberStmt, err = db.PrepareContext(ctx, getAccountMember); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountMember: %w", err)
+	}
+	if q.getAccountOwnerTierStmt, err = db.PrepareContext(ctx, getAccountOwnerTier); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountOwnerTier: %w", err)
+	}
+	if q.getAccountProductStmt, err = db.PrepareContext(ctx, getAccountProduct); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAccountProduct: %w", err)
+	}
+	if q.getAliasStmt, err = db.PrepareContext(ctx, getAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAlias: %w", err)
+	}
+	if q.getApiKeyByPrefixStmt, err = db.PrepareContext(ctx, getApiKeyByPrefix); err != nil {
+		return nil, fmt.Errorf("error preparing query GetApiKeyByPrefix: %w", err)
+	}
+	if q.getApplicableApprovalPolicyStmt, err = db.PrepareContext(ctx, getApplicableApprovalPolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query GetApplicableApprovalPolicy: %w", err)
+	}
+	if q.getApprovalPolicyStmt, err = db.PrepareContext(ctx, getApprovalPolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query GetApprovalPolicy: %w", err)
+	}
+	if q.getBeneficiaryStmt, err = db.PrepareContext(ctx, getBeneficiary); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBeneficiary: %w", err)
+	}
+	if q.getBeneficiaryByAccountStmt, err = db.PrepareContext(ctx, getBeneficiaryByAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBeneficiaryByAccount: %w", err)
+	}
 	if q.getEntryStmt, err = db.PrepareContext(ctx, getEntry); err != nil {
 		return nil, fmt.Errorf("error preparing query GetEntry: %w", err)
 	}
+	if q.getEscrowContractStmt, err = db.PrepareContext(ctx, getEscrowContract); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEscrowContract: %w", err)
+	}
+	if q.getEscrowContractForUpdateStmt, err = db.PrepareContext(ctx, getEscrowContractForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEscrowContractForUpdate: %w", err)
+	}
+	if q.getFraudCaseStmt, err = db.PrepareContext(ctx, getFraudCase); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFraudCase: %w", err)
+	}
+	if q.getFraudCaseForUpdateStmt, err = db.PrepareContext(ctx, getFraudCaseForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFraudCaseForUpdate: %w", err)
+	}
+	if q.getInterestPostingStmt, err = db.PrepareContext(ctx, getInterestPosting); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInterestPosting: %w", err)
+	}
+	if q.getInvoiceStmt, err = db.PrepareContext(ctx, getInvoice); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInvoice: %w", err)
+	}
+	if q.getInvoiceByPayTokenStmt, err = db.PrepareContext(ctx, getInvoiceByPayToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInvoiceByPayToken: %w", err)
+	}
+	if q.getInvoiceForUpdateStmt, err = db.PrepareContext(ctx, getInvoiceForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetInvoiceForUpdate: %w", err)
+	}
+	if q.getJournalStmt, err = db.PrepareContext(ctx, getJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query GetJournal: %w", err)
+	}
+	if q.getJournalByTransferStmt, err = db.PrepareContext(ctx, getJournalByTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query GetJournalByTransfer: %w", err)
+	}
+	if q.getKycProfileStmt, err = db.PrepareContext(ctx, getKycProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query GetKycProfile: %w", err)
+	}
+	if q.getKycTierStmt, err = db.PrepareContext(ctx, getKycTier); err != nil {
+		return nil, fmt.Errorf("error preparing query GetKycTier: %w", err)
+	}
+	if q.getKycTierForUserStmt, err = db.PrepareContext(ctx, getKycTierForUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetKycTierForUser: %w", err)
+	}
+	if q.getLastAuditEventStmt, err = db.PrepareContext(ctx, getLastAuditEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLastAuditEvent: %w", err)
+	}
+	if q.getLastInterestAccrualDateStmt, err = db.PrepareContext(ctx, getLastInterestAccrualDate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLastInterestAccrualDate: %w", err)
+	}
+	if q.getLatestBalanceSnapshotStmt, err = db.PrepareContext(ctx, getLatestBalanceSnapshot); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestBalanceSnapshot: %w", err)
+	}
+	if q.getLedgerAccountByCodeStmt, err = db.PrepareContext(ctx, getLedgerAccountByCode); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLedgerAccountByCode: %w", err)
+	}
+	if q.getOAuthClientStmt, err = db.PrepareContext(ctx, getOAuthClient); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOAuthClient: %w", err)
+	}
+	if q.getOutboxEventStmt, err = db.PrepareContext(ctx, getOutboxEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutboxEvent: %w", err)
+	}
+	if q.getOutgoingTransferTotalsStmt, err = db.PrepareContext(ctx, getOutgoingTransferTotals); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutgoingTransferTotals: %w", err)
+	}
+	if q.getPaymentRequestStmt, err = db.PrepareContext(ctx, getPaymentRequest); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentRequest: %w", err)
+	}
+	if q.getPaymentRequestForUpdateStmt, err = db.PrepareContext(ctx, getPaymentRequestForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentRequestForUpdate: %w", err)
+	}
+	if q.getPendingTransferStmt, err = db.PrepareContext(ctx, getPendingTransfer); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingTransfer: %w", err)
+	}
+	if q.getPendingTransferForUpdateStmt, err = db.PrepareContext(ctx, getPendingTransferForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingTransferForUpdate: %w", err)
+	}
+	if q.getPotStmt, err = db.PrepareContext(ctx, getPot); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPot: %w", err)
+	}
+	if q.getPrimaryAccountStmt, err = db.PrepareContext(ctx, getPrimaryAccount); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPrimaryAccount: %w", err)
+	}
+	if q.getRefreshTokenStmt, err = db.PrepareContext(ctx, getRefreshToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRefreshToken: %w", err)
+	}
+	if q.getRevokedAccessTokenStmt, err = db.PrepareContext(ctx, getRevokedAccessToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRevokedAccessToken: %w", err)
+	}
+	if q.getRoundUpPotStmt, err = db.PrepareContext(ctx, getRoundUpPot); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRoundUpPot: %w", err)
+	}
 	if q.getTransferStmt, err = db.PrepareContext(ctx, getTransfer); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTransfer: %w", err)
 	}
+	if q.getTransferLimitStmt, err = db.PrepareContext(ctx, getTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransferLimit: %w", err)
+	}
 	if q.getUserStmt, err = db.PrepareContext(ctx, getUser); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUser: %w", err)
 	}
+	if q.getWebhookDeliveryStmt, err = db.PrepareContext(ctx, getWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookDelivery: %w", err)
+	}
+	if q.getWebhookEndpointStmt, err = db.PrepareContext(ctx, getWebhookEndpoint); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookEndpoint: %w", err)
+	}
+	if q.incrementAliasAttemptsStmt, err = db.PrepareContext(ctx, incrementAliasAttempts); err != nil {
+		return nil, fmt.Errorf("error preparing query IncrementAliasAttempts: %w", err)
+	}
+	if q.listAccountEntryTotalsStmt, err = db.PrepareContext(ctx, listAccountEntryTotals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountEntryTotals: %w", err)
+	}
+	if q.listAccountIDsStmt, err = db.PrepareContext(ctx, listAccountIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountIDs: %w", err)
+	}
+	if q.listAccountInvitationsStmt, err = db.PrepareContext(ctx, listAccountInvitations); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountInvitations: %w", err)
+	}
+	if q.listAccountMembersStmt, err = db.PrepareContext(ctx, listAccountMembers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountMembers: %w", err)
+	}
+	if q.listAccountPendingTransfersStmt, err = db.PrepareContext(ctx, listAccountPendingTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountPendingTransfers: %w", err)
+	}
+	if q.listAccountProductsStmt, err = db.PrepareContext(ctx, listAccountProducts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountProducts: %w", err)
+	}
 	if q.listAccountsStmt, err = db.PrepareContext(ctx, listAccounts); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAccounts: %w", err)
 	}
+	if q.listAccountsWithoutNumberStmt, err = db.PrepareContext(ctx, listAccountsWithoutNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAccountsWithoutNumber: %w", err)
+	}
+	if q.listApiKeysStmt, err = db.PrepareContext(ctx, listApiKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query ListApiKeys: %w", err)
+	}
+	if q.listApplicableTransferLimitsStmt, err = db.PrepareContext(ctx, listApplicableTransferLimits); err != nil {
+		return nil, fmt.Errorf("error preparing query ListApplicableTransferLimits: %w", err)
+	}
+	if q.listApprovableTransfersStmt, err = db.PrepareContext(ctx, listApprovableTransfers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListApprovableTransfers: %w", err)
+	}
+	if q.listApprovalPoliciesStmt, err = db.PrepareContext(ctx, listApprovalPolicies); err != nil {
+		return nil, fmt.Errorf("error preparing query ListApprovalPolicies: %w", err)
+	}
+	if q.listAuditEventsStmt, err = db.PrepareContext(ctx, listAuditEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditEvents: %w", err)
+	}
+	if q.listAuditEventsAfterStmt, err = db.PrepareContext(ctx, listAuditEventsAfter); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAuditEventsAfter: %w", err)
+	}
+	if q.listBeneficiariesStmt, err = db.PrepareContext(ctx, listBeneficiaries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBeneficiaries: %w", err)
+	}
+	if q.listDueEscrowContractsStmt, err = db.PrepareContext(ctx, listDueEscrowContracts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDueEscrowContracts: %w", err)
+	}
 	if q.listEntriesStmt, err = db.PrepareContext(ctx, listEntries); err != nil {
 		return nil, fmt.Errorf("error preparing query ListEntries: %w", err)
 	}
+	if q.listEscrowContractsStmt, err = db.PrepareContext(ctx, listEscrowContracts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEscrowContracts: %w", err)
+	}
+	if q.listEscrowContractsByStatusStmt, err = db.PrepareContext(ctx, listEscrowContractsByStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEscrowContractsByStatus: %w", err)
+	}
+	if q.listEscrowContractsFundedSinceStmt, err = db.PrepareContext(ctx, listEscrowContractsFundedSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEscrowContractsFundedSince: %w", err)
+	}
+	if q.listFraudCasesStmt, err = db.PrepareContext(ctx, listFraudCases); err != nil {
+		return nil, fmt.Errorf("error preparing query ListFraudCases: %w", err)
+	}
+	if q.listIncomingPaymentRequestsStmt, err = db.PrepareContext(ctx, listIncomingPaymentRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query ListIncomingPaymentRequests: %w", err)
+	}
+	if q.listInterestAccrualsStmt, err = db.PrepareContext(ctx, listInterestAccruals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInterestAccruals: %w", err)
+	}
+	if q.listInterestBearingAccountsStmt, err = db.PrepareContext(ctx, listInterestBearingAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInterestBearingAccounts: %w", err)
+	}
+	if q.listInvoiceItemsStmt, err = db.PrepareContext(ctx, listInvoiceItems); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInvoiceItems: %w", err)
+	}
+	if q.listInvoicePaymentsStmt, err = db.PrepareContext(ctx, listInvoicePayments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInvoicePayments: %w", err)
+	}
+	if q.listInvoicesStmt, err = db.PrepareContext(ctx, listInvoices); err != nil {
+		return nil, fmt.Errorf("error preparing query ListInvoices: %w", err)
+	}
+	if q.listJournalPostingsStmt, err = db.PrepareContext(ctx, listJournalPostings); err != nil {
+		return nil, fmt.Errorf("error preparing query ListJournalPostings: %w", err)
+	}
+	if q.listKycDocumentsStmt, err = db.PrepareContext(ctx, listKycDocuments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListKycDocuments: %w", err)
+	}
+	if q.listKycProfilesByStatusStmt, err = db.PrepareContext(ctx, listKycProfilesByStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query ListKycProfilesByStatus: %w", err)
+	}
+	if q.listKycTiersStmt, err = db.PrepareContext(ctx, listKycTiers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListKycTiers: %w", err)
+	}
+	if q.listLedgerAccountsStmt, err = db.PrepareContext(ctx, listLedgerAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLedgerAccounts: %w", err)
+	}
+	if q.listMemberSpendsSinceStmt, err = db.PrepareContext(ctx, listMemberSpendsSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMemberSpendsSince: %w", err)
+	}
+	if q.listOutgoingPaymentRequestsStmt, err = db.PrepareContext(ctx, listOutgoingPaymentRequests); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOutgoingPaymentRequests: %w", err)
+	}
+	if q.listOutgoingTransfersSinceStmt, err = db.PrepareContext(ctx, listOutgoingTransfersSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOutgoingTransfersSince: %w", err)
+	}
+	if q.listOutstandingInvoicesStmt, err = db.PrepareContext(ctx, listOutstandingInvoices); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOutstandingInvoices: %w", err)
+	}
+	if q.listOwnerLimitAccountsStmt, err = db.PrepareContext(ctx, listOwnerLimitAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListOwnerLimitAccounts: %w", err)
+	}
+	if q.listPotsStmt, err = db.PrepareContext(ctx, listPots); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPots: %w", err)
+	}
+	if q.listSubscribedWebhookEndpointsStmt, err = db.PrepareContext(ctx, listSubscribedWebhookEndpoints); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSubscribedWebhookEndpoints: %w", err)
+	}
 	if q.listTransferStmt, err = db.PrepareContext(ctx, listTransfer); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTransfer: %w", err)
 	}
+	if q.listTransferApprovalsStmt, err = db.PrepareContext(ctx, listTransferApprovals); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTransferApprovals: %w", err)
+	}
+	if q.listTransferEntryCountsStmt, err = db.PrepareContext(ctx, listTransferEntryCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTransferEntryCounts: %w", err)
+	}
+	if q.listTransferLimitsStmt, err = db.PrepareContext(ctx, listTransferLimits); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTransferLimits: %w", err)
+	}
+	if q.listUnpostedInterestAccountsStmt, err = db.PrepareContext(ctx, listUnpostedInterestAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnpostedInterestAccounts: %w", err)
+	}
+	if q.listUnusedRecoveryCodesStmt, err = db.PrepareContext(ctx, listUnusedRecoveryCodes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUnusedRecoveryCodes: %w", err)
+	}
+	if q.listUserAliasesStmt, err = db.PrepareContext(ctx, listUserAliases); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUserAliases: %w", err)
+	}
+	if q.listWebhookAttemptsStmt, err = db.PrepareContext(ctx, listWebhookAttempts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookAttempts: %w", err)
+	}
+	if q.listWebhookDeliveriesStmt, err = db.PrepareContext(ctx, listWebhookDeliveries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookDeliveries: %w", err)
+	}
+	if q.listWebhookEndpointsStmt, err = db.PrepareContext(ctx, listWebhookEndpoints); err != nil {
+		return nil, fmt.Errorf("error preparing query ListWebhookEndpoints: %w", err)
+	}
+	if q.lockAccountsStmt, err = db.PrepareContext(ctx, lockAccounts); err != nil {
+		return nil, fmt.Errorf("error preparing query LockAccounts: %w", err)
+	}
+	if q.lockAuditChainStmt, err = db.PrepareContext(ctx, lockAuditChain); err != nil {
+		return nil, fmt.Errorf("error preparing query LockAuditChain: %w", err)
+	}
+	if q.lockUserStmt, err = db.PrepareContext(ctx, lockUser); err != nil {
+		return nil, fmt.Errorf("error preparing query LockUser: %w", err)
+	}
+	if q.markInvoicesOverdueStmt, err = db.PrepareContext(ctx, markInvoicesOverdue); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkInvoicesOverdue: %w", err)
+	}
+	if q.markOutboxEventDispatchedStmt, err = db.PrepareContext(ctx, markOutboxEventDispatched); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxEventDispatched: %w", err)
+	}
+	if q.markRecoveryCodeUsedStmt, err = db.PrepareContext(ctx, markRecoveryCodeUsed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkRecoveryCodeUsed: %w", err)
+	}
+	if q.nextAccountSequenceStmt, err = db.PrepareContext(ctx, nextAccountSequence); err != nil {
+		return nil, fmt.Errorf("error preparing query NextAccountSequence: %w", err)
+	}
+	if q.notifyAccountEventStmt, err = db.PrepareContext(ctx, notifyAccountEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query NotifyAccountEvent: %w", err)
+	}
+	if q.recordInvoicePaymentStmt, err = db.PrepareContext(ctx, recordInvoicePayment); err != nil {
+		return nil, fmt.Errorf("error preparing query RecordInvoicePayment: %w", err)
+	}
+	if q.recordTOTPFailureStmt, err = db.PrepareContext(ctx, recordTOTPFailure); err != nil {
+		return nil, fmt.Errorf("error preparing query RecordTOTPFailure: %w", err)
+	}
+	if q.redeliverWebhookStmt, err = db.PrepareContext(ctx, redeliverWebhook); err != nil {
+		return nil, fmt.Errorf("error preparing query RedeliverWebhook: %w", err)
+	}
+	if q.renameBeneficiaryStmt, err = db.PrepareContext(ctx, renameBeneficiary); err != nil {
+		return nil, fmt.Errorf("error preparing query RenameBeneficiary: %w", err)
+	}
+	if q.reviewKycProfileStmt, err = db.PrepareContext(ctx, reviewKycProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query ReviewKycProfile: %w", err)
+	}
+	if q.revokeAccessTokenStmt, err = db.PrepareContext(ctx, revokeAccessToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAccessToken: %w", err)
+	}
+	if q.revokeApiKeyStmt, err = db.PrepareContext(ctx, revokeApiKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeApiKey: %w", err)
+	}
+	if q.revokeRefreshTokenStmt, err = db.PrepareContext(ctx, revokeRefreshToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeRefreshToken: %w", err)
+	}
+	if q.sendInvoiceStmt, err = db.PrepareContext(ctx, sendInvoice); err != nil {
+		return nil, fmt.Errorf("error preparing query SendInvoice: %w", err)
+	}
+	if q.setAccountNumberStmt, err = db.PrepareContext(ctx, setAccountNumber); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAccountNumber: %w", err)
+	}
+	if q.setAccountStatusStmt, err = db.PrepareContext(ctx, setAccountStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query SetAccountStatus: %w", err)
+	}
+	if q.setEscrowFundJournalStmt, err = db.PrepareContext(ctx, setEscrowFundJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query SetEscrowFundJournal: %w", err)
+	}
+	if q.setInterestPostingJournalStmt, err = db.PrepareContext(ctx, setInterestPostingJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query SetInterestPostingJournal: %w", err)
+	}
+	if q.setUserTierStmt, err = db.PrepareContext(ctx, setUserTier); err != nil {
+		return nil, fmt.Errorf("error preparing query SetUserTier: %w", err)
+	}
+	if q.submitKycProfileStmt, err = db.PrepareContext(ctx, submitKycProfile); err != nil {
+		return nil, fmt.Errorf("error preparing query SubmitKycProfile: %w", err)
+	}
+	if q.sumEntriesBetweenStmt, err = db.PrepareContext(ctx, sumEntriesBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query SumEntriesBetween: %w", err)
+	}
+	if q.sumInterestAccrualsStmt, err = db.PrepareContext(ctx, sumInterestAccruals); err != nil {
+		return nil, fmt.Errorf("error preparing query SumInterestAccruals: %w", err)
+	}
+	if q.sumPostingsByCurrencyStmt, err = db.PrepareContext(ctx, sumPostingsByCurrency); err != nil {
+		return nil, fmt.Errorf("error preparing query SumPostingsByCurrency: %w", err)
+	}
+	if q.touchApiKeyStmt, err = db.PrepareContext(ctx, touchApiKey); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchApiKey: %w", err)
+	}
 	if q.updateAccountStmt, err = db.PrepareContext(ctx, updateAccount); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAccount: %w", err)
 	}
+	if q.updatePotStmt, err = db.PrepareContext(ctx, updatePot); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdatePot: %w", err)
+	}
+	if q.updateUserTOTPCounterStmt, err = db.PrepareContext(ctx, updateUserTOTPCounter); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserTOTPCounter: %w", err)
+	}
+	if q.updateUserTOTPSecretStmt, err = db.PrepareContext(ctx, updateUserTOTPSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserTOTPSecret: %w", err)
+	}
+	if q.updateWebhookDeliveryStmt, err = db.PrepareContext(ctx, updateWebhookDelivery); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWebhookDelivery: %w", err)
+	}
+	if q.upsertApprovalPolicyStmt, err = db.PrepareContext(ctx, upsertApprovalPolicy); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertApprovalPolicy: %w", err)
+	}
+	if q.upsertTransferLimitStmt, err = db.PrepareContext(ctx, upsertTransferLimit); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertTransferLimit: %w", err)
+	}
+	if q.useAuthorizationCodeStmt, err = db.PrepareContext(ctx, useAuthorizationCode); err != nil {
+		return nil, fmt.Errorf("error preparing query UseAuthorizationCode: %w", err)
+	}
+	if q.verifyAliasStmt, err = db.PrepareContext(ctx, verifyAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query VerifyAlias: %w", err)
+	}
+	if q.voidInvoiceStmt, err = db.PrepareContext(ctx, voidInvoice); err != nil {
+		return nil, fmt.Errorf("error preparing query VoidInvoice: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.acceptAccountMemberStmt != nil {
+		if cerr := q.acceptAccountMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing acceptAccountMemberStmt: %w", cerr)
+		}
+	}
 	if q.addAccountBalanceStmt != nil {
 		if cerr := q.addAccountBalanceStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addAccountBalanceStmt: %w", cerr)
 		}
 	}
+	if q.addLedgerAccountBalanceStmt != nil {
+		if cerr := q.addLedgerAccountBalanceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addLedgerAccountBalanceStmt: %w", cerr)
+		}
+	}
+	if q.claimAliasStmt != nil {
+		if cerr := q.claimAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimAliasStmt: %w", cerr)
+		}
+	}
+	if q.claimDueWebhookDeliveriesStmt != nil {
+		if cerr := q.claimDueWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimDueWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.claimOutboxEventsStmt != nil {
+		if cerr := q.claimOutboxEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimOutboxEventsStmt: %w", cerr)
+		}
+	}
+	if q.closeEscrowContractStmt != nil {
+		if cerr := q.closeEscrowContractStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing closeEscrowContractStmt: %w", cerr)
+		}
+	}
+	if q.closeFraudCaseStmt != nil {
+		if cerr := q.closeFraudCaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing closeFraudCaseStmt: %w", cerr)
+		}
+	}
+	if q.closePaymentRequestStmt != nil {
+		if cerr := q.closePaymentRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing closePaymentRequestStmt: %w", cerr)
+		}
+	}
+	if q.closePendingTransferStmt != nil {
+		if cerr := q.closePendingTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing closePendingTransferStmt: %w", cerr)
+		}
+	}
+	if q.countBeneficiariesAddedSinceStmt != nil {
+		if cerr := q.countBeneficiariesAddedSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countBeneficiariesAddedSinceStmt: %w", cerr)
+		}
+	}
+	if q.countOwnerProductAccountsStmt != nil {
+		if cerr := q.countOwnerProductAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countOwnerProductAccountsStmt: %w", cerr)
+		}
+	}
+	if q.countTransfersBetweenStmt != nil {
+		if cerr := q.countTransfersBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTransfersBetweenStmt: %w", cerr)
+		}
+	}
 	if q.createAccountStmt != nil {
 		if cerr := q.createAccountStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAccountStmt: %w", cerr)
 		}
 	}
+	if q.createAccountMemberStmt != nil {
+		if cerr := q.createAccountMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAccountMemberStmt: %w", cerr)
+		}
+	}
+	if q.createApiKeyStmt != nil {
+		if cerr := q.createApiKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createApiKeyStmt: %w", cerr)
+		}
+	}
+	if q.createAuditEventStmt != nil {
+		if cerr := q.createAuditEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditEventStmt: %w", cerr)
+		}
+	}
+	if q.createAuthorizationCodeStmt != nil {
+		if cerr := q.createAuthorizationCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuthorizationCodeStmt: %w", cerr)
+		}
+	}
+	if q.createBalanceSnapshotsStmt != nil {
+		if cerr := q.createBalanceSnapshotsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBalanceSnapshotsStmt: %w", cerr)
+		}
+	}
+	if q.createBeneficiaryStmt != nil {
+		if cerr := q.createBeneficiaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBeneficiaryStmt: %w", cerr)
+		}
+	}
 	if q.createEntryStmt != nil {
 		if cerr := q.createEntryStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createEntryStmt: %w", cerr)
 		}
 	}
-	if q.createTransferStmt != nil {
-		if cerr := q.createTransferStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createTransferStmt: %w", cerr)
+	if q.createEscrowContractStmt != nil {
+		if cerr := q.createEscrowContractStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEscrowContractStmt: %w", cerr)
 		}
 	}
-	if q.createUserStmt != nil {
-		if cerr := q.createUserStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+	if q.createFraudCaseStmt != nil {
+		if cerr := q.createFraudCaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createFraudCaseStmt: %w", cerr)
 		}
 	}
-	if q.deleteAccountStmt != nil {
-		if cerr := q.deleteAccountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing deleteAccountStmt: %w", cerr)
+	if q.createInterestAccrualStmt != nil {
+		if cerr := q.createInterestAccrualStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInterestAccrualStmt: %w", cerr)
 		}
 	}
-	if q.getAccountStmt != nil {
-		if cerr := q.getAccountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getAccountStmt: %w", cerr)
+	if q.createInterestPostingStmt != nil {
+		if cerr := q.createInterestPostingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInterestPostingStmt: %w", cerr)
 		}
 	}
-	if q.getAccountForUpdateStmt != nil {
-		if cerr := q.getAccountForUpdateStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getAccountForUpdateStmt: %w", cerr)
+	if q.createInvoiceStmt != nil {
+		if cerr := q.createInvoiceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInvoiceStmt: %w", cerr)
 		}
 	}
-	if q.getEntryStmt != nil {
-		if cerr := q.getEntryStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getEntryStmt: %w", cerr)
+	if q.createInvoiceItemStmt != nil {
+		if cerr := q.createInvoiceItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInvoiceItemStmt: %w", cerr)
 		}
 	}
-	if q.getTransferStmt != nil {
-		if cerr := q.getTransferStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getTransferStmt: %w", cerr)
+	if q.createInvoicePaymentStmt != nil {
+		if cerr := q.createInvoicePaymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createInvoicePaymentStmt: %w", cerr)
 		}
 	}
-	if q.getUserStmt != nil {
-		if cerr := q.getUserStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
+	if q.createJournalStmt != nil {
+		if cerr := q.createJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createJournalStmt: %w", cerr)
 		}
 	}
-	if q.listAccountsStmt != nil {
-		if cerr := q.listAccountsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listAccountsStmt: %w", cerr)
+	if q.createKycDocumentStmt != nil {
+		if cerr := q.createKycDocumentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createKycDocumentStmt: %w", cerr)
 		}
 	}
-	if q.listEntriesStmt != nil {
-		if cerr := q.listEntriesStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listEntriesStmt: %w", cerr)
+	if q.createOAuthClientStmt != nil {
+		if cerr := q.createOAuthClientStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOAuthClientStmt: %w", cerr)
 		}
 	}
-	if q.listTransferStmt != nil {
-		if cerr := q.listTransferStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listTransferStmt: %w", cerr)
+	if q.createOutboxEventStmt != nil {
+		if cerr := q.createOutboxEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOutboxEventStmt: %w", cerr)
 		}
 	}
-	if q.updateAccountStmt != nil {
-		if cerr := q.updateAccountStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing updateAccountStmt: %w", cerr)
+	if q.createPaymentRequestStmt != nil {
+		if cerr := q.createPaymentRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPaymentRequestStmt: %w", cerr)
+		}
+	}
+	if q.createPendingTransferStmt != nil {
+		if cerr := q.createPendingTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPendingTransferStmt: %w", cerr)
+		}
+	}
+	if q.createPostingStmt != nil {
+		if cerr := q.createPostingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPostingStmt: %w", cerr)
+		}
+	}
+	if q.createPotStmt != nil {
+		if cerr := q.createPotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPotStmt: %w", cerr)
+		}
+	}
+	if q.createPotAccountStmt != nil {
+		if cerr := q.createPotAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPotAccountStmt: %w", cerr)
+		}
+	}
+	if q.createRecoveryCodeStmt != nil {
+		if cerr := q.createRecoveryCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRecoveryCodeStmt: %w", cerr)
+		}
+	}
+	if q.createRefreshTokenStmt != nil {
+		if cerr := q.createRefreshTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRefreshTokenStmt: %w", cerr)
+		}
+	}
+	if q.createTransferStmt != nil {
+		if cerr := q.createTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTransferStmt: %w", cerr)
+		}
+	}
+	if q.createTransferApprovalStmt != nil {
+		if cerr := q.createTransferApprovalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTransferApprovalStmt: %w", cerr)
+		}
+	}
+	if q.createUserStmt != nil {
+		if cerr := q.createUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+		}
+	}
+	if q.createWebhookAttemptStmt != nil {
+		if cerr := q.createWebhookAttemptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookAttemptStmt: %w", cerr)
+		}
+	}
+	if q.createWebhookDeliveryStmt != nil {
+		if cerr := q.createWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.createWebhookEndpointStmt != nil {
+		if cerr := q.createWebhookEndpointStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookEndpointStmt: %w", cerr)
+		}
+	}
+	if q.deactivateWebhookEndpointStmt != nil {
+		if cerr := q.deactivateWebhookEndpointStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deactivateWebhookEndpointStmt: %w", cerr)
+		}
+	}
+	if q.deleteAccountStmt != nil {
+		if cerr := q.deleteAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAccountStmt: %w", cerr)
+		}
+	}
+	if q.deleteAccountMemberStmt != nil {
+		if cerr := q.deleteAccountMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAccountMemberStmt: %w", cerr)
+		}
+	}
+	if q.deleteAliasStmt != nil {
+		if cerr := q.deleteAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAliasStmt: %w", cerr)
+		}
+	}
+	if q.deleteApprovalPolicyStmt != nil {
+		if cerr := q.deleteApprovalPolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteApprovalPolicyStmt: %w", cerr)
+		}
+	}
+	if q.deleteBeneficiaryStmt != nil {
+		if cerr := q.deleteBeneficiaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBeneficiaryStmt: %w", cerr)
+		}
+	}
+	if q.deleteRecoveryCodesStmt != nil {
+		if cerr := q.deleteRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteRecoveryCodesStmt: %w", cerr)
+		}
+	}
+	if q.deleteTransferLimitStmt != nil {
+		if cerr := q.deleteTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.disableUserTOTPStmt != nil {
+		if cerr := q.disableUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing disableUserTOTPStmt: %w", cerr)
+		}
+	}
+	if q.disputeEscrowContractStmt != nil {
+		if cerr := q.disputeEscrowContractStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing disputeEscrowContractStmt: %w", cerr)
+		}
+	}
+	if q.enableUserTOTPStmt != nil {
+		if cerr := q.enableUserTOTPStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing enableUserTOTPStmt: %w", cerr)
+		}
+	}
+	if q.expirePaymentRequestsStmt != nil {
+		if cerr := q.expirePaymentRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing expirePaymentRequestsStmt: %w", cerr)
+		}
+	}
+	if q.expirePendingTransfersStmt != nil {
+		if cerr := q.expirePendingTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing expirePendingTransfersStmt: %w", cerr)
+		}
+	}
+	if q.getAccountStmt != nil {
+		if cerr := q.getAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountStmt: %w", cerr)
+		}
+	}
+	if q.getAccountByNumberStmt != nil {
+		if cerr := q.getAccountByNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountByNumberStmt: %w", cerr)
+		}
+	}
+	if q.getAccountForUpdateStmt != nil {
+		if cerr := q.getAccountForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getAccountMemberStmt != nil {
+		if cerr := q.getAccountMemberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountMemberStmt: %w", cerr)
+		}
+	}
+	if q.getAccountOwnerTierStmt != nil {
+		if cerr := q.getAccountOwnerTierStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountOwnerTierStmt: %w", cerr)
+		}
+	}
+	if q.getAccountProductStmt != nil {
+		if cerr := q.getAccountProductStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAccountProductStmt: %w", cerr)
+		}
+	}
+	if q.getAliasStmt != nil {
+		if cerr := q.getAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAliasStmt: %w", cerr)
+		}
+	}
+	if q.getApiKeyByPrefixStmt != nil {
+		if cerr := q.getApiKeyByPrefixStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getApiKeyByPrefixStmt: %w", cerr)
+		}
+	}
+	if q.getApplicableApprovalPolicyStmt != nil {
+		if cerr := q.getApplicableApprovalPolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getApplicableApprovalPolicyStmt: %w", cerr)
+		}
+	}
+	if q.getApprovalPolicyStmt != nil {
+		if cerr := q.getApprovalPolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getApprovalPolicyStmt: %w", cerr)
+		}
+	}
+	if q.getBeneficiaryStmt != nil {
+		if cerr := q.getBeneficiaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBeneficiaryStmt: %w", cerr)
+		}
+	}
+	if q.getBeneficiaryByAccountStmt != nil {
+		if cerr := q.getBeneficiaryByAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBeneficiaryByAccountStmt: %w", cerr)
+		}
+	}
+	if q.getEntryStmt != nil {
+		if cerr := q.getEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEntryStmt: %w", cerr)
+		}
+	}
+	if q.getEscrowContractStmt != nil {
+		if cerr := q.getEscrowContractStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEscrowContractStmt: %w", cerr)
+		}
+	}
+	if q.getEscrowContractForUpdateStmt != nil {
+		if cerr := q.getEscrowContractForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEscrowContractForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getFraudCaseStmt != nil {
+		if cerr := q.getFraudCaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFraudCaseStmt: %w", cerr)
+		}
+	}
+	if q.getFraudCaseForUpdateStmt != nil {
+		if cerr := q.getFraudCaseForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFraudCaseForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getInterestPostingStmt != nil {
+		if cerr := q.getInterestPostingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInterestPostingStmt: %w", cerr)
+		}
+	}
+	if q.getInvoiceStmt != nil {
+		if cerr := q.getInvoiceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInvoiceStmt: %w", cerr)
+		}
+	}
+	if q.getInvoiceByPayTokenStmt != nil {
+		if cerr := q.getInvoiceByPayTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInvoiceByPayTokenStmt: %w", cerr)
+		}
+	}
+	if q.getInvoiceForUpdateStmt != nil {
+		if cerr := q.getInvoiceForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getInvoiceForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getJournalStmt != nil {
+		if cerr := q.getJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getJournalStmt: %w", cerr)
+		}
+	}
+	if q.getJournalByTransferStmt != nil {
+		if cerr := q.getJournalByTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getJournalByTransferStmt: %w", cerr)
+		}
+	}
+	if q.getKycProfileStmt != nil {
+		if cerr := q.getKycProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getKycProfileStmt: %w", cerr)
+		}
+	}
+	if q.getKycTierStmt != nil {
+		if cerr := q.getKycTierStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getKycTierStmt: %w", cerr)
+		}
+	}
+	if q.getKycTierForUserStmt != nil {
+		if cerr := q.getKycTierForUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getKycTierForUserStmt: %w", cerr)
+		}
+	}
+	if q.getLastAuditEventStmt != nil {
+		if cerr := q.getLastAuditEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastAuditEventStmt: %w", cerr)
+		}
+	}
+	if q.getLastInterestAccrualDateStmt != nil {
+		if cerr := q.getLastInterestAccrualDateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastInterestAccrualDateStmt: %w", cerr)
+		}
+	}
+	if q.getLatestBalanceSnapshotStmt != nil {
+		if cerr := q.getLatestBalanceSnapshotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestBalanceSnapshotStmt: %w", cerr)
+		}
+	}
+	if q.getLedgerAccountByCodeStmt != nil {
+		if cerr := q.getLedgerAccountByCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLedgerAccountByCodeStmt: %w", cerr)
+		}
+	}
+	if q.getOAuthClientStmt != nil {
+		if cerr := q.getOAuthClientStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOAuthClientStmt: %w", cerr)
+		}
+	}
+	if q.getOutboxEventStmt != nil {
+		if cerr := q.getOutboxEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutboxEventStmt: %w", cerr)
+		}
+	}
+	if q.getOutgoingTransferTotalsStmt != nil {
+		if cerr := q.getOutgoingTransferTotalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutgoingTransferTotalsStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentRequestStmt != nil {
+		if cerr := q.getPaymentRequestStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentRequestStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentRequestForUpdateStmt != nil {
+		if cerr := q.getPaymentRequestForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentRequestForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getPendingTransferStmt != nil {
+		if cerr := q.getPendingTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingTransferStmt: %w", cerr)
+		}
+	}
+	if q.getPendingTransferForUpdateStmt != nil {
+		if cerr := q.getPendingTransferForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingTransferForUpdateStmt: %w", cerr)
+		}
+	}
+	if q.getPotStmt != nil {
+		if cerr := q.getPotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPotStmt: %w", cerr)
+		}
+	}
+	if q.getPrimaryAccountStmt != nil {
+		if cerr := q.getPrimaryAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPrimaryAccountStmt: %w", cerr)
+		}
+	}
+	if q.getRefreshTokenStmt != nil {
+		if cerr := q.getRefreshTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRefreshTokenStmt: %w", cerr)
+		}
+	}
+	if q.getRevokedAccessTokenStmt != nil {
+		if cerr := q.getRevokedAccessTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRevokedAccessTokenStmt: %w", cerr)
+		}
+	}
+	if q.getRoundUpPotStmt != nil {
+		if cerr := q.getRoundUpPotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRoundUpPotStmt: %w", cerr)
+		}
+	}
+	if q.getTransferStmt != nil {
+		if cerr := q.getTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransferStmt: %w", cerr)
+		}
+	}
+	if q.getTransferLimitStmt != nil {
+		if cerr := q.getTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.getUserStmt != nil {
+		if cerr := q.getUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookDeliveryStmt != nil {
+		if cerr := q.getWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookEndpointStmt != nil {
+		if cerr := q.getWebhookEndpointStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookEndpointStmt: %w", cerr)
+		}
+	}
+	if q.incrementAliasAttemptsStmt != nil {
+		if cerr := q.incrementAliasAttemptsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing incrementAliasAttemptsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountEntryTotalsStmt != nil {
+		if cerr := q.listAccountEntryTotalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountEntryTotalsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountIDsStmt != nil {
+		if cerr := q.listAccountIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountIDsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountInvitationsStmt != nil {
+		if cerr := q.listAccountInvitationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountInvitationsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountMembersStmt != nil {
+		if cerr := q.listAccountMembersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountMembersStmt: %w", cerr)
+		}
+	}
+	if q.listAccountPendingTransfersStmt != nil {
+		if cerr := q.listAccountPendingTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountPendingTransfersStmt: %w", cerr)
+		}
+	}
+	if q.listAccountProductsStmt != nil {
+		if cerr := q.listAccountProductsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountProductsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountsStmt != nil {
+		if cerr := q.listAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsStmt: %w", cerr)
+		}
+	}
+	if q.listAccountsWithoutNumberStmt != nil {
+		if cerr := q.listAccountsWithoutNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAccountsWithoutNumberStmt: %w", cerr)
+		}
+	}
+	if q.listApiKeysStmt != nil {
+		if cerr := q.listApiKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listApiKeysStmt: %w", cerr)
+		}
+	}
+	if q.listApplicableTransferLimitsStmt != nil {
+		if cerr := q.listApplicableTransferLimitsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listApplicableTransferLimitsStmt: %w", cerr)
+		}
+	}
+	if q.listApprovableTransfersStmt != nil {
+		if cerr := q.listApprovableTransfersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listApprovableTransfersStmt: %w", cerr)
+		}
+	}
+	if q.listApprovalPoliciesStmt != nil {
+		if cerr := q.listApprovalPoliciesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listApprovalPoliciesStmt: %w", cerr)
+		}
+	}
+	if q.listAuditEventsStmt != nil {
+		if cerr := q.listAuditEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditEventsStmt: %w", cerr)
+		}
+	}
+	if q.listAuditEventsAfterStmt != nil {
+		if cerr := q.listAuditEventsAfterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAuditEventsAfterStmt: %w", cerr)
+		}
+	}
+	if q.listBeneficiariesStmt != nil {
+		if cerr := q.listBeneficiariesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBeneficiariesStmt: %w", cerr)
+		}
+	}
+	if q.listDueEscrowContractsStmt != nil {
+		if cerr := q.listDueEscrowContractsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDueEscrowContractsStmt: %w", cerr)
+		}
+	}
+	if q.listEntriesStmt != nil {
+		if cerr := q.listEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEntriesStmt: %w", cerr)
+		}
+	}
+	if q.listEscrowContractsStmt != nil {
+		if cerr := q.listEscrowContractsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEscrowContractsStmt: %w", cerr)
+		}
+	}
+	if q.listEscrowContractsByStatusStmt != nil {
+		if cerr := q.listEscrowContractsByStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEscrowContractsByStatusStmt: %w", cerr)
+		}
+	}
+	if q.listEscrowContractsFundedSinceStmt != nil {
+		if cerr := q.listEscrowContractsFundedSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEscrowContractsFundedSinceStmt: %w", cerr)
+		}
+	}
+	if q.listFraudCasesStmt != nil {
+		if cerr := q.listFraudCasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listFraudCasesStmt: %w", cerr)
+		}
+	}
+	if q.listIncomingPaymentRequestsStmt != nil {
+		if cerr := q.listIncomingPaymentRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listIncomingPaymentRequestsStmt: %w", cerr)
+		}
+	}
+	if q.listInterestAccrualsStmt != nil {
+		if cerr := q.listInterestAccrualsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInterestAccrualsStmt: %w", cerr)
+		}
+	}
+	if q.listInterestBearingAccountsStmt != nil {
+		if cerr := q.listInterestBearingAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInterestBearingAccountsStmt: %w", cerr)
+		}
+	}
+	if q.listInvoiceItemsStmt != nil {
+		if cerr := q.listInvoiceItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInvoiceItemsStmt: %w", cerr)
+		}
+	}
+	if q.listInvoicePaymentsStmt != nil {
+		if cerr := q.listInvoicePaymentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInvoicePaymentsStmt: %w", cerr)
+		}
+	}
+	if q.listInvoicesStmt != nil {
+		if cerr := q.listInvoicesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listInvoicesStmt: %w", cerr)
+		}
+	}
+	if q.listJournalPostingsStmt != nil {
+		if cerr := q.listJournalPostingsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listJournalPostingsStmt: %w", cerr)
+		}
+	}
+	if q.listKycDocumentsStmt != nil {
+		if cerr := q.listKycDocumentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listKycDocumentsStmt: %w", cerr)
+		}
+	}
+	if q.listKycProfilesByStatusStmt != nil {
+		if cerr := q.listKycProfilesByStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listKycProfilesByStatusStmt: %w", cerr)
+		}
+	}
+	if q.listKycTiersStmt != nil {
+		if cerr := q.listKycTiersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listKycTiersStmt: %w", cerr)
+		}
+	}
+	if q.listLedgerAccountsStmt != nil {
+		if cerr := q.listLedgerAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLedgerAccountsStmt: %w", cerr)
+		}
+	}
+	if q.listMemberSpendsSinceStmt != nil {
+		if cerr := q.listMemberSpendsSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMemberSpendsSinceStmt: %w", cerr)
+		}
+	}
+	if q.listOutgoingPaymentRequestsStmt != nil {
+		if cerr := q.listOutgoingPaymentRequestsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOutgoingPaymentRequestsStmt: %w", cerr)
+		}
+	}
+	if q.listOutgoingTransfersSinceStmt != nil {
+		if cerr := q.listOutgoingTransfersSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOutgoingTransfersSinceStmt: %w", cerr)
+		}
+	}
+	if q.listOutstandingInvoicesStmt != nil {
+		if cerr := q.listOutstandingInvoicesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOutstandingInvoicesStmt: %w", cerr)
+		}
+	}
+	if q.listOwnerLimitAccountsStmt != nil {
+		if cerr := q.listOwnerLimitAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listOwnerLimitAccountsStmt: %w", cerr)
+		}
+	}
+	if q.listPotsStmt != nil {
+		if cerr := q.listPotsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPotsStmt: %w", cerr)
+		}
+	}
+	if q.listSubscribedWebhookEndpointsStmt != nil {
+		if cerr := q.listSubscribedWebhookEndpointsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSubscribedWebhookEndpointsStmt: %w", cerr)
+		}
+	}
+	if q.listTransferStmt != nil {
+		if cerr := q.listTransferStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTransferStmt: %w", cerr)
+		}
+	}
+	if q.listTransferApprovalsStmt != nil {
+		if cerr := q.listTransferApprovalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTransferApprovalsStmt: %w", cerr)
+		}
+	}
+	if q.listTransferEntryCountsStmt != nil {
+		if cerr := q.listTransferEntryCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTransferEntryCountsStmt: %w", cerr)
+		}
+	}
+	if q.listTransferLimitsStmt != nil {
+		if cerr := q.listTransferLimitsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTransferLimitsStmt: %w", cerr)
+		}
+	}
+	if q.listUnpostedInterestAccountsStmt != nil {
+		if cerr := q.listUnpostedInterestAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnpostedInterestAccountsStmt: %w", cerr)
+		}
+	}
+	if q.listUnusedRecoveryCodesStmt != nil {
+		if cerr := q.listUnusedRecoveryCodesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUnusedRecoveryCodesStmt: %w", cerr)
+		}
+	}
+	if q.listUserAliasesStmt != nil {
+		if cerr := q.listUserAliasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUserAliasesStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookAttemptsStmt != nil {
+		if cerr := q.listWebhookAttemptsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookAttemptsStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookDeliveriesStmt != nil {
+		if cerr := q.listWebhookDeliveriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookDeliveriesStmt: %w", cerr)
+		}
+	}
+	if q.listWebhookEndpointsStmt != nil {
+		if cerr := q.listWebhookEndpointsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listWebhookEndpointsStmt: %w", cerr)
+		}
+	}
+	if q.lockAccountsStmt != nil {
+		if cerr := q.lockAccountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockAccountsStmt: %w", cerr)
+		}
+	}
+	if q.lockAuditChainStmt != nil {
+		if cerr := q.lockAuditChainStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockAuditChainStmt: %w", cerr)
+		}
+	}
+	if q.lockUserStmt != nil {
+		if cerr := q.lockUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockUserStmt: %w", cerr)
+		}
+	}
+	if q.markInvoicesOverdueStmt != nil {
+		if cerr := q.markInvoicesOverdueStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markInvoicesOverdueStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxEventDispatchedStmt != nil {
+		if cerr := q.markOutboxEventDispatchedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxEventDispatchedStmt: %w", cerr)
+		}
+	}
+	if q.markRecoveryCodeUsedStmt != nil {
+		if cerr := q.markRecoveryCodeUsedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markRecoveryCodeUsedStmt: %w", cerr)
+		}
+	}
+	if q.nextAccountSequenceStmt != nil {
+		if cerr := q.nextAccountSequenceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing nextAccountSequenceStmt: %w", cerr)
+		}
+	}
+	if q.notifyAccountEventStmt != nil {
+		if cerr := q.notifyAccountEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing notifyAccountEventStmt: %w", cerr)
+		}
+	}
+	if q.recordInvoicePaymentStmt != nil {
+		if cerr := q.recordInvoicePaymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recordInvoicePaymentStmt: %w", cerr)
+		}
+	}
+	if q.recordTOTPFailureStmt != nil {
+		if cerr := q.recordTOTPFailureStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing recordTOTPFailureStmt: %w", cerr)
+		}
+	}
+	if q.redeliverWebhookStmt != nil {
+		if cerr := q.redeliverWebhookStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing redeliverWebhookStmt: %w", cerr)
+		}
+	}
+	if q.renameBeneficiaryStmt != nil {
+		if cerr := q.renameBeneficiaryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing renameBeneficiaryStmt: %w", cerr)
+		}
+	}
+	if q.reviewKycProfileStmt != nil {
+		if cerr := q.reviewKycProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing reviewKycProfileStmt: %w", cerr)
+		}
+	}
+	if q.revokeAccessTokenStmt != nil {
+		if cerr := q.revokeAccessTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAccessTokenStmt: %w", cerr)
+		}
+	}
+	if q.revokeApiKeyStmt != nil {
+		if cerr := q.revokeApiKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeApiKeyStmt: %w", cerr)
+		}
+	}
+	if q.revokeRefreshTokenStmt != nil {
+		if cerr := q.revokeRefreshTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeRefreshTokenStmt: %w", cerr)
+		}
+	}
+	if q.sendInvoiceStmt != nil {
+		if cerr := q.sendInvoiceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sendInvoiceStmt: %w", cerr)
+		}
+	}
+	if q.setAccountNumberStmt != nil {
+		if cerr := q.setAccountNumberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAccountNumberStmt: %w", cerr)
+		}
+	}
+	if q.setAccountStatusStmt != nil {
+		if cerr := q.setAccountStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setAccountStatusStmt: %w", cerr)
+		}
+	}
+	if q.setEscrowFundJournalStmt != nil {
+		if cerr := q.setEscrowFundJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setEscrowFundJournalStmt: %w", cerr)
+		}
+	}
+	if q.setInterestPostingJournalStmt != nil {
+		if cerr := q.setInterestPostingJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setInterestPostingJournalStmt: %w", cerr)
+		}
+	}
+	if q.setUserTierStmt != nil {
+		if cerr := q.setUserTierStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setUserTierStmt: %w", cerr)
+		}
+	}
+	if q.submitKycProfileStmt != nil {
+		if cerr := q.submitKycProfileStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing submitKycProfileStmt: %w", cerr)
+		}
+	}
+	if q.sumEntriesBetweenStmt != nil {
+		if cerr := q.sumEntriesBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumEntriesBetweenStmt: %w", cerr)
+		}
+	}
+	if q.sumInterestAccrualsStmt != nil {
+		if cerr := q.sumInterestAccrualsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumInterestAccrualsStmt: %w", cerr)
+		}
+	}
+	if q.sumPostingsByCurrencyStmt != nil {
+		if cerr := q.sumPostingsByCurrencyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing sumPostingsByCurrencyStmt: %w", cerr)
+		}
+	}
+	if q.touchApiKeyStmt != nil {
+		if cerr := q.touchApiKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchApiKeyStmt: %w", cerr)
+		}
+	}
+	if q.updateAccountStmt != nil {
+		if cerr := q.updateAccountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAccountStmt: %w", cerr)
+		}
+	}
+	if q.updatePotStmt != nil {
+		if cerr := q.updatePotStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updatePotStmt: %w", cerr)
+		}
+	}
+	if q.updateUserTOTPCounterStmt != nil {
+		if cerr := q.updateUserTOTPCounterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserTOTPCounterStmt: %w", cerr)
+		}
+	}
+	if q.updateUserTOTPSecretStmt != nil {
+		if cerr := q.updateUserTOTPSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserTOTPSecretStmt: %w", cerr)
+		}
+	}
+	if q.updateWebhookDeliveryStmt != nil {
+		if cerr := q.updateWebhookDeliveryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWebhookDeliveryStmt: %w", cerr)
+		}
+	}
+	if q.upsertApprovalPolicyStmt != nil {
+		if cerr := q.upsertApprovalPolicyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertApprovalPolicyStmt: %w", cerr)
+		}
+	}
+	if q.upsertTransferLimitStmt != nil {
+		if cerr := q.upsertTransferLimitStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertTransferLimitStmt: %w", cerr)
+		}
+	}
+	if q.useAuthorizationCodeStmt != nil {
+		if cerr := q.useAuthorizationCodeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useAuthorizationCodeStmt: %w", cerr)
+		}
+	}
+	if q.verifyAliasStmt != nil {
+		if cerr := q.verifyAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing verifyAliasStmt: %w", cerr)
+		}
+	}
+	if q.voidInvoiceStmt != nil {
+		if cerr := q.voidInvoiceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing voidInvoiceStmt: %w", cerr)
 		}
 	}
 	return err
@@ -186,43 +1602,397 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                      DBTX
-	tx                      *sql.Tx
-	addAccountBalanceStmt   *sql.Stmt
-	createAccountStmt       *sql.Stmt
-	createEntryStmt         *sql.Stmt
-	createTransferStmt      *sql.Stmt
-	createUserStmt          *sql.Stmt
-	deleteAccountStmt       *sql.Stmt
-	getAccountStmt          *sql.Stmt
-	getAccountForUpdateStmt *sql.Stmt
-	getEntryStmt            *sql.Stmt
-	getTransferStmt         *sql.Stmt
-	getUserStmt             *sql.Stmt
-	listAccountsStmt        *sql.Stmt
-	listEntriesStmt         *sql.Stmt
-	listTransferStmt        *sql.Stmt
-	updateAccountStmt       *sql.Stmt
+	db                                 DBTX
+	tx                                 *sql.Tx
+	acceptAccountMemberStmt            *sql.Stmt
+	addAccountBalanceStmt              *sql.Stmt
+	addLedgerAccountBalanceStmt        *sql.Stmt
+	claimAliasStmt                     *sql.Stmt
+	claimDueWebhookDeliveriesStmt      *sql.Stmt
+	claimOutboxEventsStmt              *sql.Stmt
+	closeEscrowContractStmt            *sql.Stmt
+	closeFraudCaseStmt                 *sql.Stmt
+	closePaymentRequestStmt            *sql.Stmt
+	closePendingTransferStmt           *sql.Stmt
+	countBeneficiariesAddedSinceStmt   *sql.Stmt
+	countOwnerProductAccountsStmt      *sql.Stmt
+	countTransfersBetweenStmt          *sql.Stmt
+	createAccountStmt                  *sql.Stmt
+	createAccountMemberStmt            *sql.Stmt
+	createApiKeyStmt                   *sql.Stmt
+	createAuditEventStmt               *sql.Stmt
+	createAuthorizationCodeStmt        *sql.Stmt
+	createBalanceSnapshotsStmt         *sql.Stmt
+	createBeneficiaryStmt              *sql.Stmt
+	createEntryStmt                    *sql.Stmt
+	createEscrowContractStmt           *sql.Stmt
+	createFraudCaseStmt                *sql.Stmt
+	createInterestAccrualStmt          *sql.Stmt
+	createInterestPostingStmt          *sql.Stmt
+	createInvoiceStmt                  *sql.Stmt
+	createInvoiceItemStmt              *sql.Stmt
+	createInvoicePaymentStmt           *sql.Stmt
+	createJournalStmt                  *sql.Stmt
+	createKycDocumentStmt              *sql.Stmt
+	createOAuthClientStmt              *sql.Stmt
+	createOutboxEventStmt              *sql.Stmt
+	createPaymentRequestStmt           *sql.Stmt
+	createPendingTransferStmt          *sql.Stmt
+	createPostingStmt                  *sql.Stmt
+	createPotStmt                      *sql.Stmt
+	createPotAccountStmt               *sql.Stmt
+	createRecoveryCodeStmt             *sql.Stmt
+	createRefreshTokenStmt             *sql.Stmt
+	createTransferStmt                 *sql.Stmt
+	createTransferApprovalStmt         *sql.Stmt
+	createUserStmt                     *sql.Stmt
+	createWebhookAttemptStmt           *sql.Stmt
+	createWebhookDeliveryStmt          *sql.Stmt
+	createWebhookEndpointStmt          *sql.Stmt
+	deactivateWebhookEndpointStmt      *sql.Stmt
+	deleteAccountStmt                  *sql.Stmt
+	deleteAccountMemberStmt            *sql.Stmt
+	deleteAliasStmt                    *sql.Stmt
+	deleteApprovalPolicyStmt           *sql.Stmt
+	deleteBeneficiaryStmt              *sql.Stmt
+	deleteRecoveryCodesStmt            *sql.Stmt
+	deleteTransferLimitStmt            *sql.Stmt
+	disableUserTOTPStmt                *sql.Stmt
+	disputeEscrowContractStmt          *sql.Stmt
+	enableUserTOTPStmt                 *sql.Stmt
+	expirePaymentRequestsStmt          *sql.Stmt
+	expirePendingTransfersStmt         *sql.Stmt
+	getAccountStmt                     *sql.Stmt
+	getAccountByNumberStmt             *sql.Stmt
+	getAccountForUpdateStmt            *sql.Stmt
+	getAccountMemberStmt               *sql.Stmt
+	getAccountOwnerTierStmt            *sql.Stmt
+	getAccountProductStmt              *sql.Stmt
+	getAliasStmt                       *sql.Stmt
+	getApiKeyByPrefixStmt              *sql.Stmt
+	getApplicableApprovalPolicyStmt    *sql.Stmt
+	getApprovalPolicyStmt              *sql.Stmt
+	getBeneficiaryStmt                 *sql.Stmt
+	getBeneficiaryByAccountStmt        *sql.Stmt
+	getEntryStmt                       *sql.Stmt
+	getEscrowContractStmt              *sql.Stmt
+	getEscrowContractForUpdateStmt     *sql.Stmt
+	getFraudCaseStmt                   *sql.Stmt
+	getFraudCaseForUpdateStmt          *sql.Stmt
+	getInterestPostingStmt             *sql.Stmt
+	getInvoiceStmt                     *sql.Stmt
+	getInvoiceByPayTokenStmt           *sql.Stmt
+	getInvoiceForUpdateStmt            *sql.Stmt
+	getJournalStmt                     *sql.Stmt
+	getJournalByTransferStmt           *sql.Stmt
+	getKycProfileStmt                  *sql.Stmt
+	getKycTierStmt                     *sql.Stmt
+	getKycTierForUserStmt              *sql.Stmt
+	getLastAuditEventStmt              *sql.Stmt
+	getLastInterestAccrualDateStmt     *sql.Stmt
+	getLatestBalanceSnapshotStmt       *sql.Stmt
+	getLedgerAccountByCodeStmt         *sql.Stmt
+	getOAuthClientStmt                 *sql.Stmt
+	getOutboxEventStmt                 *sql.Stmt
+	getOutgoingTransferTotalsStmt      *sql.Stmt
+	getPaymentRequestStmt              *sql.Stmt
+	getPaymentRequestForUpdateStmt     *sql.Stmt
+	getPendingTransferStmt             *sql.Stmt
+	getPendingTransferForUpdateStmt    *sql.Stmt
+	getPotStmt                         *sql.Stmt
+	getPrimaryAccountStmt              *sql.Stmt
+	getRefreshTokenStmt                *sql.Stmt
+	getRevokedAccessTokenStmt          *sql.Stmt
+	getRoundUpPotStmt                  *sql.Stmt
+	getTransferStmt                    *sql.Stmt
+	getTransferLimitStmt               *sql.Stmt
+	getUserStmt                        *sql.Stmt
+	getWebhookDeliveryStmt             *sql.Stmt
+	getWebhookEndpointStmt             *sql.Stmt
+	incrementAliasAttemptsStmt         *sql.Stmt
+	listAccountEntryTotalsStmt         *sql.Stmt
+	listAccountIDsStmt                 *sql.Stmt
+	listAccountInvitationsStmt         *sql.Stmt
+	listAccountMembersStmt             *sql.Stmt
+	listAccountPendingTransfersStmt    *sql.Stmt
+	listAccountProductsStmt            *sql.Stmt
+	listAccountsStmt                   *sql.Stmt
+	listAccountsWithoutNumberStmt      *sql.Stmt
+	listApiKeysStmt                    *sql.Stmt
+	listApplicableTransferLimitsStmt   *sql.Stmt
+	listApprovableTransfersStmt        *sql.Stmt
+	listApprovalPoliciesStmt           *sql.Stmt
+	listAuditEventsStmt                *sql.Stmt
+	listAuditEventsAfterStmt           *sql.Stmt
+	listBeneficiariesStmt              *sql.Stmt
+	listDueEscrowContractsStmt         *sql.Stmt
+	listEntriesStmt                    *sql.Stmt
+	listEscrowContractsStmt            *sql.Stmt
+	listEscrowContractsByStatusStmt    *sql.Stmt
+	listEscrowContractsFundedSinceStmt *sql.Stmt
+	listFraudCasesStmt                 *sql.Stmt
+	listIncomingPaymentRequestsStmt    *sql.Stmt
+	listInterestAccrualsStmt           *sql.Stmt
+	listInterestBearingAccountsStmt    *sql.Stmt
+	listInvoiceItemsStmt               *sql.Stmt
+	listInvoicePaymentsStmt            *sql.Stmt
+	listInvoicesStmt                   *sql.Stmt
+	listJournalPostingsStmt            *sql.Stmt
+	listKycDocumentsStmt               *sql.Stmt
+	listKycProfilesByStatusStmt        *sql.Stmt
+	listKycTiersStmt                   *sql.Stmt
+	listLedgerAccountsStmt             *sql.Stmt
+	listMemberSpendsSinceStmt          *sql.Stmt
+	listOutgoingPaymentRequestsStmt    *sql.Stmt
+	listOutgoingTransfersSinceStmt     *sql.Stmt
+	listOutstandingInvoicesStmt        *sql.Stmt
+	listOwnerLimitAccountsStmt         *sql.Stmt
+	listPotsStmt                       *sql.Stmt
+	listSubscribedWebhookEndpointsStmt *sql.Stmt
+	listTransferStmt                   *sql.Stmt
+	listTransferApprovalsStmt          *sql.Stmt
+	listTransferEntryCountsStmt        *sql.Stmt
+	listTransferLimitsStmt             *sql.Stmt
+	listUnpostedInterestAccountsStmt   *sql.Stmt
+	listUnusedRecoveryCodesStmt        *sql.Stmt
+	listUserAliasesStmt                *sql.Stmt
+	listWebhookAttemptsStmt            *sql.Stmt
+	listWebhookDeliveriesStmt          *sql.Stmt
+	listWebhookEndpointsStmt           *sql.Stmt
+	lockAccountsStmt                   *sql.Stmt
+	lockAuditChainStmt                 *sql.Stmt
+	lockUserStmt                       *sql.Stmt
+	markInvoicesOverdueStmt            *sql.Stmt
+	markOutboxEventDispatchedStmt      *sql.Stmt
+	markRecoveryCodeUsedStmt           *sql.Stmt
+	nextAccountSequenceStmt            *sql.Stmt
+	notifyAccountEventStmt             *sql.Stmt
+	recordInvoicePaymentStmt           *sql.Stmt
+	recordTOTPFailureStmt              *sql.Stmt
+	redeliverWebhookStmt               *sql.Stmt
+	renameBeneficiaryStmt              *sql.Stmt
+	reviewKycProfileStmt               *sql.Stmt
+	revokeAccessTokenStmt              *sql.Stmt
+	revokeApiKeyStmt                   *sql.Stmt
+	revokeRefreshTokenStmt             *sql.Stmt
+	sendInvoiceStmt                    *sql.Stmt
+	setAccountNumberStmt               *sql.Stmt
+	setAccountStatusStmt               *sql.Stmt
+	setEscrowFundJournalStmt           *sql.Stmt
+	setInterestPostingJournalStmt      *sql.Stmt
+	setUserTierStmt                    *sql.Stmt
+	submitKycProfileStmt               *sql.Stmt
+	sumEntriesBetweenStmt              *sql.Stmt
+	sumInterestAccrualsStmt            *sql.Stmt
+	sumPostingsByCurrencyStmt          *sql.Stmt
+	touchApiKeyStmt                    *sql.Stmt
+	updateAccountStmt                  *sql.Stmt
+	updatePotStmt                      *sql.Stmt
+	updateUserTOTPCounterStmt          *sql.Stmt
+	updateUserTOTPSecretStmt           *sql.Stmt
+	updateWebhookDeliveryStmt          *sql.Stmt
+	upsertApprovalPolicyStmt           *sql.Stmt
+	upsertTransferLimitStmt            *sql.Stmt
+	useAuthorizationCodeStmt           *sql.Stmt
+	verifyAliasStmt                    *sql.Stmt
+	voidInvoiceStmt                    *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                      tx,
-		tx:                      tx,
-		addAccountBalanceStmt:   q.addAccountBalanceStmt,
-		createAccountStmt:       q.createAccountStmt,
-		createEntryStmt:         q.createEntryStmt,
-		createTransferStmt:      q.createTransferStmt,
-		createUserStmt:          q.createUserStmt,
-		deleteAccountStmt:       q.deleteAccountStmt,
-		getAccountStmt:          q.getAccountStmt,
-		getAccountForUpdateStmt: q.getAccountForUpdateStmt,
-		getEntryStmt:            q.getEntryStmt,
-		getTransferStmt:         q.getTransferStmt,
-		getUserStmt:             q.getUserStmt,
-		listAccountsStmt:        q.listAccountsStmt,
-		listEntriesStmt:         q.listEntriesStmt,
-		listTransferStmt:        q.listTransferStmt,
-		updateAccountStmt:       q.updateAccountStmt,
+		db:                                 tx,
+		tx:                                 tx,
+		acceptAccountMemberStmt:            q.acceptAccountMemberStmt,
+		addAccountBalanceStmt:              q.addAccountBalanceStmt,
+		addLedgerAccountBalanceStmt:        q.addLedgerAccountBalanceStmt,
+		claimAliasStmt:                     q.claimAliasStmt,
+		claimDueWebhookDeliveriesStmt:      q.claimDueWebhookDeliveriesStmt,
+		claimOutboxEventsStmt:              q.claimOutboxEventsStmt,
+		closeEscrowContractStmt:            q.closeEscrowContractStmt,
+		closeFraudCaseStmt:                 q.closeFraudCaseStmt,
+		closePaymentRequestStmt:            q.closePaymentRequestStmt,
+		closePendingTransferStmt:           q.closePendingTransferStmt,
+		countBeneficiariesAddedSinceStmt:   q.countBeneficiariesAddedSinceStmt,
+		countOwnerProductAccountsStmt:      q.countOwnerProductAccountsStmt,
+		countTransfersBetweenStmt:          q.countTransfersBetweenStmt,
+		createAccountStmt:                  q.createAccountStmt,
+		createAccountMemberStmt:            q.createAccountMemberStmt,
+		createApiKeyStmt:                   q.createApiKeyStmt,
+		createAuditEventStmt:               q.createAuditEventStmt,
+		createAuthorizationCodeStmt:        q.createAuthorizationCodeStmt,
+		createBalanceSnapshotsStmt:         q.createBalanceSnapshotsStmt,
+		createBeneficiaryStmt:              q.createBeneficiaryStmt,
+		createEntryStmt:                    q.createEntryStmt,
+		createEscrowContractStmt:           q.createEscrowContractStmt,
+		createFraudCaseStmt:                q.createFraudCaseStmt,
+		createInterestAccrualStmt:          q.createInterestAccrualStmt,
+		createInterestPostingStmt:          q.createInterestPostingStmt,
+		createInvoiceStmt:                  q.createInvoiceStmt,
+		createInvoiceItemStmt:              q.createInvoiceItemStmt,
+		createInvoicePaymentStmt:           q.createInvoicePaymentStmt,
+		createJournalStmt:                  q.createJournalStmt,
+		createKycDocumentStmt:              q.createKycDocumentStmt,
+		createOAuthClientStmt:              q.createOAuthClientStmt,
+		createOutboxEventStmt:              q.createOutboxEventStmt,
+		createPaymentRequestStmt:           q.createPaymentRequestStmt,
+		createPendingTransferStmt:          q.createPendingTransferStmt,
+		createPostingStmt:                  q.createPostingStmt,
+		createPotStmt:                      q.createPotStmt,
+		createPotAccountStmt:               q.createPotAccountStmt,
+		createRecoveryCodeStmt:             q.createRecoveryCodeStmt,
+		createRefreshTokenStmt:             q.createRefreshTokenStmt,
+		createTransferStmt:                 q.createTransferStmt,
+		createTransferApprovalStmt:         q.createTransferApprovalStmt,
+		createUserStmt:                     q.createUserStmt,
+		createWebhookAttemptStmt:           q.createWebhookAttemptStmt,
+		createWebhookDeliveryStmt:          q.createWebhookDeliveryStmt,
+		createWebhookEndpointStmt:          q.createWebhookEndpointStmt,
+		deactivateWebhookEndpointStmt:      q.deactivateWebhookEndpointStmt,
+		deleteAccountStmt:                  q.deleteAccountStmt,
+		deleteAccountMemberStmt:            q.deleteAccountMemberStmt,
+		deleteAliasStmt:                    q.deleteAliasStmt,
+		deleteApprovalPolicyStmt:           q.deleteApprovalPolicyStmt,
+		deleteBeneficiaryStmt:              q.deleteBeneficiaryStmt,
+		deleteRecoveryCodesStmt:            q.deleteRecoveryCodesStmt,
+		deleteTransferLimitStmt:            q.deleteTransferLimitStmt,
+		disableUserTOTPStmt:                q.disableUserTOTPStmt,
+		disputeEscrowContractStmt:          q.disputeEscrowContractStmt,
+		enableUserTOTPStmt:                 q.enableUserTOTPStmt,
+		expirePaymentRequestsStmt:          q.expirePaymentRequestsStmt,
+		expirePendingTransfersStmt:         q.expirePendingTransfersStmt,
+		getAccountStmt:                     q.getAccountStmt,
+		getAccountByNumberStmt:             q.getAccountByNumberStmt,
+		getAccountForUpdateStmt:            q.getAccountForUpdateStmt,
+		getAccountMemberStmt:               q.getAccountMemberStmt,
+		getAccountOwnerTierStmt:            q.getAccountOwnerTierStmt,
+		getAccountProductStmt:              q.getAccountProductStmt,
+		getAliasStmt:                       q.getAliasStmt,
+		getApiKeyByPrefixStmt:              q.getApiKeyByPrefixStmt,
+		getApplicableApprovalPolicyStmt:    q.getApplicableApprovalPolicyStmt,
+		getApprovalPolicyStmt:              q.getApprovalPolicyStmt,
+		getBeneficiaryStmt:                 q.getBeneficiaryStmt,
+		getBeneficiaryByAccountStmt:        q.getBeneficiaryByAccountStmt,
+		getEntryStmt:                       q.getEntryStmt,
+		getEscrowContractStmt:              q.getEscrowContractStmt,
+		getEscrowContractForUpdateStmt:     q.getEscrowContractForUpdateStmt,
+		getFraudCaseStmt:                   q.getFraudCaseStmt,
+		getFraudCaseForUpdateStmt:          q.getFraudCaseForUpdateStmt,
+		getInterestPostingStmt:             q.getInterestPostingStmt,
+		getInvoiceStmt:                     q.getInvoiceStmt,
+		getInvoiceByPayTokenStmt:           q.getInvoiceByPayTokenStmt,
+		getInvoiceForUpdateStmt:            q.getInvoiceForUpdateStmt,
+		getJournalStmt:                     q.getJournalStmt,
+		getJournalByTransferStmt:           q.getJournalByTransferStmt,
+		getKycProfileStmt:                  q.getKycProfileStmt,
+		getKycTierStmt:                     q.getKycTierStmt,
+		getKycTierForUserStmt:              q.getKycTierForUserStmt,
+		getLastAuditEventStmt:              q.getLastAuditEventStmt,
+		getLastInterestAccrualDateStmt:     q.getLastInterestAccrualDateStmt,
+		getLatestBalanceSnapshotStmt:       q.getLatestBalanceSnapshotStmt,
+		getLedgerAccountByCodeStmt:         q.getLedgerAccountByCodeStmt,
+		getOAuthClientStmt:                 q.getOAuthClientStmt,
+		getOutboxEventStmt:                 q.getOutboxEventStmt,
+		getOutgoingTransferTotalsStmt:      q.getOutgoingTransferTotalsStmt,
+		getPaymentRequestStmt:              q.getPaymentRequestStmt,
+		getPaymentRequestForUpdateStmt:     q.getPaymentRequestForUpdateStmt,
+		getPendingTransferStmt:             q.getPendingTransferStmt,
+		getPendingTransferForUpdateStmt:    q.getPendingTransferForUpdateStmt,
+		getPotStmt:                         q.getPotStmt,
+		getPrimaryAccountStmt:              q.getPrimaryAccountStmt,
+		getRefreshTokenStmt:                q.getRefreshTokenStmt,
+		getRevokedAccessTokenStmt:          q.getRevokedAccessTokenStmt,
+		getRoundUpPotStmt:                  q.getRoundUpPotStmt,
+		getTransferStmt:                    q.getTransferStmt,
+		getTransferLimitStmt:               q.getTransferLimitStmt,
+		getUserStmt:                        q.getUserStmt,
+		getWebhookDeliveryStmt:             q.getWebhookDeliveryStmt,
+		getWebhookEndpointStmt:             q.getWebhookEndpointStmt,
+		incrementAliasAttemptsStmt:         q.incrementAliasAttemptsStmt,
+		listAccountEntryTotalsStmt:         q.listAccountEntryTotalsStmt,
+		listAccountIDsStmt:                 q.listAccountIDsStmt,
+		listAccountInvitationsStmt:         q.listAccountInvitationsStmt,
+		listAccountMembersStmt:             q.listAccountMembersStmt,
+		listAccountPendingTransfersStmt:    q.listAccountPendingTransfersStmt,
+		listAccountProductsStmt:            q.listAccountProductsStmt,
+		listAccountsStmt:                   q.listAccountsStmt,
+		listAccountsWithoutNumberStmt:      q.listAccountsWithoutNumberStmt,
+		listApiKeysStmt:                    q.listApiKeysStmt,
+		listApplicableTransferLimitsStmt:   q.listApplicableTransferLimitsStmt,
+		listApprovableTransfersStmt:        q.listApprovableTransfersStmt,
+		listApprovalPoliciesStmt:           q.listApprovalPoliciesStmt,
+		listAuditEventsStmt:                q.listAuditEventsStmt,
+		listAuditEventsAfterStmt:           q.listAuditEventsAfterStmt,
+		listBeneficiariesStmt:              q.listBeneficiariesStmt,
+		listDueEscrowContractsStmt:         q.listDueEscrowContractsStmt,
+		listEntriesStmt:                    q.listEntriesStmt,
+		listEscrowContractsStmt:            q.listEscrowContractsStmt,
+		listEscrowContractsByStatusStmt:    q.listEscrowContractsByStatusStmt,
+		listEscrowContractsFundedSinceStmt: q.listEscrowContractsFundedSinceStmt,
+		listFraudCasesStmt:                 q.listFraudCasesStmt,
+		listIncomingPaymentRequestsStmt:    q.listIncomingPaymentRequestsStmt,
+		listInterestAccrualsStmt:           q.listInterestAccrualsStmt,
+		listInterestBearingAccountsStmt:    q.listInterestBearingAccountsStmt,
+		listInvoiceItemsStmt:               q.listInvoiceItemsStmt,
+		listInvoicePaymentsStmt:            q.listInvoicePaymentsStmt,
+		listInvoicesStmt:                   q.listInvoicesStmt,
+		listJournalPostingsStmt:            q.listJournalPostingsStmt,
+		listKycDocumentsStmt:               q.listKycDocumentsStmt,
+		listKycProfilesByStatusStmt:        q.listKycProfilesByStatusStmt,
+		listKycTiersStmt:                   q.listKycTiersStmt,
+		listLedgerAccountsStmt:             q.listLedgerAccountsStmt,
+		listMemberSpendsSinceStmt:          q.listMemberSpendsSinceStmt,
+		listOutgoingPaymentRequestsStmt:    q.listOutgoingPaymentRequestsStmt,
+		listOutgoingTransfersSinceStmt:     q.listOutgoingTransfersSinceStmt,
+		listOutstandingInvoicesStmt:        q.listOutstandingInvoicesStmt,
+		listOwnerLimitAccountsStmt:         q.listOwnerLimitAccountsStmt,
+		listPotsStmt:                       q.listPotsStmt,
+		listSubscribedWebhookEndpointsStmt: q.listSubscribedWebhookEndpointsStmt,
+		listTransferStmt:                   q.listTransferStmt,
+		listTransferApprovalsStmt:          q.listTransferApprovalsStmt,
+		listTransferEntryCountsStmt:        q.listTransferEntryCountsStmt,
+		listTransferLimitsStmt:             q.listTransferLimitsStmt,
+		listUnpostedInterestAccountsStmt:   q.listUnpostedInterestAccountsStmt,
+		listUnusedRecoveryCodesStmt:        q.listUnusedRecoveryCodesStmt,
+		listUserAliasesStmt:                q.listUserAliasesStmt,
+		listWebhookAttemptsStmt:            q.listWebhookAttemptsStmt,
+		listWebhookDeliveriesStmt:          q.listWebhookDeliveriesStmt,
+		listWebhookEndpointsStmt:           q.listWebhookEndpointsStmt,
+		lockAccountsStmt:                   q.lockAccountsStmt,
+		lockAuditChainStmt:                 q.lockAuditChainStmt,
+		lockUserStmt:                       q.lockUserStmt,
+		markInvoicesOverdueStmt:            q.markInvoicesOverdueStmt,
+		markOutboxEventDispatchedStmt:      q.markOutboxEventDispatchedStmt,
+		markRecoveryCodeUsedStmt:           q.markRecoveryCodeUsedStmt,
+		nextAccountSequenceStmt:            q.nextAccountSequenceStmt,
+		notifyAccountEventStmt:             q.notifyAccountEventStmt,
+		recordInvoicePaymentStmt:           q.recordInvoicePaymentStmt,
+		recordTOTPFailureStmt:              q.recordTOTPFailureStmt,
+		redeliverWebhookStmt:               q.redeliverWebhookStmt,
+		renameBeneficiaryStmt:              q.renameBeneficiaryStmt,
+		reviewKycProfileStmt:               q.reviewKycProfileStmt,
+		revokeAccessTokenStmt:              q.revokeAccessTokenStmt,
+		revokeApiKeyStmt:                   q.revokeApiKeyStmt,
+		revokeRefreshTokenStmt:             q.revokeRefreshTokenStmt,
+		sendInvoiceStmt:                    q.sendInvoiceStmt,
+		setAccountNumberStmt:               q.setAccountNumberStmt,
+		setAccountStatusStmt:               q.setAccountStatusStmt,
+		setEscrowFundJournalStmt:           q.setEscrowFundJournalStmt,
+		setInterestPostingJournalStmt:      q.setInterestPostingJournalStmt,
+		setUserTierStmt:                    q.setUserTierStmt,
+		submitKycProfileStmt:               q.submitKycProfileStmt,
+		sumEntriesBetweenStmt:              q.sumEntriesBetweenStmt,
+		sumInterestAccrualsStmt:            q.sumInterestAccrualsStmt,
+		sumPostingsByCurrencyStmt:          q.sumPostingsByCurrencyStmt,
+		touchApiKeyStmt:                    q.touchApiKeyStmt,
+		updateAccountStmt:                  q.updateAccountStmt,
+		updatePotStmt:                      q.updatePotStmt,
+		updateUserTOTPCounterStmt:          q.updateUserTOTPCounterStmt,
+		updateUserTOTPSecretStmt:           q.updateUserTOTPSecretStmt,
+		updateWebhookDeliveryStmt:          q.updateWebhookDeliveryStmt,
+		upsertApprovalPolicyStmt:           q.upsertApprovalPolicyStmt,
+		upsertTransferLimitStmt:            q.upsertTransferLimitStmt,
+		useAuthorizationCodeStmt:           q.useAuthorizationCodeStmt,
+		verifyAliasStmt:                    q.verifyAliasStmt,
+		voidInvoiceStmt:                    q.voidInvoiceStmt,
 	}
 }
@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
@@ -14,6 +16,124 @@ type Account struct {
 	Balance      int64     `json:"balance"`
 	CurrencyCode string    `json:"currency_code"`
 	CreatedAt    time.Time `json:"created_at"`
+	// active or blocked, money can't move in or out of blocked accounts
+	Status      string `json:"status"`
+	ProductCode string `json:"product_code"`
+	// set on the accounts that hold a pot's money
+	ParentAccountID sql.NullInt64  `json:"parent_account_id"`
+	AccountNumber   sql.NullString `json:"account_number"`
+}
+
+type AccountMember struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	// co_owner, spender or viewer, the account owner is accounts.owner
+	Role string `json:"role"`
+	// the most a spender can send from the account in a rolling 24 hours
+	SpendLimit sql.NullInt64 `json:"spend_limit"`
+	// invited until the user accepts, then active
+	Status     string       `json:"status"`
+	InvitedBy  string       `json:"invited_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	AcceptedAt sql.NullTime `json:"accepted_at"`
+}
+
+type AccountProduct struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// annual interest rate in basis points, 250 is 2.50%
+	InterestRateBps int64 `json:"interest_rate_bps"`
+	// day-count convention used to turn the annual rate into a daily one: ACT/365, ACT/360 or ACT/ACT
+	DayCount  string    `json:"day_count"`
+	CreatedAt time.Time `json:"created_at"`
+	// how far below zero a transfer may take the balance
+	OverdraftLimit int64 `json:"overdraft_limit"`
+	// how many accounts of the product, pots aside, a user may hold in each currency, no limit when NULL
+	MaxAccounts sql.NullInt32 `json:"max_accounts"`
+}
+
+type Alias struct {
+	// normalized email address or phone number, payments to it go to username
+	Alias string `json:"alias"`
+	// email or phone
+	Kind          string    `json:"kind"`
+	Username      string    `json:"username"`
+	CodeHash      string    `json:"code_hash"`
+	CodeExpiresAt time.Time `json:"code_expires_at"`
+	Attempts      int32     `json:"attempts"`
+	// aliases only resolve once verified, until then anyone can claim them again
+	VerifiedAt sql.NullTime `json:"verified_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Name  string `json:"name"`
+	// public part of the key used to look it up
+	Prefix string `json:"prefix"`
+	// sha256 of the full key, the key itself is never stored
+	HashedKey string   `json:"hashed_key"`
+	Scopes    []string `json:"scopes"`
+	// IPs or CIDR ranges the key may be used from, empty allows any
+	AllowedIps []string     `json:"allowed_ips"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ApprovalPolicy struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// transfers of at least this amount need approval, the policy with the highest threshold applies
+	MinAmount         int64 `json:"min_amount"`
+	RequiredApprovals int32 `json:"required_approvals"`
+	// users with this role may approve, along with the named approvers
+	ApproverRole  sql.NullString `json:"approver_role"`
+	Approvers     []string       `json:"approvers"`
+	ExpirySeconds int32          `json:"expiry_seconds"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type AuditEvent struct {
+	ID           int64  `json:"id"`
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	// json rather than jsonb so the stored text is byte for byte what was hashed
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	Ip        string          `json:"ip"`
+	RequestID string          `json:"request_id"`
+	PrevHash  string          `json:"prev_hash"`
+	// sha256 over prev_hash and the event, chaining every row to the one before it in its chain
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+	// which of the hash chains the event links into, picked from its resource so unrelated writes don't wait on each other
+	Chain int32 `json:"chain"`
+}
+
+type BalanceSnapshot struct {
+	AccountID int64 `json:"account_id"`
+	// midnight UTC ending the day, the snapshot covers entries created before it
+	AsOf time.Time `json:"as_of"`
+	// sum of the account's entries created before as_of
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Beneficiary struct {
+	ID int64 `json:"id"`
+	// the user who saved the payee
+	Owner     string         `json:"owner"`
+	AccountID int64          `json:"account_id"`
+	Nickname  sql.NullString `json:"nickname"`
+	// large transfers to the payee are refused until the cooling-off period after this has passed
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Entry struct {
@@ -24,6 +144,323 @@ type Entry struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type EscrowContract struct {
+	ID              int64  `json:"id"`
+	Buyer           string `json:"buyer"`
+	Seller          string `json:"seller"`
+	BuyerAccountID  int64  `json:"buyer_account_id"`
+	SellerAccountID int64  `json:"seller_account_id"`
+	Amount          int64  `json:"amount"`
+	CurrencyCode    string `json:"currency_code"`
+	Description     string `json:"description"`
+	// funded until the buyer confirms delivery or release_at passes, disputed until an admin releases or refunds it
+	Status string `json:"status"`
+	// when the funds go to the seller if the buyer neither confirms nor disputes
+	ReleaseAt time.Time `json:"release_at"`
+	// the journal that moved the buyer's money into the escrow ledger account
+	FundJournalID sql.NullInt64 `json:"fund_journal_id"`
+	// the journal that paid the seller or refunded the buyer
+	SettleJournalID sql.NullInt64  `json:"settle_journal_id"`
+	DisputedBy      sql.NullString `json:"disputed_by"`
+	DisputeReason   string         `json:"dispute_reason"`
+	DisputedAt      sql.NullTime   `json:"disputed_at"`
+	ResolvedBy      sql.NullString `json:"resolved_by"`
+	ResolutionNote  string         `json:"resolution_note"`
+	CreatedAt       time.Time      `json:"created_at"`
+	ClosedAt        sql.NullTime   `json:"closed_at"`
+}
+
+type FraudCase struct {
+	ID int64 `json:"id"`
+	// open while held for review, then approved or rejected, blocked transfers are recorded as blocked
+	Status        string `json:"status"`
+	Decision      string `json:"decision"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	InitiatedBy   string `json:"initiated_by"`
+	// the rules that held or blocked the transfer and why
+	Verdicts json.RawMessage `json:"verdicts"`
+	// the transfer made once the case was approved
+	TransferID sql.NullInt64  `json:"transfer_id"`
+	Reviewer   sql.NullString `json:"reviewer"`
+	ReviewNote string         `json:"review_note"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ReviewedAt sql.NullTime   `json:"reviewed_at"`
+	// the payment request the transfer pays, settled when the transfer is made
+	PaymentRequestID sql.NullInt64 `json:"payment_request_id"`
+	// the invoice the transfer pays, settled when the transfer is made
+	InvoiceID sql.NullInt64 `json:"invoice_id"`
+	// set when the transfer funds an escrow contract, which releases this long after it is funded
+	EscrowReleaseSeconds sql.NullInt64 `json:"escrow_release_seconds"`
+	EscrowDescription    string        `json:"escrow_description"`
+}
+
+type InterestAccrual struct {
+	AccountID   int64     `json:"account_id"`
+	AccrualDate time.Time `json:"accrual_date"`
+	// end-of-day balance interest was computed on
+	Balance  int64  `json:"balance"`
+	RateBps  int64  `json:"rate_bps"`
+	DayCount string `json:"day_count"`
+	// interest in millionths of the currency's minor unit, rounded half to even
+	AmountMicros int64     `json:"amount_micros"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type InterestPosting struct {
+	AccountID     int64     `json:"account_id"`
+	PeriodStart   time.Time `json:"period_start"`
+	PeriodEnd     time.Time `json:"period_end"`
+	AccruedMicros int64     `json:"accrued_micros"`
+	// accrued_micros rounded half to even to the minor unit and paid to the account
+	Amount    int64         `json:"amount"`
+	JournalID sql.NullInt64 `json:"journal_id"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type Invoice struct {
+	ID       int64  `json:"id"`
+	Merchant string `json:"merchant"`
+	// the merchant's account payments go to
+	ToAccountID  int64  `json:"to_account_id"`
+	Customer     string `json:"customer"`
+	CurrencyCode string `json:"currency_code"`
+	Subtotal     int64  `json:"subtotal"`
+	// tax charged on the subtotal in basis points, 750 is 7.5%
+	TaxRateBps int32 `json:"tax_rate_bps"`
+	TaxAmount  int64 `json:"tax_amount"`
+	Total      int64 `json:"total"`
+	AmountPaid int64 `json:"amount_paid"`
+	// draft, sent, paid, overdue or void. Partly paid invoices stay sent or overdue until paid in full
+	Status string `json:"status"`
+	// the secret in the invoice's pay link
+	PayToken  string       `json:"pay_token"`
+	Memo      string       `json:"memo"`
+	DueDate   time.Time    `json:"due_date"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	SentAt    sql.NullTime `json:"sent_at"`
+	PaidAt    sql.NullTime `json:"paid_at"`
+	VoidedAt  sql.NullTime `json:"voided_at"`
+}
+
+type InvoiceItem struct {
+	ID          int64  `json:"id"`
+	InvoiceID   int64  `json:"invoice_id"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitAmount  int64  `json:"unit_amount"`
+	Amount      int64  `json:"amount"`
+}
+
+type InvoicePayment struct {
+	ID         int64     `json:"id"`
+	InvoiceID  int64     `json:"invoice_id"`
+	TransferID int64     `json:"transfer_id"`
+	Amount     int64     `json:"amount"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Journal struct {
+	ID          int64         `json:"id"`
+	Description string        `json:"description"`
+	TransferID  sql.NullInt64 `json:"transfer_id"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+type KycDocument struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	DocumentType string `json:"document_type"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Sha256       string `json:"sha256"`
+	// where the file lives in document storage, only its metadata is kept here
+	StorageKey string    `json:"storage_key"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type KycProfile struct {
+	Username string `json:"username"`
+	// pending, approved or rejected, a submission moves it to pending and a review out of it
+	Status            string    `json:"status"`
+	RequestedTier     string    `json:"requested_tier"`
+	DateOfBirth       time.Time `json:"date_of_birth"`
+	PhoneNumber       string    `json:"phone_number"`
+	AddressLine1      string    `json:"address_line1"`
+	AddressLine2      string    `json:"address_line2"`
+	City              string    `json:"city"`
+	PostalCode        string    `json:"postal_code"`
+	CountryCode       string    `json:"country_code"`
+	Nationality       string    `json:"nationality"`
+	IDNumber          string    `json:"id_number"`
+	Provider          string    `json:"provider"`
+	ProviderReference string    `json:"provider_reference"`
+	// clear or consider, the identity check is advice for the reviewer and never decides on its own
+	ProviderOutcome string         `json:"provider_outcome"`
+	ProviderReasons []string       `json:"provider_reasons"`
+	Reviewer        sql.NullString `json:"reviewer"`
+	ReviewNote      string         `json:"review_note"`
+	SubmittedAt     time.Time      `json:"submitted_at"`
+	ReviewedAt      sql.NullTime   `json:"reviewed_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type KycTier struct {
+	Tier  string `json:"tier"`
+	Name  string `json:"name"`
+	Level int32  `json:"level"`
+	// account products users in this tier may open
+	AllowedProducts []string  `json:"allowed_products"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID           int64  `json:"id"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	CurrencyCode string `json:"currency_code"`
+	// credits are positive and debits negative, the same sign convention as customer account balances
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID         int64        `json:"id"`
+	Username   string       `json:"username"`
+	HashedCode string       `json:"hashed_code"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type OauthAuthorizationCode struct {
+	CodeHash    string   `json:"code_hash"`
+	ClientID    string   `json:"client_id"`
+	Username    string   `json:"username"`
+	RedirectUri string   `json:"redirect_uri"`
+	Scopes      []string `json:"scopes"`
+	AccountIds  []int64  `json:"account_ids"`
+	// S256 PKCE challenge
+	CodeChallenge string       `json:"code_challenge"`
+	ExpiresAt     time.Time    `json:"expires_at"`
+	UsedAt        sql.NullTime `json:"used_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type OauthClient struct {
+	ClientID string `json:"client_id"`
+	Owner    string `json:"owner"`
+	Name     string `json:"name"`
+	// empty for public clients which must rely on PKCE alone
+	HashedSecret string    `json:"hashed_secret"`
+	RedirectUris []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OauthRefreshToken struct {
+	TokenHash  string       `json:"token_hash"`
+	ClientID   string       `json:"client_id"`
+	Username   string       `json:"username"`
+	Scopes     []string     `json:"scopes"`
+	AccountIds []int64      `json:"account_ids"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type OauthRevokedAccessToken struct {
+	TokenID string `json:"token_id"`
+	// rows can be purged once the token would have expired anyway
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OutboxEvent struct {
+	ID        int64  `json:"id"`
+	EventType string `json:"event_type"`
+	// user whose webhook endpoints receive the event
+	Owner         string          `json:"owner"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	// set once a delivery row exists for every matching endpoint
+	DispatchedAt sql.NullTime `json:"dispatched_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID        int64  `json:"id"`
+	Requester string `json:"requester"`
+	Payer     string `json:"payer"`
+	// the requester's account the payment goes to
+	ToAccountID  int64  `json:"to_account_id"`
+	Amount       int64  `json:"amount"`
+	CurrencyCode string `json:"currency_code"`
+	Note         string `json:"note"`
+	// pending until paid, declined by the payer, cancelled by the requester or expired
+	Status string `json:"status"`
+	// the transfer that paid the request
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ClosedAt   sql.NullTime  `json:"closed_at"`
+}
+
+type PendingTransfer struct {
+	ID            int64  `json:"id"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	InitiatedBy   string `json:"initiated_by"`
+	// pending until the quorum executes it, a rejection or expiry
+	Status   string        `json:"status"`
+	PolicyID sql.NullInt64 `json:"policy_id"`
+	// copied from the policy, so changing a policy leaves pending transfers alone
+	RequiredApprovals int32          `json:"required_approvals"`
+	ApproverRole      sql.NullString `json:"approver_role"`
+	Approvers         []string       `json:"approvers"`
+	TransferID        sql.NullInt64  `json:"transfer_id"`
+	ExpiresAt         time.Time      `json:"expires_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	DecidedAt         sql.NullTime   `json:"decided_at"`
+	// the payment request the transfer pays, settled when the transfer is made
+	PaymentRequestID sql.NullInt64 `json:"payment_request_id"`
+	// the invoice the transfer pays, settled when the transfer is made
+	InvoiceID sql.NullInt64 `json:"invoice_id"`
+	// set when the transfer funds an escrow contract, which releases this long after it is funded
+	EscrowReleaseSeconds sql.NullInt64 `json:"escrow_release_seconds"`
+	EscrowDescription    string        `json:"escrow_description"`
+}
+
+type Posting struct {
+	ID        int64 `json:"id"`
+	JournalID int64 `json:"journal_id"`
+	// customer account posted to, mutually exclusive with ledger_account_id
+	AccountID       sql.NullInt64 `json:"account_id"`
+	LedgerAccountID sql.NullInt64 `json:"ledger_account_id"`
+	// statement line written for postings to customer accounts
+	EntryID      sql.NullInt64 `json:"entry_id"`
+	CurrencyCode string        `json:"currency_code"`
+	Amount       int64         `json:"amount"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type Pot struct {
+	AccountID       int64         `json:"account_id"`
+	ParentAccountID int64         `json:"parent_account_id"`
+	Name            string        `json:"name"`
+	TargetAmount    sql.NullInt64 `json:"target_amount"`
+	TargetDate      sql.NullTime  `json:"target_date"`
+	// outgoing transfers from the parent are rounded up to a multiple of this and the difference swept into the pot
+	RoundUpUnit sql.NullInt64 `json:"round_up_unit"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"from_account_id"`
@@ -31,6 +468,36 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// the user who sent the transfer, NULL for transfers the bank made itself
+	InitiatedBy sql.NullString `json:"initiated_by"`
+}
+
+type TransferApproval struct {
+	PendingTransferID int64     `json:"pending_transfer_id"`
+	Approver          string    `json:"approver"`
+	Decision          string    `json:"decision"`
+	Note              string    `json:"note"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// limits for a single account, they take precedence over tier and currency limits
+	AccountID sql.NullInt64 `json:"account_id"`
+	// limits for accounts of users in this tier, optionally narrowed to currency_code
+	Tier         sql.NullString `json:"tier"`
+	CurrencyCode sql.NullString `json:"currency_code"`
+	MaxAmount    sql.NullInt64  `json:"max_amount"`
+	// outgoing total over a rolling 24 hours, a null limit is not enforced
+	DailyAmount sql.NullInt64 `json:"daily_amount"`
+	// outgoing total over a rolling 30 days, a null limit is not enforced
+	MonthlyAmount sql.NullInt64 `json:"monthly_amount"`
+	DailyCount    sql.NullInt64 `json:"daily_count"`
+	MonthlyCount  sql.NullInt64 `json:"monthly_count"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	// limits for accounts of this product, they take precedence over tier and currency limits
+	ProductCode sql.NullString `json:"product_code"`
 }
 
 type User struct {
@@ -40,4 +507,48 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	TotpSecret        string    `json:"totp_secret"`
+	TotpEnabled       bool      `json:"totp_enabled"`
+	// last accepted TOTP time step, guards against code replay
+	TotpLastCounter int64  `json:"totp_last_counter"`
+	Role            string `json:"role"`
+	Tier            string `json:"tier"`
+	// invalid second factor codes since the last accepted one or lockout
+	TotpFailedAttempts int32 `json:"totp_failed_attempts"`
+	// second factor codes are refused until then after too many invalid ones
+	TotpLockedUntil sql.NullTime `json:"totp_locked_until"`
+}
+
+type WebhookAttempt struct {
+	ID             int64     `json:"id"`
+	DeliveryID     int64     `json:"delivery_id"`
+	ResponseStatus int32     `json:"response_status"`
+	Error          string    `json:"error"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EventID    int64 `json:"event_id"`
+	EndpointID int64 `json:"endpoint_id"`
+	// pending, succeeded or failed
+	Status        string       `json:"status"`
+	Attempts      int32        `json:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at"`
+	LastError     string       `json:"last_error"`
+	DeliveredAt   sql.NullTime `json:"delivered_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type WebhookEndpoint struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// HMAC-SHA256 signing key, kept in clear because it signs every delivery
+	Secret string `json:"secret"`
+	// empty subscribes to every event type
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
 }
@@ -6,24 +6,203 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AcceptAccountMember(ctx context.Context, arg AcceptAccountMemberParams) (AccountMember, error)
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddLedgerAccountBalance(ctx context.Context, arg AddLedgerAccountBalanceParams) (LedgerAccount, error)
+	ClaimAlias(ctx context.Context, arg ClaimAliasParams) (Alias, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg ClaimDueWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ClaimOutboxEvents(ctx context.Context, limit int32) ([]OutboxEvent, error)
+	CloseEscrowContract(ctx context.Context, arg CloseEscrowContractParams) (EscrowContract, error)
+	CloseFraudCase(ctx context.Context, arg CloseFraudCaseParams) (FraudCase, error)
+	ClosePaymentRequest(ctx context.Context, arg ClosePaymentRequestParams) (PaymentRequest, error)
+	ClosePendingTransfer(ctx context.Context, arg ClosePendingTransferParams) (PendingTransfer, error)
+	CountBeneficiariesAddedSince(ctx context.Context, arg CountBeneficiariesAddedSinceParams) (int64, error)
+	CountOwnerProductAccounts(ctx context.Context, arg CountOwnerProductAccountsParams) (int64, error)
+	CountTransfersBetween(ctx context.Context, arg CountTransfersBetweenParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	CreateApiKey(ctx context.Context, arg CreateApiKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateAuthorizationCode(ctx context.Context, arg CreateAuthorizationCodeParams) (OauthAuthorizationCode, error)
+	CreateBalanceSnapshots(ctx context.Context, arg CreateBalanceSnapshotsParams) error
+	CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateEscrowContract(ctx context.Context, arg CreateEscrowContractParams) (EscrowContract, error)
+	CreateFraudCase(ctx context.Context, arg CreateFraudCaseParams) (FraudCase, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) error
+	CreateInterestPosting(ctx context.Context, arg CreateInterestPostingParams) (InterestPosting, error)
+	CreateInvoice(ctx context.Context, arg CreateInvoiceParams) (Invoice, error)
+	CreateInvoiceItem(ctx context.Context, arg CreateInvoiceItemParams) (InvoiceItem, error)
+	CreateInvoicePayment(ctx context.Context, arg CreateInvoicePaymentParams) (InvoicePayment, error)
+	CreateJournal(ctx context.Context, arg CreateJournalParams) (Journal, error)
+	CreateKycDocument(ctx context.Context, arg CreateKycDocumentParams) (KycDocument, error)
+	CreateOAuthClient(ctx context.Context, arg CreateOAuthClientParams) (OauthClient, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (PendingTransfer, error)
+	CreatePosting(ctx context.Context, arg CreatePostingParams) (Posting, error)
+	CreatePot(ctx context.Context, arg CreatePotParams) (Pot, error)
+	CreatePotAccount(ctx context.Context, arg CreatePotAccountParams) (Account, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (MfaRecoveryCode, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (OauthRefreshToken, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferApproval(ctx context.Context, arg CreateTransferApprovalParams) (TransferApproval, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhookAttempt(ctx context.Context, arg CreateWebhookAttemptParams) (WebhookAttempt, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeactivateWebhookEndpoint(ctx context.Context, arg DeactivateWebhookEndpointParams) (WebhookEndpoint, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error
+	DeleteAlias(ctx context.Context, arg DeleteAliasParams) error
+	DeleteApprovalPolicy(ctx context.Context, id int64) error
+	DeleteBeneficiary(ctx context.Context, id int64) error
+	DeleteRecoveryCodes(ctx context.Context, username string) error
+	DeleteTransferLimit(ctx context.Context, id int64) error
+	DisableUserTOTP(ctx context.Context, username string) (User, error)
+	DisputeEscrowContract(ctx context.Context, arg DisputeEscrowContractParams) (EscrowContract, error)
+	EnableUserTOTP(ctx context.Context, username string) (User, error)
+	ExpirePaymentRequests(ctx context.Context, now time.Time) ([]PaymentRequest, error)
+	ExpirePendingTransfers(ctx context.Context, now time.Time) ([]PendingTransfer, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountByNumber(ctx context.Context, accountNumber sql.NullString) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	GetAccountOwnerTier(ctx context.Context, id int64) (string, error)
+	GetAccountProduct(ctx context.Context, code string) (AccountProduct, error)
+	GetAlias(ctx context.Context, alias string) (Alias, error)
+	GetApiKeyByPrefix(ctx context.Context, prefix string) (ApiKey, error)
+	GetApplicableApprovalPolicy(ctx context.Context, arg GetApplicableApprovalPolicyParams) (ApprovalPolicy, error)
+	GetApprovalPolicy(ctx context.Context, id int64) (ApprovalPolicy, error)
+	GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error)
+	GetBeneficiaryByAccount(ctx context.Context, arg GetBeneficiaryByAccountParams) (Beneficiary, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetEscrowContract(ctx context.Context, id int64) (EscrowContract, error)
+	GetEscrowContractForUpdate(ctx context.Context, id int64) (EscrowContract, error)
+	GetFraudCase(ctx context.Context, id int64) (FraudCase, error)
+	GetFraudCaseForUpdate(ctx context.Context, id int64) (FraudCase, error)
+	GetInterestPosting(ctx context.Context, arg GetInterestPostingParams) (InterestPosting, error)
+	GetInvoice(ctx context.Context, id int64) (Invoice, error)
+	GetInvoiceByPayToken(ctx context.Context, payToken string) (Invoice, error)
+	GetInvoiceForUpdate(ctx context.Context, id int64) (Invoice, error)
+	GetJournal(ctx context.Context, id int64) (Journal, error)
+	GetJournalByTransfer(ctx context.Context, transferID sql.NullInt64) (Journal, error)
+	GetKycProfile(ctx context.Context, username string) (KycProfile, error)
+	GetKycTier(ctx context.Context, tier string) (KycTier, error)
+	GetKycTierForUser(ctx context.Context, username string) (KycTier, error)
+	GetLastAuditEvent(ctx context.Context, chain int32) (AuditEvent, error)
+	GetLastInterestAccrualDate(ctx context.Context) (time.Time, error)
+	GetLatestBalanceSnapshot(ctx context.Context, arg GetLatestBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetLedgerAccountByCode(ctx context.Context, arg GetLedgerAccountByCodeParams) (LedgerAccount, error)
+	GetOAuthClient(ctx context.Context, clientID string) (OauthClient, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetOutgoingTransferTotals(ctx context.Context, arg GetOutgoingTransferTotalsParams) (GetOutgoingTransferTotalsRow, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPendingTransfer(ctx context.Context, id int64) (PendingTransfer, error)
+	GetPendingTransferForUpdate(ctx context.Context, id int64) (PendingTransfer, error)
+	GetPot(ctx context.Context, accountID int64) (Pot, error)
+	GetPrimaryAccount(ctx context.Context, arg GetPrimaryAccountParams) (Account, error)
+	GetRefreshToken(ctx context.Context, tokenHash string) (OauthRefreshToken, error)
+	GetRevokedAccessToken(ctx context.Context, tokenID string) (OauthRevokedAccessToken, error)
+	GetRoundUpPot(ctx context.Context, parentAccountID int64) (Pot, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferLimit(ctx context.Context, id int64) (TransferLimit, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	IncrementAliasAttempts(ctx context.Context, alias string) (Alias, error)
+	ListAccountEntryTotals(ctx context.Context, arg ListAccountEntryTotalsParams) ([]ListAccountEntryTotalsRow, error)
+	ListAccountIDs(ctx context.Context, arg ListAccountIDsParams) ([]int64, error)
+	ListAccountInvitations(ctx context.Context, username string) ([]AccountMember, error)
+	ListAccountMembers(ctx context.Context, accountID int64) ([]AccountMember, error)
+	ListAccountPendingTransfers(ctx context.Context, arg ListAccountPendingTransfersParams) ([]PendingTransfer, error)
+	ListAccountProducts(ctx context.Context) ([]AccountProduct, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsWithoutNumber(ctx context.Context, limit int32) ([]Account, error)
+	ListApiKeys(ctx context.Context, arg ListApiKeysParams) ([]ApiKey, error)
+	ListApplicableTransferLimits(ctx context.Context, arg ListApplicableTransferLimitsParams) ([]TransferLimit, error)
+	ListApprovableTransfers(ctx context.Context, arg ListApprovableTransfersParams) ([]PendingTransfer, error)
+	ListApprovalPolicies(ctx context.Context, accountID int64) ([]ApprovalPolicy, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvent, error)
+	ListBeneficiaries(ctx context.Context, owner string) ([]ListBeneficiariesRow, error)
+	ListDueEscrowContracts(ctx context.Context, arg ListDueEscrowContractsParams) ([]EscrowContract, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEscrowContracts(ctx context.Context, arg ListEscrowContractsParams) ([]EscrowContract, error)
+	ListEscrowContractsByStatus(ctx context.Context, arg ListEscrowContractsByStatusParams) ([]EscrowContract, error)
+	ListEscrowContractsFundedSince(ctx context.Context, arg ListEscrowContractsFundedSinceParams) ([]EscrowContract, error)
+	ListFraudCases(ctx context.Context, arg ListFraudCasesParams) ([]FraudCase, error)
+	ListIncomingPaymentRequests(ctx context.Context, arg ListIncomingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListInterestAccruals(ctx context.Context, arg ListInterestAccrualsParams) ([]InterestAccrual, error)
+	ListInterestBearingAccounts(ctx context.Context, arg ListInterestBearingAccountsParams) ([]ListInterestBearingAccountsRow, error)
+	ListInvoiceItems(ctx context.Context, invoiceID int64) ([]InvoiceItem, error)
+	ListInvoicePayments(ctx context.Context, invoiceID int64) ([]InvoicePayment, error)
+	ListInvoices(ctx context.Context, arg ListInvoicesParams) ([]Invoice, error)
+	ListJournalPostings(ctx context.Context, journalID int64) ([]Posting, error)
+	ListKycDocuments(ctx context.Context, username string) ([]KycDocument, error)
+	ListKycProfilesByStatus(ctx context.Context, arg ListKycProfilesByStatusParams) ([]KycProfile, error)
+	ListKycTiers(ctx context.Context) ([]KycTier, error)
+	ListLedgerAccounts(ctx context.Context) ([]LedgerAccount, error)
+	ListMemberSpendsSince(ctx context.Context, arg ListMemberSpendsSinceParams) ([]ListMemberSpendsSinceRow, error)
+	ListOutgoingPaymentRequests(ctx context.Context, arg ListOutgoingPaymentRequestsParams) ([]PaymentRequest, error)
+	ListOutgoingTransfersSince(ctx context.Context, arg ListOutgoingTransfersSinceParams) ([]Transfer, error)
+	ListOutstandingInvoices(ctx context.Context, merchant string) ([]Invoice, error)
+	ListOwnerLimitAccounts(ctx context.Context, arg ListOwnerLimitAccountsParams) ([]int64, error)
+	ListPots(ctx context.Context, parentAccountID int64) ([]ListPotsRow, error)
+	ListSubscribedWebhookEndpoints(ctx context.Context, arg ListSubscribedWebhookEndpointsParams) ([]WebhookEndpoint, error)
 	ListTransfer(ctx context.Context, arg ListTransferParams) ([]Transfer, error)
+	ListTransferApprovals(ctx context.Context, pendingTransferID int64) ([]TransferApproval, error)
+	ListTransferEntryCounts(ctx context.Context, arg ListTransferEntryCountsParams) ([]ListTransferEntryCountsRow, error)
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimit, error)
+	ListUnpostedInterestAccounts(ctx context.Context, arg ListUnpostedInterestAccountsParams) ([]int64, error)
+	ListUnusedRecoveryCodes(ctx context.Context, username string) ([]MfaRecoveryCode, error)
+	ListUserAliases(ctx context.Context, username string) ([]Alias, error)
+	ListWebhookAttempts(ctx context.Context, deliveryID int64) ([]WebhookAttempt, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookEndpoints(ctx context.Context, owner string) ([]WebhookEndpoint, error)
+	LockAccounts(ctx context.Context, ids []int64) ([]int64, error)
+	LockAuditChain(ctx context.Context, chain int32) error
+	LockUser(ctx context.Context, username string) error
+	MarkInvoicesOverdue(ctx context.Context, today time.Time) ([]Invoice, error)
+	MarkOutboxEventDispatched(ctx context.Context, id int64) error
+	MarkRecoveryCodeUsed(ctx context.Context, id int64) (MfaRecoveryCode, error)
+	NextAccountSequence(ctx context.Context) (int64, error)
+	NotifyAccountEvent(ctx context.Context, arg NotifyAccountEventParams) error
+	RecordInvoicePayment(ctx context.Context, arg RecordInvoicePaymentParams) (Invoice, error)
+	RecordTOTPFailure(ctx context.Context, arg RecordTOTPFailureParams) (User, error)
+	RedeliverWebhook(ctx context.Context, id int64) (WebhookDelivery, error)
+	RenameBeneficiary(ctx context.Context, arg RenameBeneficiaryParams) (Beneficiary, error)
+	ReviewKycProfile(ctx context.Context, arg ReviewKycProfileParams) (KycProfile, error)
+	RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error
+	RevokeApiKey(ctx context.Context, arg RevokeApiKeyParams) (ApiKey, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string) (OauthRefreshToken, error)
+	SendInvoice(ctx context.Context, id int64) (Invoice, error)
+	SetAccountNumber(ctx context.Context, arg SetAccountNumberParams) (Account, error)
+	SetAccountStatus(ctx context.Context, arg SetAccountStatusParams) (Account, error)
+	SetEscrowFundJournal(ctx context.Context, arg SetEscrowFundJournalParams) (EscrowContract, error)
+	SetInterestPostingJournal(ctx context.Context, arg SetInterestPostingJournalParams) (InterestPosting, error)
+	SetUserTier(ctx context.Context, arg SetUserTierParams) (User, error)
+	SubmitKycProfile(ctx context.Context, arg SubmitKycProfileParams) (KycProfile, error)
+	SumEntriesBetween(ctx context.Context, arg SumEntriesBetweenParams) (int64, error)
+	SumInterestAccruals(ctx context.Context, arg SumInterestAccrualsParams) (int64, error)
+	SumPostingsByCurrency(ctx context.Context) ([]SumPostingsByCurrencyRow, error)
+	TouchApiKey(ctx context.Context, id int64) error
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdatePot(ctx context.Context, arg UpdatePotParams) (Pot, error)
+	UpdateUserTOTPCounter(ctx context.Context, arg UpdateUserTOTPCounterParams) (User, error)
+	UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) (User, error)
+	UpdateWebhookDelivery(ctx context.Context, arg UpdateWebhookDeliveryParams) (WebhookDelivery, error)
+	UpsertApprovalPolicy(ctx context.Context, arg UpsertApprovalPolicyParams) (ApprovalPolicy, error)
+	UpsertTransferLimit(ctx context.Context, arg UpsertTransferLimitParams) (TransferLimit, error)
+	UseAuthorizationCode(ctx context.Context, codeHash string) (OauthAuthorizationCode, error)
+	VerifyAlias(ctx context.Context, arg VerifyAliasParams) (Alias, error)
+	VoidInvoice(ctx context.Context, id int64) (Invoice, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -4,12 +4,39 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 )
 
 type Store interface {
 	Querier
 	PerformTransactionTrxn(ctx context.Context, arg TransferTxnParams) (TransferTrxResult, error)
+	PostJournalTrxn(ctx context.Context, arg PostJournalTxnParams) (PostJournalTxnResult, error)
+	ReplaceRecoveryCodesTrxn(ctx context.Context, arg ReplaceRecoveryCodesTxnParams) (User, error)
+	DisableMFATrxn(ctx context.Context, username string) (User, error)
+	RotateRefreshTokenTrxn(ctx context.Context, arg RotateRefreshTokenTxnParams) (OauthRefreshToken, error)
+	RecordAuditEventTrxn(ctx context.Context, arg RecordAuditEventParams) (AuditEvent, error)
+	DispatchOutboxTrxn(ctx context.Context, batchSize int32) (int, error)
+	RecordWebhookAttemptTrxn(ctx context.Context, arg RecordWebhookAttemptTxnParams) (WebhookDelivery, error)
+	GetBalanceAt(ctx context.Context, arg BalanceAtParams) (int64, error)
+	GetAccountLimits(ctx context.Context, accountID int64) (AccountLimits, error)
+	ReviewKYCTrxn(ctx context.Context, arg ReviewKYCTxnParams) (ReviewKYCTxnResult, error)
+	ApproveFraudCaseTrxn(ctx context.Context, arg ApproveFraudCaseTxnParams) (ApproveFraudCaseTxnResult, error)
+	DecideTransferTrxn(ctx context.Context, arg DecideTransferTxnParams) (DecideTransferTxnResult, error)
+	PostInterestTrxn(ctx context.Context, arg PostInterestTxnParams) (InterestPosting, error)
+	CreatePotTrxn(ctx context.Context, arg CreatePotTxnParams) (CreatePotTxnResult, error)
+	OpenAccountTrxn(ctx context.Context, arg CreateAccountParams) (Account, error)
+	MovePotTrxn(ctx context.Context, arg MovePotTxnParams) (TransferTrxResult, error)
+	CreatePaymentRequestTrxn(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	ClosePaymentRequestTrxn(ctx context.Context, arg ClosePaymentRequestTxnParams) (PaymentRequest, error)
+	ExpirePaymentRequestsTrxn(ctx context.Context, now time.Time) ([]PaymentRequest, error)
+	CreateInvoiceTrxn(ctx context.Context, arg CreateInvoiceTxnParams) (CreateInvoiceTxnResult, error)
+	MarkInvoicesOverdueTrxn(ctx context.Context, today time.Time) ([]Invoice, error)
+	CreateEscrowTrxn(ctx context.Context, arg CreateEscrowContractParams) (EscrowTxnResult, error)
+	ReleaseEscrowTrxn(ctx context.Context, id int64) (EscrowTxnResult, error)
+	DisputeEscrowTrxn(ctx context.Context, arg DisputeEscrowContractParams) (EscrowContract, error)
+	ResolveEscrowTrxn(ctx context.Context, arg ResolveEscrowTxnParams) (EscrowTxnResult, error)
 }
 
 // Store provides all necessary information to execute db queries and transactions
@@ -50,6 +77,17 @@ type TransferTxnParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+	// PaymentRequestID is the payment request the transfer pays, if any
+	PaymentRequestID int64 `json:"payment_request_id,omitempty"`
+	// InvoiceID is the invoice the transfer pays towards, if any
+	InvoiceID int64 `json:"invoice_id,omitempty"`
+	// EscrowReleaseSeconds is set when the transfer funds an escrow contract
+	// with the recipient as seller instead of paying them, see EscrowContractFor
+	EscrowReleaseSeconds int64  `json:"escrow_release_seconds,omitempty"`
+	EscrowDescription    string `json:"escrow_description,omitempty"`
+	// InitiatedBy is the user sending the transfer, left empty for transfers
+	// the bank makes itself. A spender's transfers count towards their spend limit.
+	InitiatedBy string `json:"initiated_by,omitempty"`
 }
 
 // TransferTxnResult is the result of the  transfer transaction.
@@ -59,65 +97,151 @@ type TransferTrxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	// RoundUp is the sweep of spare change into the source account's round-up pot
+	RoundUp *TransferTrxResult `json:"round_up,omitempty"`
+	// PaymentRequest is the payment request the transfer paid
+	PaymentRequest *PaymentRequest `json:"payment_request,omitempty"`
+	// Invoice is the invoice the transfer paid towards
+	Invoice *Invoice `json:"invoice,omitempty"`
 }
 
 // PerformTransactionTrxn performs a money from one account to the other .
-// It creates a transfer record , add account entries and update accounts balance within a single database transaction
+// It checks the source account's transfer limits, creates a transfer record and posts it to the ledger as a balanced
+// journal, which writes the account entries and updates the account balances, within a single database transaction.
+// A transfer that breaks a limit, or the initiator's spend limit when they are a spender on the source account, fails
+// with a *LimitExceededError and one that takes the source account further below zero than its product's overdraft
+// allows fails with ErrInsufficientFunds. Moves between an account and its own pots don't count towards limits, and
+// when the source account has a round-up pot the spare change is swept into it. A transfer that pays a payment
+// request or an invoice settles it in the same transaction.
 func (store *SQLStore) PerformTransactionTrxn(ctx context.Context, arg TransferTxnParams) (TransferTrxResult, error) {
 	var result TransferTrxResult
 
 	err := store.executeTrxn(ctx, func(q *Queries) error {
 		var err error
+		result, err = performTransfer(ctx, q, arg)
+		return err
+	})
 
-		transfer := CreateTransferParams{}
-		bt, _ := json.Marshal(arg)
-		_ = json.Unmarshal(bt, &transfer)
+	return result, err
+}
 
-		result.Transfer, err = q.CreateTransfer(ctx, transfer)
-		if err != nil {
-			return err
+func performTransfer(ctx context.Context, q *Queries, arg TransferTxnParams) (TransferTrxResult, error) {
+	var result TransferTrxResult
+
+	internal, err := isPotMove(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
+
+	ids := []int64{arg.FromAccountID, arg.ToAccountID}
+	var roundUp *Pot
+	if !internal {
+		pot, err := q.GetRoundUpPot(ctx, arg.FromAccountID)
+		switch {
+		case err == nil:
+			roundUp = &pot
+			ids = append(ids, pot.AccountID)
+		case !errors.Is(err, sql.ErrNoRows):
+			return result, err
 		}
+	}
 
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
+	// lock the accounts in id order, like postJournal does, before reading the
+	// limit windows so concurrent transfers can't both squeeze under a limit
+	_, err = q.LockAccounts(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+
+	if !internal {
+		if err = checkPotSource(ctx, q, arg.FromAccountID); err != nil {
+			return result, err
+		}
+		if err = checkTransferLimits(ctx, q, arg, time.Now()); err != nil {
+			return result, err
+		}
+		if err = checkSpendLimit(ctx, q, arg.FromAccountID, arg.InitiatedBy, arg.Amount, time.Now()); err != nil {
+			return result, err
 		}
+	}
+
+	if err = checkOverdraft(ctx, q, arg); err != nil {
+		return result, err
+	}
+
+	result, err = postTransfer(ctx, q, arg)
+	if err != nil {
+		return result, err
+	}
 
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	if arg.PaymentRequestID != 0 {
+		request, err := settlePaymentRequest(ctx, q, arg.PaymentRequestID, result, time.Now())
 		if err != nil {
-			return err
+			return result, err
 		}
+		result.PaymentRequest = &request
+	}
 
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	if arg.InvoiceID != 0 {
+		invoice, err := settleInvoice(ctx, q, arg.InvoiceID, result, time.Now())
+		if err != nil {
+			return result, err
 		}
+		result.Invoice = &invoice
+	}
 
-		return err
-	})
+	if roundUp == nil {
+		return result, nil
+	}
 
-	return result, err
+	sweep, err := sweepRoundUp(ctx, q, result, *roundUp)
+	if err != nil {
+		return result, err
+	}
+	if sweep != nil {
+		result.RoundUp = sweep
+		result.FromAccount = sweep.FromAccount
+	}
+	return result, nil
 }
 
-func addMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64, accountID2 int64, amount2 int64) (account1 Account, account2 Account, err error) {
-	account1, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     accountID1,
-		Amount: amount1,
-	})
+// postTransfer records a transfer and posts it to the ledger. The accounts
+// must already be locked.
+func postTransfer(ctx context.Context, q *Queries, arg TransferTxnParams) (TransferTrxResult, error) {
+	var result TransferTrxResult
+
+	transfer := CreateTransferParams{}
+	bt, _ := json.Marshal(arg)
+	_ = json.Unmarshal(bt, &transfer)
+	transfer.InitiatedBy = sql.NullString{String: arg.InitiatedBy, Valid: arg.InitiatedBy != ""}
+
+	var err error
+	result.Transfer, err = q.CreateTransfer(ctx, transfer)
 	if err != nil {
-		return
+		return result, err
 	}
 
-	account2, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
-		ID:     accountID2,
-		Amount: amount2,
+	journal, err := postJournal(ctx, q, PostJournalTxnParams{
+		Description: "transfer",
+		TransferID:  sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		Postings: []PostingParams{
+			{AccountID: arg.FromAccountID, Amount: -arg.Amount},
+			{AccountID: arg.ToAccountID, Amount: arg.Amount},
+		},
 	})
-	return
+	if err != nil {
+		return result, err
+	}
+
+	result.FromEntry, result.ToEntry = journal.Entries[0], journal.Entries[1]
+	result.FromAccount, result.ToAccount = journal.Accounts[0], journal.Accounts[1]
+
+	if err = notifyAccountChange(ctx, q, result.FromAccount, result.FromEntry); err != nil {
+		return result, err
+	}
+	if err = notifyAccountChange(ctx, q, result.ToAccount, result.ToEntry); err != nil {
+		return result, err
+	}
+
+	return result, writeTransferEvents(ctx, q, result)
 }
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAccountLimit is returned when the owner already holds as many accounts of
+// a product in a currency as the product allows
+var ErrAccountLimit = errors.New("account limit for the product reached")
+
+// OpenAccountTrxn opens an account for a customer, up to the product's
+// max_accounts in each currency. The owner is locked so concurrent requests
+// can't both open the last account allowed.
+func (store *SQLStore) OpenAccountTrxn(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		account, err = openAccount(ctx, q, arg)
+		return err
+	})
+
+	return account, err
+}
+
+func openAccount(ctx context.Context, q *Queries, arg CreateAccountParams) (Account, error) {
+	if err := q.LockUser(ctx, arg.Owner); err != nil {
+		return Account{}, err
+	}
+
+	product, err := q.GetAccountProduct(ctx, arg.ProductCode)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if product.MaxAccounts.Valid {
+		count, err := q.CountOwnerProductAccounts(ctx, CountOwnerProductAccountsParams{
+			Owner:        arg.Owner,
+			CurrencyCode: arg.CurrencyCode,
+			ProductCode:  arg.ProductCode,
+		})
+		if err != nil {
+			return Account{}, err
+		}
+		if count >= int64(product.MaxAccounts.Int32) {
+			return Account{}, fmt.Errorf("%w: at most %d %s accounts in %s",
+				ErrAccountLimit, product.MaxAccounts.Int32, product.Code, arg.CurrencyCode)
+		}
+	}
+
+	return q.CreateAccount(ctx, arg)
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAccountTrxnMaxAccounts(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+
+	arg := CreateAccountParams{
+		Owner:        user.Username,
+		CurrencyCode: utils.CAD,
+		ProductCode:  AccountProductChecking,
+	}
+
+	account, err := store.OpenAccountTrxn(context.Background(), arg)
+	require.NoError(t, err)
+
+	// pots don't count towards the product's accounts
+	_, err = store.CreatePotTrxn(context.Background(), CreatePotTxnParams{
+		ParentAccountID: account.ID,
+		Name:            utils.RandomString(8),
+	})
+	require.NoError(t, err)
+
+	// one checking account per currency
+	_, err = store.OpenAccountTrxn(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAccountLimit)
+
+	arg.CurrencyCode = utils.EUR
+	_, err = store.OpenAccountTrxn(context.Background(), arg)
+	require.NoError(t, err)
+}
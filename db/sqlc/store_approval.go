@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Pending transfer statuses. A transfer waits in pending until enough approvers
+// sign it off and it is executed, one of them rejects it, or it expires.
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferExecuted = "executed"
+	PendingTransferRejected = "rejected"
+	PendingTransferExpired  = "expired"
+)
+
+// Decisions an approver can make on a pending transfer
+const (
+	ApprovalDecisionApprove = "approve"
+	ApprovalDecisionReject  = "reject"
+)
+
+var (
+	ErrPendingTransferClosed  = errors.New("pending transfer is no longer pending")
+	ErrPendingTransferExpired = errors.New("pending transfer has expired")
+	ErrAlreadyDecided         = errors.New("approver has already decided on this transfer")
+)
+
+// PendingTransferFor builds the pending transfer that holds arg under policy
+// until enough of its approvers sign it off
+func PendingTransferFor(policy ApprovalPolicy, arg TransferTxnParams, initiatedBy string, now time.Time) CreatePendingTransferParams {
+	return CreatePendingTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		InitiatedBy:       initiatedBy,
+		PolicyID:          sql.NullInt64{Int64: policy.ID, Valid: true},
+		RequiredApprovals: policy.RequiredApprovals,
+		ApproverRole:      policy.ApproverRole,
+		Approvers:         policy.Approvers,
+		ExpiresAt:         now.Add(time.Duration(policy.ExpirySeconds) * time.Second),
+		PaymentRequestID:  sql.NullInt64{Int64: arg.PaymentRequestID, Valid: arg.PaymentRequestID != 0},
+		InvoiceID:         sql.NullInt64{Int64: arg.InvoiceID, Valid: arg.InvoiceID != 0},
+		// an escrow is funded only once the transfer is approved
+		EscrowReleaseSeconds: sql.NullInt64{Int64: arg.EscrowReleaseSeconds, Valid: arg.EscrowReleaseSeconds != 0},
+		EscrowDescription:    arg.EscrowDescription,
+	}
+}
+
+// DecideTransferTxnParams contains the input parameters of the decide transfer transaction
+type DecideTransferTxnParams struct {
+	ID       int64  `json:"id"`
+	Approver string `json:"approver"`
+	Decision string `json:"decision"`
+	Note     string `json:"note"`
+}
+
+// DecideTransferTxnResult is the result of the decide transfer transaction.
+// Transfer is set once the decision completed the quorum and the transfer was
+// made, Escrow instead when the transfer funded an escrow contract.
+type DecideTransferTxnResult struct {
+	PendingTransfer PendingTransfer    `json:"pending_transfer"`
+	Approvals       []TransferApproval `json:"approvals"`
+	Transfer        *TransferTrxResult `json:"transfer"`
+	Escrow          *EscrowTxnResult   `json:"escrow,omitempty"`
+}
+
+// DecideTransferTrxn records an approver's decision on a pending transfer. A
+// rejection closes it straight away, the approval that completes the quorum
+// makes the transfer in the same transaction, still within the source
+// account's limits. Checking the approver is allowed to decide is up to the caller.
+func (store *SQLStore) DecideTransferTrxn(ctx context.Context, arg DecideTransferTxnParams) (DecideTransferTxnResult, error) {
+	var result DecideTransferTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = decideTransfer(ctx, q, arg, time.Now())
+		return err
+	})
+
+	return result, err
+}
+
+func decideTransfer(ctx context.Context, q *Queries, arg DecideTransferTxnParams, now time.Time) (DecideTransferTxnResult, error) {
+	var result DecideTransferTxnResult
+
+	pending, err := q.GetPendingTransferForUpdate(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+	if pending.Status != PendingTransferPending {
+		return result, ErrPendingTransferClosed
+	}
+	// the expiry job closes it on its next run
+	if !now.Before(pending.ExpiresAt) {
+		return result, ErrPendingTransferExpired
+	}
+
+	approvals, err := q.ListTransferApprovals(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+	for _, approval := range approvals {
+		if approval.Approver == arg.Approver {
+			return result, ErrAlreadyDecided
+		}
+	}
+
+	approval, err := q.CreateTransferApproval(ctx, CreateTransferApprovalParams{
+		PendingTransferID: arg.ID,
+		Approver:          arg.Approver,
+		Decision:          arg.Decision,
+		Note:              arg.Note,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Approvals = append(approvals, approval)
+	result.PendingTransfer = pending
+
+	if arg.Decision == ApprovalDecisionReject {
+		result.PendingTransfer, err = q.ClosePendingTransfer(ctx, ClosePendingTransferParams{
+			ID:     arg.ID,
+			Status: PendingTransferRejected,
+		})
+		return result, err
+	}
+
+	var approved int32
+	for _, approval := range result.Approvals {
+		if approval.Decision == ApprovalDecisionApprove {
+			approved++
+		}
+	}
+	if approved < pending.RequiredApprovals {
+		return result, nil
+	}
+
+	result.Transfer, result.Escrow, err = makeClearedTransfer(ctx, q, TransferTxnParams{
+		FromAccountID: pending.FromAccountID,
+		ToAccountID:   pending.ToAccountID,
+		Amount:        pending.Amount,
+		// a transfer that pays a payment request settles it once approved
+		PaymentRequestID:     pending.PaymentRequestID.Int64,
+		InvoiceID:            pending.InvoiceID.Int64,
+		EscrowReleaseSeconds: pending.EscrowReleaseSeconds.Int64,
+		EscrowDescription:    pending.EscrowDescription,
+	}, pending.InitiatedBy)
+	if err != nil {
+		return result, err
+	}
+
+	closeArg := ClosePendingTransferParams{
+		ID:     arg.ID,
+		Status: PendingTransferExecuted,
+	}
+	if result.Transfer != nil {
+		closeArg.TransferID = sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}
+	}
+	result.PendingTransfer, err = q.ClosePendingTransfer(ctx, closeArg)
+	return result, err
+}
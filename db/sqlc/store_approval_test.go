@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPendingTransfer(t *testing.T, from, to Account, approvers []string, expiresAt time.Time) PendingTransfer {
+	pending, err := testQueries.CreatePendingTransfer(context.Background(), CreatePendingTransferParams{
+		FromAccountID:     from.ID,
+		ToAccountID:       to.ID,
+		Amount:            10,
+		InitiatedBy:       from.Owner,
+		RequiredApprovals: 2,
+		Approvers:         approvers,
+		ExpiresAt:         expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferPending, pending.Status)
+	return pending
+}
+
+func TestDecideTransferTrxn(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccountInCurrency(t, "USD")
+	account2 := createRandomAccountInCurrency(t, "USD")
+	checker1 := createRandomUser(t)
+	checker2 := createRandomUser(t)
+
+	pending := createRandomPendingTransfer(t, account1, account2,
+		[]string{checker1.Username, checker2.Username}, time.Now().Add(time.Hour))
+
+	// the first approval doesn't reach the quorum
+	result, err := store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: checker1.Username,
+		Decision: ApprovalDecisionApprove,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Transfer)
+	require.Len(t, result.Approvals, 1)
+	require.Equal(t, PendingTransferPending, result.PendingTransfer.Status)
+
+	_, err = store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: checker1.Username,
+		Decision: ApprovalDecisionApprove,
+	})
+	require.ErrorIs(t, err, ErrAlreadyDecided)
+
+	result, err = store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: checker2.Username,
+		Decision: ApprovalDecisionApprove,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Transfer)
+	require.Len(t, result.Approvals, 2)
+	require.Equal(t, PendingTransferExecuted, result.PendingTransfer.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.PendingTransfer.TransferID.Int64)
+	require.Equal(t, account1.Balance-10, result.Transfer.FromAccount.Balance)
+
+	_, err = store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: account2.Owner,
+		Decision: ApprovalDecisionReject,
+	})
+	require.ErrorIs(t, err, ErrPendingTransferClosed)
+}
+
+func TestRejectAndExpirePendingTransfers(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccountInCurrency(t, "USD")
+	account2 := createRandomAccountInCurrency(t, "USD")
+	checker := createRandomUser(t)
+
+	rejected := createRandomPendingTransfer(t, account1, account2, []string{checker.Username}, time.Now().Add(time.Hour))
+	result, err := store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       rejected.ID,
+		Approver: checker.Username,
+		Decision: ApprovalDecisionReject,
+		Note:     "wrong payee",
+	})
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferRejected, result.PendingTransfer.Status)
+	require.False(t, result.PendingTransfer.TransferID.Valid)
+
+	overdue := createRandomPendingTransfer(t, account1, account2, []string{checker.Username}, time.Now().Add(-time.Minute))
+	_, err = store.DecideTransferTrxn(context.Background(), DecideTransferTxnParams{
+		ID:       overdue.ID,
+		Approver: checker.Username,
+		Decision: ApprovalDecisionApprove,
+	})
+	require.ErrorIs(t, err, ErrPendingTransferExpired)
+
+	expired, err := testQueries.ExpirePendingTransfers(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	var found bool
+	for _, pending := range expired {
+		require.Equal(t, PendingTransferExpired, pending.Status)
+		if pending.ID == overdue.ID {
+			found = true
+		}
+		require.NotEqual(t, rejected.ID, pending.ID)
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditedStore(t *testing.T) {
+	store := NewAuditedStore(db)
+	user := createRandomUser(t)
+
+	ctx := context.WithValue(context.Background(), AuditMetadataKey, AuditMetadata{
+		Actor:     user.Username,
+		IP:        "10.0.0.1",
+		RequestID: "request-1",
+	})
+
+	account, err := store.CreateAccount(ctx, CreateAccountParams{
+		Owner:        user.Username,
+		Balance:      0,
+		CurrencyCode: "USD",
+		ProductCode:  AccountProductChecking,
+	})
+	require.NoError(t, err)
+
+	events, err := store.ListAuditEvents(ctx, ListAuditEventsParams{
+		ResourceType: nullString("account"),
+		ResourceID:   nullString(strconv.FormatInt(account.ID, 10)),
+		PageLimit:    5,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	event := events[0]
+	require.Equal(t, "account.create", event.Action)
+	require.Equal(t, user.Username, event.Actor)
+	require.Equal(t, "request-1", event.RequestID)
+	require.Equal(t, event.ComputeHash(), event.Hash)
+
+	var after Account
+	require.NoError(t, json.Unmarshal(event.After, &after))
+	require.Equal(t, account.ID, after.ID)
+
+	require.NoError(t, store.DeleteAccount(ctx, account.ID))
+
+	last, err := store.GetLastAuditEvent(ctx, event.Chain)
+	require.NoError(t, err)
+	require.Equal(t, "account.delete", last.Action)
+	require.Equal(t, event.Hash, last.PrevHash)
+	require.Equal(t, last.ComputeHash(), last.Hash)
+}
+
+func TestAuditedStoreRecordsTokensLedgerAndJobs(t *testing.T) {
+	store := NewAuditedStore(db)
+	ctx := context.Background()
+	user := createRandomUser(t)
+
+	client, err := testQueries.CreateOAuthClient(ctx, CreateOAuthClientParams{
+		ClientID:     uuid.NewString(),
+		Owner:        user.Username,
+		Name:         utils.RandomString(6),
+		RedirectUris: []string{"https://partner.example.com/callback"},
+		Scopes:       []string{"accounts:read"},
+	})
+	require.NoError(t, err)
+
+	refreshToken, err := store.CreateRefreshToken(ctx, CreateRefreshTokenParams{
+		TokenHash: utils.HashOpaqueToken(utils.RandomString(32)),
+		ClientID:  client.ClientID,
+		Username:  user.Username,
+		Scopes:    client.Scopes,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	event := requireAuditEvent(t, store, "oauth.refresh_token.create", "oauth_client", client.ClientID)
+	require.NotContains(t, string(event.After), "token_hash")
+
+	_, err = store.RotateRefreshTokenTrxn(ctx, RotateRefreshTokenTxnParams{
+		TokenHash:    refreshToken.TokenHash,
+		NewTokenHash: utils.HashOpaqueToken(utils.RandomString(32)),
+		ExpiresAt:    time.Now().Add(2 * time.Hour),
+	})
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "oauth.refresh_token.rotate", "oauth_client", client.ClientID)
+
+	from := createFundedAccount(t, 100)
+	to := createRandomAccountInCurrency(t, utils.USD)
+	journal, err := store.PostJournalTrxn(ctx, PostJournalTxnParams{
+		Description: "correction",
+		Postings: []PostingParams{
+			{AccountID: from.ID, Amount: -10},
+			{AccountID: to.ID, Amount: 10},
+		},
+	})
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "journal.post", "journal", strconv.FormatInt(journal.Journal.ID, 10))
+
+	_, err = store.PostInterestTrxn(ctx, PostInterestTxnParams{
+		AccountID:   to.ID,
+		PeriodStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "interest.post", "account", strconv.FormatInt(to.ID, 10))
+
+	pending := createRandomPendingTransfer(t, from, to, []string{user.Username}, time.Now().Add(-time.Minute))
+	_, err = store.ExpirePendingTransfers(ctx, time.Now())
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "pending_transfer.expire", "pending_transfer", strconv.FormatInt(pending.ID, 10))
+
+	request := createRandomPaymentRequest(t, store, from, to, 10)
+	_, err = store.ExpirePaymentRequestsTrxn(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "payment_request.expire", "payment_request", strconv.FormatInt(request.ID, 10))
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	invoice := createRandomInvoice(t, store, to, today.AddDate(0, 0, -1))
+	_, err = store.SendInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+	_, err = store.MarkInvoicesOverdueTrxn(ctx, today)
+	require.NoError(t, err)
+	requireAuditEvent(t, store, "invoice.overdue", "invoice", strconv.FormatInt(invoice.ID, 10))
+}
+
+// requireAuditEvent returns the latest event of the action on the resource and
+// checks it chains correctly
+func requireAuditEvent(t *testing.T, store Store, action, resourceType, resourceID string) AuditEvent {
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Action:       nullString(action),
+		ResourceType: nullString(resourceType),
+		ResourceID:   nullString(resourceID),
+		PageLimit:    5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	require.Equal(t, events[0].ComputeHash(), events[0].Hash)
+	return events[0]
+}
+
+// BenchmarkRecordAuditEvent measures audited writes from concurrent
+// transactions, every one of them waits for the one before it in its chain
+func BenchmarkRecordAuditEvent(b *testing.B) {
+	store := NewStore(db)
+
+	for _, bc := range []struct {
+		name       string
+		resourceID func(i int64) string
+	}{
+		// the worst case, and how every audited write behaved with a single chain
+		{"OneChain", func(i int64) string { return "bench" }},
+		{"Spread", func(i int64) string { return strconv.FormatInt(i, 10) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var n int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, err := store.RecordAuditEventTrxn(context.Background(), RecordAuditEventParams{
+						Action:       "benchmark.write",
+						ResourceType: "benchmark",
+						ResourceID:   bc.resourceID(atomic.AddInt64(&n, 1)),
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestAuditSnapshotRedactsSecrets(t *testing.T) {
+	snapshot, err := auditSnapshot(User{Username: "alice", HashedPassword: "hash", TotpSecret: "secret"})
+	require.NoError(t, err)
+	require.Contains(t, string(snapshot), "alice")
+	require.NotContains(t, string(snapshot), "hashed_password")
+	require.NotContains(t, string(snapshot), "totp_secret")
+
+	snapshot, err = auditSnapshot(nil)
+	require.NoError(t, err)
+	require.Equal(t, "null", string(snapshot))
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,297 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// LedgerEscrow is the ledger account escrowed money is held in between buyer
+// and seller
+const LedgerEscrow = "escrow"
+
+// Escrow contract statuses. Money is held while a contract is funded or
+// disputed and has left escrow once it is released or refunded.
+const (
+	EscrowFunded   = "funded"
+	EscrowDisputed = "disputed"
+	EscrowReleased = "released"
+	EscrowRefunded = "refunded"
+)
+
+var (
+	ErrEscrowNotFunded   = errors.New("escrow contract is no longer funded")
+	ErrEscrowNotDisputed = errors.New("escrow contract isn't disputed")
+)
+
+// EscrowTxnResult is the result of the escrow transactions that move money, the
+// buyer's account when it is funded or refunded and the seller's when released
+type EscrowTxnResult struct {
+	Contract EscrowContract `json:"contract"`
+	Journal  Journal        `json:"journal"`
+	Account  Account        `json:"account"`
+	Entry    Entry          `json:"entry"`
+}
+
+// EscrowContractFor builds the contract an escrow transfer from the buyer's
+// account to the seller's funds, its release time counts from now
+func EscrowContractFor(from, to Account, arg TransferTxnParams, buyer string, now time.Time) CreateEscrowContractParams {
+	return CreateEscrowContractParams{
+		Buyer:           buyer,
+		Seller:          to.Owner,
+		BuyerAccountID:  from.ID,
+		SellerAccountID: to.ID,
+		Amount:          arg.Amount,
+		CurrencyCode:    from.CurrencyCode,
+		Description:     arg.EscrowDescription,
+		ReleaseAt:       now.Add(time.Duration(arg.EscrowReleaseSeconds) * time.Second),
+	}
+}
+
+// makeClearedTransfer makes a transfer that a fraud reviewer or the source
+// account's approvers let through, funding the escrow contract it was made
+// for when it has escrow terms
+func makeClearedTransfer(ctx context.Context, q *Queries, arg TransferTxnParams, initiatedBy string) (*TransferTrxResult, *EscrowTxnResult, error) {
+	if arg.EscrowReleaseSeconds == 0 {
+		arg.InitiatedBy = initiatedBy
+		transfer, err := performTransfer(ctx, q, arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &transfer, nil, nil
+	}
+
+	from, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := q.GetAccount(ctx, arg.ToAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	escrow, err := fundEscrow(ctx, q, EscrowContractFor(from, to, arg, initiatedBy, time.Now()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, &escrow, nil
+}
+
+// CreateEscrowTrxn records a contract and moves the buyer's money into the
+// escrow ledger account. Funding escrow counts towards the buyer's account's
+// transfer limits, and the buyer's spend limit when they are a spender on it,
+// like a transfer to the seller would, and fails with
+// ErrInsufficientFunds when the buyer's account would go further below zero
+// than its product allows.
+func (store *SQLStore) CreateEscrowTrxn(ctx context.Context, arg CreateEscrowContractParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = fundEscrow(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func fundEscrow(ctx context.Context, q *Queries, arg CreateEscrowContractParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+
+	_, err := q.LockAccounts(ctx, []int64{arg.BuyerAccountID})
+	if err != nil {
+		return result, err
+	}
+
+	if err = checkPotSource(ctx, q, arg.BuyerAccountID); err != nil {
+		return result, err
+	}
+
+	transfer := TransferTxnParams{FromAccountID: arg.BuyerAccountID, Amount: arg.Amount}
+	if err = checkTransferLimits(ctx, q, transfer, time.Now()); err != nil {
+		return result, err
+	}
+
+	if err = checkSpendLimit(ctx, q, arg.BuyerAccountID, arg.Buyer, arg.Amount, time.Now()); err != nil {
+		return result, err
+	}
+
+	if err = checkOverdraft(ctx, q, transfer); err != nil {
+		return result, err
+	}
+
+	contract, err := q.CreateEscrowContract(ctx, arg)
+	if err != nil {
+		return result, err
+	}
+
+	journal, err := postJournal(ctx, q, PostJournalTxnParams{
+		Description: fmt.Sprintf("escrow contract %d funded", contract.ID),
+		Postings: []PostingParams{
+			{AccountID: contract.BuyerAccountID, Amount: -contract.Amount},
+			{LedgerCode: LedgerEscrow, CurrencyCode: contract.CurrencyCode, Amount: contract.Amount},
+		},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	contract, err = q.SetEscrowFundJournal(ctx, SetEscrowFundJournalParams{
+		ID:            contract.ID,
+		FundJournalID: sql.NullInt64{Int64: journal.Journal.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = EscrowTxnResult{
+		Contract: contract,
+		Journal:  journal.Journal,
+		Account:  journal.Accounts[0],
+		Entry:    journal.Entries[0],
+	}
+	if err = notifyAccountChange(ctx, q, result.Account, result.Entry); err != nil {
+		return result, err
+	}
+	return result, writeEscrowEvent(ctx, q, EventEscrowFunded, contract)
+}
+
+// ReleaseEscrowTrxn pays a funded contract's money out to the seller, when the
+// buyer confirms delivery or the contract reaches its release time. Checking
+// which of the two it is is up to the caller.
+func (store *SQLStore) ReleaseEscrowTrxn(ctx context.Context, id int64) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = settleEscrow(ctx, q, id, EscrowFunded, ResolveEscrowTxnParams{Outcome: EscrowReleased})
+		return err
+	})
+
+	return result, err
+}
+
+// DisputeEscrowTrxn stops a funded contract from being released until an
+// admin resolves the dispute
+func (store *SQLStore) DisputeEscrowTrxn(ctx context.Context, arg DisputeEscrowContractParams) (EscrowContract, error) {
+	var contract EscrowContract
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		contract, err = disputeEscrow(ctx, q, arg)
+		return err
+	})
+
+	return contract, err
+}
+
+func disputeEscrow(ctx context.Context, q *Queries, arg DisputeEscrowContractParams) (EscrowContract, error) {
+	contract, err := q.GetEscrowContractForUpdate(ctx, arg.ID)
+	if err != nil {
+		return contract, err
+	}
+	if contract.Status != EscrowFunded {
+		return contract, ErrEscrowNotFunded
+	}
+
+	contract, err = q.DisputeEscrowContract(ctx, arg)
+	if err != nil {
+		return contract, err
+	}
+	return contract, writeEscrowEvent(ctx, q, EventEscrowDisputed, contract)
+}
+
+// ResolveEscrowTxnParams contains the input parameters of the resolve escrow
+// transaction, Outcome is released to pay the seller or refunded to pay the
+// buyer back
+type ResolveEscrowTxnParams struct {
+	ID         int64  `json:"id"`
+	Outcome    string `json:"outcome"`
+	ResolvedBy string `json:"resolved_by"`
+	Note       string `json:"note"`
+}
+
+// ResolveEscrowTrxn settles a disputed contract either way
+func (store *SQLStore) ResolveEscrowTrxn(ctx context.Context, arg ResolveEscrowTxnParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = settleEscrow(ctx, q, arg.ID, EscrowDisputed, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// settleEscrow moves a contract's money out of escrow to the seller or back to
+// the buyer and closes it. The contract must still be in the status the caller
+// saw, so a release and a dispute racing each other can't both win.
+func settleEscrow(ctx context.Context, q *Queries, id int64, from string, arg ResolveEscrowTxnParams) (EscrowTxnResult, error) {
+	var result EscrowTxnResult
+
+	contract, err := q.GetEscrowContractForUpdate(ctx, id)
+	if err != nil {
+		return result, err
+	}
+	if contract.Status != from {
+		if from == EscrowDisputed {
+			return result, ErrEscrowNotDisputed
+		}
+		return result, ErrEscrowNotFunded
+	}
+
+	payee := contract.SellerAccountID
+	eventType := EventEscrowReleased
+	if arg.Outcome == EscrowRefunded {
+		payee = contract.BuyerAccountID
+		eventType = EventEscrowRefunded
+	}
+
+	journal, err := postJournal(ctx, q, PostJournalTxnParams{
+		Description: fmt.Sprintf("escrow contract %d %s", contract.ID, arg.Outcome),
+		Postings: []PostingParams{
+			{LedgerCode: LedgerEscrow, CurrencyCode: contract.CurrencyCode, Amount: -contract.Amount},
+			{AccountID: payee, Amount: contract.Amount},
+		},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	contract, err = q.CloseEscrowContract(ctx, CloseEscrowContractParams{
+		ID:              contract.ID,
+		Status:          arg.Outcome,
+		SettleJournalID: sql.NullInt64{Int64: journal.Journal.ID, Valid: true},
+		ResolvedBy:      sql.NullString{String: arg.ResolvedBy, Valid: arg.ResolvedBy != ""},
+		ResolutionNote:  arg.Note,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result = EscrowTxnResult{
+		Contract: contract,
+		Journal:  journal.Journal,
+		Account:  journal.Accounts[1],
+		Entry:    journal.Entries[1],
+	}
+	if err = notifyAccountChange(ctx, q, result.Account, result.Entry); err != nil {
+		return result, err
+	}
+	return result, writeEscrowEvent(ctx, q, eventType, contract)
+}
+
+// writeEscrowEvent tells both sides of a contract about it
+func writeEscrowEvent(ctx context.Context, q *Queries, eventType string, contract EscrowContract) error {
+	for _, owner := range []string{contract.Buyer, contract.Seller} {
+		err := writeOutboxEvent(ctx, q, eventType, owner, "escrow_contract", strconv.FormatInt(contract.ID, 10), contract)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
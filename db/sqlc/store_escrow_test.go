@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomEscrow(t *testing.T, store Store, buyer, seller Account, amount int64) EscrowContract {
+	result, err := store.CreateEscrowTrxn(context.Background(), CreateEscrowContractParams{
+		Buyer:           buyer.Owner,
+		Seller:          seller.Owner,
+		BuyerAccountID:  buyer.ID,
+		SellerAccountID: seller.ID,
+		Amount:          amount,
+		CurrencyCode:    buyer.CurrencyCode,
+		Description:     utils.RandomString(12),
+		ReleaseAt:       time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowFunded, result.Contract.Status)
+	require.True(t, result.Contract.FundJournalID.Valid)
+	require.Equal(t, buyer.ID, result.Account.ID)
+	require.Equal(t, -amount, result.Entry.Amount)
+	return result.Contract
+}
+
+func TestReleaseEscrow(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	buyer := createFundedAccount(t, 1000)
+	seller := createRandomAccountInCurrency(t, utils.USD)
+	contract := createRandomEscrow(t, store, buyer, seller, 300)
+
+	account, err := testQueries.GetAccount(ctx, buyer.ID)
+	require.NoError(t, err)
+	require.Equal(t, buyer.Balance-300, account.Balance)
+
+	result, err := store.ReleaseEscrowTrxn(ctx, contract.ID)
+	require.NoError(t, err)
+	require.Equal(t, EscrowReleased, result.Contract.Status)
+	require.True(t, result.Contract.SettleJournalID.Valid)
+	require.True(t, result.Contract.ClosedAt.Valid)
+	require.Equal(t, seller.ID, result.Account.ID)
+	require.Equal(t, seller.Balance+300, result.Account.Balance)
+
+	// money only leaves escrow once
+	_, err = store.ReleaseEscrowTrxn(ctx, contract.ID)
+	require.ErrorIs(t, err, ErrEscrowNotFunded)
+
+	_, err = store.DisputeEscrowTrxn(ctx, DisputeEscrowContractParams{
+		ID:            contract.ID,
+		DisputedBy:    sql.NullString{String: buyer.Owner, Valid: true},
+		DisputeReason: "too late",
+	})
+	require.ErrorIs(t, err, ErrEscrowNotFunded)
+}
+
+func TestRefundDisputedEscrow(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	buyer := createFundedAccount(t, 1000)
+	seller := createRandomAccountInCurrency(t, utils.USD)
+	contract := createRandomEscrow(t, store, buyer, seller, 300)
+
+	// the seller can't be refunded a contract nobody disputed
+	_, err := store.ResolveEscrowTrxn(ctx, ResolveEscrowTxnParams{ID: contract.ID, Outcome: EscrowRefunded})
+	require.ErrorIs(t, err, ErrEscrowNotDisputed)
+
+	contract, err = store.DisputeEscrowTrxn(ctx, DisputeEscrowContractParams{
+		ID:            contract.ID,
+		DisputedBy:    sql.NullString{String: buyer.Owner, Valid: true},
+		DisputeReason: "never arrived",
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowDisputed, contract.Status)
+	require.True(t, contract.DisputedAt.Valid)
+
+	// disputed contracts aren't released on confirmation or timeout
+	_, err = store.ReleaseEscrowTrxn(ctx, contract.ID)
+	require.ErrorIs(t, err, ErrEscrowNotFunded)
+
+	result, err := store.ResolveEscrowTrxn(ctx, ResolveEscrowTxnParams{
+		ID:         contract.ID,
+		Outcome:    EscrowRefunded,
+		ResolvedBy: seller.Owner,
+		Note:       "no proof of delivery",
+	})
+	require.NoError(t, err)
+	require.Equal(t, EscrowRefunded, result.Contract.Status)
+	require.Equal(t, "no proof of delivery", result.Contract.ResolutionNote)
+	require.Equal(t, buyer.ID, result.Account.ID)
+	require.Equal(t, buyer.Balance, result.Account.Balance)
+
+	account, err := testQueries.GetAccount(ctx, seller.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.Balance, account.Balance)
+}
+
+func TestListDueEscrowContracts(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	buyer := createFundedAccount(t, 1000)
+	seller := createRandomAccountInCurrency(t, utils.USD)
+	contract := createRandomEscrow(t, store, buyer, seller, 100)
+
+	due, err := testQueries.ListDueEscrowContracts(ctx, ListDueEscrowContractsParams{
+		Now:       time.Now().Add(2 * time.Hour),
+		AfterID:   contract.ID - 1,
+		BatchSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, contract.ID, due[0].ID)
+
+	due, err = testQueries.ListDueEscrowContracts(ctx, ListDueEscrowContractsParams{
+		Now:       time.Now(),
+		AfterID:   contract.ID - 1,
+		BatchSize: 1,
+	})
+	require.NoError(t, err)
+	for _, c := range due {
+		require.NotEqual(t, contract.ID, c.ID)
+	}
+}
+
+func TestEscrowCountsTowardsTransferLimits(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	buyer := createFundedAccount(t, 1000)
+	seller := createRandomAccountInCurrency(t, utils.USD)
+
+	_, err := testQueries.UpsertTransferLimit(ctx, UpsertTransferLimitParams{
+		AccountID:   sql.NullInt64{Int64: buyer.ID, Valid: true},
+		DailyAmount: sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	contract := createRandomEscrow(t, store, buyer, seller, 300)
+
+	// a second escrow can't split the rest of a payment past the daily limit
+	_, err = store.CreateEscrowTrxn(ctx, CreateEscrowContractParams{
+		Buyer:           buyer.Owner,
+		Seller:          seller.Owner,
+		BuyerAccountID:  buyer.ID,
+		SellerAccountID: seller.ID,
+		Amount:          300,
+		CurrencyCode:    buyer.CurrencyCode,
+		ReleaseAt:       time.Now().Add(time.Hour),
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(300), limitErr.Used)
+	require.NotNil(t, limitErr.ResetsAt)
+	require.WithinDuration(t, contract.CreatedAt.Add(DailyLimitWindow), *limitErr.ResetsAt, time.Second)
+
+	// and neither can a plain transfer
+	_, err = store.PerformTransactionTrxn(ctx, TransferTxnParams{FromAccountID: buyer.ID, ToAccountID: seller.ID, Amount: 300})
+	require.True(t, errors.As(err, &limitErr))
+}
+
+func TestApprovedTransferFundsEscrow(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	buyer := createFundedAccount(t, 1000)
+	seller := createRandomAccountInCurrency(t, utils.USD)
+	checker := createRandomUser(t)
+
+	policy, err := testQueries.UpsertApprovalPolicy(ctx, UpsertApprovalPolicyParams{
+		AccountID:         buyer.ID,
+		MinAmount:         100,
+		RequiredApprovals: 1,
+		Approvers:         []string{checker.Username},
+		ExpirySeconds:     3600,
+	})
+	require.NoError(t, err)
+
+	pending, err := testQueries.CreatePendingTransfer(ctx, PendingTransferFor(policy, TransferTxnParams{
+		FromAccountID:        buyer.ID,
+		ToAccountID:          seller.ID,
+		Amount:               200,
+		EscrowReleaseSeconds: 7200,
+		EscrowDescription:    "bike",
+	}, buyer.Owner, time.Now()))
+	require.NoError(t, err)
+
+	result, err := store.DecideTransferTrxn(ctx, DecideTransferTxnParams{
+		ID:       pending.ID,
+		Approver: checker.Username,
+		Decision: ApprovalDecisionApprove,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferExecuted, result.PendingTransfer.Status)
+	require.Nil(t, result.Transfer)
+	require.NotNil(t, result.Escrow)
+
+	// the seller isn't paid until the escrow is released
+	contract := result.Escrow.Contract
+	require.Equal(t, EscrowFunded, contract.Status)
+	require.Equal(t, buyer.Owner, contract.Buyer)
+	require.Equal(t, seller.Owner, contract.Seller)
+	require.Equal(t, "bike", contract.Description)
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), contract.ReleaseAt, 5*time.Second)
+	require.Equal(t, buyer.Balance-200, result.Escrow.Account.Balance)
+
+	account, err := testQueries.GetAccount(ctx, seller.ID)
+	require.NoError(t, err)
+	require.Equal(t, seller.Balance, account.Balance)
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Fraud case statuses. Held transfers open a case that a reviewer approves,
+// which makes the transfer, or rejects. Blocked transfers are recorded as
+// blocked cases and never reviewed.
+const (
+	FraudCaseOpen     = "open"
+	FraudCaseApproved = "approved"
+	FraudCaseRejected = "rejected"
+	FraudCaseBlocked  = "blocked"
+)
+
+var ErrFraudCaseClosed = errors.New("fraud case is not open")
+
+// ApproveFraudCaseTxnParams contains the input parameters of the approve fraud case transaction
+type ApproveFraudCaseTxnParams struct {
+	ID       int64  `json:"id"`
+	Reviewer string `json:"reviewer"`
+	Note     string `json:"note"`
+}
+
+// ApproveFraudCaseTxnResult is the result of the approve fraud case transaction.
+// Transfer is set when the transfer was made, Escrow when it funded an escrow
+// contract and PendingTransfer when it still needs the source account's approvers.
+type ApproveFraudCaseTxnResult struct {
+	Case            FraudCase          `json:"case"`
+	Transfer        *TransferTrxResult `json:"transfer"`
+	Escrow          *EscrowTxnResult   `json:"escrow,omitempty"`
+	PendingTransfer *PendingTransfer   `json:"pending_transfer"`
+}
+
+// ApproveFraudCaseTrxn releases a held transfer. The transfer is made and the
+// case closed in one transaction, so a case is approved only if its transfer
+// went through, and still within the source account's limits. A transfer one
+// of the account's approval policies covers is handed on to its approvers
+// instead, clearing fraud review doesn't stand in for their quorum.
+func (store *SQLStore) ApproveFraudCaseTrxn(ctx context.Context, arg ApproveFraudCaseTxnParams) (ApproveFraudCaseTxnResult, error) {
+	var result ApproveFraudCaseTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = approveFraudCase(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func approveFraudCase(ctx context.Context, q *Queries, arg ApproveFraudCaseTxnParams) (ApproveFraudCaseTxnResult, error) {
+	var result ApproveFraudCaseTxnResult
+
+	fraudCase, err := q.GetFraudCaseForUpdate(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+	if fraudCase.Status != FraudCaseOpen {
+		return result, ErrFraudCaseClosed
+	}
+
+	transferArg := TransferTxnParams{
+		FromAccountID: fraudCase.FromAccountID,
+		ToAccountID:   fraudCase.ToAccountID,
+		Amount:        fraudCase.Amount,
+		// a transfer that pays a payment request or an invoice settles it once released
+		PaymentRequestID:     fraudCase.PaymentRequestID.Int64,
+		InvoiceID:            fraudCase.InvoiceID.Int64,
+		EscrowReleaseSeconds: fraudCase.EscrowReleaseSeconds.Int64,
+		EscrowDescription:    fraudCase.EscrowDescription,
+	}
+	closeArg := CloseFraudCaseParams{
+		ID:         arg.ID,
+		Status:     FraudCaseApproved,
+		Reviewer:   sql.NullString{String: arg.Reviewer, Valid: true},
+		ReviewNote: arg.Note,
+	}
+
+	policy, err := q.GetApplicableApprovalPolicy(ctx, GetApplicableApprovalPolicyParams{
+		AccountID: fraudCase.FromAccountID,
+		Amount:    fraudCase.Amount,
+	})
+	switch {
+	case err == nil:
+		pending, err := q.CreatePendingTransfer(ctx, PendingTransferFor(policy, transferArg, fraudCase.InitiatedBy, time.Now()))
+		if err != nil {
+			return result, err
+		}
+		result.PendingTransfer = &pending
+	case errors.Is(err, sql.ErrNoRows):
+		result.Transfer, result.Escrow, err = makeClearedTransfer(ctx, q, transferArg, fraudCase.InitiatedBy)
+		if err != nil {
+			return result, err
+		}
+		if result.Transfer != nil {
+			closeArg.TransferID = sql.NullInt64{Int64: result.Transfer.Transfer.ID, Valid: true}
+		}
+	default:
+		return result, err
+	}
+
+	result.Case, err = q.CloseFraudCase(ctx, closeArg)
+	return result, err
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveFraudCaseTrxn(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccountInCurrency(t, "USD")
+	account2 := createRandomAccountInCurrency(t, "USD")
+
+	verdicts, err := json.Marshal([]map[string]string{{"rule": "new_payee", "decision": "hold"}})
+	require.NoError(t, err)
+
+	fraudCase, err := testQueries.CreateFraudCase(context.Background(), CreateFraudCaseParams{
+		Status:        FraudCaseOpen,
+		Decision:      "hold",
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		InitiatedBy:   account1.Owner,
+		Verdicts:      verdicts,
+	})
+	require.NoError(t, err)
+	require.False(t, fraudCase.TransferID.Valid)
+
+	count, err := testQueries.CountTransfersBetween(context.Background(), CountTransfersBetweenParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	result, err := store.ApproveFraudCaseTrxn(context.Background(), ApproveFraudCaseTxnParams{
+		ID:       fraudCase.ID,
+		Reviewer: "reviewer",
+		Note:     "known payee",
+	})
+	require.NoError(t, err)
+	require.Equal(t, FraudCaseApproved, result.Case.Status)
+	require.Equal(t, "reviewer", result.Case.Reviewer.String)
+	require.True(t, result.Case.ReviewedAt.Valid)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Case.TransferID.Int64)
+	require.Equal(t, account1.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+10, result.Transfer.ToAccount.Balance)
+
+	count, err = testQueries.CountTransfersBetween(context.Background(), CountTransfersBetweenParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// a closed case can't be approved, or rejected, again
+	_, err = store.ApproveFraudCaseTrxn(context.Background(), ApproveFraudCaseTxnParams{ID: fraudCase.ID})
+	require.ErrorIs(t, err, ErrFraudCaseClosed)
+
+	_, err = testQueries.CloseFraudCase(context.Background(), CloseFraudCaseParams{
+		ID:     fraudCase.ID,
+		Status: FraudCaseRejected,
+	})
+	require.Error(t, err)
+}
+
+func TestApproveFraudCaseUnderApprovalPolicy(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+	account1 := createRandomAccountInCurrency(t, "USD")
+	account2 := createRandomAccountInCurrency(t, "USD")
+
+	_, err := testQueries.UpsertApprovalPolicy(ctx, UpsertApprovalPolicyParams{
+		AccountID:         account1.ID,
+		MinAmount:         5,
+		RequiredApprovals: 2,
+		Approvers:         []string{"checker1", "checker2"},
+		ExpirySeconds:     3600,
+	})
+	require.NoError(t, err)
+
+	fraudCase, err := testQueries.CreateFraudCase(ctx, CreateFraudCaseParams{
+		Status:        FraudCaseOpen,
+		Decision:      "hold",
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		InitiatedBy:   account1.Owner,
+		Verdicts:      []byte(`[]`),
+	})
+	require.NoError(t, err)
+
+	// the reviewer clears the fraud hold, the policy's approvers still decide
+	result, err := store.ApproveFraudCaseTrxn(ctx, ApproveFraudCaseTxnParams{ID: fraudCase.ID, Reviewer: "reviewer"})
+	require.NoError(t, err)
+	require.Equal(t, FraudCaseApproved, result.Case.Status)
+	require.False(t, result.Case.TransferID.Valid)
+	require.Nil(t, result.Transfer)
+	require.NotNil(t, result.PendingTransfer)
+	require.Equal(t, PendingTransferPending, result.PendingTransfer.Status)
+	require.Equal(t, int32(2), result.PendingTransfer.RequiredApprovals)
+	require.Equal(t, account1.Owner, result.PendingTransfer.InitiatedBy)
+
+	count, err := testQueries.CountTransfersBetween(ctx, CountTransfersBetweenParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Account products
+const (
+	AccountProductChecking = "checking"
+	AccountProductSavings  = "savings"
+	AccountProductEscrow   = "escrow"
+	AccountProductBusiness = "business"
+)
+
+// LedgerInterestExpense is the ledger account interest is paid from
+const LedgerInterestExpense = "interest_expense"
+
+var ErrInterestAlreadyPosted = errors.New("interest for this period was already posted")
+
+// PostInterestTxnParams contains the input parameters of the post interest transaction.
+// AccruedMicros is the period's accrued interest and Amount what it rounds to.
+type PostInterestTxnParams struct {
+	AccountID     int64     `json:"account_id"`
+	PeriodStart   time.Time `json:"period_start"`
+	PeriodEnd     time.Time `json:"period_end"`
+	AccruedMicros int64     `json:"accrued_micros"`
+	Amount        int64     `json:"amount"`
+}
+
+// PostInterestTrxn pays a period's accrued interest into the account from the
+// interest expense ledger account. The posting record is keyed by account and
+// period, so posting the same period twice fails with ErrInterestAlreadyPosted
+// instead of paying again.
+func (store *SQLStore) PostInterestTrxn(ctx context.Context, arg PostInterestTxnParams) (InterestPosting, error) {
+	var posting InterestPosting
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		posting, err = postInterest(ctx, q, arg)
+		return err
+	})
+
+	return posting, err
+}
+
+func postInterest(ctx context.Context, q *Queries, arg PostInterestTxnParams) (InterestPosting, error) {
+	posting, err := q.CreateInterestPosting(ctx, CreateInterestPostingParams{
+		AccountID:     arg.AccountID,
+		PeriodStart:   arg.PeriodStart,
+		PeriodEnd:     arg.PeriodEnd,
+		AccruedMicros: arg.AccruedMicros,
+		Amount:        arg.Amount,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return posting, ErrInterestAlreadyPosted
+	}
+	if err != nil || arg.Amount == 0 {
+		return posting, err
+	}
+
+	account, err := q.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return posting, err
+	}
+
+	journal, err := postJournal(ctx, q, PostJournalTxnParams{
+		Description: fmt.Sprintf("interest %s to %s", arg.PeriodStart.Format("2006-01-02"), arg.PeriodEnd.Format("2006-01-02")),
+		Postings: []PostingParams{
+			{AccountID: account.ID, Amount: arg.Amount},
+			{LedgerCode: LedgerInterestExpense, CurrencyCode: account.CurrencyCode, Amount: -arg.Amount},
+		},
+	})
+	if err != nil {
+		return posting, err
+	}
+
+	posting, err = q.SetInterestPostingJournal(ctx, SetInterestPostingJournalParams{
+		JournalID:   sql.NullInt64{Int64: journal.Journal.ID, Valid: true},
+		AccountID:   arg.AccountID,
+		PeriodStart: arg.PeriodStart,
+	})
+	if err != nil {
+		return posting, err
+	}
+
+	return posting, notifyAccountChange(ctx, q, journal.Accounts[0], journal.Entries[0])
+}
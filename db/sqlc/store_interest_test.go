@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostInterestTrxnIsIdempotent(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:        user.Username,
+		Balance:      0,
+		CurrencyCode: utils.EUR,
+		ProductCode:  AccountProductSavings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AccountProductSavings, account.ProductCode)
+
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	// rerunning the accrual for a day keeps the first record
+	for i := 0; i < 2; i++ {
+		err = testQueries.CreateInterestAccrual(context.Background(), CreateInterestAccrualParams{
+			AccountID:    account.ID,
+			AccrualDate:  start,
+			Balance:      1_000_000,
+			RateBps:      250,
+			DayCount:     utils.DayCountActual365,
+			AmountMicros: 68_493_151,
+		})
+		require.NoError(t, err)
+	}
+
+	// other tests share the table, so only check the day counts as accrued
+	last, err := testQueries.GetLastInterestAccrualDate(context.Background())
+	require.NoError(t, err)
+	require.False(t, last.Before(start))
+
+	accrued, err := testQueries.SumInterestAccruals(context.Background(), SumInterestAccrualsParams{
+		AccountID:   account.ID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(68_493_151), accrued)
+
+	expense, err := testQueries.GetLedgerAccountByCode(context.Background(), GetLedgerAccountByCodeParams{
+		Code:         LedgerInterestExpense,
+		CurrencyCode: utils.EUR,
+	})
+	require.NoError(t, err)
+
+	arg := PostInterestTxnParams{
+		AccountID:     account.ID,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		AccruedMicros: accrued,
+		Amount:        utils.RoundMicros(accrued),
+	}
+
+	posting, err := store.PostInterestTrxn(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(68), posting.Amount)
+	require.True(t, posting.JournalID.Valid)
+
+	_, err = store.PostInterestTrxn(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInterestAlreadyPosted)
+
+	account, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(68), account.Balance)
+
+	after, err := testQueries.GetLedgerAccountByCode(context.Background(), GetLedgerAccountByCodeParams{
+		Code:         LedgerInterestExpense,
+		CurrencyCode: utils.EUR,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expense.Balance-68, after.Balance)
+}
@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Invoice statuses. A draft becomes payable once sent, turns overdue when its
+// due date passes and is paid once payments add up to its total. Invoices
+// nothing was paid on can be voided until then.
+const (
+	InvoiceDraft   = "draft"
+	InvoiceSent    = "sent"
+	InvoicePaid    = "paid"
+	InvoiceOverdue = "overdue"
+	InvoiceVoid    = "void"
+)
+
+var (
+	ErrInvoiceNotPayable = errors.New("invoice isn't open for payment")
+	ErrInvoiceOverpaid   = errors.New("payment is more than the invoice's amount due")
+	ErrInvoiceMismatch   = errors.New("transfer doesn't match the invoice")
+)
+
+// InvoicePayable reports whether an invoice in the given status takes payments
+func InvoicePayable(status string) bool {
+	return status == InvoiceSent || status == InvoiceOverdue
+}
+
+// CreateInvoiceTxnParams contains the input parameters of the create invoice
+// transaction, the items' InvoiceID is filled in by it
+type CreateInvoiceTxnParams struct {
+	Invoice CreateInvoiceParams       `json:"invoice"`
+	Items   []CreateInvoiceItemParams `json:"items"`
+}
+
+// CreateInvoiceTxnResult is the result of the create invoice transaction
+type CreateInvoiceTxnResult struct {
+	Invoice Invoice       `json:"invoice"`
+	Items   []InvoiceItem `json:"items"`
+}
+
+// CreateInvoiceTrxn records a draft invoice with its line items. Working the
+// totals out from the items is up to the caller.
+func (store *SQLStore) CreateInvoiceTrxn(ctx context.Context, arg CreateInvoiceTxnParams) (CreateInvoiceTxnResult, error) {
+	var result CreateInvoiceTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = draftInvoice(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func draftInvoice(ctx context.Context, q *Queries, arg CreateInvoiceTxnParams) (CreateInvoiceTxnResult, error) {
+	var (
+		result CreateInvoiceTxnResult
+		err    error
+	)
+
+	result.Invoice, err = q.CreateInvoice(ctx, arg.Invoice)
+	if err != nil {
+		return result, err
+	}
+
+	result.Items = make([]InvoiceItem, 0, len(arg.Items))
+	for _, item := range arg.Items {
+		item.InvoiceID = result.Invoice.ID
+		created, err := q.CreateInvoiceItem(ctx, item)
+		if err != nil {
+			return result, err
+		}
+		result.Items = append(result.Items, created)
+	}
+
+	return result, nil
+}
+
+// MarkInvoicesOverdueTrxn marks every sent invoice that is past its due date
+// on today as overdue and tells the merchants
+func (store *SQLStore) MarkInvoicesOverdueTrxn(ctx context.Context, today time.Time) ([]Invoice, error) {
+	var overdue []Invoice
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		overdue, err = markOverdueInvoices(ctx, q, today)
+		return err
+	})
+
+	return overdue, err
+}
+
+func markOverdueInvoices(ctx context.Context, q *Queries, today time.Time) ([]Invoice, error) {
+	overdue, err := q.MarkInvoicesOverdue(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, invoice := range overdue {
+		if err = writeInvoiceEvent(ctx, q, EventInvoiceOverdue, invoice); err != nil {
+			return nil, err
+		}
+	}
+	return overdue, nil
+}
+
+// settleInvoice records a payment towards an invoice by a transfer made in the
+// same transaction. The invoice is paid once its payments add up to the total,
+// paying more than is due fails the transfer.
+func settleInvoice(ctx context.Context, q *Queries, id int64, transfer TransferTrxResult, now time.Time) (Invoice, error) {
+	invoice, err := q.GetInvoiceForUpdate(ctx, id)
+	if err != nil {
+		return invoice, err
+	}
+	if !InvoicePayable(invoice.Status) {
+		return invoice, ErrInvoiceNotPayable
+	}
+	if transfer.Transfer.ToAccountID != invoice.ToAccountID {
+		return invoice, ErrInvoiceMismatch
+	}
+	if transfer.Transfer.Amount > invoice.Total-invoice.AmountPaid {
+		return invoice, ErrInvoiceOverpaid
+	}
+
+	_, err = q.CreateInvoicePayment(ctx, CreateInvoicePaymentParams{
+		InvoiceID:  id,
+		TransferID: transfer.Transfer.ID,
+		Amount:     transfer.Transfer.Amount,
+	})
+	if err != nil {
+		return invoice, err
+	}
+
+	arg := RecordInvoicePaymentParams{
+		ID:         id,
+		AmountPaid: invoice.AmountPaid + transfer.Transfer.Amount,
+		Status:     invoice.Status,
+	}
+	if arg.AmountPaid == invoice.Total {
+		arg.Status = InvoicePaid
+		arg.PaidAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	invoice, err = q.RecordInvoicePayment(ctx, arg)
+	if err != nil {
+		return invoice, err
+	}
+
+	if invoice.Status == InvoicePaid {
+		return invoice, writeInvoiceEvent(ctx, q, EventInvoicePaid, invoice)
+	}
+	return invoice, writeInvoiceEvent(ctx, q, EventInvoicePaymentReceived, invoice)
+}
+
+func writeInvoiceEvent(ctx context.Context, q *Queries, eventType string, invoice Invoice) error {
+	return writeOutboxEvent(ctx, q, eventType, invoice.Merchant, "invoice", strconv.FormatInt(invoice.ID, 10), invoice)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomInvoice(t *testing.T, store Store, to Account, dueDate time.Time) Invoice {
+	result, err := store.CreateInvoiceTrxn(context.Background(), CreateInvoiceTxnParams{
+		Invoice: CreateInvoiceParams{
+			Merchant:     to.Owner,
+			ToAccountID:  to.ID,
+			CurrencyCode: to.CurrencyCode,
+			Subtotal:     100,
+			TaxRateBps:   1000,
+			TaxAmount:    10,
+			Total:        110,
+			PayToken:     utils.RandomString(32),
+			DueDate:      dueDate,
+		},
+		Items: []CreateInvoiceItemParams{
+			{Description: "widget", Quantity: 2, UnitAmount: 50, Amount: 100},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, InvoiceDraft, result.Invoice.Status)
+	require.Len(t, result.Items, 1)
+	require.Equal(t, result.Invoice.ID, result.Items[0].InvoiceID)
+	return result.Invoice
+}
+
+func TestPayInvoice(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	payer := createFundedAccount(t, 1000)
+	to := createRandomAccountInCurrency(t, utils.USD)
+	invoice := createRandomInvoice(t, store, to, time.Now().AddDate(0, 0, 7))
+
+	pay := func(amount int64) (TransferTrxResult, error) {
+		return store.PerformTransactionTrxn(ctx, TransferTxnParams{
+			FromAccountID: payer.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			InvoiceID:     invoice.ID,
+		})
+	}
+
+	// drafts can't be paid
+	_, err := pay(10)
+	require.ErrorIs(t, err, ErrInvoiceNotPayable)
+
+	_, err = testQueries.SendInvoice(ctx, invoice.ID)
+	require.NoError(t, err)
+
+	result, err := pay(60)
+	require.NoError(t, err)
+	require.Equal(t, InvoiceSent, result.Invoice.Status)
+	require.Equal(t, int64(60), result.Invoice.AmountPaid)
+
+	_, err = pay(51)
+	require.ErrorIs(t, err, ErrInvoiceOverpaid)
+
+	// invoices that were paid towards can't be voided
+	_, err = testQueries.VoidInvoice(ctx, invoice.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err = pay(50)
+	require.NoError(t, err)
+	require.Equal(t, InvoicePaid, result.Invoice.Status)
+	require.True(t, result.Invoice.PaidAt.Valid)
+
+	payments, err := testQueries.ListInvoicePayments(ctx, invoice.ID)
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+
+	_, err = pay(1)
+	require.ErrorIs(t, err, ErrInvoiceNotPayable)
+}
+
+func TestMarkInvoicesOverdue(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	to := createRandomAccountInCurrency(t, utils.USD)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	late := createRandomInvoice(t, store, to, today.AddDate(0, 0, -1))
+	onTime := createRandomInvoice(t, store, to, today)
+	for _, invoice := range []Invoice{late, onTime} {
+		_, err := testQueries.SendInvoice(ctx, invoice.ID)
+		require.NoError(t, err)
+	}
+
+	overdue, err := store.MarkInvoicesOverdueTrxn(ctx, today)
+	require.NoError(t, err)
+
+	ids := map[int64]bool{}
+	for _, invoice := range overdue {
+		ids[invoice.ID] = true
+	}
+	require.True(t, ids[late.ID])
+	require.False(t, ids[onTime.ID])
+
+	outstanding, err := testQueries.ListOutstandingInvoices(ctx, to.Owner)
+	require.NoError(t, err)
+	require.Len(t, outstanding, 2)
+	require.Equal(t, late.ID, outstanding[0].ID)
+	require.Equal(t, InvoiceOverdue, outstanding[0].Status)
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// KYC statuses. Submitting moves a profile to pending, from any status but
+// pending, and a review moves it on to approved or rejected.
+const (
+	KYCStatusPending  = "pending"
+	KYCStatusApproved = "approved"
+	KYCStatusRejected = "rejected"
+)
+
+var ErrKYCNotPending = errors.New("kyc profile is not pending review")
+
+// ReviewKYCTxnParams contains the input parameters of the review kyc transaction.
+// Tier is the tier an approved user moves to and is ignored on rejection.
+type ReviewKYCTxnParams struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+	Tier     string `json:"tier"`
+	Reviewer string `json:"reviewer"`
+	Note     string `json:"note"`
+}
+
+// ReviewKYCTxnResult is the result of the review kyc transaction
+type ReviewKYCTxnResult struct {
+	Profile KycProfile `json:"profile"`
+	User    User       `json:"user"`
+}
+
+// ReviewKYCTrxn records a back-office decision on a pending profile and, when
+// it is approved, moves the user to the granted tier in the same transaction
+func (store *SQLStore) ReviewKYCTrxn(ctx context.Context, arg ReviewKYCTxnParams) (ReviewKYCTxnResult, error) {
+	var result ReviewKYCTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = reviewKYC(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func reviewKYC(ctx context.Context, q *Queries, arg ReviewKYCTxnParams) (ReviewKYCTxnResult, error) {
+	var result ReviewKYCTxnResult
+	var err error
+
+	result.Profile, err = q.ReviewKycProfile(ctx, ReviewKycProfileParams{
+		Username:   arg.Username,
+		Status:     arg.Status,
+		Reviewer:   sql.NullString{String: arg.Reviewer, Valid: true},
+		ReviewNote: arg.Note,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return result, ErrKYCNotPending
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if arg.Status != KYCStatusApproved {
+		result.User, err = q.GetUser(ctx, arg.Username)
+		return result, err
+	}
+
+	result.User, err = q.SetUserTier(ctx, SetUserTierParams{
+		Username: arg.Username,
+		Tier:     arg.Tier,
+	})
+	return result, err
+}
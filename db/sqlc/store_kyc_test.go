@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func submitRandomKYC(t *testing.T, username string) KycProfile {
+	profile, err := testQueries.SubmitKycProfile(context.Background(), SubmitKycProfileParams{
+		Username:          username,
+		RequestedTier:     "verified",
+		DateOfBirth:       time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC),
+		PhoneNumber:       "+2348012345678",
+		AddressLine1:      "12 Marina Road",
+		City:              "Lagos",
+		PostalCode:        "101001",
+		CountryCode:       "NG",
+		Nationality:       "NG",
+		IDNumber:          utils.RandomString(9),
+		Provider:          "fake",
+		ProviderReference: utils.RandomString(12),
+		ProviderOutcome:   "clear",
+		ProviderReasons:   []string{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, KYCStatusPending, profile.Status)
+	return profile
+}
+
+func TestReviewKYCTrxn(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+	submitRandomKYC(t, user.Username)
+
+	// a pending profile can't be submitted again
+	_, err := testQueries.SubmitKycProfile(context.Background(), SubmitKycProfileParams{
+		Username:        user.Username,
+		RequestedTier:   "verified",
+		DateOfBirth:     time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC),
+		ProviderReasons: []string{},
+	})
+	require.Error(t, err)
+
+	result, err := store.ReviewKYCTrxn(context.Background(), ReviewKYCTxnParams{
+		Username: user.Username,
+		Status:   KYCStatusApproved,
+		Tier:     "verified",
+		Reviewer: "reviewer",
+	})
+	require.NoError(t, err)
+	require.Equal(t, KYCStatusApproved, result.Profile.Status)
+	require.Equal(t, "reviewer", result.Profile.Reviewer.String)
+	require.True(t, result.Profile.ReviewedAt.Valid)
+	require.Equal(t, "verified", result.User.Tier)
+
+	tier, err := testQueries.GetKycTierForUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Contains(t, tier.AllowedProducts, AccountProductSavings)
+
+	// decisions are final until the customer submits again
+	_, err = store.ReviewKYCTrxn(context.Background(), ReviewKYCTxnParams{
+		Username: user.Username,
+		Status:   KYCStatusRejected,
+		Reviewer: "reviewer",
+		Note:     "changed my mind",
+	})
+	require.ErrorIs(t, err, ErrKYCNotPending)
+}
+
+func TestReviewKYCTrxnReject(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+	submitRandomKYC(t, user.Username)
+
+	result, err := store.ReviewKYCTrxn(context.Background(), ReviewKYCTxnParams{
+		Username: user.Username,
+		Status:   KYCStatusRejected,
+		Tier:     "enhanced",
+		Reviewer: "reviewer",
+		Note:     "document is expired",
+	})
+	require.NoError(t, err)
+	require.Equal(t, KYCStatusRejected, result.Profile.Status)
+	require.Equal(t, UserTierStandard, result.User.Tier)
+
+	// a rejected customer may try again
+	profile := submitRandomKYC(t, user.Username)
+	require.Empty(t, profile.ReviewNote)
+	require.False(t, profile.ReviewedAt.Valid)
+}
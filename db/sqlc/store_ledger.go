@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Codes of the system ledger accounts, each exists once per supported currency
+const (
+	LedgerCash        = "cash"
+	LedgerFeesRevenue = "fees_revenue"
+	LedgerFXGainLoss  = "fx_gain_loss"
+	LedgerSuspense    = "suspense"
+)
+
+// Account statuses, money can't move in or out of a blocked account
+const (
+	AccountStatusActive  = "active"
+	AccountStatusBlocked = "blocked"
+)
+
+var (
+	ErrUnbalancedJournal = errors.New("journal postings do not balance")
+	ErrAccountBlocked    = errors.New("account is blocked")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+)
+
+// PostingParams is one line of a journal. It posts either to a customer account,
+// in the account's currency, or to the system ledger account LedgerCode in CurrencyCode.
+// Amounts follow the balance sign convention: credits are positive, debits negative.
+type PostingParams struct {
+	AccountID    int64  `json:"account_id"`
+	LedgerCode   string `json:"ledger_code"`
+	CurrencyCode string `json:"currency_code"`
+	Amount       int64  `json:"amount"`
+}
+
+// PostJournalTxnParams contains the input parameters of the post journal transaction
+type PostJournalTxnParams struct {
+	Description string          `json:"description"`
+	TransferID  sql.NullInt64   `json:"transfer_id"`
+	Postings    []PostingParams `json:"postings"`
+}
+
+// PostJournalTxnResult is the result of the post journal transaction. Postings,
+// Entries and Accounts are indexed like the postings of the request, Entries and
+// Accounts are only filled in for postings to customer accounts.
+type PostJournalTxnResult struct {
+	Journal  Journal   `json:"journal"`
+	Postings []Posting `json:"postings"`
+	Entries  []Entry   `json:"entries"`
+	Accounts []Account `json:"accounts"`
+}
+
+// PostJournalTrxn records a balanced journal and applies its postings to the
+// balances of the accounts involved within a single database transaction
+func (store *SQLStore) PostJournalTrxn(ctx context.Context, arg PostJournalTxnParams) (PostJournalTxnResult, error) {
+	var result PostJournalTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = postJournal(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// postJournal is the only way money moves. It refuses journals whose postings
+// don't sum to zero in every currency, and the postings_balanced constraint
+// trigger checks the same again when the transaction commits.
+func postJournal(ctx context.Context, q *Queries, arg PostJournalTxnParams) (PostJournalTxnResult, error) {
+	result := PostJournalTxnResult{
+		Postings: make([]Posting, len(arg.Postings)),
+		Entries:  make([]Entry, len(arg.Postings)),
+		Accounts: make([]Account, len(arg.Postings)),
+	}
+
+	if len(arg.Postings) < 2 {
+		return result, fmt.Errorf("%w: a journal needs at least two postings", ErrUnbalancedJournal)
+	}
+
+	ledgerAccounts := make([]LedgerAccount, len(arg.Postings))
+	for i, posting := range arg.Postings {
+		if posting.Amount == 0 {
+			return result, fmt.Errorf("posting %d has no amount", i)
+		}
+		if (posting.AccountID == 0) == (posting.LedgerCode == "") {
+			return result, fmt.Errorf("posting %d must name either an account or a ledger account", i)
+		}
+		if posting.LedgerCode == "" {
+			continue
+		}
+
+		ledgerAccount, err := q.GetLedgerAccountByCode(ctx, GetLedgerAccountByCodeParams{
+			Code:         posting.LedgerCode,
+			CurrencyCode: posting.CurrencyCode,
+		})
+		if err != nil {
+			return result, fmt.Errorf("ledger account %s %s: %w", posting.LedgerCode, posting.CurrencyCode, err)
+		}
+		ledgerAccounts[i] = ledgerAccount
+	}
+
+	// update balances in a fixed order, customer accounts then ledger accounts
+	// by id, so concurrent journals over the same accounts can't deadlock
+	order := make([]int, len(arg.Postings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := arg.Postings[order[a]], arg.Postings[order[b]]
+		if (pa.AccountID == 0) != (pb.AccountID == 0) {
+			return pa.AccountID != 0
+		}
+		if pa.AccountID != 0 {
+			return pa.AccountID < pb.AccountID
+		}
+		return ledgerAccounts[order[a]].ID < ledgerAccounts[order[b]].ID
+	})
+
+	currencies := make([]string, len(arg.Postings))
+	for _, i := range order {
+		posting := arg.Postings[i]
+		if posting.AccountID == 0 {
+			_, err := q.AddLedgerAccountBalance(ctx, AddLedgerAccountBalanceParams{
+				ID:     ledgerAccounts[i].ID,
+				Amount: posting.Amount,
+			})
+			if err != nil {
+				return result, err
+			}
+			currencies[i] = ledgerAccounts[i].CurrencyCode
+			continue
+		}
+
+		account, err := q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     posting.AccountID,
+			Amount: posting.Amount,
+		})
+		if err != nil {
+			return result, err
+		}
+		if account.Status != AccountStatusActive {
+			return result, fmt.Errorf("%w: account %d", ErrAccountBlocked, account.ID)
+		}
+		result.Accounts[i] = account
+		currencies[i] = account.CurrencyCode
+	}
+
+	totals := make(map[string]int64)
+	for i, posting := range arg.Postings {
+		totals[currencies[i]] += posting.Amount
+	}
+	for currency, total := range totals {
+		if total != 0 {
+			return result, fmt.Errorf("%w: %s postings sum to %d", ErrUnbalancedJournal, currency, total)
+		}
+	}
+
+	var err error
+	result.Journal, err = q.CreateJournal(ctx, CreateJournalParams{
+		Description: arg.Description,
+		TransferID:  arg.TransferID,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for i, posting := range arg.Postings {
+		params := CreatePostingParams{
+			JournalID:    result.Journal.ID,
+			CurrencyCode: currencies[i],
+			Amount:       posting.Amount,
+		}
+
+		if posting.AccountID != 0 {
+			result.Entries[i], err = q.CreateEntry(ctx, CreateEntryParams{
+				AccountID: posting.AccountID,
+				Amount:    posting.Amount,
+			})
+			if err != nil {
+				return result, err
+			}
+			params.AccountID = sql.NullInt64{Int64: posting.AccountID, Valid: true}
+			params.EntryID = sql.NullInt64{Int64: result.Entries[i].ID, Valid: true}
+		} else {
+			params.LedgerAccountID = sql.NullInt64{Int64: ledgerAccounts[i].ID, Valid: true}
+		}
+
+		result.Postings[i], err = q.CreatePosting(ctx, params)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostJournalTrxn(t *testing.T) {
+	store := NewStore(db)
+	account := createRandomAccountInCurrency(t, utils.USD)
+
+	cash, err := testQueries.GetLedgerAccountByCode(context.Background(), GetLedgerAccountByCodeParams{
+		Code:         LedgerCash,
+		CurrencyCode: utils.USD,
+	})
+	require.NoError(t, err)
+
+	// a cash deposit credits the customer and debits cash
+	result, err := store.PostJournalTrxn(context.Background(), PostJournalTxnParams{
+		Description: "cash deposit",
+		Postings: []PostingParams{
+			{AccountID: account.ID, Amount: 500},
+			{LedgerCode: LedgerCash, CurrencyCode: utils.USD, Amount: -500},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+500, result.Accounts[0].Balance)
+	require.Equal(t, int64(500), result.Entries[0].Amount)
+	require.Zero(t, result.Entries[1].ID)
+
+	postings, err := testQueries.ListJournalPostings(context.Background(), result.Journal.ID)
+	require.NoError(t, err)
+	require.Len(t, postings, 2)
+
+	var total int64
+	for _, posting := range postings {
+		require.Equal(t, utils.USD, posting.CurrencyCode)
+		total += posting.Amount
+	}
+	require.Zero(t, total)
+	require.Equal(t, sql.NullInt64{Int64: result.Entries[0].ID, Valid: true}, postings[0].EntryID)
+	require.Equal(t, sql.NullInt64{Int64: cash.ID, Valid: true}, postings[1].LedgerAccountID)
+
+	updatedCash, err := testQueries.GetLedgerAccountByCode(context.Background(), GetLedgerAccountByCodeParams{
+		Code:         LedgerCash,
+		CurrencyCode: utils.USD,
+	})
+	require.NoError(t, err)
+	require.LessOrEqual(t, updatedCash.Balance, cash.Balance-500)
+}
+
+func TestPostJournalTrxnUnbalanced(t *testing.T) {
+	store := NewStore(db)
+	account := createRandomAccountInCurrency(t, utils.USD)
+	other := createRandomAccountInCurrency(t, utils.EUR)
+
+	testCases := []struct {
+		name     string
+		postings []PostingParams
+	}{
+		{
+			name: "AmountsDontSumToZero",
+			postings: []PostingParams{
+				{AccountID: account.ID, Amount: 100},
+				{LedgerCode: LedgerCash, CurrencyCode: utils.USD, Amount: -90},
+			},
+		},
+		{
+			name: "CurrenciesDontMatch",
+			postings: []PostingParams{
+				{AccountID: account.ID, Amount: -100},
+				{AccountID: other.ID, Amount: 100},
+			},
+		},
+		{
+			name: "SinglePosting",
+			postings: []PostingParams{
+				{AccountID: account.ID, Amount: 100},
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.PostJournalTrxn(context.Background(), PostJournalTxnParams{
+				Description: tc.name,
+				Postings:    tc.postings,
+			})
+			require.ErrorIs(t, err, ErrUnbalancedJournal)
+
+			unchanged, err := testQueries.GetAccount(context.Background(), account.ID)
+			require.NoError(t, err)
+			require.Equal(t, account.Balance, unchanged.Balance)
+		})
+	}
+}
+
+func TestUnbalancedPostingsRejectedAtCommit(t *testing.T) {
+	account := createRandomAccountInCurrency(t, utils.USD)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	q := New(tx)
+	journal, err := q.CreateJournal(context.Background(), CreateJournalParams{Description: "bypass"})
+	require.NoError(t, err)
+
+	_, err = q.CreatePosting(context.Background(), CreatePostingParams{
+		JournalID:    journal.ID,
+		AccountID:    sql.NullInt64{Int64: account.ID, Valid: true},
+		CurrencyCode: utils.USD,
+		Amount:       100,
+	})
+	require.NoError(t, err)
+
+	require.Error(t, tx.Commit())
+}
+
+func TestPostJournalTrxnBlockedAccount(t *testing.T) {
+	store := NewStore(db)
+	account := createRandomAccountInCurrency(t, utils.USD)
+
+	_, err := testQueries.SetAccountStatus(context.Background(), SetAccountStatusParams{
+		ID:     account.ID,
+		Status: AccountStatusBlocked,
+	})
+	require.NoError(t, err)
+
+	_, err = store.PostJournalTrxn(context.Background(), PostJournalTxnParams{
+		Description: "cash deposit",
+		Postings: []PostingParams{
+			{AccountID: account.ID, Amount: 500},
+			{LedgerCode: LedgerCash, CurrencyCode: utils.USD, Amount: -500},
+		},
+	})
+	require.ErrorIs(t, err, ErrAccountBlocked)
+}
+
+func TestListTransferEntryCounts(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountInCurrency(t, account1.CurrencyCode)
+
+	result, err := store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	counts, err := testQueries.ListTransferEntryCounts(context.Background(), ListTransferEntryCountsParams{
+		AfterID:   result.Transfer.ID - 1,
+		BatchSize: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	require.Equal(t, int64(1), counts[0].FromEntries)
+	require.Equal(t, int64(1), counts[0].ToEntries)
+	require.Equal(t, int64(2), counts[0].TotalEntries)
+}
@@ -0,0 +1,393 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// UserTierStandard is the tier every user starts in
+const UserTierStandard = "standard"
+
+// Limits reported by LimitExceededError
+const (
+	LimitMaxAmount     = "max_amount"
+	LimitDailyAmount   = "daily_amount"
+	LimitMonthlyAmount = "monthly_amount"
+	LimitDailyCount    = "daily_count"
+	LimitMonthlyCount  = "monthly_count"
+	LimitSpend         = "spend_limit"
+)
+
+// Rolling windows the daily and monthly limits are enforced over
+const (
+	DailyLimitWindow   = 24 * time.Hour
+	MonthlyLimitWindow = 30 * 24 * time.Hour
+)
+
+// LimitExceededError tells which limit a transfer would break. ResetsAt is when
+// enough of the window has rolled over for the transfer to fit, it is nil when
+// waiting won't help.
+type LimitExceededError struct {
+	Limit     string     `json:"limit"`
+	Max       int64      `json:"max"`
+	Used      int64      `json:"used"`
+	Requested int64      `json:"requested"`
+	ResetsAt  *time.Time `json:"resets_at,omitempty"`
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("transfer limit %s exceeded: used %d of %d, requested %d", e.Limit, e.Used, e.Max, e.Requested)
+}
+
+// TransferLimits are the limits in force for an account. Each one comes from
+// the most specific row that sets it: the account's own, then the account's
+// product, the owner's tier in the account's currency, the tier, and finally
+// the currency.
+type TransferLimits struct {
+	MaxAmount     sql.NullInt64 `json:"max_amount"`
+	DailyAmount   sql.NullInt64 `json:"daily_amount"`
+	MonthlyAmount sql.NullInt64 `json:"monthly_amount"`
+	DailyCount    sql.NullInt64 `json:"daily_count"`
+	MonthlyCount  sql.NullInt64 `json:"monthly_count"`
+
+	// scopes are whose transfers each window limit counts, by limit name
+	scopes map[string]limitScope
+}
+
+// limitScope is whose transfers count against a limit. A limit set on the
+// account itself only counts the account's transfers. The others cap what the
+// owner sends from all their accounts in the account's currency, and product
+// for product limits, so opening more accounts doesn't raise them.
+type limitScope struct {
+	account     bool
+	productCode sql.NullString
+}
+
+// accountIDs lists the accounts whose transfers count against a limit of account
+func (scope limitScope) accountIDs(ctx context.Context, q *Queries, account Account) ([]int64, error) {
+	if scope.account {
+		return []int64{account.ID}, nil
+	}
+	return q.ListOwnerLimitAccounts(ctx, ListOwnerLimitAccountsParams{
+		Owner:        account.Owner,
+		CurrencyCode: account.CurrencyCode,
+		ProductCode:  scope.productCode,
+	})
+}
+
+// scope returns whose transfers the limit counts, limits nobody set count the account's own
+func (limits TransferLimits) scope(limit string) limitScope {
+	scope, ok := limits.scopes[limit]
+	if !ok {
+		return limitScope{account: true}
+	}
+	return scope
+}
+
+// AccountLimits are an account's limits along with what it used of them
+type AccountLimits struct {
+	Limits  TransferLimits               `json:"limits"`
+	Daily   GetOutgoingTransferTotalsRow `json:"daily"`
+	Monthly GetOutgoingTransferTotalsRow `json:"monthly"`
+}
+
+// GetAccountLimits returns the limits in force for an account and its usage of
+// the rolling windows. The usage counts the transfers the window's amount limit
+// counts, or its count limit when only that is set.
+func (store *SQLStore) GetAccountLimits(ctx context.Context, accountID int64) (AccountLimits, error) {
+	var result AccountLimits
+
+	account, err := store.GetAccount(ctx, accountID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Limits, err = accountTransferLimits(ctx, store.Queries, account)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	for _, w := range []struct {
+		usage       *GetOutgoingTransferTotalsRow
+		window      time.Duration
+		amountLimit string
+		amount      sql.NullInt64
+		countLimit  string
+	}{
+		{&result.Daily, DailyLimitWindow, LimitDailyAmount, result.Limits.DailyAmount, LimitDailyCount},
+		{&result.Monthly, MonthlyLimitWindow, LimitMonthlyAmount, result.Limits.MonthlyAmount, LimitMonthlyCount},
+	} {
+		scope := result.Limits.scope(w.countLimit)
+		if w.amount.Valid {
+			scope = result.Limits.scope(w.amountLimit)
+		}
+
+		accountIDs, err := scope.accountIDs(ctx, store.Queries, account)
+		if err != nil {
+			return result, err
+		}
+
+		*w.usage, err = store.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			AccountIds: accountIDs,
+			Since:      now.Add(-w.window),
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func accountTransferLimits(ctx context.Context, q *Queries, account Account) (TransferLimits, error) {
+	limits := TransferLimits{scopes: map[string]limitScope{}}
+
+	tier, err := q.GetAccountOwnerTier(ctx, account.ID)
+	if err != nil {
+		return limits, err
+	}
+
+	rows, err := q.ListApplicableTransferLimits(ctx, ListApplicableTransferLimitsParams{
+		AccountID:    sql.NullInt64{Int64: account.ID, Valid: true},
+		ProductCode:  sql.NullString{String: account.ProductCode, Valid: true},
+		Tier:         sql.NullString{String: tier, Valid: true},
+		CurrencyCode: sql.NullString{String: account.CurrencyCode, Valid: true},
+	})
+	if err != nil {
+		return limits, err
+	}
+
+	// rows come most specific first, so the first value set for a limit wins
+	for _, row := range rows {
+		scope := limitScope{account: row.AccountID.Valid, productCode: row.ProductCode}
+		for _, field := range []struct {
+			name  string
+			limit *sql.NullInt64
+			value sql.NullInt64
+		}{
+			{LimitMaxAmount, &limits.MaxAmount, row.MaxAmount},
+			{LimitDailyAmount, &limits.DailyAmount, row.DailyAmount},
+			{LimitMonthlyAmount, &limits.MonthlyAmount, row.MonthlyAmount},
+			{LimitDailyCount, &limits.DailyCount, row.DailyCount},
+			{LimitMonthlyCount, &limits.MonthlyCount, row.MonthlyCount},
+		} {
+			if !field.limit.Valid && field.value.Valid {
+				*field.limit = field.value
+				limits.scopes[field.name] = scope
+			}
+		}
+	}
+
+	return limits, nil
+}
+
+// checkTransferLimits refuses a transfer that would break one of the source
+// account's limits. The accounts must already be locked, and the owner is
+// locked here when a limit counts their other accounts too, so concurrent
+// transfers counted against the same limit are counted one after the other.
+func checkTransferLimits(ctx context.Context, q *Queries, arg TransferTxnParams, now time.Time) error {
+	account, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	limits, err := accountTransferLimits(ctx, q, account)
+	if err != nil {
+		return err
+	}
+
+	if limits.MaxAmount.Valid && arg.Amount > limits.MaxAmount.Int64 {
+		return &LimitExceededError{
+			Limit:     LimitMaxAmount,
+			Max:       limits.MaxAmount.Int64,
+			Requested: arg.Amount,
+		}
+	}
+
+	windowLimits := []struct {
+		name   string
+		window time.Duration
+		max    sql.NullInt64
+		count  bool
+	}{
+		{LimitDailyCount, DailyLimitWindow, limits.DailyCount, true},
+		{LimitDailyAmount, DailyLimitWindow, limits.DailyAmount, false},
+		{LimitMonthlyCount, MonthlyLimitWindow, limits.MonthlyCount, true},
+		{LimitMonthlyAmount, MonthlyLimitWindow, limits.MonthlyAmount, false},
+	}
+
+	ownerLocked := false
+	for _, w := range windowLimits {
+		if !w.max.Valid {
+			continue
+		}
+
+		scope := limits.scope(w.name)
+		if !scope.account && !ownerLocked {
+			if err := q.LockUser(ctx, account.Owner); err != nil {
+				return err
+			}
+			ownerLocked = true
+		}
+
+		accountIDs, err := scope.accountIDs(ctx, q, account)
+		if err != nil {
+			return err
+		}
+
+		since := now.Add(-w.window)
+		totals, err := q.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+			AccountIds: accountIDs,
+			Since:      since,
+		})
+		if err != nil {
+			return err
+		}
+
+		fits := func(count, total int64) bool {
+			if w.count {
+				return count+1 <= w.max.Int64
+			}
+			return total+arg.Amount <= w.max.Int64
+		}
+		if fits(totals.Count, totals.Total) {
+			continue
+		}
+
+		limitErr := &LimitExceededError{Limit: w.name, Max: w.max.Int64, Used: totals.Total, Requested: arg.Amount}
+		if w.count {
+			limitErr.Used, limitErr.Requested = totals.Count, 1
+		}
+
+		transfers, err := q.ListOutgoingTransfersSince(ctx, ListOutgoingTransfersSinceParams{
+			AccountIds: accountIDs,
+			Since:      since,
+		})
+		if err != nil {
+			return err
+		}
+
+		// money paid into escrow leaves the window like the transfers do
+		contracts, err := q.ListEscrowContractsFundedSince(ctx, ListEscrowContractsFundedSinceParams{
+			AccountIds: accountIDs,
+			Since:      since,
+		})
+		if err != nil {
+			return err
+		}
+		for _, contract := range contracts {
+			transfers = append(transfers, Transfer{Amount: contract.Amount, CreatedAt: contract.CreatedAt})
+		}
+		sort.SliceStable(transfers, func(i, j int) bool {
+			return transfers[i].CreatedAt.Before(transfers[j].CreatedAt)
+		})
+
+		limitErr.ResetsAt = windowResetAt(transfers, w.window, fits)
+		return limitErr
+	}
+
+	return nil
+}
+
+// checkSpendLimit refuses a transfer that would take a spender over what their
+// membership lets them send from the account in a rolling DailyLimitWindow.
+// Users without a spend limit, the owner among them, aren't limited. The
+// account must already be locked, so the spender's transfers from it are
+// counted one after the other.
+func checkSpendLimit(ctx context.Context, q *Queries, accountID int64, username string, amount int64, now time.Time) error {
+	if username == "" {
+		return nil
+	}
+
+	member, err := q.GetAccountMember(ctx, GetAccountMemberParams{AccountID: accountID, Username: username})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if !member.SpendLimit.Valid {
+		return nil
+	}
+
+	spends, err := q.ListMemberSpendsSince(ctx, ListMemberSpendsSinceParams{
+		AccountID: accountID,
+		Username:  username,
+		Since:     now.Add(-DailyLimitWindow),
+	})
+	if err != nil {
+		return err
+	}
+
+	transfers := make([]Transfer, 0, len(spends))
+	var used int64
+	for _, spend := range spends {
+		transfers = append(transfers, Transfer{Amount: spend.Amount, CreatedAt: spend.CreatedAt})
+		used += spend.Amount
+	}
+
+	fits := func(count, total int64) bool {
+		return total+amount <= member.SpendLimit.Int64
+	}
+	if fits(int64(len(spends)), used) {
+		return nil
+	}
+
+	return &LimitExceededError{
+		Limit:     LimitSpend,
+		Max:       member.SpendLimit.Int64,
+		Used:      used,
+		Requested: amount,
+		ResetsAt:  windowResetAt(transfers, DailyLimitWindow, fits),
+	}
+}
+
+// windowResetAt returns when enough of the oldest transfers have left the window
+// for fits to hold on what remains, or nil if it doesn't even hold on an empty window
+func windowResetAt(transfers []Transfer, window time.Duration, fits func(count, total int64) bool) *time.Time {
+	var total int64
+	for _, transfer := range transfers {
+		total += transfer.Amount
+	}
+
+	count := int64(len(transfers))
+	for _, transfer := range transfers {
+		count--
+		total -= transfer.Amount
+		if fits(count, total) {
+			resetsAt := transfer.CreatedAt.Add(window)
+			return &resetsAt
+		}
+	}
+	return nil
+}
+
+// checkOverdraft refuses a transfer that would take the source account further
+// below zero than its product allows. The accounts must already be locked.
+func checkOverdraft(ctx context.Context, q *Queries, arg TransferTxnParams) error {
+	account, err := q.GetAccount(ctx, arg.FromAccountID)
+	if err != nil {
+		return err
+	}
+
+	product, err := q.GetAccountProduct(ctx, account.ProductCode)
+	if err != nil {
+		return err
+	}
+
+	// pots share their parent's product but only hold money set aside, they
+	// never go below zero
+	limit := product.OverdraftLimit
+	if account.ParentAccountID.Valid {
+		limit = 0
+	}
+
+	if account.Balance-arg.Amount < -limit {
+		return fmt.Errorf("%w: account %d can go %d below zero", ErrInsufficientFunds, account.ID, limit)
+	}
+	return nil
+}
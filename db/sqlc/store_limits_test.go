@@ -0,0 +1,263 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferLimitsDailyCount(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccountInCurrency(t, utils.USD)
+	account2 := createRandomAccountInCurrency(t, utils.USD)
+
+	_, err := testQueries.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		AccountID:  sql.NullInt64{Int64: account1.ID, Valid: true},
+		DailyCount: sql.NullInt64{Int64: 2, Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg := TransferTxnParams{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 1}
+
+	first, err := store.PerformTransactionTrxn(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = store.PerformTransactionTrxn(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = store.PerformTransactionTrxn(context.Background(), arg)
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDailyCount, limitErr.Limit)
+	require.Equal(t, int64(2), limitErr.Used)
+	require.NotNil(t, limitErr.ResetsAt)
+	require.WithinDuration(t, first.Transfer.CreatedAt.Add(DailyLimitWindow), *limitErr.ResetsAt, time.Second)
+
+	// the refused transfer moved nothing
+	account1After, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-2, account1After.Balance)
+}
+
+func TestTransferLimitsPrecedence(t *testing.T) {
+	store := NewStore(db)
+	account1 := createRandomAccountInCurrency(t, utils.GBP)
+	account2 := createRandomAccountInCurrency(t, utils.GBP)
+
+	tier := "t" + utils.RandomString(10)
+	_, err := testQueries.SetUserTier(context.Background(), SetUserTierParams{Username: account1.Owner, Tier: tier})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Tier:        sql.NullString{String: tier, Valid: true},
+		MaxAmount:   sql.NullInt64{Int64: 10, Valid: true},
+		DailyAmount: sql.NullInt64{Int64: 25, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// the account's own max wins over the tier's, its daily amount still comes from the tier
+	_, err = testQueries.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		MaxAmount: sql.NullInt64{Int64: 20, Valid: true},
+	})
+	require.NoError(t, err)
+
+	limits, err := store.GetAccountLimits(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), limits.Limits.MaxAmount.Int64)
+	require.Equal(t, int64(25), limits.Limits.DailyAmount.Int64)
+	require.False(t, limits.Limits.MonthlyAmount.Valid)
+
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        21,
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitMaxAmount, limitErr.Limit)
+	require.Nil(t, limitErr.ResetsAt)
+
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        15,
+	})
+	require.NoError(t, err)
+
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        15,
+	})
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(15), limitErr.Used)
+	require.NotNil(t, limitErr.ResetsAt)
+}
+
+func TestTransferLimitsCountOwnersAccounts(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+	payee := createRandomAccountInCurrency(t, utils.USD)
+
+	tier := "t" + utils.RandomString(10)
+	_, err := testQueries.SetUserTier(context.Background(), SetUserTierParams{Username: user.Username, Tier: tier})
+	require.NoError(t, err)
+
+	_, err = testQueries.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		Tier:        sql.NullString{String: tier, Valid: true},
+		DailyAmount: sql.NullInt64{Int64: 25, Valid: true},
+	})
+	require.NoError(t, err)
+
+	accounts := make([]Account, 0, 3)
+	for _, product := range []string{AccountProductChecking, AccountProductSavings, AccountProductSavings} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:        user.Username,
+			Balance:      100,
+			CurrencyCode: utils.USD,
+			ProductCode:  product,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: accounts[0].ID,
+		ToAccountID:   payee.ID,
+		Amount:        15,
+	})
+	require.NoError(t, err)
+
+	// the tier limit caps the user, another account doesn't come with its own allowance
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: accounts[1].ID,
+		ToAccountID:   payee.ID,
+		Amount:        15,
+	})
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitDailyAmount, limitErr.Limit)
+	require.Equal(t, int64(15), limitErr.Used)
+	require.NotNil(t, limitErr.ResetsAt)
+
+	limits, err := store.GetAccountLimits(context.Background(), accounts[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(15), limits.Daily.Total)
+
+	// an account's own limit only counts the account's transfers
+	_, err = testQueries.UpsertTransferLimit(context.Background(), UpsertTransferLimitParams{
+		AccountID:   sql.NullInt64{Int64: accounts[2].ID, Valid: true},
+		DailyAmount: sql.NullInt64{Int64: 20, Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: accounts[2].ID,
+		ToAccountID:   payee.ID,
+		Amount:        15,
+	})
+	require.NoError(t, err)
+}
+
+func TestSpendLimitWindow(t *testing.T) {
+	store := NewStore(db)
+	account := createRandomAccountInCurrency(t, utils.USD)
+	payee := createRandomAccountInCurrency(t, utils.USD)
+	spender := createRandomUser(t)
+
+	_, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID:  account.ID,
+		Username:   spender.Username,
+		Role:       "spender",
+		SpendLimit: sql.NullInt64{Int64: 10, Valid: true},
+		InvitedBy:  account.Owner,
+	})
+	require.NoError(t, err)
+
+	arg := TransferTxnParams{FromAccountID: account.ID, ToAccountID: payee.ID, Amount: 6, InitiatedBy: spender.Username}
+
+	first, err := store.PerformTransactionTrxn(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, spender.Username, first.Transfer.InitiatedBy.String)
+
+	// a second transfer under the limit on its own still can't take the spender past it
+	_, err = store.PerformTransactionTrxn(context.Background(), arg)
+	var limitErr *LimitExceededError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, LimitSpend, limitErr.Limit)
+	require.Equal(t, int64(6), limitErr.Used)
+	require.NotNil(t, limitErr.ResetsAt)
+	require.WithinDuration(t, first.Transfer.CreatedAt.Add(DailyLimitWindow), *limitErr.ResetsAt, time.Second)
+
+	// the owner's transfers don't count against the spender, nor are they limited
+	arg.InitiatedBy = account.Owner
+	_, err = store.PerformTransactionTrxn(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = store.PerformTransactionTrxn(context.Background(), arg)
+	require.NoError(t, err)
+}
+
+func TestWindowResetAt(t *testing.T) {
+	start := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	transfers := []Transfer{
+		{Amount: 40, CreatedAt: start},
+		{Amount: 30, CreatedAt: start.Add(time.Hour)},
+		{Amount: 20, CreatedAt: start.Add(2 * time.Hour)},
+	}
+
+	// 60 more only fits under 100 once the first two transfers have rolled out
+	resetsAt := windowResetAt(transfers, DailyLimitWindow, func(count, total int64) bool {
+		return total+60 <= 100
+	})
+	require.Equal(t, start.Add(time.Hour).Add(DailyLimitWindow), *resetsAt)
+
+	resetsAt = windowResetAt(transfers, DailyLimitWindow, func(count, total int64) bool {
+		return total+150 <= 100
+	})
+	require.Nil(t, resetsAt)
+}
+
+func TestProductRules(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+
+	// products replaced the one account per currency rule
+	accounts := make([]Account, 0, 2)
+	for _, product := range []string{AccountProductSavings, AccountProductEscrow} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:        user.Username,
+			Balance:      100,
+			CurrencyCode: utils.EUR,
+			ProductCode:  product,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+	savings, escrow := accounts[0], accounts[1]
+
+	limits, err := store.GetAccountLimits(context.Background(), savings.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), limits.Limits.MonthlyCount.Int64)
+
+	// neither product can be overdrawn
+	_, err = store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: escrow.ID,
+		ToAccountID:   savings.ID,
+		Amount:        101,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	result, err := store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: escrow.ID,
+		ToAccountID:   savings.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.FromAccount.Balance)
+}
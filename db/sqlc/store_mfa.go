@@ -0,0 +1,75 @@
+package db
+
+import "context"
+
+// ReplaceRecoveryCodesTxnParams contains the input parameters of the recovery code transaction.
+type ReplaceRecoveryCodesTxnParams struct {
+	Username    string   `json:"username"`
+	HashedCodes []string `json:"hashed_codes"`
+	// EnableTOTP switches two-factor authentication on in the same transaction
+	EnableTOTP bool `json:"enable_totp"`
+}
+
+// ReplaceRecoveryCodesTrxn drops every recovery code of a user and stores the new set.
+// It optionally enables TOTP so confirming enrollment and issuing codes happen atomically.
+func (store *SQLStore) ReplaceRecoveryCodesTrxn(ctx context.Context, arg ReplaceRecoveryCodesTxnParams) (User, error) {
+	var user User
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		user, err = replaceRecoveryCodes(ctx, q, arg)
+		return err
+	})
+
+	return user, err
+}
+
+func replaceRecoveryCodes(ctx context.Context, q *Queries, arg ReplaceRecoveryCodesTxnParams) (User, error) {
+	var user User
+	var err error
+
+	if arg.EnableTOTP {
+		user, err = q.EnableUserTOTP(ctx, arg.Username)
+	} else {
+		user, err = q.GetUser(ctx, arg.Username)
+	}
+	if err != nil {
+		return user, err
+	}
+
+	if err = q.DeleteRecoveryCodes(ctx, arg.Username); err != nil {
+		return user, err
+	}
+
+	for _, hashedCode := range arg.HashedCodes {
+		_, err = q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+			Username:   arg.Username,
+			HashedCode: hashedCode,
+		})
+		if err != nil {
+			return user, err
+		}
+	}
+	return user, nil
+}
+
+// DisableMFATrxn turns two-factor authentication off and removes the user's recovery codes.
+func (store *SQLStore) DisableMFATrxn(ctx context.Context, username string) (User, error) {
+	var user User
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		user, err = disableMFA(ctx, q, username)
+		return err
+	})
+
+	return user, err
+}
+
+func disableMFA(ctx context.Context, q *Queries, username string) (User, error) {
+	user, err := q.DisableUserTOTP(ctx, username)
+	if err != nil {
+		return user, err
+	}
+	return user, q.DeleteRecoveryCodes(ctx, username)
+}
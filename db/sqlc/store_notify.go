@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AccountEventsChannel is the Postgres channel account changes are announced on
+const AccountEventsChannel = "account_events"
+
+// AccountNotification is the NOTIFY payload sent whenever an entry changes an account's balance
+type AccountNotification struct {
+	AccountID int64   `json:"account_id"`
+	Account   Account `json:"account"`
+	Entry     Entry   `json:"entry"`
+}
+
+// notifyAccountChange announces a new entry on the account. Postgres holds the
+// notification until the surrounding transaction commits and drops it on rollback,
+// so listeners never see money that didn't move.
+func notifyAccountChange(ctx context.Context, q *Queries, account Account, entry Entry) error {
+	payload, err := json.Marshal(AccountNotification{
+		AccountID: account.ID,
+		Account:   account,
+		Entry:     entry,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.NotifyAccountEvent(ctx, NotifyAccountEventParams{
+		Channel: AccountEventsChannel,
+		Payload: string(payload),
+	})
+}
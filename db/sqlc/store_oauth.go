@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// RotateRefreshTokenTxnParams contains the input parameters of the refresh token rotation.
+type RotateRefreshTokenTxnParams struct {
+	TokenHash    string    `json:"token_hash"`
+	NewTokenHash string    `json:"new_token_hash"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// RotateRefreshTokenTrxn revokes a refresh token and issues its replacement with the
+// same grant. A token that was already revoked fails with sql.ErrNoRows so a replayed
+// refresh token can't be exchanged twice.
+func (store *SQLStore) RotateRefreshTokenTrxn(ctx context.Context, arg RotateRefreshTokenTxnParams) (OauthRefreshToken, error) {
+	var refreshToken OauthRefreshToken
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		refreshToken, err = rotateRefreshToken(ctx, q, arg)
+		return err
+	})
+
+	return refreshToken, err
+}
+
+func rotateRefreshToken(ctx context.Context, q *Queries, arg RotateRefreshTokenTxnParams) (OauthRefreshToken, error) {
+	old, err := q.RevokeRefreshToken(ctx, arg.TokenHash)
+	if err != nil {
+		return OauthRefreshToken{}, err
+	}
+
+	return q.CreateRefreshToken(ctx, CreateRefreshTokenParams{
+		TokenHash:  arg.NewTokenHash,
+		ClientID:   old.ClientID,
+		Username:   old.Username,
+		Scopes:     old.Scopes,
+		AccountIds: old.AccountIds,
+		ExpiresAt:  arg.ExpiresAt,
+	})
+}
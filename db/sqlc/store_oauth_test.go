@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateRefreshTokenTrxn(t *testing.T) {
+	store := NewStore(db)
+	user := createRandomUser(t)
+
+	client, err := testQueries.CreateOAuthClient(context.Background(), CreateOAuthClientParams{
+		ClientID:     uuid.NewString(),
+		Owner:        user.Username,
+		Name:         utils.RandomString(6),
+		RedirectUris: []string{"https://partner.example.com/callback"},
+		Scopes:       []string{"accounts:read"},
+	})
+	require.NoError(t, err)
+
+	refreshToken, err := testQueries.CreateRefreshToken(context.Background(), CreateRefreshTokenParams{
+		TokenHash:  utils.HashOpaqueToken(utils.RandomString(32)),
+		ClientID:   client.ClientID,
+		Username:   user.Username,
+		Scopes:     client.Scopes,
+		AccountIds: []int64{1, 2},
+		ExpiresAt:  time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	arg := RotateRefreshTokenTxnParams{
+		TokenHash:    refreshToken.TokenHash,
+		NewTokenHash: utils.HashOpaqueToken(utils.RandomString(32)),
+		ExpiresAt:    time.Now().Add(2 * time.Hour),
+	}
+
+	rotated, err := store.RotateRefreshTokenTrxn(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.NewTokenHash, rotated.TokenHash)
+	require.Equal(t, refreshToken.AccountIds, rotated.AccountIds)
+	require.Equal(t, refreshToken.Scopes, rotated.Scopes)
+
+	old, err := testQueries.GetRefreshToken(context.Background(), refreshToken.TokenHash)
+	require.NoError(t, err)
+	require.True(t, old.RevokedAt.Valid)
+
+	// a revoked token can't be rotated again
+	arg.NewTokenHash = utils.HashOpaqueToken(utils.RandomString(32))
+	_, err = store.RotateRefreshTokenTrxn(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Event types written to the outbox
+const (
+	EventTransferCompleted = "transfer.completed"
+	EventAccountCredited   = "account.credited"
+	EventAccountDebited    = "account.debited"
+
+	EventPaymentRequestCreated   = "payment_request.created"
+	EventPaymentRequestPaid      = "payment_request.paid"
+	EventPaymentRequestDeclined  = "payment_request.declined"
+	EventPaymentRequestCancelled = "payment_request.cancelled"
+	EventPaymentRequestExpired   = "payment_request.expired"
+
+	EventInvoicePaymentReceived = "invoice.payment_received"
+	EventInvoicePaid            = "invoice.paid"
+	EventInvoiceOverdue         = "invoice.overdue"
+
+	EventEscrowFunded   = "escrow.funded"
+	EventEscrowDisputed = "escrow.disputed"
+	EventEscrowReleased = "escrow.released"
+	EventEscrowRefunded = "escrow.refunded"
+)
+
+// EventTypes lists every event type webhook endpoints can subscribe to
+var EventTypes = []string{
+	EventTransferCompleted,
+	EventAccountCredited,
+	EventAccountDebited,
+	EventPaymentRequestCreated,
+	EventPaymentRequestPaid,
+	EventPaymentRequestDeclined,
+	EventPaymentRequestCancelled,
+	EventPaymentRequestExpired,
+	EventInvoicePaymentReceived,
+	EventInvoicePaid,
+	EventInvoiceOverdue,
+	EventEscrowFunded,
+	EventEscrowDisputed,
+	EventEscrowReleased,
+	EventEscrowRefunded,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// AccountEvent is the payload of account.credited and account.debited events
+type AccountEvent struct {
+	AccountID    int64  `json:"account_id"`
+	TransferID   int64  `json:"transfer_id"`
+	Amount       int64  `json:"amount"`
+	Balance      int64  `json:"balance"`
+	CurrencyCode string `json:"currency_code"`
+}
+
+// writeTransferEvents adds the events of a completed transfer to the outbox.
+// It runs inside the transfer's transaction so events exist if and only if the
+// transfer was committed.
+func writeTransferEvents(ctx context.Context, q *Queries, result TransferTrxResult) error {
+	transferID := strconv.FormatInt(result.Transfer.ID, 10)
+
+	err := writeOutboxEvent(ctx, q, EventTransferCompleted, result.FromAccount.Owner, "transfer", transferID, result.Transfer)
+	if err != nil {
+		return err
+	}
+
+	err = writeOutboxEvent(ctx, q, EventAccountDebited, result.FromAccount.Owner, "account", strconv.FormatInt(result.FromAccount.ID, 10), AccountEvent{
+		AccountID:    result.FromAccount.ID,
+		TransferID:   result.Transfer.ID,
+		Amount:       result.FromEntry.Amount,
+		Balance:      result.FromAccount.Balance,
+		CurrencyCode: result.FromAccount.CurrencyCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeOutboxEvent(ctx, q, EventAccountCredited, result.ToAccount.Owner, "account", strconv.FormatInt(result.ToAccount.ID, 10), AccountEvent{
+		AccountID:    result.ToAccount.ID,
+		TransferID:   result.Transfer.ID,
+		Amount:       result.ToEntry.Amount,
+		Balance:      result.ToAccount.Balance,
+		CurrencyCode: result.ToAccount.CurrencyCode,
+	})
+}
+
+func writeOutboxEvent(ctx context.Context, q *Queries, eventType, owner, aggregateType, aggregateID string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType:     eventType,
+		Owner:         owner,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+	})
+	return err
+}
+
+// DispatchOutboxTrxn fans undispatched outbox events out into one delivery per
+// subscribed webhook endpoint. Deliveries are unique per event and endpoint and
+// the event is only marked dispatched in the same transaction, so a crash at
+// any point neither loses nor duplicates a delivery.
+func (store *SQLStore) DispatchOutboxTrxn(ctx context.Context, batchSize int32) (int, error) {
+	var dispatched int
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		events, err := q.ClaimOutboxEvents(ctx, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			endpoints, err := q.ListSubscribedWebhookEndpoints(ctx, ListSubscribedWebhookEndpointsParams{
+				Owner:     event.Owner,
+				EventType: event.EventType,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, endpoint := range endpoints {
+				err = q.CreateWebhookDelivery(ctx, CreateWebhookDeliveryParams{
+					EventID:    event.ID,
+					EndpointID: endpoint.ID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if err = q.MarkOutboxEventDispatched(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+
+		dispatched = len(events)
+		return nil
+	})
+
+	return dispatched, err
+}
+
+// RecordWebhookAttemptTxnParams contains the outcome of one delivery attempt
+type RecordWebhookAttemptTxnParams struct {
+	DeliveryID     int64         `json:"delivery_id"`
+	ResponseStatus int32         `json:"response_status"`
+	Error          string        `json:"error"`
+	Duration       time.Duration `json:"duration"`
+	Status         string        `json:"status"`
+	Attempts       int32         `json:"attempts"`
+	NextAttemptAt  time.Time     `json:"next_attempt_at"`
+	DeliveredAt    sql.NullTime  `json:"delivered_at"`
+}
+
+// RecordWebhookAttemptTrxn logs a delivery attempt and moves the delivery to its next state
+func (store *SQLStore) RecordWebhookAttemptTrxn(ctx context.Context, arg RecordWebhookAttemptTxnParams) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		_, err := q.CreateWebhookAttempt(ctx, CreateWebhookAttemptParams{
+			DeliveryID:     arg.DeliveryID,
+			ResponseStatus: arg.ResponseStatus,
+			Error:          arg.Error,
+			DurationMs:     arg.Duration.Milliseconds(),
+		})
+		if err != nil {
+			return err
+		}
+
+		delivery, err = q.UpdateWebhookDelivery(ctx, UpdateWebhookDeliveryParams{
+			ID:            arg.DeliveryID,
+			Status:        arg.Status,
+			Attempts:      arg.Attempts,
+			NextAttemptAt: arg.NextAttemptAt,
+			LastError:     arg.Error,
+			DeliveredAt:   arg.DeliveredAt,
+		})
+		return err
+	})
+
+	return delivery, err
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferWritesOutboxEvents(t *testing.T) {
+	store := NewStore(db)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccountInCurrency(t, fromAccount.CurrencyCode)
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), CreateWebhookEndpointParams{
+		Owner:      toAccount.Owner,
+		Url:        "https://hooks.example.com/bank",
+		Secret:     "whsec_test",
+		EventTypes: []string{EventAccountCredited},
+	})
+	require.NoError(t, err)
+
+	result, err := store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	// drain the outbox, other tests may have left events behind
+	for {
+		dispatched, err := store.DispatchOutboxTrxn(context.Background(), 100)
+		require.NoError(t, err)
+		if dispatched == 0 {
+			break
+		}
+	}
+
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpoint.ID,
+		Limit:      10,
+		Offset:     0,
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, WebhookDeliveryPending, deliveries[0].Status)
+
+	event, err := testQueries.GetOutboxEvent(context.Background(), deliveries[0].EventID)
+	require.NoError(t, err)
+	require.Equal(t, EventAccountCredited, event.EventType)
+	require.Equal(t, toAccount.Owner, event.Owner)
+	require.True(t, event.DispatchedAt.Valid)
+	require.Contains(t, string(event.Payload), `"transfer_id":`)
+	require.NotZero(t, result.Transfer.ID)
+}
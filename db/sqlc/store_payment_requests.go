@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Payment request statuses. A request waits in pending until the payer pays or
+// declines it, the requester cancels it, or it expires.
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestPaid      = "paid"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+)
+
+var (
+	ErrPaymentRequestClosed   = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired  = errors.New("payment request has expired")
+	ErrPaymentRequestMismatch = errors.New("transfer doesn't match the payment request")
+)
+
+// CreatePaymentRequestTrxn records a payment request and tells the payer about it
+func (store *SQLStore) CreatePaymentRequestTrxn(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	var request PaymentRequest
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		request, err = openPaymentRequest(ctx, q, arg)
+		return err
+	})
+
+	return request, err
+}
+
+func openPaymentRequest(ctx context.Context, q *Queries, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	request, err := q.CreatePaymentRequest(ctx, arg)
+	if err != nil {
+		return request, err
+	}
+	return request, writePaymentRequestEvent(ctx, q, EventPaymentRequestCreated, request.Payer, request)
+}
+
+// ClosePaymentRequestTxnParams contains the input parameters of the close
+// payment request transaction, Status is declined or cancelled
+type ClosePaymentRequestTxnParams struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+// ClosePaymentRequestTrxn declines or cancels a pending payment request and
+// tells the other party. Checking who may close it is up to the caller.
+func (store *SQLStore) ClosePaymentRequestTrxn(ctx context.Context, arg ClosePaymentRequestTxnParams) (PaymentRequest, error) {
+	var request PaymentRequest
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		request, err = endPaymentRequest(ctx, q, arg)
+		return err
+	})
+
+	return request, err
+}
+
+func endPaymentRequest(ctx context.Context, q *Queries, arg ClosePaymentRequestTxnParams) (PaymentRequest, error) {
+	request, err := q.GetPaymentRequestForUpdate(ctx, arg.ID)
+	if err != nil {
+		return request, err
+	}
+	if request.Status != PaymentRequestPending {
+		return request, ErrPaymentRequestClosed
+	}
+
+	request, err = q.ClosePaymentRequest(ctx, ClosePaymentRequestParams{
+		ID:     arg.ID,
+		Status: arg.Status,
+	})
+	if err != nil {
+		return request, err
+	}
+
+	if arg.Status == PaymentRequestCancelled {
+		return request, writePaymentRequestEvent(ctx, q, EventPaymentRequestCancelled, request.Payer, request)
+	}
+	return request, writePaymentRequestEvent(ctx, q, EventPaymentRequestDeclined, request.Requester, request)
+}
+
+// ExpirePaymentRequestsTrxn expires every overdue payment request and tells
+// both parties
+func (store *SQLStore) ExpirePaymentRequestsTrxn(ctx context.Context, now time.Time) ([]PaymentRequest, error) {
+	var expired []PaymentRequest
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		expired, err = expireOverduePaymentRequests(ctx, q, now)
+		return err
+	})
+
+	return expired, err
+}
+
+func expireOverduePaymentRequests(ctx context.Context, q *Queries, now time.Time) ([]PaymentRequest, error) {
+	expired, err := q.ExpirePaymentRequests(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, request := range expired {
+		for _, owner := range []string{request.Requester, request.Payer} {
+			if err = writePaymentRequestEvent(ctx, q, EventPaymentRequestExpired, owner, request); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return expired, nil
+}
+
+// settlePaymentRequest marks a payment request paid by a transfer made in the
+// same transaction. A request that was closed or expired in the meantime fails
+// the transfer, so money only moves for requests that are still open.
+func settlePaymentRequest(ctx context.Context, q *Queries, id int64, transfer TransferTrxResult, now time.Time) (PaymentRequest, error) {
+	request, err := q.GetPaymentRequestForUpdate(ctx, id)
+	if err != nil {
+		return request, err
+	}
+	if request.Status != PaymentRequestPending {
+		return request, ErrPaymentRequestClosed
+	}
+	if !now.Before(request.ExpiresAt) {
+		return request, ErrPaymentRequestExpired
+	}
+	if transfer.Transfer.ToAccountID != request.ToAccountID || transfer.Transfer.Amount != request.Amount {
+		return request, ErrPaymentRequestMismatch
+	}
+
+	request, err = q.ClosePaymentRequest(ctx, ClosePaymentRequestParams{
+		ID:         id,
+		Status:     PaymentRequestPaid,
+		TransferID: sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return request, err
+	}
+	return request, writePaymentRequestEvent(ctx, q, EventPaymentRequestPaid, request.Requester, request)
+}
+
+func writePaymentRequestEvent(ctx context.Context, q *Queries, eventType, owner string, request PaymentRequest) error {
+	return writeOutboxEvent(ctx, q, eventType, owner, "payment_request", strconv.FormatInt(request.ID, 10), request)
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentRequest(t *testing.T, store Store, payer Account, to Account, amount int64) PaymentRequest {
+	request, err := store.CreatePaymentRequestTrxn(context.Background(), CreatePaymentRequestParams{
+		Requester:    to.Owner,
+		Payer:        payer.Owner,
+		ToAccountID:  to.ID,
+		Amount:       amount,
+		CurrencyCode: to.CurrencyCode,
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, request.Status)
+	return request
+}
+
+func TestPayPaymentRequest(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	payer := createFundedAccount(t, 1000)
+	to := createRandomAccountInCurrency(t, utils.USD)
+	amount := utils.RandomInt(1, payer.Balance)
+
+	request := createRandomPaymentRequest(t, store, payer, to, amount)
+
+	// the transfer must match what was asked for
+	_, err := store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID:    payer.ID,
+		ToAccountID:      to.ID,
+		Amount:           amount + 1,
+		PaymentRequestID: request.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestMismatch)
+
+	result, err := store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID:    payer.ID,
+		ToAccountID:      to.ID,
+		Amount:           amount,
+		PaymentRequestID: request.ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.PaymentRequest)
+	require.Equal(t, PaymentRequestPaid, result.PaymentRequest.Status)
+	require.Equal(t, result.Transfer.ID, result.PaymentRequest.TransferID.Int64)
+	require.True(t, result.PaymentRequest.ClosedAt.Valid)
+
+	// a request is only paid once
+	_, err = store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID:    payer.ID,
+		ToAccountID:      to.ID,
+		Amount:           amount,
+		PaymentRequestID: request.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
+
+func TestClosePaymentRequest(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	payer := createRandomAccountInCurrency(t, utils.USD)
+	to := createRandomAccountInCurrency(t, utils.USD)
+	request := createRandomPaymentRequest(t, store, payer, to, 10)
+
+	declined, err := store.ClosePaymentRequestTrxn(ctx, ClosePaymentRequestTxnParams{
+		ID:     request.ID,
+		Status: PaymentRequestDeclined,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, declined.Status)
+
+	_, err = store.ClosePaymentRequestTrxn(ctx, ClosePaymentRequestTxnParams{
+		ID:     request.ID,
+		Status: PaymentRequestCancelled,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
+
+func TestExpirePaymentRequests(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	payer := createRandomAccountInCurrency(t, utils.USD)
+	to := createRandomAccountInCurrency(t, utils.USD)
+	request := createRandomPaymentRequest(t, store, payer, to, 10)
+
+	expired, err := store.ExpirePaymentRequestsTrxn(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+
+	var found bool
+	for _, e := range expired {
+		if e.ID == request.ID {
+			found = true
+			require.Equal(t, PaymentRequestExpired, e.Status)
+		}
+	}
+	require.True(t, found)
+
+	_, err = store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID:    payer.ID,
+		ToAccountID:      to.ID,
+		Amount:           10,
+		PaymentRequestID: request.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Pot move directions, money is deposited from the parent account into the pot
+// and withdrawn from the pot back to the parent
+const (
+	PotDeposit  = "deposit"
+	PotWithdraw = "withdraw"
+)
+
+var (
+	ErrPotParent   = errors.New("pots can't have pots of their own")
+	ErrPotTransfer = errors.New("money only leaves a pot back to its parent account")
+)
+
+// CreatePotTxnParams contains the input parameters of the create pot transaction
+type CreatePotTxnParams struct {
+	ParentAccountID int64         `json:"parent_account_id"`
+	Name            string        `json:"name"`
+	TargetAmount    sql.NullInt64 `json:"target_amount"`
+	TargetDate      sql.NullTime  `json:"target_date"`
+	RoundUpUnit     sql.NullInt64 `json:"round_up_unit"`
+	// AccountNumber is the external number of the pot's account
+	AccountNumber sql.NullString `json:"account_number"`
+}
+
+// CreatePotTxnResult is the result of the create pot transaction
+type CreatePotTxnResult struct {
+	Pot     Pot     `json:"pot"`
+	Account Account `json:"account"`
+}
+
+// CreatePotTrxn opens the account that holds a pot's money, in the parent's
+// currency and owned by the parent's owner, and the pot that describes it.
+func (store *SQLStore) CreatePotTrxn(ctx context.Context, arg CreatePotTxnParams) (CreatePotTxnResult, error) {
+	var result CreatePotTxnResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = openPot(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func openPot(ctx context.Context, q *Queries, arg CreatePotTxnParams) (CreatePotTxnResult, error) {
+	var result CreatePotTxnResult
+
+	parent, err := q.GetAccount(ctx, arg.ParentAccountID)
+	if err != nil {
+		return result, err
+	}
+	if parent.ParentAccountID.Valid {
+		return result, ErrPotParent
+	}
+
+	result.Account, err = q.CreatePotAccount(ctx, CreatePotAccountParams{
+		Owner:           parent.Owner,
+		CurrencyCode:    parent.CurrencyCode,
+		ProductCode:     parent.ProductCode,
+		ParentAccountID: sql.NullInt64{Int64: parent.ID, Valid: true},
+		AccountNumber:   arg.AccountNumber,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Pot, err = q.CreatePot(ctx, CreatePotParams{
+		AccountID:       result.Account.ID,
+		ParentAccountID: parent.ID,
+		Name:            arg.Name,
+		TargetAmount:    arg.TargetAmount,
+		TargetDate:      arg.TargetDate,
+		RoundUpUnit:     arg.RoundUpUnit,
+	})
+	return result, err
+}
+
+// MovePotTxnParams contains the input parameters of the move pot transaction
+type MovePotTxnParams struct {
+	PotID     int64  `json:"pot_id"`
+	Direction string `json:"direction"`
+	Amount    int64  `json:"amount"`
+}
+
+// MovePotTrxn moves money between a pot and its parent account. It is a
+// transfer like any other, but it doesn't count towards the parent's limits
+// and, unlike other transfers, can't overdraw either side.
+func (store *SQLStore) MovePotTrxn(ctx context.Context, arg MovePotTxnParams) (TransferTrxResult, error) {
+	var result TransferTrxResult
+
+	err := store.executeTrxn(ctx, func(q *Queries) error {
+		var err error
+		result, err = movePot(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func movePot(ctx context.Context, q *Queries, arg MovePotTxnParams) (TransferTrxResult, error) {
+	pot, err := q.GetPot(ctx, arg.PotID)
+	if err != nil {
+		return TransferTrxResult{}, err
+	}
+
+	transfer := TransferTxnParams{
+		FromAccountID: pot.ParentAccountID,
+		ToAccountID:   pot.AccountID,
+		Amount:        arg.Amount,
+	}
+	if arg.Direction == PotWithdraw {
+		transfer.FromAccountID, transfer.ToAccountID = pot.AccountID, pot.ParentAccountID
+	}
+
+	_, err = q.LockAccounts(ctx, []int64{transfer.FromAccountID, transfer.ToAccountID})
+	if err != nil {
+		return TransferTrxResult{}, err
+	}
+
+	from, err := q.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return TransferTrxResult{}, err
+	}
+	if from.Balance < arg.Amount {
+		return TransferTrxResult{}, ErrInsufficientFunds
+	}
+
+	return performTransfer(ctx, q, transfer)
+}
+
+// isPotMove reports whether a transfer is between an account and one of its pots
+func isPotMove(ctx context.Context, q *Queries, arg TransferTxnParams) (bool, error) {
+	for _, ids := range [][2]int64{
+		{arg.ToAccountID, arg.FromAccountID},
+		{arg.FromAccountID, arg.ToAccountID},
+	} {
+		pot, err := q.GetPot(ctx, ids[0])
+		switch {
+		case err == nil:
+			if pot.ParentAccountID == ids[1] {
+				return true, nil
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// checkPotSource refuses to let money leave a pot account other than through a
+// pot move back to its parent
+func checkPotSource(ctx context.Context, q *Queries, accountID int64) error {
+	_, err := q.GetPot(ctx, accountID)
+	switch {
+	case err == nil:
+		return ErrPotTransfer
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	}
+	return err
+}
+
+// RoundUpAmount is what rounding amount up to the next multiple of unit adds,
+// nothing when it is already a multiple
+func RoundUpAmount(amount, unit int64) int64 {
+	if unit <= 0 || amount%unit == 0 {
+		return 0
+	}
+	return unit - amount%unit
+}
+
+// sweepRoundUp moves the spare change of a completed transfer into the round-up
+// pot. Nothing is swept when there is no spare change, the transfer went into
+// the pot anyway or the source account can't cover it. The accounts must
+// already be locked.
+func sweepRoundUp(ctx context.Context, q *Queries, transfer TransferTrxResult, pot Pot) (*TransferTrxResult, error) {
+	amount := RoundUpAmount(transfer.Transfer.Amount, pot.RoundUpUnit.Int64)
+	if amount == 0 || transfer.Transfer.ToAccountID == pot.AccountID || transfer.FromAccount.Balance < amount {
+		return nil, nil
+	}
+
+	sweep, err := postTransfer(ctx, q, TransferTxnParams{
+		FromAccountID: transfer.Transfer.FromAccountID,
+		ToAccountID:   pot.AccountID,
+		Amount:        amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sweep, nil
+}
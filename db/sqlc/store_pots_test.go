@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundUpAmount(t *testing.T) {
+	require.Equal(t, int64(0), RoundUpAmount(300, 100))
+	require.Equal(t, int64(55), RoundUpAmount(245, 100))
+	require.Equal(t, int64(0), RoundUpAmount(245, 0))
+}
+
+func TestPots(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	parent, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:        user.Username,
+		Balance:      1000,
+		CurrencyCode: utils.USD,
+		ProductCode:  AccountProductChecking,
+	})
+	require.NoError(t, err)
+	payee := createRandomAccountInCurrency(t, utils.USD)
+
+	created, err := store.CreatePotTrxn(ctx, CreatePotTxnParams{
+		ParentAccountID: parent.ID,
+		Name:            "holiday",
+		TargetAmount:    sql.NullInt64{Int64: 5000, Valid: true},
+		RoundUpUnit:     sql.NullInt64{Int64: 100, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, parent.Owner, created.Account.Owner)
+	require.Equal(t, parent.CurrencyCode, created.Account.CurrencyCode)
+	require.Equal(t, parent.ID, created.Account.ParentAccountID.Int64)
+	require.Zero(t, created.Account.Balance)
+
+	_, err = store.CreatePotTrxn(ctx, CreatePotTxnParams{ParentAccountID: created.Account.ID, Name: "nested"})
+	require.ErrorIs(t, err, ErrPotParent)
+
+	_, err = store.MovePotTrxn(ctx, MovePotTxnParams{PotID: created.Pot.AccountID, Direction: PotWithdraw, Amount: 1})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	moved, err := store.MovePotTrxn(ctx, MovePotTxnParams{PotID: created.Pot.AccountID, Direction: PotDeposit, Amount: 300})
+	require.NoError(t, err)
+	require.Equal(t, int64(700), moved.FromAccount.Balance)
+	require.Equal(t, int64(300), moved.ToAccount.Balance)
+	require.Nil(t, moved.RoundUp)
+
+	// the spare change of 245 sent to the payee is swept into the pot
+	result, err := store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID: parent.ID,
+		ToAccountID:   payee.ID,
+		Amount:        245,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.RoundUp)
+	require.Equal(t, int64(55), result.RoundUp.Transfer.Amount)
+	require.Equal(t, int64(355), result.RoundUp.ToAccount.Balance)
+	require.Equal(t, int64(400), result.FromAccount.Balance)
+
+	// moves to and from pots don't count towards the limits
+	totals, err := testQueries.GetOutgoingTransferTotals(ctx, GetOutgoingTransferTotalsParams{
+		AccountIds: []int64{parent.ID},
+		Since:      moved.Transfer.CreatedAt.Add(-DailyLimitWindow),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), totals.Count)
+	require.Equal(t, int64(245), totals.Total)
+
+	pots, err := testQueries.ListPots(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Len(t, pots, 1)
+	require.Equal(t, int64(355), pots[0].Balance)
+}
+
+func TestPotsCantBeOverdrawn(t *testing.T) {
+	store := NewStore(db)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	parent, err := testQueries.CreateAccount(ctx, CreateAccountParams{
+		Owner:        user.Username,
+		Balance:      1000,
+		CurrencyCode: utils.USD,
+		ProductCode:  AccountProductChecking,
+	})
+	require.NoError(t, err)
+	payee := createRandomAccountInCurrency(t, utils.USD)
+
+	created, err := store.CreatePotTrxn(ctx, CreatePotTxnParams{ParentAccountID: parent.ID, Name: "rainy day"})
+	require.NoError(t, err)
+
+	_, err = store.MovePotTrxn(ctx, MovePotTxnParams{PotID: created.Pot.AccountID, Direction: PotDeposit, Amount: 100})
+	require.NoError(t, err)
+
+	// the pot shares the parent's checking product but not its overdraft
+	_, err = store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID: created.Account.ID,
+		ToAccountID:   parent.ID,
+		Amount:        200,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// and nothing but its parent can be paid from it
+	_, err = store.PerformTransactionTrxn(ctx, TransferTxnParams{
+		FromAccountID: created.Account.ID,
+		ToAccountID:   payee.ID,
+		Amount:        50,
+	})
+	require.ErrorIs(t, err, ErrPotTransfer)
+
+	account, err := testQueries.GetAccount(ctx, created.Account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// BalanceAtParams contains the input parameters of a point-in-time balance lookup
+type BalanceAtParams struct {
+	AccountID int64     `json:"account_id"`
+	At        time.Time `json:"at"`
+}
+
+// GetBalanceAt returns an account's balance at a point in time, including entries
+// created at exactly that time. It starts from the nearest snapshot at or before
+// it and adds the entries created since, so the cost doesn't grow with the
+// account's history.
+func (store *SQLStore) GetBalanceAt(ctx context.Context, arg BalanceAtParams) (int64, error) {
+	var balance int64
+	var from time.Time
+
+	snapshot, err := store.GetLatestBalanceSnapshot(ctx, GetLatestBalanceSnapshotParams{
+		AccountID: arg.AccountID,
+		At:        arg.At,
+	})
+	if err == nil {
+		balance, from = snapshot.Balance, snapshot.AsOf
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	delta, err := store.SumEntriesBetween(ctx, SumEntriesBetweenParams{
+		AccountID: arg.AccountID,
+		FromTime:  from,
+		ToTime:    arg.At,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return balance + delta, nil
+}
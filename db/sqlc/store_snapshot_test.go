@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBalanceAtMatchesReplay(t *testing.T) {
+	store := NewStore(db)
+	account := createRandomAccountInCurrency(t, utils.USD)
+
+	// checkpoints sit between journals, snapshots are taken at some of them
+	var checkpoints []time.Time
+	for i := 0; i < 6; i++ {
+		amount := utils.RandomInt(1, 100)
+		_, err := store.PostJournalTrxn(context.Background(), PostJournalTxnParams{
+			Description: "cash deposit",
+			Postings: []PostingParams{
+				{AccountID: account.ID, Amount: amount},
+				{LedgerCode: LedgerCash, CurrencyCode: utils.USD, Amount: -amount},
+			},
+		})
+		require.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+		checkpoints = append(checkpoints, time.Now())
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for _, i := range []int{1, 3} {
+		err := testQueries.CreateBalanceSnapshots(context.Background(), CreateBalanceSnapshotsParams{
+			AsOf:       checkpoints[i],
+			AccountIds: []int64{account.ID},
+		})
+		require.NoError(t, err)
+	}
+
+	for _, at := range checkpoints {
+		replayed, err := testQueries.SumEntriesBetween(context.Background(), SumEntriesBetweenParams{
+			AccountID: account.ID,
+			ToTime:    at,
+		})
+		require.NoError(t, err)
+
+		balance, err := store.GetBalanceAt(context.Background(), BalanceAtParams{
+			AccountID: account.ID,
+			At:        at,
+		})
+		require.NoError(t, err)
+		require.Equal(t, replayed, balance)
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"testing"
@@ -14,7 +15,7 @@ func TestTransferTrxn(t *testing.T) {
 	store := NewStore(db)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountInCurrency(t, account1.CurrencyCode)
 
 	log.Printf(">>before trxn :%v %v\n", account1.Balance, account2.Balance)
 
@@ -114,3 +115,27 @@ func TestTransferTrxn(t *testing.T) {
 	require.Equal(t, account1.Balance-int64(n)*amount, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance+int64(n)*amount, updatedAccount2.Balance)
 }
+
+func TestTransferPostsBalancedJournal(t *testing.T) {
+	store := NewStore(db)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountInCurrency(t, account1.CurrencyCode)
+
+	result, err := store.PerformTransactionTrxn(context.Background(), TransferTxnParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	journal, err := store.GetJournalByTransfer(context.Background(), sql.NullInt64{Int64: result.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+
+	postings, err := store.ListJournalPostings(context.Background(), journal.ID)
+	require.NoError(t, err)
+	require.Len(t, postings, 2)
+	require.Equal(t, result.FromEntry.ID, postings[0].EntryID.Int64)
+	require.Equal(t, result.ToEntry.ID, postings[1].EntryID.Int64)
+	require.Zero(t, postings[0].Amount+postings[1].Amount)
+}
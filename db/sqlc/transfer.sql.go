@@ -7,26 +7,34 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
  from_account_id,
  to_account_id,
- amount
+ amount,
+ initiated_by
 ) VALUES (
-    $1,$2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+    $1,$2, $3, $4
+) RETURNING id, from_account_id, to_account_id, amount, created_at, initiated_by
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	InitiatedBy   sql.NullString `json:"initiated_by"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.queryRow(ctx, q.createTransferStmt, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.queryRow(ctx, q.createTransferStmt, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.InitiatedBy,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,12 +42,13 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.InitiatedBy,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, initiated_by FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -52,12 +61,13 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.InitiatedBy,
 	)
 	return i, err
 }
 
 const listTransfer = `-- name: ListTransfer :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, initiated_by FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -93,6 +103,7 @@ func (q *Queries) ListTransfer(ctx context.Context, arg ListTransferParams) ([]T
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.InitiatedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -17,7 +18,7 @@ INSERT INTO users (
  email
 ) VALUES (
     $1,$2, $3, $4
-) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+) RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
 `
 
 type CreateUserParams struct {
@@ -42,12 +43,77 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const disableUserTOTP = `-- name: DisableUserTOTP :one
+UPDATE users
+SET totp_secret = '',
+    totp_enabled = false,
+    totp_last_counter = 0
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+func (q *Queries) DisableUserTOTP(ctx context.Context, username string) (User, error) {
+	row := q.queryRow(ctx, q.disableUserTOTPStmt, disableUserTOTP, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :one
+UPDATE users
+SET totp_enabled = true
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, username string) (User, error) {
+	row := q.queryRow(ctx, q.enableUserTOTPStmt, enableUserTOTP, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -61,6 +127,157 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const recordTOTPFailure = `-- name: RecordTOTPFailure :one
+UPDATE users
+SET totp_failed_attempts = CASE
+      WHEN totp_failed_attempts + 1 >= $1::int THEN 0
+      ELSE totp_failed_attempts + 1
+    END,
+    totp_locked_until = CASE
+      WHEN totp_failed_attempts + 1 >= $1::int THEN $2::timestamptz
+      ELSE totp_locked_until
+    END
+WHERE username = $3
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+type RecordTOTPFailureParams struct {
+	MaxAttempts int32     `json:"max_attempts"`
+	LockedUntil time.Time `json:"locked_until"`
+	Username    string    `json:"username"`
+}
+
+func (q *Queries) RecordTOTPFailure(ctx context.Context, arg RecordTOTPFailureParams) (User, error) {
+	row := q.queryRow(ctx, q.recordTOTPFailureStmt, recordTOTPFailure, arg.MaxAttempts, arg.LockedUntil, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const setUserTier = `-- name: SetUserTier :one
+UPDATE users
+SET tier = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+type SetUserTierParams struct {
+	Username string `json:"username"`
+	Tier     string `json:"tier"`
+}
+
+func (q *Queries) SetUserTier(ctx context.Context, arg SetUserTierParams) (User, error) {
+	row := q.queryRow(ctx, q.setUserTierStmt, setUserTier, arg.Username, arg.Tier)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const updateUserTOTPCounter = `-- name: UpdateUserTOTPCounter :one
+UPDATE users
+SET totp_last_counter = $1,
+    totp_failed_attempts = 0
+WHERE username = $2 AND totp_last_counter < $1
+  AND (totp_locked_until IS NULL OR totp_locked_until <= now())
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+type UpdateUserTOTPCounterParams struct {
+	Counter  int64  `json:"counter"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) UpdateUserTOTPCounter(ctx context.Context, arg UpdateUserTOTPCounterParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserTOTPCounterStmt, updateUserTOTPCounter, arg.Counter, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
+	)
+	return i, err
+}
+
+const updateUserTOTPSecret = `-- name: UpdateUserTOTPSecret :one
+UPDATE users
+SET totp_secret = $2,
+    totp_enabled = false,
+    totp_last_counter = 0
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, totp_secret, totp_enabled, totp_last_counter, role, tier, totp_failed_attempts, totp_locked_until
+`
+
+type UpdateUserTOTPSecretParams struct {
+	Username   string `json:"username"`
+	TotpSecret string `json:"totp_secret"`
+}
+
+func (q *Queries) UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) (User, error) {
+	row := q.queryRow(ctx, q.updateUserTOTPSecretStmt, updateUserTOTPSecret, arg.Username, arg.TotpSecret)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.TotpLastCounter,
+		&i.Role,
+		&i.Tier,
+		&i.TotpFailedAttempts,
+		&i.TotpLockedUntil,
 	)
 	return i, err
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -56,3 +57,26 @@ func Test_GetUser(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user.CreatedAt, time.Second)
 
 }
+
+func Test_RecordTOTPFailure(t *testing.T) {
+	ctx := context.Background()
+	user := createRandomUser(t)
+	lockedUntil := time.Now().Add(time.Minute)
+
+	arg := RecordTOTPFailureParams{MaxAttempts: 2, LockedUntil: lockedUntil, Username: user.Username}
+	user, err := testQueries.RecordTOTPFailure(ctx, arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), user.TotpFailedAttempts)
+	require.False(t, user.TotpLockedUntil.Valid)
+
+	// reaching the limit locks the user out and starts counting again
+	user, err = testQueries.RecordTOTPFailure(ctx, arg)
+	require.NoError(t, err)
+	require.Zero(t, user.TotpFailedAttempts)
+	require.True(t, user.TotpLockedUntil.Valid)
+	require.WithinDuration(t, lockedUntil, user.TotpLockedUntil.Time, time.Second)
+
+	// no code is accepted until the lockout ends
+	_, err = testQueries.UpdateUserTOTPCounter(ctx, UpdateUserTOTPCounterParams{Username: user.Username, Counter: 1})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"os"
 
 	"github.com/caleberi/simple-bank/api"
 	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/approval"
+	"github.com/caleberi/simple-bank/pkg/escrow"
+	"github.com/caleberi/simple-bank/pkg/interest"
+	"github.com/caleberi/simple-bank/pkg/invoice"
+	"github.com/caleberi/simple-bank/pkg/paymentrequest"
+	"github.com/caleberi/simple-bank/pkg/snapshot"
 	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/caleberi/simple-bank/pkg/webhook"
 	_ "github.com/lib/pq"
 )
 
@@ -17,13 +26,56 @@ func main() {
 	if err != nil {
 		log.Fatal("[ERROR] cannot connect to database :", err)
 	}
-	store := db.NewStore(conn)
+	store := db.NewAuditedStore(conn)
+
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		os.Exit(runReconcile(store, os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "account-numbers" {
+		os.Exit(runAccountNumbers(store, cfg.BankCode, os.Args[2:]))
+	}
+
+	dispatcher := webhook.NewDispatcher(store, webhook.Config{
+		Interval:    cfg.WebhookInterval,
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		Timeout:     cfg.WebhookTimeout,
+	})
+	go dispatcher.Run(context.Background())
+
+	snapshots := snapshot.NewJob(store, snapshot.Config{Interval: cfg.SnapshotInterval})
+	go snapshots.Run(context.Background())
+
+	interestJob := interest.NewJob(store, interest.Config{Interval: cfg.InterestInterval})
+	go interestJob.Run(context.Background())
+
+	approvalJob := approval.NewJob(store, approval.Config{Interval: cfg.ApprovalInterval})
+	go approvalJob.Run(context.Background())
+
+	paymentRequestJob := paymentrequest.NewJob(store, paymentrequest.Config{Interval: cfg.PaymentRequestInterval})
+	go paymentRequestJob.Run(context.Background())
+
+	invoiceJob := invoice.NewJob(store, invoice.JobConfig{Interval: cfg.InvoiceInterval})
+	go invoiceJob.Run(context.Background())
+
+	escrowJob := escrow.NewJob(store, escrow.Config{Interval: cfg.EscrowInterval})
+	go escrowJob.Run(context.Background())
+
 	server, err := api.NewServer(*cfg, store)
 
 	if err != nil {
 		log.Fatal("[ERROR] cannot create server :", err)
 	}
 
+	if cfg.ReconcileInterval > 0 {
+		go server.ReconcileEvery(context.Background(), cfg.ReconcileInterval)
+	}
+
+	go func() {
+		if err := server.ListenAccountEvents(context.Background(), cfg.DBSource); err != nil {
+			log.Fatal("[ERROR] cannot listen for account events :", err)
+		}
+	}()
+
 	if err := server.Start(cfg.VBankAddr); err != nil {
 		log.Fatal("[ERROR] cannot start server :", err)
 	}
@@ -0,0 +1,119 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Roles a user can hold on an account. The account's owner always holds
+// RoleOwner, the other roles come from an accepted membership.
+const (
+	RoleOwner   = "owner"
+	RoleCoOwner = "co_owner"
+	RoleSpender = "spender"
+	RoleViewer  = "viewer"
+)
+
+// Membership statuses, an invited user becomes a member once they accept
+const (
+	MemberInvited = "invited"
+	MemberActive  = "active"
+)
+
+// Permission is something a role allows on an account
+type Permission string
+
+const (
+	PermissionView     Permission = "view"
+	PermissionTransfer Permission = "transfer"
+	PermissionManage   Permission = "manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleOwner:   {PermissionView, PermissionTransfer, PermissionManage},
+	RoleCoOwner: {PermissionView, PermissionTransfer, PermissionManage},
+	RoleSpender: {PermissionView, PermissionTransfer},
+	RoleViewer:  {PermissionView},
+}
+
+// ErrNoAccess is returned for users who are neither the owner nor an active member of an account
+var ErrNoAccess = errors.New("account doesn't belong to the authenticated user")
+
+// SpendLimitError is returned when a spender sends more than they are allowed to
+type SpendLimitError struct {
+	Limit  int64
+	Amount int64
+}
+
+func (e *SpendLimitError) Error() string {
+	return fmt.Sprintf("transfer of %d is over the spender limit of %d", e.Amount, e.Limit)
+}
+
+// Grant is what a user may do with an account
+type Grant struct {
+	Role       string        `json:"role"`
+	SpendLimit sql.NullInt64 `json:"spend_limit"`
+}
+
+// Can reports whether the grant's role allows permission
+func (g Grant) Can(permission Permission) bool {
+	for _, allowed := range rolePermissions[g.Role] {
+		if allowed == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanSpend checks a transfer of amount is within the grant's spend limit, if it
+// has one. It only catches a single transfer over the limit early, the store
+// enforces the limit over the spender's transfers in a rolling window.
+func (g Grant) CanSpend(amount int64) error {
+	if g.SpendLimit.Valid && amount > g.SpendLimit.Int64 {
+		return &SpendLimitError{Limit: g.SpendLimit.Int64, Amount: amount}
+	}
+	return nil
+}
+
+// ValidRole reports whether role can be given to a member, ownership can't be
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok && role != RoleOwner
+}
+
+// Service decides what users may do with accounts
+type Service struct {
+	store db.Store
+}
+
+func NewService(store db.Store) *Service {
+	return &Service{store: store}
+}
+
+// Grant returns what username may do with account. The owner is answered
+// without a lookup, everyone else needs an accepted membership.
+func (s *Service) Grant(ctx context.Context, account db.Account, username string) (Grant, error) {
+	if account.Owner == username {
+		return Grant{Role: RoleOwner}, nil
+	}
+
+	member, err := s.store.GetAccountMember(ctx, db.GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Grant{}, ErrNoAccess
+		}
+		return Grant{}, err
+	}
+	if member.Status != MemberActive {
+		return Grant{}, ErrNoAccess
+	}
+
+	return Grant{Role: member.Role, SpendLimit: member.SpendLimit}, nil
+}
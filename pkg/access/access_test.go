@@ -0,0 +1,65 @@
+package access
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceGrant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := db.Account{ID: 7, Owner: "alice"}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: 7, Username: "bob"})).
+		Return(db.AccountMember{Role: RoleSpender, Status: MemberActive, SpendLimit: sql.NullInt64{Int64: 500, Valid: true}}, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: 7, Username: "carol"})).
+		Return(db.AccountMember{Role: RoleCoOwner, Status: MemberInvited}, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: 7, Username: "dave"})).
+		Return(db.AccountMember{}, sql.ErrNoRows)
+
+	service := NewService(store)
+
+	// the owner is answered without a lookup
+	grant, err := service.Grant(context.Background(), account, "alice")
+	require.NoError(t, err)
+	require.Equal(t, RoleOwner, grant.Role)
+	require.True(t, grant.Can(PermissionManage))
+	require.NoError(t, grant.CanSpend(1_000_000))
+
+	grant, err = service.Grant(context.Background(), account, "bob")
+	require.NoError(t, err)
+	require.True(t, grant.Can(PermissionTransfer))
+	require.False(t, grant.Can(PermissionManage))
+	require.NoError(t, grant.CanSpend(500))
+
+	var limitErr *SpendLimitError
+	require.ErrorAs(t, grant.CanSpend(501), &limitErr)
+	require.Equal(t, int64(500), limitErr.Limit)
+
+	// invitations grant nothing until they are accepted
+	_, err = service.Grant(context.Background(), account, "carol")
+	require.ErrorIs(t, err, ErrNoAccess)
+
+	_, err = service.Grant(context.Background(), account, "dave")
+	require.ErrorIs(t, err, ErrNoAccess)
+}
+
+func TestRoles(t *testing.T) {
+	require.True(t, Grant{Role: RoleViewer}.Can(PermissionView))
+	require.False(t, Grant{Role: RoleViewer}.Can(PermissionTransfer))
+	require.False(t, Grant{Role: "unknown"}.Can(PermissionView))
+
+	require.True(t, ValidRole(RoleCoOwner))
+	require.True(t, ValidRole(RoleSpender))
+	require.True(t, ValidRole(RoleViewer))
+	require.False(t, ValidRole(RoleOwner))
+	require.False(t, ValidRole("admin"))
+}
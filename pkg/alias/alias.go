@@ -0,0 +1,125 @@
+package alias
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Alias kinds. Usernames are aliases too but need no registration, everyone
+// already owns theirs.
+const (
+	KindUsername = "username"
+	KindEmail    = "email"
+	KindPhone    = "phone"
+)
+
+// Verification codes are short lived and only a few guesses are allowed
+const (
+	CodeTTL         = 15 * time.Minute
+	MaxCodeAttempts = 5
+	codeDigits      = 6
+)
+
+var (
+	ErrInvalidEmail = errors.New("invalid email address")
+	ErrInvalidPhone = errors.New("invalid phone number, use the international format like +2348012345678")
+)
+
+// Kind tells what an alias is from how it looks
+func Kind(alias string) string {
+	switch {
+	case strings.Contains(alias, "@"):
+		return KindEmail
+	case strings.HasPrefix(strings.TrimSpace(alias), "+"):
+		return KindPhone
+	}
+	return KindUsername
+}
+
+// Normalize returns the form an alias is stored and looked up in: emails are
+// lower cased and phones reduced to + and digits
+func Normalize(kind, alias string) (string, error) {
+	alias = strings.TrimSpace(alias)
+
+	switch kind {
+	case KindEmail:
+		address, err := mail.ParseAddress(alias)
+		if err != nil || address.Address != alias {
+			return "", ErrInvalidEmail
+		}
+		return strings.ToLower(alias), nil
+	case KindPhone:
+		if !strings.HasPrefix(alias, "+") {
+			return "", ErrInvalidPhone
+		}
+		var digits strings.Builder
+		for _, c := range alias[1:] {
+			switch {
+			case unicode.IsDigit(c):
+				digits.WriteRune(c)
+			case c == ' ' || c == '-' || c == '(' || c == ')' || c == '.':
+			default:
+				return "", ErrInvalidPhone
+			}
+		}
+		// E.164 numbers have at most 15 digits
+		if digits.Len() < 8 || digits.Len() > 15 {
+			return "", ErrInvalidPhone
+		}
+		return "+" + digits.String(), nil
+	case KindUsername:
+		return alias, nil
+	}
+	return "", fmt.Errorf("unknown alias kind %q", kind)
+}
+
+// NewCode returns a random numeric verification code
+func NewCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code : [%w] ", err)
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+// HashCode returns the hex encoded sha256 of a code bound to its alias, so
+// the same code for two aliases doesn't hash the same
+func HashCode(alias, code string) string {
+	sum := sha256.Sum256([]byte(alias + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckCode compares a code against its stored hash in constant time
+func CheckCode(alias, code, hashedCode string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashCode(alias, code)), []byte(hashedCode)) == 1
+}
+
+// MaskName shows enough of a full name for a sender to confirm who they are
+// paying, the first name and the initials of the rest
+func MaskName(fullName string) string {
+	parts := strings.Fields(fullName)
+	if len(parts) == 0 {
+		return ""
+	}
+
+	masked := []string{parts[0]}
+	for _, part := range parts[1:] {
+		initial := []rune(part)[0]
+		masked = append(masked, string(unicode.ToUpper(initial))+".")
+	}
+	return strings.Join(masked, " ")
+}
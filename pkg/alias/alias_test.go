@@ -0,0 +1,60 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKind(t *testing.T) {
+	require.Equal(t, KindEmail, Kind("ada@example.com"))
+	require.Equal(t, KindPhone, Kind("+44 20 7946 0958"))
+	require.Equal(t, KindUsername, Kind("ada"))
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name  string
+		kind  string
+		alias string
+		want  string
+		err   error
+	}{
+		{name: "Email", kind: KindEmail, alias: " Ada@Example.com ", want: "ada@example.com"},
+		{name: "EmailWithName", kind: KindEmail, alias: "Ada <ada@example.com>", err: ErrInvalidEmail},
+		{name: "NotEmail", kind: KindEmail, alias: "ada", err: ErrInvalidEmail},
+		{name: "Phone", kind: KindPhone, alias: "+44 (20) 7946-0958", want: "+442079460958"},
+		{name: "PhoneWithoutCountry", kind: KindPhone, alias: "07946 0958", err: ErrInvalidPhone},
+		{name: "PhoneWithLetters", kind: KindPhone, alias: "+44 20 CALL ME", err: ErrInvalidPhone},
+		{name: "PhoneTooLong", kind: KindPhone, alias: "+1234567890123456", err: ErrInvalidPhone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.kind, tc.alias)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	code, err := NewCode()
+	require.NoError(t, err)
+	require.Len(t, code, 6)
+
+	hashed := HashCode("ada@example.com", code)
+	require.True(t, CheckCode("ada@example.com", code, hashed))
+	require.False(t, CheckCode("bob@example.com", code, hashed))
+}
+
+func TestMaskName(t *testing.T) {
+	require.Equal(t, "Ada L.", MaskName("Ada Lovelace"))
+	require.Equal(t, "Ada K. L.", MaskName(" Ada  king lovelace "))
+	require.Equal(t, "Ada", MaskName("Ada"))
+	require.Equal(t, "", MaskName(""))
+}
@@ -0,0 +1,135 @@
+package alias
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sender names. The log sender is only for development, anyone who can read
+// the log could verify any alias with it.
+const (
+	LogSenderName  = "log"
+	HTTPSenderName = "http"
+)
+
+func init() {
+	RegisterSender(LogSenderName, func(string) (Sender, error) {
+		return LogSender{}, nil
+	})
+	RegisterSender(HTTPSenderName, func(config string) (Sender, error) {
+		return NewHTTPSender(config)
+	})
+}
+
+// Sender delivers verification codes to the email address or phone being
+// registered
+type Sender interface {
+	Send(ctx context.Context, kind, alias, code string) error
+}
+
+// SenderFactory builds a sender from its configuration string
+type SenderFactory func(config string) (Sender, error)
+
+var (
+	mu      sync.RWMutex
+	senders = map[string]SenderFactory{}
+)
+
+// RegisterSender makes a sender available under name, it panics if the name is taken
+func RegisterSender(name string, factory SenderFactory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := senders[name]; ok {
+		panic(fmt.Sprintf("alias sender %q is already registered", name))
+	}
+	senders[name] = factory
+}
+
+// NewSender builds the sender registered under name
+func NewSender(name, config string) (Sender, error) {
+	mu.RLock()
+	factory, ok := senders[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown alias sender %q, available: %s", name, strings.Join(Senders(), ", "))
+	}
+	return factory(config)
+}
+
+// Senders lists the registered sender names
+func Senders() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(senders))
+	for name := range senders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LogSender writes codes to the log instead of sending them, for development
+type LogSender struct{}
+
+func (LogSender) Send(_ context.Context, kind, alias, code string) error {
+	log.Printf("[INFO] verification code for %s %s : %s", kind, alias, code)
+	return nil
+}
+
+// HTTPSender hands codes to a notification gateway that emails or texts them,
+// by posting the kind, alias and code as JSON to its URL
+type HTTPSender struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSender returns a sender posting to gatewayURL
+func NewHTTPSender(gatewayURL string) (*HTTPSender, error) {
+	u, err := url.Parse(gatewayURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid notification gateway URL %q", gatewayURL)
+	}
+	return &HTTPSender{url: gatewayURL, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+type httpSenderRequest struct {
+	Kind  string `json:"kind"`
+	Alias string `json:"alias"`
+	Code  string `json:"code"`
+}
+
+func (s *HTTPSender) Send(ctx context.Context, kind, alias, code string) error {
+	body, err := json.Marshal(httpSenderRequest{Kind: kind, Alias: alias, Code: code})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("cannot send verification code : [%w] ", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("notification gateway answered %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package alias
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSender(t *testing.T) {
+	sender, err := NewSender(LogSenderName, "")
+	require.NoError(t, err)
+	require.IsType(t, LogSender{}, sender)
+
+	sender, err = NewSender(HTTPSenderName, "https://notify.internal/codes")
+	require.NoError(t, err)
+	require.IsType(t, &HTTPSender{}, sender)
+
+	_, err = NewSender(HTTPSenderName, "notify.internal")
+	require.Error(t, err)
+
+	_, err = NewSender("carrier-pigeon", "")
+	require.Error(t, err)
+}
+
+func TestHTTPSender(t *testing.T) {
+	var received httpSenderRequest
+	status := http.StatusAccepted
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer gateway.Close()
+
+	sender, err := NewHTTPSender(gateway.URL)
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), KindEmail, "ada@example.com", "123456")
+	require.NoError(t, err)
+	require.Equal(t, httpSenderRequest{Kind: KindEmail, Alias: "ada@example.com", Code: "123456"}, received)
+
+	status = http.StatusServiceUnavailable
+	err = sender.Send(context.Background(), KindEmail, "ada@example.com", "123456")
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package approval
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Config tunes the job, a zero Interval falls back to a minute
+type Config struct {
+	Interval time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Minute
+	}
+}
+
+// Job expires pending transfers that weren't approved in time. Approvals are
+// refused once a transfer is past its expiry anyway, the job only closes it so
+// it stops showing up as pending.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config Config) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run expires overdue transfers straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot expire pending transfers: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce expires every overdue pending transfer and returns them
+func (j *Job) RunOnce(ctx context.Context) ([]db.PendingTransfer, error) {
+	return j.store.ExpirePendingTransfers(ctx, j.now())
+}
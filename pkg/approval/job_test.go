@@ -0,0 +1,32 @@
+package approval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ExpirePendingTransfers(gomock.Any(), gomock.Eq(now)).Times(1).
+		Return([]db.PendingTransfer{{ID: 3, Status: db.PendingTransferExpired}}, nil)
+
+	job := NewJob(store, Config{})
+	job.now = func() time.Time { return now }
+	require.Equal(t, time.Minute, job.config.Interval)
+
+	expired, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, int64(3), expired[0].ID)
+}
@@ -0,0 +1,93 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Config tunes the job, zero values fall back to a minute and 100 contracts per query
+type Config struct {
+	Interval  time.Duration
+	BatchSize int32
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Minute
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+}
+
+// Job releases escrowed money to the seller once a contract reaches its
+// release time without the buyer confirming or disputing it. Each contract is
+// released in its own transaction, so one that can't be, because the seller's
+// account is blocked for instance, is logged and retried on the next run.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config Config) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run releases due contracts straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot release escrow contracts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce releases every due contract and returns how many were released
+func (j *Job) RunOnce(ctx context.Context) (int, error) {
+	now := j.now()
+
+	var released int
+	var afterID int64
+	for {
+		contracts, err := j.store.ListDueEscrowContracts(ctx, db.ListDueEscrowContractsParams{
+			Now:       now,
+			AfterID:   afterID,
+			BatchSize: j.config.BatchSize,
+		})
+		if err != nil {
+			return released, err
+		}
+
+		for _, contract := range contracts {
+			_, err := j.store.ReleaseEscrowTrxn(ctx, contract.ID)
+			switch {
+			case err == nil:
+				released++
+			// the buyer confirmed or disputed it in the meantime
+			case errors.Is(err, db.ErrEscrowNotFunded):
+			default:
+				log.Printf("[ERROR] cannot release escrow contract %d: %v", contract.ID, err)
+			}
+		}
+
+		if len(contracts) < int(j.config.BatchSize) {
+			return released, nil
+		}
+		afterID = contracts[len(contracts)-1].ID
+	}
+}
@@ -0,0 +1,50 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ListDueEscrowContracts(gomock.Any(), gomock.Eq(db.ListDueEscrowContractsParams{
+			Now:       now,
+			BatchSize: 3,
+		})).Times(1).Return([]db.EscrowContract{{ID: 1}, {ID: 2}, {ID: 3}}, nil),
+		store.EXPECT().ListDueEscrowContracts(gomock.Any(), gomock.Eq(db.ListDueEscrowContractsParams{
+			Now:       now,
+			AfterID:   3,
+			BatchSize: 3,
+		})).Times(1).Return([]db.EscrowContract{{ID: 4}}, nil),
+	)
+
+	store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(int64(1))).Times(1)
+	// disputed since it was listed
+	store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(int64(2))).Times(1).
+		Return(db.EscrowTxnResult{}, db.ErrEscrowNotFunded)
+	// the seller's account is blocked, it is retried on the next run
+	store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(int64(3))).Times(1).
+		Return(db.EscrowTxnResult{}, errors.New("account is blocked"))
+	store.EXPECT().ReleaseEscrowTrxn(gomock.Any(), gomock.Eq(int64(4))).Times(1)
+
+	job := NewJob(store, Config{BatchSize: 3})
+	job.now = func() time.Time { return now }
+	require.Equal(t, time.Minute, job.config.Interval)
+
+	released, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, released)
+}
@@ -0,0 +1,96 @@
+package fraud
+
+import (
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Config tunes the default rules, zero values fall back to sensible defaults
+type Config struct {
+	VelocityWindow    time.Duration
+	VelocityMaxCount  int64
+	VelocityMaxAmount int64
+	// NewPayeeThreshold is the smallest first transfer to an account that is held
+	NewPayeeThreshold int64
+	// BeneficiaryWindow and BeneficiaryMaxAdded bound how many payees a user
+	// can save before their transfers are held
+	BeneficiaryWindow    time.Duration
+	BeneficiaryMaxAdded  int64
+	StructuringUnit      int64
+	StructuringMinAmount int64
+	StructuringWindow    time.Duration
+	StructuringRepeats   int
+	// SanctionsFile is the sanctions name list, the sanctions rule is left out without it
+	SanctionsFile string
+}
+
+func (c *Config) setDefaults() {
+	if c.VelocityWindow <= 0 {
+		c.VelocityWindow = time.Hour
+	}
+	if c.VelocityMaxCount <= 0 {
+		c.VelocityMaxCount = 10
+	}
+	if c.NewPayeeThreshold <= 0 {
+		c.NewPayeeThreshold = 100_000
+	}
+	if c.BeneficiaryWindow <= 0 {
+		c.BeneficiaryWindow = 24 * time.Hour
+	}
+	if c.BeneficiaryMaxAdded <= 0 {
+		c.BeneficiaryMaxAdded = 3
+	}
+	if c.StructuringUnit <= 0 {
+		c.StructuringUnit = 10_000
+	}
+	if c.StructuringMinAmount <= 0 {
+		c.StructuringMinAmount = 100_000
+	}
+	if c.StructuringWindow <= 0 {
+		c.StructuringWindow = 24 * time.Hour
+	}
+	if c.StructuringRepeats <= 0 {
+		c.StructuringRepeats = 3
+	}
+}
+
+// DefaultRules returns the rules every transfer is screened with
+func DefaultRules(store db.Store, config Config) ([]Rule, error) {
+	config.setDefaults()
+
+	rules := []Rule{
+		VelocityRule{
+			Store:     store,
+			Window:    config.VelocityWindow,
+			MaxCount:  config.VelocityMaxCount,
+			MaxAmount: config.VelocityMaxAmount,
+		},
+		NewPayeeRule{
+			Store:     store,
+			Threshold: config.NewPayeeThreshold,
+		},
+		BeneficiaryChurnRule{
+			Store:    store,
+			Window:   config.BeneficiaryWindow,
+			MaxAdded: config.BeneficiaryMaxAdded,
+		},
+		StructuringRule{
+			Store:     store,
+			Unit:      config.StructuringUnit,
+			MinAmount: config.StructuringMinAmount,
+			Window:    config.StructuringWindow,
+			Repeats:   config.StructuringRepeats,
+		},
+	}
+
+	if config.SanctionsFile != "" {
+		list, err := LoadSanctionsList(config.SanctionsFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, SanctionsRule{Store: store, List: list})
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,94 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Decisions a rule can reach, from the weakest to the strongest. A transfer
+// gets the strongest decision of any rule.
+const (
+	DecisionAllow = "allow"
+	DecisionHold  = "hold"
+	DecisionBlock = "block"
+)
+
+func severity(decision string) int {
+	switch decision {
+	case DecisionBlock:
+		return 2
+	case DecisionHold:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Transfer is what the rules screen, At is when it was requested
+type Transfer struct {
+	From        db.Account `json:"from"`
+	To          db.Account `json:"to"`
+	Amount      int64      `json:"amount"`
+	InitiatedBy string     `json:"initiated_by"`
+	At          time.Time  `json:"at"`
+}
+
+// Verdict is a rule's decision on a transfer and the reason for it
+type Verdict struct {
+	Rule     string `json:"rule"`
+	Decision string `json:"decision"`
+	Reason   string `json:"reason"`
+}
+
+// Rule screens a transfer. Rules that find nothing return DecisionAllow.
+type Rule interface {
+	Name() string
+	Evaluate(ctx context.Context, transfer Transfer) (Verdict, error)
+}
+
+// Result is the engine's decision along with the verdicts of the rules that
+// held or blocked the transfer
+type Result struct {
+	Decision string    `json:"decision"`
+	Verdicts []Verdict `json:"verdicts"`
+}
+
+// Engine runs every rule on a transfer
+type Engine struct {
+	rules []Rule
+	now   func() time.Time
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules, now: time.Now}
+}
+
+// Screen runs all the rules, it doesn't stop at the first one that objects so
+// reviewers see every reason a transfer was held
+func (e *Engine) Screen(ctx context.Context, transfer Transfer) (Result, error) {
+	result := Result{Decision: DecisionAllow, Verdicts: []Verdict{}}
+	if transfer.At.IsZero() {
+		transfer.At = e.now()
+	}
+
+	for _, rule := range e.rules {
+		verdict, err := rule.Evaluate(ctx, transfer)
+		if err != nil {
+			return result, fmt.Errorf("fraud rule %s: %w", rule.Name(), err)
+		}
+		if severity(verdict.Decision) == 0 {
+			continue
+		}
+
+		verdict.Rule = rule.Name()
+		result.Verdicts = append(result.Verdicts, verdict)
+		if severity(verdict.Decision) > severity(result.Decision) {
+			result.Decision = verdict.Decision
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package fraud
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedRule struct {
+	name     string
+	decision string
+}
+
+func (r fixedRule) Name() string {
+	return r.name
+}
+
+func (r fixedRule) Evaluate(context.Context, Transfer) (Verdict, error) {
+	return Verdict{Decision: r.decision, Reason: r.name}, nil
+}
+
+func TestEngineScreen(t *testing.T) {
+	engine := NewEngine(
+		fixedRule{"a", DecisionHold},
+		fixedRule{"b", DecisionAllow},
+		fixedRule{"c", DecisionBlock},
+		fixedRule{"d", DecisionHold},
+	)
+
+	result, err := engine.Screen(context.Background(), Transfer{Amount: 10})
+	require.NoError(t, err)
+	require.Equal(t, DecisionBlock, result.Decision)
+
+	// every objection is reported, not just the strongest
+	require.Len(t, result.Verdicts, 3)
+	require.Equal(t, "a", result.Verdicts[0].Rule)
+	require.Equal(t, "c", result.Verdicts[1].Rule)
+	require.Equal(t, "d", result.Verdicts[2].Rule)
+
+	result, err = NewEngine().Screen(context.Background(), Transfer{Amount: 10})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, result.Decision)
+	require.Empty(t, result.Verdicts)
+}
+
+func TestVelocityRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	transfer := Transfer{From: db.Account{ID: 1}, To: db.Account{ID: 2}, Amount: 100, At: now}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetOutgoingTransferTotals(gomock.Any(), gomock.Eq(db.GetOutgoingTransferTotalsParams{
+		AccountIds: []int64{1},
+		Since:      now.Add(-time.Hour),
+	})).Times(3).Return(db.GetOutgoingTransferTotalsRow{Count: 4, Total: 900}, nil)
+
+	for _, tc := range []struct {
+		rule     VelocityRule
+		decision string
+	}{
+		{VelocityRule{Store: store, Window: time.Hour, MaxCount: 5}, DecisionAllow},
+		{VelocityRule{Store: store, Window: time.Hour, MaxCount: 4}, DecisionHold},
+		{VelocityRule{Store: store, Window: time.Hour, MaxAmount: 999}, DecisionHold},
+	} {
+		verdict, err := tc.rule.Evaluate(context.Background(), transfer)
+		require.NoError(t, err)
+		require.Equal(t, tc.decision, verdict.Decision)
+	}
+}
+
+func TestNewPayeeRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	rule := NewPayeeRule{Store: store, Threshold: 1000}
+	arg := db.CountTransfersBetweenParams{FromAccountID: 1, ToAccountID: 2}
+
+	// small amounts don't look at the history
+	verdict, err := rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, To: db.Account{ID: 2}, Amount: 999})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+
+	store.EXPECT().CountTransfersBetween(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(0), nil)
+	verdict, err = rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, To: db.Account{ID: 2}, Amount: 1000})
+	require.NoError(t, err)
+	require.Equal(t, DecisionHold, verdict.Decision)
+
+	store.EXPECT().CountTransfersBetween(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+	verdict, err = rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, To: db.Account{ID: 2}, Amount: 1000})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+}
+
+func TestBeneficiaryChurnRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	store := mockdb.NewMockStore(ctrl)
+	rule := BeneficiaryChurnRule{Store: store, Window: time.Hour, MaxAdded: 2}
+	transfer := Transfer{From: db.Account{ID: 1}, To: db.Account{ID: 2}, Amount: 10, InitiatedBy: "ada", At: now}
+	arg := db.CountBeneficiariesAddedSinceParams{Owner: "ada", Since: now.Add(-time.Hour)}
+
+	store.EXPECT().CountBeneficiariesAddedSince(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(2), nil)
+	verdict, err := rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+
+	store.EXPECT().CountBeneficiariesAddedSince(gomock.Any(), gomock.Eq(arg)).Times(1).Return(int64(3), nil)
+	verdict, err = rule.Evaluate(context.Background(), transfer)
+	require.NoError(t, err)
+	require.Equal(t, DecisionHold, verdict.Decision)
+}
+
+func TestStructuringRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	store := mockdb.NewMockStore(ctrl)
+	rule := StructuringRule{Store: store, Unit: 100, MinAmount: 1000, Window: time.Hour, Repeats: 3}
+
+	// odd amounts aren't round
+	verdict, err := rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, Amount: 1050, At: now})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+
+	store.EXPECT().ListOutgoingTransfersSince(gomock.Any(), gomock.Eq(db.ListOutgoingTransfersSinceParams{
+		AccountIds: []int64{1},
+		Since:      now.Add(-time.Hour),
+	})).Times(2).Return([]db.Transfer{{Amount: 2000}, {Amount: 1234}, {Amount: 500}, {Amount: 3000}}, nil)
+
+	verdict, err = rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, Amount: 1000, At: now})
+	require.NoError(t, err)
+	require.Equal(t, DecisionHold, verdict.Decision)
+
+	rule.Repeats = 4
+	verdict, err = rule.Evaluate(context.Background(), Transfer{From: db.Account{ID: 1}, Amount: 1000, At: now})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+}
+
+func TestSanctionsRule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	list, err := ParseSanctionsList(strings.NewReader("# sanctioned names\n\nDOE, John\n  Jane   Roe \n"))
+	require.NoError(t, err)
+	require.Equal(t, 2, list.Len())
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("sender")).AnyTimes().Return(db.User{FullName: "Mary Major"}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("listed")).AnyTimes().Return(db.User{FullName: "john doe"}, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq("clean")).AnyTimes().Return(db.User{FullName: "John Doerr"}, nil)
+
+	rule := SanctionsRule{Store: store, List: list}
+
+	verdict, err := rule.Evaluate(context.Background(), Transfer{From: db.Account{Owner: "sender"}, To: db.Account{Owner: "listed"}})
+	require.NoError(t, err)
+	require.Equal(t, DecisionBlock, verdict.Decision)
+	require.Contains(t, verdict.Reason, "recipient")
+
+	verdict, err = rule.Evaluate(context.Background(), Transfer{From: db.Account{Owner: "sender"}, To: db.Account{Owner: "clean"}})
+	require.NoError(t, err)
+	require.Equal(t, DecisionAllow, verdict.Decision)
+}
@@ -0,0 +1,196 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+func allow() (Verdict, error) {
+	return Verdict{Decision: DecisionAllow}, nil
+}
+
+// VelocityRule holds transfers once the source account has sent more than
+// MaxCount transfers, or more than MaxAmount, within Window. A zero limit is
+// not checked.
+type VelocityRule struct {
+	Store     db.Store
+	Window    time.Duration
+	MaxCount  int64
+	MaxAmount int64
+}
+
+func (VelocityRule) Name() string {
+	return "velocity"
+}
+
+func (r VelocityRule) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	totals, err := r.Store.GetOutgoingTransferTotals(ctx, db.GetOutgoingTransferTotalsParams{
+		AccountIds: []int64{transfer.From.ID},
+		Since:      transfer.At.Add(-r.Window),
+	})
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	if r.MaxCount > 0 && totals.Count+1 > r.MaxCount {
+		return Verdict{
+			Decision: DecisionHold,
+			Reason:   fmt.Sprintf("%d transfers within %s, at most %d expected", totals.Count+1, r.Window, r.MaxCount),
+		}, nil
+	}
+	if r.MaxAmount > 0 && totals.Total+transfer.Amount > r.MaxAmount {
+		return Verdict{
+			Decision: DecisionHold,
+			Reason:   fmt.Sprintf("%d sent within %s, at most %d expected", totals.Total+transfer.Amount, r.Window, r.MaxAmount),
+		}, nil
+	}
+	return allow()
+}
+
+// NewPayeeRule holds the first transfer to an account when it is for at least Threshold
+type NewPayeeRule struct {
+	Store     db.Store
+	Threshold int64
+}
+
+func (NewPayeeRule) Name() string {
+	return "new_payee"
+}
+
+func (r NewPayeeRule) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	if transfer.Amount < r.Threshold {
+		return allow()
+	}
+
+	count, err := r.Store.CountTransfersBetween(ctx, db.CountTransfersBetweenParams{
+		FromAccountID: transfer.From.ID,
+		ToAccountID:   transfer.To.ID,
+	})
+	if err != nil {
+		return Verdict{}, err
+	}
+	if count > 0 {
+		return allow()
+	}
+
+	return Verdict{
+		Decision: DecisionHold,
+		Reason:   fmt.Sprintf("first transfer to account %d is for %d", transfer.To.ID, transfer.Amount),
+	}, nil
+}
+
+// BeneficiaryChurnRule holds transfers by a user who saved more than MaxAdded
+// new beneficiaries within Window. A burst of new payees is a common sign of a
+// taken over login being emptied.
+type BeneficiaryChurnRule struct {
+	Store    db.Store
+	Window   time.Duration
+	MaxAdded int64
+}
+
+func (BeneficiaryChurnRule) Name() string {
+	return "beneficiary_churn"
+}
+
+func (r BeneficiaryChurnRule) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	added, err := r.Store.CountBeneficiariesAddedSince(ctx, db.CountBeneficiariesAddedSinceParams{
+		Owner: transfer.InitiatedBy,
+		Since: transfer.At.Add(-r.Window),
+	})
+	if err != nil {
+		return Verdict{}, err
+	}
+	if added <= r.MaxAdded {
+		return allow()
+	}
+
+	return Verdict{
+		Decision: DecisionHold,
+		Reason:   fmt.Sprintf("%d beneficiaries added within %s, at most %d expected", added, r.Window, r.MaxAdded),
+	}, nil
+}
+
+// StructuringRule holds round amounts, multiples of Unit of at least MinAmount,
+// once the source account has sent Repeats of them within Window. Splitting a
+// large sum into a run of round transfers is a common way to stay under
+// reporting thresholds.
+type StructuringRule struct {
+	Store     db.Store
+	Unit      int64
+	MinAmount int64
+	Window    time.Duration
+	Repeats   int
+}
+
+func (StructuringRule) Name() string {
+	return "structuring"
+}
+
+func (r StructuringRule) round(amount int64) bool {
+	return amount >= r.MinAmount && amount%r.Unit == 0
+}
+
+func (r StructuringRule) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	if !r.round(transfer.Amount) {
+		return allow()
+	}
+
+	transfers, err := r.Store.ListOutgoingTransfersSince(ctx, db.ListOutgoingTransfersSinceParams{
+		AccountIds: []int64{transfer.From.ID},
+		Since:      transfer.At.Add(-r.Window),
+	})
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	count := 1
+	for _, previous := range transfers {
+		if r.round(previous.Amount) {
+			count++
+		}
+	}
+	if count < r.Repeats {
+		return allow()
+	}
+
+	return Verdict{
+		Decision: DecisionHold,
+		Reason:   fmt.Sprintf("%d round amount transfers within %s", count, r.Window),
+	}, nil
+}
+
+// SanctionsRule blocks transfers whose sender or recipient is on the sanctions list
+type SanctionsRule struct {
+	Store db.Store
+	List  *SanctionsList
+}
+
+func (SanctionsRule) Name() string {
+	return "sanctions"
+}
+
+func (r SanctionsRule) Evaluate(ctx context.Context, transfer Transfer) (Verdict, error) {
+	for _, party := range []struct {
+		role     string
+		username string
+	}{
+		{"sender", transfer.From.Owner},
+		{"recipient", transfer.To.Owner},
+	} {
+		user, err := r.Store.GetUser(ctx, party.username)
+		if err != nil {
+			return Verdict{}, err
+		}
+
+		if entry, ok := r.List.Match(user.FullName); ok {
+			return Verdict{
+				Decision: DecisionBlock,
+				Reason:   fmt.Sprintf("%s matches sanctioned name %q", party.role, entry),
+			}, nil
+		}
+	}
+	return allow()
+}
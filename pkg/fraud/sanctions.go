@@ -0,0 +1,70 @@
+package fraud
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SanctionsList is a list of sanctioned names. Names are compared on their
+// words regardless of case, punctuation and order, so "DOE, John" matches
+// "John Doe".
+type SanctionsList struct {
+	names map[string]string
+}
+
+// LoadSanctionsList reads a list from a file with one name per line, blank
+// lines and lines starting with # are skipped
+func LoadSanctionsList(path string) (*SanctionsList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseSanctionsList(file)
+}
+
+func ParseSanctionsList(r io.Reader) (*SanctionsList, error) {
+	list := &SanctionsList{names: map[string]string{}}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key := normalizeName(line); key != "" {
+			list.names[key] = line
+		}
+	}
+	return list, scanner.Err()
+}
+
+// Len returns how many names are on the list
+func (l *SanctionsList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.names)
+}
+
+// Match returns the listed name that name matches
+func (l *SanctionsList) Match(name string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	entry, ok := l.names[normalizeName(name)]
+	return entry, ok
+}
+
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,205 @@
+package interest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/snapshot"
+	"github.com/caleberi/simple-bank/pkg/utils"
+)
+
+// Config tunes the job, zero values fall back to sensible defaults
+type Config struct {
+	Interval  time.Duration
+	BatchSize int32
+	// Grace is how long after midnight a day is accrued, so transactions that
+	// started before midnight have committed by then
+	Grace time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Hour
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 1000
+	}
+	if c.Grace <= 0 {
+		c.Grace = time.Hour
+	}
+}
+
+// Job accrues interest daily on the end-of-day balance of interest-bearing
+// accounts and pays it out once a month. Both steps are keyed by date, so the
+// job can be rerun or run on several instances without paying twice.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config Config) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run accrues and posts straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot process interest: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce accrues every day up to the last closed one that hasn't been accrued
+// yet and posts the last closed month
+func (j *Job) RunOnce(ctx context.Context) error {
+	dayEnd := snapshot.LastClosedDay(j.now().Add(-j.config.Grace))
+	lastClosedDay := dayEnd.AddDate(0, 0, -1)
+
+	from, err := j.firstDayToAccrue(ctx, lastClosedDay)
+	if err != nil {
+		return err
+	}
+	for day := from; !day.After(lastClosedDay); day = day.AddDate(0, 0, 1) {
+		if err := j.Accrue(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	monthStart := time.Date(dayEnd.Year(), dayEnd.Month(), 1, 0, 0, 0, 0, time.UTC)
+	_, err = j.Post(ctx, monthStart.AddDate(0, -1, 0), monthStart.AddDate(0, 0, -1))
+	return err
+}
+
+// firstDayToAccrue picks up where the last run stopped, so days missed while
+// the job was down or failing are still accrued. The last accrued day is done
+// again as a failed run may have left some accounts out, accruals already
+// recorded are skipped. The first run only accrues the last closed day.
+func (j *Job) firstDayToAccrue(ctx context.Context, lastClosedDay time.Time) (time.Time, error) {
+	last, err := j.store.GetLastInterestAccrualDate(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return lastClosedDay, nil
+		}
+		return time.Time{}, err
+	}
+	return time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// Accrue records a day's interest for every interest-bearing account
+func (j *Job) Accrue(ctx context.Context, day time.Time) error {
+	// the end-of-day balance includes everything up to the last microsecond of the day
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Microsecond)
+
+	var afterID int64
+	for {
+		accounts, err := j.store.ListInterestBearingAccounts(ctx, db.ListInterestBearingAccountsParams{
+			AfterID:   afterID,
+			BatchSize: j.config.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			balance, err := j.store.GetBalanceAt(ctx, db.BalanceAtParams{
+				AccountID: account.ID,
+				At:        endOfDay,
+			})
+			if err != nil {
+				return err
+			}
+
+			// only credit balances earn interest
+			var micros int64
+			if balance > 0 {
+				micros, err = utils.DailyInterestMicros(balance, account.InterestRateBps, account.DayCount, day)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = j.store.CreateInterestAccrual(ctx, db.CreateInterestAccrualParams{
+				AccountID:    account.ID,
+				AccrualDate:  day,
+				Balance:      balance,
+				RateBps:      account.InterestRateBps,
+				DayCount:     account.DayCount,
+				AmountMicros: micros,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(accounts) < int(j.config.BatchSize) {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// Post pays out the interest accrued between periodStart and periodEnd, inclusive,
+// to every account that hasn't been paid for the period yet. An account that can't
+// be paid, because it is blocked for instance, is logged and retried on the next run.
+func (j *Job) Post(ctx context.Context, periodStart, periodEnd time.Time) (int, error) {
+	var posted int
+	var afterID int64
+	for {
+		accountIDs, err := j.store.ListUnpostedInterestAccounts(ctx, db.ListUnpostedInterestAccountsParams{
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			AfterID:     afterID,
+			BatchSize:   j.config.BatchSize,
+		})
+		if err != nil {
+			return posted, err
+		}
+
+		for _, accountID := range accountIDs {
+			accrued, err := j.store.SumInterestAccruals(ctx, db.SumInterestAccrualsParams{
+				AccountID:   accountID,
+				PeriodStart: periodStart,
+				PeriodEnd:   periodEnd,
+			})
+			if err != nil {
+				return posted, err
+			}
+
+			_, err = j.store.PostInterestTrxn(ctx, db.PostInterestTxnParams{
+				AccountID:     accountID,
+				PeriodStart:   periodStart,
+				PeriodEnd:     periodEnd,
+				AccruedMicros: accrued,
+				Amount:        utils.RoundMicros(accrued),
+			})
+			switch {
+			case err == nil:
+				posted++
+			case errors.Is(err, db.ErrInterestAlreadyPosted):
+			default:
+				log.Printf("[ERROR] cannot post interest to account %d: %v", accountID, err)
+			}
+		}
+
+		if len(accountIDs) < int(j.config.BatchSize) {
+			return posted, nil
+		}
+		afterID = accountIDs[len(accountIDs)-1]
+	}
+}
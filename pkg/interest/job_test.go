@@ -0,0 +1,148 @@
+package interest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobAccrue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	endOfDay := time.Date(2024, 3, 8, 23, 59, 59, 999999000, time.UTC)
+
+	expected, err := utils.DailyInterestMicros(1_000_000, 250, utils.DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(68_493_151), expected)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListInterestBearingAccounts(gomock.Any(), gomock.Eq(db.ListInterestBearingAccountsParams{AfterID: 0, BatchSize: 2})).
+		Return([]db.ListInterestBearingAccountsRow{
+			{ID: 1, CurrencyCode: utils.USD, InterestRateBps: 250, DayCount: utils.DayCountActual365},
+			{ID: 2, CurrencyCode: utils.USD, InterestRateBps: 250, DayCount: utils.DayCountActual365},
+		}, nil)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Eq(db.BalanceAtParams{AccountID: 1, At: endOfDay})).
+		Return(int64(1_000_000), nil)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Eq(db.BalanceAtParams{AccountID: 2, At: endOfDay})).
+		Return(int64(-500), nil)
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+		AccountID:    1,
+		AccrualDate:  day,
+		Balance:      1_000_000,
+		RateBps:      250,
+		DayCount:     utils.DayCountActual365,
+		AmountMicros: expected,
+	})).Return(nil)
+	// an overdrawn balance is recorded but earns nothing
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Eq(db.CreateInterestAccrualParams{
+		AccountID:    2,
+		AccrualDate:  day,
+		Balance:      -500,
+		RateBps:      250,
+		DayCount:     utils.DayCountActual365,
+		AmountMicros: 0,
+	})).Return(nil)
+	store.EXPECT().ListInterestBearingAccounts(gomock.Any(), gomock.Eq(db.ListInterestBearingAccountsParams{AfterID: 2, BatchSize: 2})).
+		Return([]db.ListInterestBearingAccountsRow{}, nil)
+
+	job := NewJob(store, Config{BatchSize: 2})
+	require.NoError(t, job.Accrue(context.Background(), day))
+}
+
+func TestJobRunOnceCatchesUpMissedDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the job was down on the 8th and 9th, the 7th was the last day accrued
+	now := time.Date(2024, 3, 10, 2, 0, 0, 0, time.UTC)
+	lastAccrued := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetLastInterestAccrualDate(gomock.Any()).Times(1).Return(lastAccrued, nil)
+	store.EXPECT().ListInterestBearingAccounts(gomock.Any(), gomock.Any()).Times(3).
+		Return([]db.ListInterestBearingAccountsRow{
+			{ID: 1, CurrencyCode: utils.USD, InterestRateBps: 250, DayCount: utils.DayCountActual365},
+		}, nil)
+	store.EXPECT().GetBalanceAt(gomock.Any(), gomock.Any()).Times(3).Return(int64(1_000_000), nil)
+
+	var accrued []time.Time
+	store.EXPECT().CreateInterestAccrual(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CreateInterestAccrualParams) error {
+			accrued = append(accrued, arg.AccrualDate)
+			return nil
+		})
+	store.EXPECT().ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+
+	job := NewJob(store, Config{})
+	job.now = func() time.Time { return now }
+	require.NoError(t, job.RunOnce(context.Background()))
+
+	require.Equal(t, []time.Time{
+		lastAccrued,
+		time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+	}, accrued)
+}
+
+func TestJobRunOnceFirstRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetLastInterestAccrualDate(gomock.Any()).Times(1).Return(time.Time{}, sql.ErrNoRows)
+	store.EXPECT().ListInterestBearingAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListInterestBearingAccountsRow{}, nil)
+	store.EXPECT().ListUnpostedInterestAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]int64{}, nil)
+
+	job := NewJob(store, Config{})
+	job.now = func() time.Time { return time.Date(2024, 3, 10, 2, 0, 0, 0, time.UTC) }
+	require.NoError(t, job.RunOnce(context.Background()))
+}
+
+func TestJobPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListUnpostedInterestAccounts(gomock.Any(), gomock.Eq(db.ListUnpostedInterestAccountsParams{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		AfterID:     0,
+		BatchSize:   10,
+	})).Return([]int64{1, 2}, nil)
+	store.EXPECT().SumInterestAccruals(gomock.Any(), gomock.Eq(db.SumInterestAccrualsParams{AccountID: 1, PeriodStart: start, PeriodEnd: end})).
+		Return(int64(1_986_301_379), nil)
+	store.EXPECT().SumInterestAccruals(gomock.Any(), gomock.Eq(db.SumInterestAccrualsParams{AccountID: 2, PeriodStart: start, PeriodEnd: end})).
+		Return(int64(2_500_000), nil)
+	store.EXPECT().PostInterestTrxn(gomock.Any(), gomock.Eq(db.PostInterestTxnParams{
+		AccountID:     1,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		AccruedMicros: 1_986_301_379,
+		Amount:        1986,
+	})).Return(db.InterestPosting{AccountID: 1, Amount: 1986}, nil)
+	// another instance got there first
+	store.EXPECT().PostInterestTrxn(gomock.Any(), gomock.Eq(db.PostInterestTxnParams{
+		AccountID:     2,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		AccruedMicros: 2_500_000,
+		Amount:        2,
+	})).Return(db.InterestPosting{}, db.ErrInterestAlreadyPosted)
+
+	job := NewJob(store, Config{BatchSize: 10})
+	posted, err := job.Post(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Equal(t, 1, posted)
+}
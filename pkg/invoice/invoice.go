@@ -0,0 +1,157 @@
+package invoice
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MaxTaxRateBps is a 100% tax rate in basis points
+const MaxTaxRateBps = 10000
+
+// payTokenLength is the number of random bytes in a pay token
+const payTokenLength = 24
+
+var ErrAmountOverflow = errors.New("invoice total is too large")
+
+// Item is a line on an invoice, it costs Quantity times UnitAmount
+type Item struct {
+	Description string
+	Quantity    int64
+	UnitAmount  int64
+}
+
+// Amount is what the line costs before tax
+func (i Item) Amount() (int64, error) {
+	if i.UnitAmount != 0 && i.Quantity > (1<<63-1)/i.UnitAmount {
+		return 0, ErrAmountOverflow
+	}
+	return i.Quantity * i.UnitAmount, nil
+}
+
+// Totals adds up an invoice. Tax is charged on the subtotal and rounded half
+// up to the currency's minor unit.
+func Totals(items []Item, taxRateBps int32) (subtotal, tax, total int64, err error) {
+	for _, item := range items {
+		amount, err := item.Amount()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if subtotal > 1<<63-1-amount {
+			return 0, 0, 0, ErrAmountOverflow
+		}
+		subtotal += amount
+	}
+
+	if subtotal > (1<<63-1-MaxTaxRateBps/2)/MaxTaxRateBps {
+		return 0, 0, 0, ErrAmountOverflow
+	}
+	tax = (subtotal*int64(taxRateBps) + MaxTaxRateBps/2) / MaxTaxRateBps
+	return subtotal, tax, subtotal + tax, nil
+}
+
+// NewPayToken returns the url safe secret that goes in an invoice's pay link
+func NewPayToken() (string, error) {
+	buf := make([]byte, payTokenLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate pay token : [%w] ", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Aging buckets, by whole days past the due date
+const (
+	BucketCurrent = "current"
+	Bucket1To30   = "1-30"
+	Bucket31To60  = "31-60"
+	Bucket61To90  = "61-90"
+	BucketOver90  = "90+"
+)
+
+const (
+	hoursInDay    = 24
+	daysPerBucket = 30
+)
+
+// Bucket tells which aging bucket an invoice due on dueDate falls in on today.
+// Only the calendar dates count, the time of day is ignored.
+func Bucket(dueDate, today time.Time) string {
+	days := daysBetween(dueDate, today)
+	switch {
+	case days <= 0:
+		return BucketCurrent
+	case days <= daysPerBucket:
+		return Bucket1To30
+	case days <= 2*daysPerBucket:
+		return Bucket31To60
+	case days <= 3*daysPerBucket:
+		return Bucket61To90
+	}
+	return BucketOver90
+}
+
+func daysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / hoursInDay)
+}
+
+// Outstanding is what is still owed on an invoice
+type Outstanding struct {
+	CurrencyCode string
+	DueDate      time.Time
+	AmountDue    int64
+}
+
+// AgingRow sums what is owed in one currency by how late it is
+type AgingRow struct {
+	CurrencyCode string `json:"currency_code"`
+	Current      int64  `json:"current"`
+	Days1To30    int64  `json:"days_1_30"`
+	Days31To60   int64  `json:"days_31_60"`
+	Days61To90   int64  `json:"days_61_90"`
+	Over90       int64  `json:"over_90"`
+	Total        int64  `json:"total"`
+	Invoices     int    `json:"invoices"`
+}
+
+// Aging builds the aging report of the outstanding invoices, one row per
+// currency in currency order since amounts in different currencies can't be
+// added up
+func Aging(invoices []Outstanding, today time.Time) []AgingRow {
+	rows := map[string]*AgingRow{}
+	for _, invoice := range invoices {
+		row, ok := rows[invoice.CurrencyCode]
+		if !ok {
+			row = &AgingRow{CurrencyCode: invoice.CurrencyCode}
+			rows[invoice.CurrencyCode] = row
+		}
+
+		switch Bucket(invoice.DueDate, today) {
+		case BucketCurrent:
+			row.Current += invoice.AmountDue
+		case Bucket1To30:
+			row.Days1To30 += invoice.AmountDue
+		case Bucket31To60:
+			row.Days31To60 += invoice.AmountDue
+		case Bucket61To90:
+			row.Days61To90 += invoice.AmountDue
+		default:
+			row.Over90 += invoice.AmountDue
+		}
+		row.Total += invoice.AmountDue
+		row.Invoices++
+	}
+
+	report := make([]AgingRow, 0, len(rows))
+	for _, row := range rows {
+		report = append(report, *row)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].CurrencyCode < report[j].CurrencyCode
+	})
+	return report
+}
@@ -0,0 +1,67 @@
+package invoice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTotals(t *testing.T) {
+	items := []Item{
+		{Description: "design", Quantity: 3, UnitAmount: 1500},
+		{Description: "hosting", Quantity: 1, UnitAmount: 999},
+	}
+
+	subtotal, tax, total, err := Totals(items, 750)
+	require.NoError(t, err)
+	require.Equal(t, int64(5499), subtotal)
+	// 412.425 rounds down
+	require.Equal(t, int64(412), tax)
+	require.Equal(t, int64(5911), total)
+
+	// 0.5 rounds up
+	_, tax, _, err = Totals([]Item{{Quantity: 1, UnitAmount: 10}}, 500)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tax)
+
+	_, _, _, err = Totals([]Item{{Quantity: 1 << 40, UnitAmount: 1 << 40}}, 0)
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestNewPayToken(t *testing.T) {
+	first, err := NewPayToken()
+	require.NoError(t, err)
+	second, err := NewPayToken()
+	require.NoError(t, err)
+
+	require.Len(t, first, 32)
+	require.NotEqual(t, first, second)
+}
+
+func TestBucket(t *testing.T) {
+	due := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, BucketCurrent, Bucket(due, due.Add(23*time.Hour)))
+	require.Equal(t, Bucket1To30, Bucket(due, due.AddDate(0, 0, 1)))
+	require.Equal(t, Bucket1To30, Bucket(due, due.AddDate(0, 0, 30)))
+	require.Equal(t, Bucket31To60, Bucket(due, due.AddDate(0, 0, 31)))
+	require.Equal(t, Bucket61To90, Bucket(due, due.AddDate(0, 0, 90)))
+	require.Equal(t, BucketOver90, Bucket(due, due.AddDate(0, 0, 91)))
+}
+
+func TestAging(t *testing.T) {
+	today := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	report := Aging([]Outstanding{
+		{CurrencyCode: "USD", DueDate: today.AddDate(0, 0, 5), AmountDue: 100},
+		{CurrencyCode: "USD", DueDate: today.AddDate(0, 0, -10), AmountDue: 200},
+		{CurrencyCode: "USD", DueDate: today.AddDate(0, 0, -100), AmountDue: 300},
+		{CurrencyCode: "EUR", DueDate: today.AddDate(0, 0, -45), AmountDue: 50},
+	}, today)
+
+	require.Equal(t, []AgingRow{
+		{CurrencyCode: "EUR", Days31To60: 50, Total: 50, Invoices: 1},
+		{CurrencyCode: "USD", Current: 100, Days1To30: 200, Over90: 300, Total: 600, Invoices: 3},
+	}, report)
+}
@@ -0,0 +1,58 @@
+package invoice
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// JobConfig tunes the overdue job, a zero Interval falls back to an hour
+type JobConfig struct {
+	Interval time.Duration
+}
+
+func (c *JobConfig) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Hour
+	}
+}
+
+// Job marks sent invoices overdue once their due date has passed and tells the
+// merchants. Overdue invoices can still be paid.
+type Job struct {
+	store  db.Store
+	config JobConfig
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config JobConfig) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run marks overdue invoices straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot mark invoices overdue: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce marks every invoice due before today, in UTC, overdue and returns them
+func (j *Job) RunOnce(ctx context.Context) ([]db.Invoice, error) {
+	now := j.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return j.store.MarkInvoicesOverdueTrxn(ctx, today)
+}
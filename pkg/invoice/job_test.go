@@ -0,0 +1,33 @@
+package invoice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().MarkInvoicesOverdueTrxn(gomock.Any(), gomock.Eq(today)).Times(1).
+		Return([]db.Invoice{{ID: 7, Status: db.InvoiceOverdue}}, nil)
+
+	job := NewJob(store, JobConfig{})
+	job.now = func() time.Time { return now }
+	require.Equal(t, time.Hour, job.config.Interval)
+
+	overdue, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	require.Equal(t, int64(7), overdue[0].ID)
+}
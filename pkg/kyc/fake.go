@@ -0,0 +1,59 @@
+package kyc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// FakeProviderName is the provider used when none is configured
+const FakeProviderName = "fake"
+
+func init() {
+	Register(FakeProviderName, func(string) (Provider, error) {
+		return FakeProvider{}, nil
+	})
+}
+
+// FakeProvider verifies applicants locally without calling anyone, for
+// development and tests. It asks for a closer look at applicants under 18,
+// without an identity document, or whose ID number ends in 0000.
+type FakeProvider struct {
+	Now func() time.Time
+}
+
+func (FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p FakeProvider) Check(_ context.Context, applicant Applicant) (Result, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+
+	sum := sha256.Sum256([]byte(applicant.Username + ":" + applicant.IDNumber))
+	result := Result{
+		Provider:  FakeProviderName,
+		Reference: "fake_" + hex.EncodeToString(sum[:8]),
+		Outcome:   OutcomeClear,
+		Reasons:   []string{},
+	}
+
+	if applicant.DateOfBirth.AddDate(18, 0, 0).After(now()) {
+		result.Reasons = append(result.Reasons, "applicant is under 18")
+	}
+	if !HasIdentityDocument(applicant.Documents) {
+		result.Reasons = append(result.Reasons, "no identity document")
+	}
+	if strings.HasSuffix(applicant.IDNumber, "0000") {
+		result.Reasons = append(result.Reasons, "id number could not be matched")
+	}
+
+	if len(result.Reasons) > 0 {
+		result.Outcome = OutcomeConsider
+	}
+	return result, nil
+}
@@ -0,0 +1,113 @@
+package kyc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Outcomes of an identity check. A check only advises the reviewer, approving
+// or rejecting a customer is always a back-office decision.
+const (
+	OutcomeClear    = "clear"
+	OutcomeConsider = "consider"
+)
+
+// Document types customers can upload
+const (
+	DocumentPassport       = "passport"
+	DocumentNationalID     = "national_id"
+	DocumentDriversLicense = "drivers_license"
+	DocumentProofOfAddress = "proof_of_address"
+	DocumentSelfie         = "selfie"
+)
+
+// HasIdentityDocument reports whether documents include one that proves identity
+func HasIdentityDocument(documents []Document) bool {
+	for _, document := range documents {
+		switch document.Type {
+		case DocumentPassport, DocumentNationalID, DocumentDriversLicense:
+			return true
+		}
+	}
+	return false
+}
+
+// Document is an uploaded identity document, identified by its content hash
+type Document struct {
+	Type   string `json:"type"`
+	SHA256 string `json:"sha256"`
+}
+
+// Applicant is what a provider is asked to verify
+type Applicant struct {
+	Username    string     `json:"username"`
+	FullName    string     `json:"full_name"`
+	Email       string     `json:"email"`
+	DateOfBirth time.Time  `json:"date_of_birth"`
+	CountryCode string     `json:"country_code"`
+	Nationality string     `json:"nationality"`
+	IDNumber    string     `json:"id_number"`
+	Documents   []Document `json:"documents"`
+}
+
+// Result is a provider's verdict on an applicant
+type Result struct {
+	Provider  string   `json:"provider"`
+	Reference string   `json:"reference"`
+	Outcome   string   `json:"outcome"`
+	Reasons   []string `json:"reasons"`
+}
+
+// Provider checks an applicant's identity with a third-party service
+type Provider interface {
+	Name() string
+	Check(ctx context.Context, applicant Applicant) (Result, error)
+}
+
+// Factory builds a provider from its configuration string
+type Factory func(config string) (Provider, error)
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+)
+
+// Register makes a provider available under name, it panics if the name is taken
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("kyc provider %q is already registered", name))
+	}
+	factories[name] = factory
+}
+
+// NewProvider builds the provider registered under name
+func NewProvider(name, config string) (Provider, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown kyc provider %q, available: %s", name, strings.Join(Providers(), ", "))
+	}
+	return factory(config)
+}
+
+// Providers lists the registered provider names
+func Providers() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package kyc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(FakeProviderName, "")
+	require.NoError(t, err)
+	require.Equal(t, FakeProviderName, provider.Name())
+
+	_, err = NewProvider("unknown", "")
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		Register(FakeProviderName, func(string) (Provider, error) { return FakeProvider{}, nil })
+	})
+}
+
+func TestFakeProviderCheck(t *testing.T) {
+	now := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	provider := FakeProvider{Now: func() time.Time { return now }}
+
+	applicant := Applicant{
+		Username:    "ada",
+		DateOfBirth: time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC),
+		IDNumber:    "A12345678",
+		Documents:   []Document{{Type: DocumentNationalID, SHA256: "abc"}},
+	}
+
+	result, err := provider.Check(context.Background(), applicant)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeClear, result.Outcome)
+	require.Empty(t, result.Reasons)
+	require.NotEmpty(t, result.Reference)
+
+	// the reference is stable for the same applicant
+	again, err := provider.Check(context.Background(), applicant)
+	require.NoError(t, err)
+	require.Equal(t, result.Reference, again.Reference)
+
+	applicant.DateOfBirth = now.AddDate(-17, 0, 0)
+	applicant.IDNumber = "B00000000"
+	applicant.Documents = []Document{{Type: DocumentSelfie, SHA256: "def"}}
+
+	result, err = provider.Check(context.Background(), applicant)
+	require.NoError(t, err)
+	require.Equal(t, OutcomeConsider, result.Outcome)
+	require.Len(t, result.Reasons, 3)
+}
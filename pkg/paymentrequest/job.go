@@ -0,0 +1,57 @@
+package paymentrequest
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Config tunes the job, a zero Interval falls back to a minute
+type Config struct {
+	Interval time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Minute
+	}
+}
+
+// Job expires payment requests that weren't paid in time and notifies both
+// sides. Payments are refused once a request is past its expiry anyway, the
+// job only closes it so it stops showing up as pending.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config Config) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run expires overdue requests straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot expire payment requests: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce expires every overdue payment request and returns them
+func (j *Job) RunOnce(ctx context.Context) ([]db.PaymentRequest, error) {
+	return j.store.ExpirePaymentRequestsTrxn(ctx, j.now())
+}
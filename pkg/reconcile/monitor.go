@@ -0,0 +1,100 @@
+package reconcile
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// States of the latest scheduled run
+const (
+	StatePending       = "pending"
+	StateOK            = "ok"
+	StateDiscrepancies = "discrepancies"
+	StateError         = "error"
+)
+
+// maxKeptDiscrepancies bounds how many discrepancies a Monitor remembers, the
+// reconcile command reports all of them
+const maxKeptDiscrepancies = 100
+
+// Status is the outcome of the latest scheduled run
+type Status struct {
+	State         string        `json:"state"`
+	Summary       *Summary      `json:"summary,omitempty"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+	Error         string        `json:"error,omitempty"`
+}
+
+// Healthy reports whether the ledger is known to be consistent or not checked yet
+func (s Status) Healthy() bool {
+	return s.State == StatePending || s.State == StateOK
+}
+
+// Monitor runs the reconciler on a schedule and keeps the latest outcome as a health signal
+type Monitor struct {
+	reconciler *Reconciler
+
+	mu     sync.RWMutex
+	status Status
+}
+
+func NewMonitor(reconciler *Reconciler) *Monitor {
+	return &Monitor{
+		reconciler: reconciler,
+		status:     Status{State: StatePending, Discrepancies: []Discrepancy{}},
+	}
+}
+
+// Run reconciles straight away and then every interval until ctx is cancelled
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := m.RunOnce(ctx)
+		if !status.Healthy() {
+			log.Printf("[ERROR] ledger reconciliation: %s, %d discrepancies %s", status.State, len(status.Discrepancies), status.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce reconciles and records the outcome
+func (m *Monitor) RunOnce(ctx context.Context) Status {
+	status := Status{State: StateOK, Discrepancies: []Discrepancy{}}
+
+	summary, err := m.reconciler.Run(ctx, func(discrepancy Discrepancy) error {
+		if len(status.Discrepancies) < maxKeptDiscrepancies {
+			status.Discrepancies = append(status.Discrepancies, discrepancy)
+		}
+		return nil
+	})
+	status.Summary = &summary
+
+	switch {
+	case err != nil:
+		status.State = StateError
+		status.Error = err.Error()
+	case summary.Discrepancies > 0:
+		status.State = StateDiscrepancies
+	}
+
+	m.mu.Lock()
+	m.status = status
+	m.mu.Unlock()
+	return status
+}
+
+// Status returns the outcome of the latest run
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
@@ -0,0 +1,213 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+// Checks a discrepancy can come from
+const (
+	CheckAccountBalance  = "account_balance"
+	CheckTransferEntries = "transfer_entries"
+	CheckLedgerBalance   = "ledger_balance"
+)
+
+// Discrepancy is one broken invariant, reported as a line of JSON
+type Discrepancy struct {
+	Check        string `json:"check"`
+	AccountID    int64  `json:"account_id,omitempty"`
+	TransferID   int64  `json:"transfer_id,omitempty"`
+	CurrencyCode string `json:"currency_code,omitempty"`
+	Expected     int64  `json:"expected"`
+	Actual       int64  `json:"actual"`
+	Detail       string `json:"detail"`
+}
+
+// Summary describes a finished run
+type Summary struct {
+	StartedAt        time.Time `json:"started_at"`
+	FinishedAt       time.Time `json:"finished_at"`
+	AccountsChecked  int       `json:"accounts_checked"`
+	TransfersChecked int       `json:"transfers_checked"`
+	Discrepancies    int       `json:"discrepancies"`
+	BlockedAccounts  []int64   `json:"blocked_accounts"`
+}
+
+// Options tunes a run, a zero BatchSize falls back to 1000 rows per query
+type Options struct {
+	BatchSize int32
+	// BlockAccounts blocks every account involved in a discrepancy so no more
+	// money moves through it until someone has looked at it
+	BlockAccounts bool
+}
+
+// Reconciler checks that account balances equal the sum of their entries,
+// that every transfer has exactly its two entries and that the ledger's
+// debits equal its credits in every currency. Tables are read in batches by
+// id so memory use doesn't grow with their size.
+type Reconciler struct {
+	store   db.Store
+	options Options
+}
+
+func New(store db.Store, options Options) *Reconciler {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	return &Reconciler{store: store, options: options}
+}
+
+// Run checks every invariant, handing each discrepancy to report as it is found
+func (r *Reconciler) Run(ctx context.Context, report func(Discrepancy) error) (Summary, error) {
+	summary := Summary{
+		StartedAt:       time.Now(),
+		BlockedAccounts: []int64{},
+	}
+	blocked := make(map[int64]bool)
+
+	found := func(discrepancy Discrepancy, accountIDs ...int64) error {
+		summary.Discrepancies++
+		if err := report(discrepancy); err != nil {
+			return err
+		}
+		if !r.options.BlockAccounts {
+			return nil
+		}
+		for _, id := range accountIDs {
+			if blocked[id] {
+				continue
+			}
+			if err := r.block(ctx, id); err != nil {
+				return err
+			}
+			blocked[id] = true
+			summary.BlockedAccounts = append(summary.BlockedAccounts, id)
+		}
+		return nil
+	}
+
+	err := r.checkAccounts(ctx, &summary, found)
+	if err == nil {
+		err = r.checkTransfers(ctx, &summary, found)
+	}
+	if err == nil {
+		err = r.checkLedger(ctx, found)
+	}
+
+	summary.FinishedAt = time.Now()
+	return summary, err
+}
+
+func (r *Reconciler) checkAccounts(ctx context.Context, summary *Summary, found func(Discrepancy, ...int64) error) error {
+	var afterID int64
+	for {
+		accounts, err := r.store.ListAccountEntryTotals(ctx, db.ListAccountEntryTotalsParams{
+			AfterID:   afterID,
+			BatchSize: r.options.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			if account.Balance == account.EntriesTotal {
+				continue
+			}
+			err = found(Discrepancy{
+				Check:        CheckAccountBalance,
+				AccountID:    account.ID,
+				CurrencyCode: account.CurrencyCode,
+				Expected:     account.EntriesTotal,
+				Actual:       account.Balance,
+				Detail:       "balance differs from the sum of the account's entries",
+			}, account.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		summary.AccountsChecked += len(accounts)
+		if len(accounts) < int(r.options.BatchSize) {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+func (r *Reconciler) checkTransfers(ctx context.Context, summary *Summary, found func(Discrepancy, ...int64) error) error {
+	var afterID int64
+	for {
+		transfers, err := r.store.ListTransferEntryCounts(ctx, db.ListTransferEntryCountsParams{
+			AfterID:   afterID,
+			BatchSize: r.options.BatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			if transfer.FromEntries == 1 && transfer.ToEntries == 1 && transfer.TotalEntries == 2 {
+				continue
+			}
+			err = found(Discrepancy{
+				Check:      CheckTransferEntries,
+				TransferID: transfer.ID,
+				Expected:   2,
+				Actual:     transfer.TotalEntries,
+				Detail: fmt.Sprintf("expected one debit entry on account %d and one credit entry on account %d, found %d and %d",
+					transfer.FromAccountID, transfer.ToAccountID, transfer.FromEntries, transfer.ToEntries),
+			}, transfer.FromAccountID, transfer.ToAccountID)
+			if err != nil {
+				return err
+			}
+		}
+
+		summary.TransfersChecked += len(transfers)
+		if len(transfers) < int(r.options.BatchSize) {
+			return nil
+		}
+		afterID = transfers[len(transfers)-1].ID
+	}
+}
+
+func (r *Reconciler) checkLedger(ctx context.Context, found func(Discrepancy, ...int64) error) error {
+	totals, err := r.store.SumPostingsByCurrency(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, total := range totals {
+		if total.Credits == total.Debits {
+			continue
+		}
+		err = found(Discrepancy{
+			Check:        CheckLedgerBalance,
+			CurrencyCode: total.CurrencyCode,
+			Expected:     total.Debits,
+			Actual:       total.Credits,
+			Detail:       "ledger credits differ from debits",
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) block(ctx context.Context, accountID int64) error {
+	ctx = context.WithValue(ctx, db.AuditMetadataKey, db.AuditMetadata{Actor: "reconciler"})
+	_, err := r.store.SetAccountStatus(ctx, db.SetAccountStatusParams{
+		ID:     accountID,
+		Status: db.AccountStatusBlocked,
+	})
+	// the account may have been deleted since it was checked
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcilerRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	// accounts are read in batches of two, the short third batch ends the scan
+	gomock.InOrder(
+		store.EXPECT().ListAccountEntryTotals(gomock.Any(), gomock.Eq(db.ListAccountEntryTotalsParams{AfterID: 0, BatchSize: 2})).
+			Return([]db.ListAccountEntryTotalsRow{
+				{ID: 1, CurrencyCode: "USD", Balance: 100, EntriesTotal: 100},
+				{ID: 2, CurrencyCode: "USD", Balance: 90, EntriesTotal: 80},
+			}, nil),
+		store.EXPECT().ListAccountEntryTotals(gomock.Any(), gomock.Eq(db.ListAccountEntryTotalsParams{AfterID: 2, BatchSize: 2})).
+			Return([]db.ListAccountEntryTotalsRow{
+				{ID: 3, CurrencyCode: "EUR", Balance: 0, EntriesTotal: 0},
+			}, nil),
+	)
+	store.EXPECT().ListTransferEntryCounts(gomock.Any(), gomock.Eq(db.ListTransferEntryCountsParams{AfterID: 0, BatchSize: 2})).
+		Return([]db.ListTransferEntryCountsRow{
+			{ID: 7, FromAccountID: 1, ToAccountID: 2, Amount: 10, FromEntries: 1, ToEntries: 1, TotalEntries: 2},
+			{ID: 8, FromAccountID: 2, ToAccountID: 3, Amount: 10, FromEntries: 1, ToEntries: 0, TotalEntries: 1},
+		}, nil)
+	store.EXPECT().ListTransferEntryCounts(gomock.Any(), gomock.Eq(db.ListTransferEntryCountsParams{AfterID: 8, BatchSize: 2})).
+		Return([]db.ListTransferEntryCountsRow{}, nil)
+	store.EXPECT().SumPostingsByCurrency(gomock.Any()).
+		Return([]db.SumPostingsByCurrencyRow{
+			{CurrencyCode: "EUR", Credits: 50, Debits: 50},
+			{CurrencyCode: "USD", Credits: 70, Debits: 60},
+		}, nil)
+
+	// account 2 shows up twice but is only blocked once
+	for _, id := range []int64{2, 3} {
+		store.EXPECT().SetAccountStatus(gomock.Any(), gomock.Eq(db.SetAccountStatusParams{
+			ID:     id,
+			Status: db.AccountStatusBlocked,
+		})).Times(1).Return(db.Account{ID: id, Status: db.AccountStatusBlocked}, nil)
+	}
+
+	var discrepancies []Discrepancy
+	summary, err := New(store, Options{BatchSize: 2, BlockAccounts: true}).Run(context.Background(), func(d Discrepancy) error {
+		discrepancies = append(discrepancies, d)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 3, summary.AccountsChecked)
+	require.Equal(t, 2, summary.TransfersChecked)
+	require.Equal(t, 3, summary.Discrepancies)
+	require.Equal(t, []int64{2, 3}, summary.BlockedAccounts)
+
+	require.Len(t, discrepancies, 3)
+	require.Equal(t, Discrepancy{
+		Check:        CheckAccountBalance,
+		AccountID:    2,
+		CurrencyCode: "USD",
+		Expected:     80,
+		Actual:       90,
+		Detail:       "balance differs from the sum of the account's entries",
+	}, discrepancies[0])
+	require.Equal(t, CheckTransferEntries, discrepancies[1].Check)
+	require.Equal(t, int64(8), discrepancies[1].TransferID)
+	require.Equal(t, CheckLedgerBalance, discrepancies[2].Check)
+	require.Equal(t, "USD", discrepancies[2].CurrencyCode)
+}
+
+func TestMonitor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListAccountEntryTotals(gomock.Any(), gomock.Any()).Times(2).
+		Return([]db.ListAccountEntryTotalsRow{{ID: 1, Balance: 10, EntriesTotal: 10}}, nil)
+	store.EXPECT().ListTransferEntryCounts(gomock.Any(), gomock.Any()).Times(2).
+		Return([]db.ListTransferEntryCountsRow{}, nil)
+	gomock.InOrder(
+		store.EXPECT().SumPostingsByCurrency(gomock.Any()).Return([]db.SumPostingsByCurrencyRow{}, nil),
+		store.EXPECT().SumPostingsByCurrency(gomock.Any()).Return([]db.SumPostingsByCurrencyRow{
+			{CurrencyCode: "USD", Credits: 10, Debits: 0},
+		}, nil),
+	)
+	store.EXPECT().SetAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	monitor := NewMonitor(New(store, Options{}))
+	require.Equal(t, StatePending, monitor.Status().State)
+	require.True(t, monitor.Status().Healthy())
+
+	status := monitor.RunOnce(context.Background())
+	require.Equal(t, StateOK, status.State)
+	require.True(t, status.Healthy())
+
+	status = monitor.RunOnce(context.Background())
+	require.Equal(t, StateDiscrepancies, status.State)
+	require.False(t, monitor.Status().Healthy())
+	require.Len(t, monitor.Status().Discrepancies, 1)
+}
@@ -0,0 +1,102 @@
+package snapshot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+const day = 24 * time.Hour
+
+// Config tunes the job, zero values fall back to sensible defaults
+type Config struct {
+	Interval  time.Duration
+	BatchSize int32
+	// Grace is how long after midnight a day is snapshotted, so transactions
+	// that started before midnight have committed by then
+	Grace time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = time.Hour
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 1000
+	}
+	if c.Grace <= 0 {
+		c.Grace = time.Hour
+	}
+}
+
+// Job writes end-of-day balance snapshots for every account. Each snapshot
+// builds on the account's previous one, and writing a snapshot that already
+// exists is a no-op, so runs are cheap and safe to repeat.
+type Job struct {
+	store  db.Store
+	config Config
+	now    func() time.Time
+}
+
+func NewJob(store db.Store, config Config) *Job {
+	config.setDefaults()
+	return &Job{store: store, config: config, now: time.Now}
+}
+
+// Run snapshots the last closed day straight away and then every interval until ctx is cancelled
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			log.Printf("[ERROR] cannot snapshot balances: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce snapshots the last day that closed at least Grace ago and returns its end
+func (j *Job) RunOnce(ctx context.Context) (time.Time, error) {
+	asOf := LastClosedDay(j.now().Add(-j.config.Grace))
+	return asOf, j.Snapshot(ctx, asOf)
+}
+
+// Snapshot writes the balances of every account as of asOf, in batches of accounts
+func (j *Job) Snapshot(ctx context.Context, asOf time.Time) error {
+	var afterID int64
+	for {
+		ids, err := j.store.ListAccountIDs(ctx, db.ListAccountIDsParams{
+			AfterID:   afterID,
+			BatchSize: j.config.BatchSize,
+		})
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = j.store.CreateBalanceSnapshots(ctx, db.CreateBalanceSnapshotsParams{
+			AsOf:       asOf,
+			AccountIds: ids,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(ids) < int(j.config.BatchSize) {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// LastClosedDay returns the most recent midnight UTC at or before t
+func LastClosedDay(t time.Time) time.Time {
+	return t.UTC().Truncate(day)
+}
@@ -0,0 +1,50 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastClosedDay(t *testing.T) {
+	lagos := time.FixedZone("WAT", 60*60)
+
+	require.Equal(t,
+		time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+		LastClosedDay(time.Date(2024, 3, 9, 17, 45, 0, 0, time.UTC)))
+	require.Equal(t,
+		time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+		LastClosedDay(time.Date(2024, 3, 9, 0, 30, 0, 0, lagos)))
+}
+
+func TestJobRunOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	asOf := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ListAccountIDs(gomock.Any(), gomock.Eq(db.ListAccountIDsParams{AfterID: 0, BatchSize: 2})).
+			Return([]int64{1, 2}, nil),
+		store.EXPECT().CreateBalanceSnapshots(gomock.Any(), gomock.Eq(db.CreateBalanceSnapshotsParams{AsOf: asOf, AccountIds: []int64{1, 2}})).
+			Return(nil),
+		store.EXPECT().ListAccountIDs(gomock.Any(), gomock.Eq(db.ListAccountIDsParams{AfterID: 2, BatchSize: 2})).
+			Return([]int64{5}, nil),
+		store.EXPECT().CreateBalanceSnapshots(gomock.Any(), gomock.Eq(db.CreateBalanceSnapshotsParams{AsOf: asOf, AccountIds: []int64{5}})).
+			Return(nil),
+	)
+
+	job := NewJob(store, Config{BatchSize: 2, Grace: time.Hour})
+	// within the grace period the day before yesterday is the last one snapshotted
+	job.now = func() time.Time { return time.Date(2024, 3, 9, 0, 30, 0, 0, time.UTC) }
+
+	snapshotted, err := job.RunOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, asOf, snapshotted)
+}
@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/lib/pq"
+)
+
+// subscriberBuffer is how many notifications a subscriber may fall behind by
+// before it is disconnected
+const subscriberBuffer = 32
+
+// Broker fans account notifications received from Postgres out to the
+// subscribers connected to this process
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan db.AccountNotification]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int64]map[chan db.AccountNotification]struct{}),
+	}
+}
+
+// Subscribe returns the notifications for an account and a func that stops them.
+// The channel is closed when the subscriber can no longer be sure it has seen
+// every notification, it should then reload the account and subscribe again.
+func (b *Broker) Subscribe(accountID int64) (<-chan db.AccountNotification, func()) {
+	ch := make(chan db.AccountNotification, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[accountID] == nil {
+		b.subscribers[accountID] = make(map[chan db.AccountNotification]struct{})
+	}
+	b.subscribers[accountID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(accountID, ch)
+	}
+}
+
+// Publish hands a notification to every subscriber of its account without blocking
+func (b *Broker) Publish(notification db.AccountNotification) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[notification.AccountID] {
+		select {
+		case ch <- notification:
+		default:
+			b.remove(notification.AccountID, ch)
+		}
+	}
+}
+
+// Reset disconnects every subscriber, used when notifications may have been missed
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for accountID, subscribers := range b.subscribers {
+		for ch := range subscribers {
+			b.remove(accountID, ch)
+		}
+	}
+}
+
+func (b *Broker) remove(accountID int64, ch chan db.AccountNotification) {
+	subscribers := b.subscribers[accountID]
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, accountID)
+	}
+}
+
+// Listen publishes the notifications sent on db.AccountEventsChannel until ctx is cancelled
+func (b *Broker) Listen(ctx context.Context, dataSource string) error {
+	listener := pq.NewListener(dataSource, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("[ERROR] account events listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(db.AccountEventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// a nil notification means the connection was re-established and
+			// anything sent in the meantime is lost
+			if notification == nil {
+				b.Reset()
+				continue
+			}
+
+			var event db.AccountNotification
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				log.Printf("[ERROR] cannot decode account notification: %v", err)
+				continue
+			}
+			b.Publish(event)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"testing"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	broker := NewBroker()
+
+	events, unsubscribe := broker.Subscribe(1)
+	others, unsubscribeOthers := broker.Subscribe(2)
+	defer unsubscribeOthers()
+
+	notification := db.AccountNotification{
+		AccountID: 1,
+		Account:   db.Account{ID: 1, Balance: 90},
+		Entry:     db.Entry{ID: 5, AccountID: 1, Amount: -10},
+	}
+	broker.Publish(notification)
+
+	require.Equal(t, notification, <-events)
+	require.Empty(t, others)
+
+	unsubscribe()
+	_, ok := <-events
+	require.False(t, ok)
+
+	// unsubscribing twice must not panic on the closed channel
+	unsubscribe()
+	broker.Publish(notification)
+}
+
+func TestBrokerDisconnectsSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+
+	events, unsubscribe := broker.Subscribe(1)
+	defer unsubscribe()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		broker.Publish(db.AccountNotification{AccountID: 1, Entry: db.Entry{ID: int64(i)}})
+	}
+
+	var received int
+	for range events {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+}
+
+func TestBrokerReset(t *testing.T) {
+	broker := NewBroker()
+
+	first, unsubscribeFirst := broker.Subscribe(1)
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := broker.Subscribe(2)
+	defer unsubscribeSecond()
+
+	broker.Reset()
+
+	_, ok := <-first
+	require.False(t, ok)
+	_, ok = <-second
+	require.False(t, ok)
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Account number schemes. MOD97 numbers are the bank code, a ten digit
+// sequence and two ISO 7064 MOD 97-10 check digits, like the account part of
+// an IBAN. NUBAN numbers, used for NGN accounts, are a nine digit sequence and
+// one check digit computed over the bank code and the sequence.
+const (
+	AccountNumberMOD97 = "mod97"
+	AccountNumberNUBAN = "nuban"
+)
+
+// DefaultBankCode is used when no bank code is configured
+const DefaultBankCode = "999"
+
+const (
+	mod97SequenceDigits = 10
+	nubanSequenceDigits = 9
+)
+
+var nubanWeights = []int{3, 7, 3}
+
+// ValidBankCode reports whether code can prefix account numbers, three to six digits
+func ValidBankCode(code string) bool {
+	return len(code) >= 3 && len(code) <= 6 && isDigits(code)
+}
+
+// AccountNumberScheme returns the scheme accounts in currencyCode are numbered with
+func AccountNumberScheme(currencyCode string) string {
+	if currencyCode == NGN {
+		return AccountNumberNUBAN
+	}
+	return AccountNumberMOD97
+}
+
+// NewAccountNumber returns the account number for the sequence number of an
+// account in currencyCode. Numbers are stable, the same inputs always give the
+// same number.
+func NewAccountNumber(bankCode, currencyCode string, sequence int64) (string, error) {
+	if !ValidBankCode(bankCode) {
+		return "", fmt.Errorf("invalid bank code %q", bankCode)
+	}
+	if sequence < 1 {
+		return "", fmt.Errorf("invalid account sequence %d", sequence)
+	}
+
+	if AccountNumberScheme(currencyCode) == AccountNumberNUBAN {
+		serial := fmt.Sprintf("%0*d", nubanSequenceDigits, sequence)
+		if len(serial) > nubanSequenceDigits {
+			return "", fmt.Errorf("account sequence %d doesn't fit a NUBAN", sequence)
+		}
+		return serial + string(nubanCheckDigit(bankCode, serial)), nil
+	}
+
+	body := bankCode + fmt.Sprintf("%0*d", mod97SequenceDigits, sequence)
+	if len(body) > len(bankCode)+mod97SequenceDigits {
+		return "", fmt.Errorf("account sequence %d doesn't fit an account number", sequence)
+	}
+	return fmt.Sprintf("%s%02d", body, 98-mod97(body+"00")), nil
+}
+
+// ValidAccountNumber reports whether number is a well formed account number of
+// the bank, in either scheme, with correct check digits
+func ValidAccountNumber(bankCode, number string) bool {
+	if !ValidBankCode(bankCode) || !isDigits(number) {
+		return false
+	}
+
+	switch len(number) {
+	case nubanSequenceDigits + 1:
+		return nubanCheckDigit(bankCode, number[:nubanSequenceDigits]) == number[nubanSequenceDigits]
+	case len(bankCode) + mod97SequenceDigits + 2:
+		return strings.HasPrefix(number, bankCode) && mod97(number) == 1
+	}
+	return false
+}
+
+// mod97 returns the remainder of the decimal number in digits divided by 97
+func mod97(digits string) int {
+	remainder := 0
+	for _, digit := range digits {
+		remainder = (remainder*10 + int(digit-'0')) % 97
+	}
+	return remainder
+}
+
+// nubanCheckDigit weighs the bank code and serial digits by 3, 7, 3 in turn
+func nubanCheckDigit(bankCode, serial string) byte {
+	sum := 0
+	for i, digit := range bankCode + serial {
+		sum += int(digit-'0') * nubanWeights[i%len(nubanWeights)]
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
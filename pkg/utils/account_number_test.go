@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNUBANAccountNumber(t *testing.T) {
+	// the worked example from the CBN NUBAN specification
+	number, err := NewAccountNumber("011", NGN, 1457)
+	require.NoError(t, err)
+	require.Equal(t, "0000014579", number)
+	require.True(t, ValidAccountNumber("011", number))
+
+	require.False(t, ValidAccountNumber("011", "0000014578"))
+	require.False(t, ValidAccountNumber("058", number))
+
+	_, err = NewAccountNumber("011", NGN, 1_000_000_000)
+	require.Error(t, err)
+}
+
+func TestMOD97AccountNumber(t *testing.T) {
+	number, err := NewAccountNumber("999", USD, 42)
+	require.NoError(t, err)
+	require.Len(t, number, 15)
+	require.Equal(t, "9990000000042", number[:13])
+	require.True(t, ValidAccountNumber("999", number))
+
+	// a single mistyped digit and swapped neighbours are both caught
+	require.False(t, ValidAccountNumber("999", "9990000000043"+number[13:]))
+	require.False(t, ValidAccountNumber("999", "9990000000024"+number[13:]))
+	require.False(t, ValidAccountNumber("998", number))
+}
+
+func TestAccountNumberInput(t *testing.T) {
+	_, err := NewAccountNumber("12", USD, 1)
+	require.Error(t, err)
+	_, err = NewAccountNumber("999", USD, 0)
+	require.Error(t, err)
+
+	require.False(t, ValidAccountNumber("999", ""))
+	require.False(t, ValidAccountNumber("999", "99900000000a242"))
+	require.Equal(t, AccountNumberNUBAN, AccountNumberScheme(NGN))
+	require.Equal(t, AccountNumberMOD97, AccountNumberScheme(EUR))
+}
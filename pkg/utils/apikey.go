@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	apiKeyLabel        = "sb"
+	apiKeyPrefixLength = 8
+	apiKeySecretLength = 32
+)
+
+// GenerateAPIKey returns a new key formatted as sb_<prefix>_<secret> together
+// with its prefix. Only the prefix and the hash of the key should be stored.
+func GenerateAPIKey() (key string, prefix string, err error) {
+	buf := make([]byte, apiKeyPrefixLength/2+apiKeySecretLength/2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key : [%w] ", err)
+	}
+
+	encoded := hex.EncodeToString(buf)
+	prefix = encoded[:apiKeyPrefixLength]
+	return fmt.Sprintf("%s_%s_%s", apiKeyLabel, prefix, encoded[apiKeyPrefixLength:]), prefix, nil
+}
+
+// APIKeyPrefix extracts the lookup prefix from a key
+func APIKeyPrefix(key string) (string, bool) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyLabel || len(parts[1]) != apiKeyPrefixLength || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashAPIKey returns the hex encoded sha256 of the key. Keys are long random
+// values so a fast hash is enough, unlike passwords.
+func HashAPIKey(key string) string {
+	return sha256Hex(key)
+}
+
+// CheckAPIKey compares a key against its stored hash in constant time
+func CheckAPIKey(key, hashedKey string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hashedKey)) == 1
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidIPRule reports whether rule is an IP address or a CIDR range
+func ValidIPRule(rule string) bool {
+	if net.ParseIP(rule) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(rule)
+	return err == nil
+}
+
+// IPAllowed reports whether ip matches one of the rules. An empty rule set allows any IP.
+func IPAllowed(ip string, rules []string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, rule := range rules {
+		if _, network, err := net.ParseCIDR(rule); err == nil {
+			if network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(rule); allowed != nil && allowed.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_APIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	require.NoError(t, err)
+	require.Len(t, prefix, apiKeyPrefixLength)
+
+	parsed, ok := APIKeyPrefix(key)
+	require.True(t, ok)
+	require.Equal(t, prefix, parsed)
+
+	hashed := HashAPIKey(key)
+	require.True(t, CheckAPIKey(key, hashed))
+	require.False(t, CheckAPIKey(key+"x", hashed))
+
+	_, ok = APIKeyPrefix("not-a-key")
+	require.False(t, ok)
+}
+
+func Test_IPAllowed(t *testing.T) {
+	require.True(t, IPAllowed("10.0.0.1", nil))
+	require.True(t, IPAllowed("10.0.0.1", []string{"10.0.0.0/24"}))
+	require.True(t, IPAllowed("192.168.1.5", []string{"10.0.0.0/24", "192.168.1.5"}))
+	require.False(t, IPAllowed("10.0.1.1", []string{"10.0.0.0/24"}))
+	require.False(t, IPAllowed("garbage", []string{"10.0.0.0/24"}))
+
+	require.True(t, ValidIPRule("::1"))
+	require.True(t, ValidIPRule("10.0.0.0/8"))
+	require.False(t, ValidIPRule("10.0.0.0/33"))
+}
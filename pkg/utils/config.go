@@ -8,19 +8,61 @@ import (
 	"github.com/spf13/viper"
 )
 
+// EnvDevelopment is the environment where development only conveniences,
+// like logging alias verification codes, are allowed
+const EnvDevelopment = "development"
+
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	VBankAddr            string        `mapstructure:"VBANK_ADDR"`
-	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	EmailSenderName      string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderAddress   string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
-	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	Environment           string        `mapstructure:"ENVIRONMENT"`
+	DBDriver              string        `mapstructure:"DB_DRIVER"`
+	DBSource              string        `mapstructure:"DB_SOURCE"`
+	VBankAddr             string        `mapstructure:"VBANK_ADDR"`
+	RedisAddress          string        `mapstructure:"REDIS_ADDRESS"`
+	HTTPServerAddress     string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GRPCServerAddress     string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	TokenType             string        `mapstructure:"TOKEN_TYPE"`
+	TokenIssuer           string        `mapstructure:"TOKEN_ISSUER"`
+	TokenAudience         string        `mapstructure:"TOKEN_AUDIENCE"`
+	TokenSymmetricKey     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenSigningKeyID     string        `mapstructure:"TOKEN_SIGNING_KEY_ID"`
+	TokenSigningKey       string        `mapstructure:"TOKEN_SIGNING_KEY"`
+	TokenVerificationKeys string        `mapstructure:"TOKEN_VERIFICATION_KEYS"`
+	AccessTokenDuration   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	OAuthCodeDuration     time.Duration `mapstructure:"OAUTH_CODE_DURATION"`
+	EmailSenderName       string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress    string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailSenderPassword   string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	MFAIssuer             string        `mapstructure:"MFA_ISSUER"`
+	MFATokenDuration      time.Duration `mapstructure:"MFA_TOKEN_DURATION"`
+	MFATransferThreshold  int64         `mapstructure:"MFA_TRANSFER_THRESHOLD"`
+	MFAMaxAttempts        int32         `mapstructure:"MFA_MAX_ATTEMPTS"`
+	MFALockoutDuration    time.Duration `mapstructure:"MFA_LOCKOUT_DURATION"`
+	WebhookInterval       time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	WebhookMaxAttempts    int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookTimeout        time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	ReconcileInterval     time.Duration `mapstructure:"RECONCILE_INTERVAL"`
+	ReconcileBatchSize    int32         `mapstructure:"RECONCILE_BATCH_SIZE"`
+	ReconcileAutoBlock    bool          `mapstructure:"RECONCILE_AUTO_BLOCK"`
+	SnapshotInterval      time.Duration `mapstructure:"SNAPSHOT_INTERVAL"`
+	InterestInterval      time.Duration `mapstructure:"INTEREST_INTERVAL"`
+	ApprovalInterval      time.Duration `mapstructure:"APPROVAL_INTERVAL"`
+	KYCProvider           string        `mapstructure:"KYC_PROVIDER"`
+	KYCProviderConfig     string        `mapstructure:"KYC_PROVIDER_CONFIG"`
+	FraudSanctionsFile    string        `mapstructure:"FRAUD_SANCTIONS_FILE"`
+	AliasSender           string        `mapstructure:"ALIAS_SENDER"`
+	AliasSenderConfig     string        `mapstructure:"ALIAS_SENDER_CONFIG"`
+	BankCode              string        `mapstructure:"BANK_CODE"`
+	// TrustedProxies are the proxies whose X-Forwarded-For header is believed,
+	// when empty the client IP is the address the request came from
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	// BeneficiaryCoolingOff is how long after a payee is saved transfers of
+	// BeneficiaryCoolingOffAmount or more to it are refused
+	BeneficiaryCoolingOff       time.Duration `mapstructure:"BENEFICIARY_COOLING_OFF"`
+	BeneficiaryCoolingOffAmount int64         `mapstructure:"BENEFICIARY_COOLING_OFF_AMOUNT"`
+	PaymentRequestInterval      time.Duration `mapstructure:"PAYMENT_REQUEST_INTERVAL"`
+	InvoiceInterval             time.Duration `mapstructure:"INVOICE_INTERVAL"`
+	EscrowInterval              time.Duration `mapstructure:"ESCROW_INTERVAL"`
 }
 
 var cfg = &Config{}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Day-count conventions turning an annual rate into a daily one
+const (
+	DayCountActual365    = "ACT/365"
+	DayCountActual360    = "ACT/360"
+	DayCountActualActual = "ACT/ACT"
+)
+
+// MicrosPerMinorUnit is the precision interest accrues at, a millionth of a cent
+const MicrosPerMinorUnit = 1_000_000
+
+func IsSupportedDayCount(dayCount string) bool {
+	switch dayCount {
+	case DayCountActual365, DayCountActual360, DayCountActualActual:
+		return true
+	}
+	return false
+}
+
+// DaysInYear returns the denominator of the day-count convention for the given day
+func DaysInYear(dayCount string, day time.Time) (int64, error) {
+	switch dayCount {
+	case DayCountActual365:
+		return 365, nil
+	case DayCountActual360:
+		return 360, nil
+	case DayCountActualActual:
+		year := day.Year()
+		return int64(time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC).Sub(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24), nil
+	}
+	return 0, fmt.Errorf("unsupported day-count convention %q", dayCount)
+}
+
+// DailyInterestMicros returns one day's interest on balance at an annual rate of
+// rateBps basis points, in millionths of the minor unit and rounded half to even.
+// The arithmetic is exact, so no precision is lost before the final rounding.
+func DailyInterestMicros(balance, rateBps int64, dayCount string, day time.Time) (int64, error) {
+	daysInYear, err := DaysInYear(dayCount, day)
+	if err != nil {
+		return 0, err
+	}
+
+	num := new(big.Int).Mul(big.NewInt(balance), big.NewInt(rateBps))
+	num.Mul(num, big.NewInt(MicrosPerMinorUnit))
+	den := big.NewInt(10_000 * daysInYear)
+	return roundHalfEven(num, den), nil
+}
+
+// RoundMicros rounds an amount in millionths of the minor unit to the minor unit, half to even
+func RoundMicros(micros int64) int64 {
+	return roundHalfEven(big.NewInt(micros), big.NewInt(MicrosPerMinorUnit))
+}
+
+// roundHalfEven returns num/den rounded to the nearest integer, ties to even.
+// den must be positive.
+func roundHalfEven(num, den *big.Int) int64 {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+
+	cmp := twice.Cmp(den)
+	if cmp > 0 || (cmp == 0 && quo.Bit(0) == 1) {
+		if num.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo.Int64()
+}
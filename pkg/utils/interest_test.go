@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundMicros(t *testing.T) {
+	require.Equal(t, int64(0), RoundMicros(499_999))
+	require.Equal(t, int64(0), RoundMicros(500_000))
+	require.Equal(t, int64(1), RoundMicros(500_001))
+	require.Equal(t, int64(2), RoundMicros(1_500_000))
+	require.Equal(t, int64(2), RoundMicros(2_500_000))
+	require.Equal(t, int64(-2), RoundMicros(-2_500_000))
+	require.Equal(t, int64(-3), RoundMicros(-2_500_001))
+}
+
+func TestDailyInterestMicros(t *testing.T) {
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	leapDay := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// 1,000,000 minor units at 3.65% for one day over 365 days is exactly 100
+	micros, err := DailyInterestMicros(1_000_000, 365, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(100*MicrosPerMinorUnit), micros)
+
+	micros, err = DailyInterestMicros(1_000_000, 360, DayCountActual360, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(100*MicrosPerMinorUnit), micros)
+
+	// 10,000 at 2.50% over 366 days is 0.683060109... minor units
+	micros, err = DailyInterestMicros(10_000, 250, DayCountActualActual, leapDay)
+	require.NoError(t, err)
+	require.Equal(t, int64(683_060), micros)
+
+	micros, err = DailyInterestMicros(10_000, 250, DayCountActualActual, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(684_932), micros)
+
+	// large balances must not overflow
+	micros, err = DailyInterestMicros(1_000_000_000_000_000, 10_000, DayCountActual365, day)
+	require.NoError(t, err)
+	require.Equal(t, int64(2_739_726_027_397_260_274), micros)
+
+	_, err = DailyInterestMicros(10_000, 250, "30/360", day)
+	require.Error(t, err)
+}
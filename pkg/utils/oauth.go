@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateOpaqueToken returns a random url-safe token such as an OAuth2
+// authorization code, refresh token or client secret
+func GenerateOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token : [%w] ", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashOpaqueToken returns the hex encoded sha256 an opaque token is stored as
+func HashOpaqueToken(token string) string {
+	return sha256Hex(token)
+}
+
+// CheckOpaqueToken compares a token against its stored hash in constant time
+func CheckOpaqueToken(token, hashedToken string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashOpaqueToken(token)), []byte(hashedToken)) == 1
+}
+
+// PKCEChallenge derives the S256 code challenge of a PKCE code verifier (RFC 7636)
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE checks a code verifier against the S256 challenge sent with the authorization request
+func VerifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(PKCEChallenge(verifier)), []byte(challenge)) == 1
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PKCE(t *testing.T) {
+	verifier, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+
+	challenge := PKCEChallenge(verifier)
+	require.Len(t, challenge, 43)
+	require.NotEqual(t, verifier, challenge)
+	require.True(t, VerifyPKCE(verifier, challenge))
+	require.False(t, VerifyPKCE(verifier+"x", challenge))
+	require.False(t, VerifyPKCE("short", PKCEChallenge("short")))
+}
+
+func Test_OpaqueToken(t *testing.T) {
+	token, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+	require.Len(t, token, 43)
+
+	require.True(t, CheckOpaqueToken(token, HashOpaqueToken(token)))
+	require.False(t, CheckOpaqueToken(token, HashOpaqueToken(token+"x")))
+}
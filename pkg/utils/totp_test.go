@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TOTP(t *testing.T) {
+	// RFC 6238 appendix B SHA1 seed, truncated to six digits
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := GenerateTOTPCode(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code)
+
+		counter, ok := ValidateTOTPCode(secret, code, time.Unix(unix, 0))
+		require.True(t, ok)
+		require.Equal(t, TOTPCounter(time.Unix(unix, 0)), counter)
+	}
+
+	code, err := GenerateTOTPCode(secret, time.Unix(59, 0))
+	require.NoError(t, err)
+
+	_, ok := ValidateTOTPCode(secret, code, time.Unix(59+totpPeriod, 0))
+	require.True(t, ok)
+
+	_, ok = ValidateTOTPCode(secret, code, time.Unix(59+3*totpPeriod, 0))
+	require.False(t, ok)
+
+	_, ok = ValidateTOTPCode(secret, "12345", time.Unix(59, 0))
+	require.False(t, ok)
+}
+
+func Test_TOTPSecretAndURI(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	uri := TOTPProvisioningURI("Simple Bank", "alice", secret)
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Simple%20Bank:alice?"))
+	require.Contains(t, uri, "secret="+secret)
+
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	for _, code := range codes {
+		require.Len(t, code, 11)
+	}
+}
@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned for webhook URLs that point into our own
+// network, so registering a webhook can't be used to reach internal services
+var ErrPrivateAddress = errors.New("webhook URL must resolve to a public address")
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice but
+// not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Resolver looks up the addresses of a host, net.DefaultResolver is one
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// CheckURL resolves the host of a webhook URL and refuses it when any of the
+// addresses it resolves to isn't public
+func CheckURL(ctx context.Context, resolver Resolver, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("cannot resolve webhook host %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("cannot resolve webhook host %s", host)
+	}
+
+	for _, addr := range addrs {
+		if !PublicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateAddress, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// PublicIP reports whether ip is routable on the internet, rather than a
+// private, loopback, link-local, multicast or unspecified address
+func PublicIP(ip net.IP) bool {
+	return !(ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// publicDialer only connects to public addresses. The address is checked
+// after the host is resolved so a host that resolved to a public address when
+// it was registered can't be pointed at an internal one later.
+func publicDialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+}
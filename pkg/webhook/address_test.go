@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type staticResolver []string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs := make([]net.IPAddr, len(r))
+	for i, ip := range r {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs, nil
+}
+
+func TestPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"93.184.216.34":        true,
+		"2606:2800:220:1::":    true,
+		"10.0.0.1":             false,
+		"172.16.5.4":           false,
+		"192.168.1.1":          false,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"169.254.169.254":      false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:93.184.216.34": true,
+	} {
+		require.Equal(t, public, PublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, CheckURL(ctx, staticResolver{"93.184.216.34"}, "https://hooks.example.com/bank"))
+
+	// one private address is enough to refuse the host
+	err := CheckURL(ctx, staticResolver{"93.184.216.34", "10.0.0.1"}, "https://hooks.example.com/bank")
+	require.ErrorIs(t, err, ErrPrivateAddress)
+
+	// addresses in the URL are checked without a lookup
+	err = CheckURL(ctx, net.DefaultResolver, "https://169.254.169.254/latest/meta-data")
+	require.ErrorIs(t, err, ErrPrivateAddress)
+
+	err = CheckURL(ctx, staticResolver{}, "https://hooks.example.com/bank")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrPrivateAddress))
+}
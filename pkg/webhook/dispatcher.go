@@ -0,0 +1,225 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+)
+
+const (
+	SignatureHeader  = "X-Webhook-Signature"
+	EventIDHeader    = "X-Webhook-Event-Id"
+	EventTypeHeader  = "X-Webhook-Event-Type"
+	DeliveryIDHeader = "X-Webhook-Delivery-Id"
+)
+
+// Config tunes the dispatcher, zero values fall back to sensible defaults
+type Config struct {
+	Interval    time.Duration
+	BatchSize   int32
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Lease is how long a claimed delivery stays hidden from other workers
+	Lease   time.Duration
+	Timeout time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Interval <= 0 {
+		c.Interval = 5 * time.Second
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 50
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = 30 * time.Second
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = 6 * time.Hour
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.Lease <= 0 {
+		c.Lease = 2 * c.Timeout
+	}
+}
+
+// Event is the JSON body every webhook delivery carries
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher moves outbox events into webhook deliveries and delivers them.
+// Delivery is at least once, receivers get a stable event id to discard duplicates.
+type Dispatcher struct {
+	store  db.Store
+	client *http.Client
+	config Config
+	now    func() time.Time
+}
+
+// NewDispatcher returns a dispatcher whose client only connects to public
+// addresses and never through a proxy, which would hide where it connects
+func NewDispatcher(store db.Store, config Config) *Dispatcher {
+	config.setDefaults()
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Timeout: config.Timeout,
+			Transport: &http.Transport{
+				DialContext:         publicDialer(config.Timeout).DialContext,
+				TLSHandshakeTimeout: config.Timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Run dispatches and delivers events until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.store.DispatchOutboxTrxn(ctx, d.config.BatchSize); err != nil {
+			log.Printf("[ERROR] cannot dispatch outbox events: %v", err)
+		}
+		if _, err := d.DeliverDue(ctx); err != nil {
+			log.Printf("[ERROR] cannot deliver webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue attempts every delivery whose retry time has come and returns how many were attempted
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeaseUntil: d.now().Add(d.config.Lease),
+		BatchSize:  d.config.BatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if _, err := d.Deliver(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Deliver makes one attempt at a delivery and records the outcome
+func (d *Dispatcher) Deliver(ctx context.Context, delivery db.WebhookDelivery) (db.WebhookDelivery, error) {
+	endpoint, err := d.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return delivery, err
+	}
+
+	event, err := d.store.GetOutboxEvent(ctx, delivery.EventID)
+	if err != nil {
+		return delivery, err
+	}
+
+	arg := db.RecordWebhookAttemptTxnParams{
+		DeliveryID: delivery.ID,
+		Attempts:   delivery.Attempts + 1,
+	}
+
+	start := d.now()
+	if endpoint.Active {
+		arg.ResponseStatus, err = d.send(ctx, endpoint, event, delivery)
+	} else {
+		err = fmt.Errorf("webhook endpoint %d has been deactivated", endpoint.ID)
+		arg.Attempts = d.config.MaxAttempts
+	}
+	arg.Duration = d.now().Sub(start)
+
+	switch {
+	case err == nil:
+		arg.Status = db.WebhookDeliverySucceeded
+		arg.NextAttemptAt = d.now()
+		arg.DeliveredAt = sql.NullTime{Time: d.now(), Valid: true}
+	case arg.Attempts >= d.config.MaxAttempts:
+		arg.Status = db.WebhookDeliveryFailed
+		arg.Error = err.Error()
+		arg.NextAttemptAt = d.now()
+	default:
+		arg.Status = db.WebhookDeliveryPending
+		arg.Error = err.Error()
+		arg.NextAttemptAt = d.now().Add(Backoff(d.config.BaseDelay, d.config.MaxDelay, arg.Attempts))
+	}
+
+	return d.store.RecordWebhookAttemptTrxn(ctx, arg)
+}
+
+func (d *Dispatcher) send(ctx context.Context, endpoint db.WebhookEndpoint, event db.OutboxEvent, delivery db.WebhookDelivery) (int32, error) {
+	body, err := json.Marshal(Event{
+		ID:        event.ID,
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(endpoint.Secret, d.now(), body))
+	request.Header.Set(EventIDHeader, strconv.FormatInt(event.ID, 10))
+	request.Header.Set(EventTypeHeader, event.EventType)
+	request.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return int32(response.StatusCode), fmt.Errorf("webhook endpoint responded with %d", response.StatusCode)
+	}
+	return int32(response.StatusCode), nil
+}
+
+// Backoff returns the delay before the next attempt, doubling with each failed attempt
+func Backoff(base, max time.Duration, attempts int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,193 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/caleberi/simple-bank/db/mock"
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	base, max := 30*time.Second, 10*time.Minute
+
+	require.Equal(t, 30*time.Second, Backoff(base, max, 1))
+	require.Equal(t, time.Minute, Backoff(base, max, 2))
+	require.Equal(t, 2*time.Minute, Backoff(base, max, 3))
+	require.Equal(t, 8*time.Minute, Backoff(base, max, 5))
+	require.Equal(t, max, Backoff(base, max, 6))
+	require.Equal(t, max, Backoff(base, max, 100))
+}
+
+func TestDeliver(t *testing.T) {
+	secret := "whsec_test"
+	now := time.Now()
+
+	event := db.OutboxEvent{
+		ID:            7,
+		EventType:     db.EventTransferCompleted,
+		Owner:         "owner",
+		AggregateType: "transfer",
+		AggregateID:   "1",
+		Payload:       json.RawMessage(`{"id":1}`),
+		CreatedAt:     now,
+	}
+
+	testCases := []struct {
+		name        string
+		status      int
+		active      bool
+		attempts    int32
+		checkRecord func(t *testing.T, arg db.RecordWebhookAttemptTxnParams)
+	}{
+		{
+			name:     "Delivered",
+			status:   http.StatusNoContent,
+			active:   true,
+			attempts: 0,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookAttemptTxnParams) {
+				require.Equal(t, db.WebhookDeliverySucceeded, arg.Status)
+				require.Equal(t, int32(http.StatusNoContent), arg.ResponseStatus)
+				require.Equal(t, int32(1), arg.Attempts)
+				require.True(t, arg.DeliveredAt.Valid)
+				require.Empty(t, arg.Error)
+			},
+		},
+		{
+			name:     "RetriedWithBackoff",
+			status:   http.StatusInternalServerError,
+			active:   true,
+			attempts: 2,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookAttemptTxnParams) {
+				require.Equal(t, db.WebhookDeliveryPending, arg.Status)
+				require.Equal(t, int32(http.StatusInternalServerError), arg.ResponseStatus)
+				require.Equal(t, int32(3), arg.Attempts)
+				require.Equal(t, now.Add(4*time.Second), arg.NextAttemptAt)
+				require.False(t, arg.DeliveredAt.Valid)
+				require.NotEmpty(t, arg.Error)
+			},
+		},
+		{
+			name:     "AttemptsExhausted",
+			status:   http.StatusBadGateway,
+			active:   true,
+			attempts: 4,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookAttemptTxnParams) {
+				require.Equal(t, db.WebhookDeliveryFailed, arg.Status)
+				require.Equal(t, int32(5), arg.Attempts)
+				require.False(t, arg.DeliveredAt.Valid)
+			},
+		},
+		{
+			name:     "EndpointDeactivated",
+			active:   false,
+			attempts: 0,
+			checkRecord: func(t *testing.T, arg db.RecordWebhookAttemptTxnParams) {
+				require.Equal(t, db.WebhookDeliveryFailed, arg.Status)
+				require.Zero(t, arg.ResponseStatus)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var received bool
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = true
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.NoError(t, Verify(secret, r.Header.Get(SignatureHeader), body, time.Minute, time.Now()))
+				require.Equal(t, strconv.FormatInt(event.ID, 10), r.Header.Get(EventIDHeader))
+				require.Equal(t, event.EventType, r.Header.Get(EventTypeHeader))
+
+				var payload Event
+				require.NoError(t, json.Unmarshal(body, &payload))
+				require.Equal(t, event.ID, payload.ID)
+				require.JSONEq(t, string(event.Payload), string(payload.Data))
+
+				w.WriteHeader(tc.status)
+			}))
+			defer receiver.Close()
+
+			endpoint := db.WebhookEndpoint{
+				ID:         3,
+				Owner:      event.Owner,
+				Url:        receiver.URL,
+				Secret:     secret,
+				EventTypes: []string{},
+				Active:     tc.active,
+			}
+			delivery := db.WebhookDelivery{
+				ID:         11,
+				EventID:    event.ID,
+				EndpointID: endpoint.ID,
+				Status:     db.WebhookDeliveryPending,
+				Attempts:   tc.attempts,
+			}
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+			store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+			store.EXPECT().RecordWebhookAttemptTrxn(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ context.Context, arg db.RecordWebhookAttemptTxnParams) (db.WebhookDelivery, error) {
+					require.Equal(t, delivery.ID, arg.DeliveryID)
+					tc.checkRecord(t, arg)
+					delivery.Status = arg.Status
+					delivery.Attempts = arg.Attempts
+					return delivery, nil
+				})
+
+			dispatcher := NewDispatcher(store, Config{MaxAttempts: 5, BaseDelay: time.Second})
+			dispatcher.now = func() time.Time { return now }
+			// the receiver listens on loopback, which the dispatcher's own client refuses
+			dispatcher.client = receiver.Client()
+
+			_, err := dispatcher.Deliver(context.Background(), delivery)
+			require.NoError(t, err)
+			require.Equal(t, tc.active, received)
+		})
+	}
+}
+
+func TestDeliverRefusesPrivateAddress(t *testing.T) {
+	var received bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer receiver.Close()
+
+	endpoint := db.WebhookEndpoint{ID: 3, Url: receiver.URL, Secret: "whsec_test", Active: true}
+	event := db.OutboxEvent{ID: 7, EventType: "transfer.created", Payload: []byte(`{}`)}
+	delivery := db.WebhookDelivery{ID: 11, EventID: event.ID, EndpointID: endpoint.ID, Status: db.WebhookDeliveryPending}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+	store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(event.ID)).Times(1).Return(event, nil)
+	store.EXPECT().RecordWebhookAttemptTrxn(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.RecordWebhookAttemptTxnParams) (db.WebhookDelivery, error) {
+			require.Equal(t, db.WebhookDeliveryPending, arg.Status)
+			require.Contains(t, arg.Error, ErrPrivateAddress.Error())
+			return delivery, nil
+		})
+
+	dispatcher := NewDispatcher(store, Config{MaxAttempts: 5, BaseDelay: time.Second})
+	_, err := dispatcher.Deliver(context.Background(), delivery)
+	require.NoError(t, err)
+	require.False(t, received)
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrExpiredSignature = errors.New("webhook signature timestamp is outside the tolerance")
+)
+
+// Sign returns the signature header for body, formatted as t=<unix time>,v1=<hex hmac>.
+// The timestamp is signed along with the body so captured requests can't be replayed later.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, computeSignature(secret, ts, body))
+}
+
+// Verify checks a signature header the way receivers are expected to
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signature = value
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(computeSignature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func computeSignature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
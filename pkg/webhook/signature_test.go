@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":1,"type":"transfer.completed"}`)
+	now := time.Now()
+
+	header := Sign(secret, now, body)
+	require.NoError(t, Verify(secret, header, body, time.Minute, now))
+
+	require.ErrorIs(t, Verify("whsec_other", header, body, time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, header, []byte(`{"id":2}`), time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, "v1=deadbeef", body, time.Minute, now), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, header, body, time.Minute, now.Add(2*time.Minute)), ErrExpiredSignature)
+}
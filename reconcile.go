@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	db "github.com/caleberi/simple-bank/db/sqlc"
+	"github.com/caleberi/simple-bank/pkg/reconcile"
+)
+
+// runReconcile implements `simple-bank reconcile`. It writes one JSON line per
+// discrepancy followed by a summary line, and exits with status 1 when the
+// ledger doesn't reconcile so it can gate scripts and cron jobs.
+func runReconcile(store db.Store, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	batchSize := flags.Int("batch-size", 1000, "rows read per query")
+	block := flags.Bool("block", false, "block accounts involved in a discrepancy")
+	output := flags.String("output", "", "file to write the report to, stdout by default")
+	flags.Parse(args)
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Printf("[ERROR] cannot create report file : %v", err)
+			return 2
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	reconciler := reconcile.New(store, reconcile.Options{
+		BatchSize:     int32(*batchSize),
+		BlockAccounts: *block,
+	})
+
+	summary, err := reconciler.Run(context.Background(), func(discrepancy reconcile.Discrepancy) error {
+		return encoder.Encode(discrepancy)
+	})
+	if err != nil {
+		log.Printf("[ERROR] cannot reconcile ledger : %v", err)
+		return 2
+	}
+
+	if err := encoder.Encode(struct {
+		Summary reconcile.Summary `json:"summary"`
+	}{summary}); err != nil {
+		log.Printf("[ERROR] cannot write report : %v", err)
+		return 2
+	}
+
+	if summary.Discrepancies > 0 {
+		return 1
+	}
+	return 0
+}
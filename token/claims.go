@@ -0,0 +1,53 @@
+package token
+
+// Option configures the registered iss and aud claims a maker stamps on and
+// expects from tokens
+type Option func(*claims)
+
+// WithIssuer stamps issuer in the iss claim of created tokens and rejects tokens
+// from any other issuer
+func WithIssuer(issuer string) Option {
+	return func(c *claims) {
+		c.issuer = issuer
+	}
+}
+
+// WithAudience stamps audience in the aud claim of created tokens and rejects
+// tokens meant for any other audience
+func WithAudience(audience string) Option {
+	return func(c *claims) {
+		c.audience = audience
+	}
+}
+
+type claims struct {
+	issuer   string
+	audience string
+}
+
+func newClaims(opts []Option) claims {
+	var c claims
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func (c claims) stamp(payload *Payload) {
+	if payload.Issuer == "" {
+		payload.Issuer = c.issuer
+	}
+	if payload.Audience == "" {
+		payload.Audience = c.audience
+	}
+}
+
+func (c claims) verify(payload *Payload) error {
+	if c.issuer != "" && payload.Issuer != c.issuer {
+		return ErrInvalidIssuer
+	}
+	if c.audience != "" && payload.Audience != c.audience {
+		return ErrInvalidAudience
+	}
+	return nil
+}
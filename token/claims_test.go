@@ -0,0 +1,141 @@
+package token
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMakers(t *testing.T, opts ...Option) map[string]Maker {
+	keyRing := newTestKeyRing(t, "primary")
+	symmetricKey := utils.RandomString(32)
+
+	makers := map[string]Maker{}
+	for _, makerType := range []string{TypePasetoLocal, TypePasetoPublic, TypeJWT, TypeJWTEdDSA} {
+		maker, err := NewMaker(makerType, symmetricKey, keyRing, opts...)
+		require.NoError(t, err)
+		makers[makerType] = maker
+	}
+	return makers
+}
+
+func Test_NewMaker(t *testing.T) {
+	makers := newTestMakers(t)
+	require.IsType(t, &PasetoMaker{}, makers[TypePasetoLocal])
+	require.IsType(t, &PasetoPublicMaker{}, makers[TypePasetoPublic])
+	require.IsType(t, &JWTMarker{}, makers[TypeJWT])
+	require.IsType(t, &JWTEdDSAMaker{}, makers[TypeJWTEdDSA])
+
+	_, err := NewMaker("unknown", utils.RandomString(32), nil)
+	require.Error(t, err)
+}
+
+func Test_IssuerAndAudienceClaims(t *testing.T) {
+	customerMakers := newTestMakers(t, WithIssuer("simple-bank"), WithAudience("customer-api"))
+
+	for makerType, maker := range customerMakers {
+		t.Run(makerType, func(t *testing.T) {
+			token, err := maker.CreateToken(utils.RandomOwner(), time.Minute)
+			require.NoError(t, err)
+
+			payload, err := maker.VerifyToken(token)
+			require.NoError(t, err)
+			require.Equal(t, "simple-bank", payload.Issuer)
+			require.Equal(t, "customer-api", payload.Audience)
+			require.WithinDuration(t, payload.IssuedAt, payload.NotBefore, time.Second)
+
+			// an admin console token signed with the same key is rejected
+			adminToken, err := maker.CreateTokenFromPayload(&Payload{
+				Username:  utils.RandomOwner(),
+				IssuedAt:  time.Now(),
+				ExpiredAt: time.Now().Add(time.Minute),
+				Purpose:   PurposeAccess,
+				Issuer:    "simple-bank",
+				Audience:  "admin-console",
+			})
+			require.NoError(t, err)
+
+			payload, err = maker.VerifyToken(adminToken)
+			require.ErrorIs(t, err, ErrInvalidAudience)
+			require.Nil(t, payload)
+
+			foreignToken, err := maker.CreateTokenFromPayload(&Payload{
+				Username:  utils.RandomOwner(),
+				IssuedAt:  time.Now(),
+				ExpiredAt: time.Now().Add(time.Minute),
+				Purpose:   PurposeAccess,
+				Issuer:    "someone-else",
+			})
+			require.NoError(t, err)
+
+			payload, err = maker.VerifyToken(foreignToken)
+			require.ErrorIs(t, err, ErrInvalidIssuer)
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func Test_NotBeforeClaim(t *testing.T) {
+	for makerType, maker := range newTestMakers(t) {
+		t.Run(makerType, func(t *testing.T) {
+			payload, err := NewPayload(utils.RandomOwner(), time.Hour)
+			require.NoError(t, err)
+			payload.NotBefore = time.Now().Add(time.Minute)
+
+			token, err := maker.CreateTokenFromPayload(payload)
+			require.NoError(t, err)
+
+			payload, err = maker.VerifyToken(token)
+			require.Error(t, err)
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func Test_Scopes(t *testing.T) {
+	payload, err := NewPayload(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	require.True(t, payload.HasScope(ScopeTransfersCreate))
+
+	payload.Scopes = []string{ScopeAccountsRead}
+	require.True(t, payload.HasScope(ScopeAccountsRead))
+	require.False(t, payload.HasScope(ScopeTransfersCreate))
+
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	require.NoError(t, err)
+
+	token, err := maker.CreateTokenFromPayload(payload)
+	require.NoError(t, err)
+
+	verified, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, []string{ScopeAccountsRead}, verified.Scopes)
+}
+
+func Test_RegisteredClaimNames(t *testing.T) {
+	payload, err := NewPayload(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	payload.Issuer = "simple-bank"
+	payload.Audience = "customer-api"
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(data, &claims))
+	require.Equal(t, "simple-bank", claims["iss"])
+	require.Equal(t, "customer-api", claims["aud"])
+	require.Equal(t, payload.Username, claims["sub"])
+	require.Equal(t, payload.ID.String(), claims["jti"])
+	require.Contains(t, claims, "iat")
+	require.Contains(t, claims, "exp")
+	require.Contains(t, claims, "nbf")
+	require.NotContains(t, claims, "issued_at")
+	require.NotContains(t, claims, "expired_at")
+	require.NotContains(t, claims, "issuer")
+	require.NotContains(t, claims, "audience")
+	require.NotContains(t, claims, "not_before")
+}
@@ -0,0 +1,69 @@
+package token
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jwtClaims encodes a payload for the JWT makers. JWT libraries expect exp,
+// iat and nbf as NumericDate seconds rather than the RFC 3339 strings PASETO
+// uses, and treat a token without a numeric exp as one that never expires.
+type jwtClaims struct {
+	*Payload
+}
+
+// payloadFields has the fields of Payload without its methods, so encoding it
+// doesn't recurse into jwtClaims
+type payloadFields Payload
+
+// jwtClaimsJSON shadows the time fields of the payload with their NumericDate
+// form, zero times are left out
+type jwtClaimsJSON struct {
+	*payloadFields
+	IssuedAt  int64 `json:"iat,omitempty"`
+	ExpiredAt int64 `json:"exp,omitempty"`
+	NotBefore int64 `json:"nbf,omitempty"`
+}
+
+func (c jwtClaims) Valid() error {
+	return c.Payload.Valid()
+}
+
+func (c jwtClaims) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jwtClaimsJSON{
+		payloadFields: (*payloadFields)(c.Payload),
+		IssuedAt:      numericDate(c.IssuedAt),
+		ExpiredAt:     numericDate(c.ExpiredAt),
+		NotBefore:     numericDate(c.NotBefore),
+	})
+}
+
+func (c *jwtClaims) UnmarshalJSON(data []byte) error {
+	if c.Payload == nil {
+		c.Payload = &Payload{}
+	}
+
+	decoded := jwtClaimsJSON{payloadFields: (*payloadFields)(c.Payload)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	c.IssuedAt = fromNumericDate(decoded.IssuedAt)
+	c.ExpiredAt = fromNumericDate(decoded.ExpiredAt)
+	c.NotBefore = fromNumericDate(decoded.NotBefore)
+	return nil
+}
+
+func numericDate(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func fromNumericDate(seconds int64) time.Time {
+	if seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningMethodEd25519 implements the EdDSA JWS algorithm which jwt-go v3 lacks
+type SigningMethodEd25519 struct{}
+
+var SigningMethodEdDSA = &SigningMethodEd25519{}
+
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
+
+func (m *SigningMethodEd25519) Alg() string {
+	return "EdDSA"
+}
+
+func (m *SigningMethodEd25519) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+func (m *SigningMethodEd25519) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+// JWTEdDSAMaker issues JWTs signed with Ed25519 and tagged with the key id
+type JWTEdDSAMaker struct {
+	keyRing *KeyRing
+	claims  claims
+}
+
+func NewJWTEdDSAMaker(keyRing *KeyRing, opts ...Option) (Maker, error) {
+	if keyRing == nil {
+		return nil, ErrMissingKeyRing
+	}
+	return &JWTEdDSAMaker{keyRing: keyRing, claims: newClaims(opts)}, nil
+}
+
+func (m *JWTEdDSAMaker) CreateToken(username string, duration time.Duration) (string, error) {
+	payload, err := NewPayload(username, duration)
+	if err != nil {
+		return "", err
+	}
+	return m.CreateTokenFromPayload(payload)
+}
+
+func (m *JWTEdDSAMaker) CreateTokenFromPayload(payload *Payload) (string, error) {
+	m.claims.stamp(payload)
+
+	kid, signingKey := m.keyRing.SigningKey()
+
+	jwtToken := jwt.NewWithClaims(SigningMethodEdDSA, jwtClaims{payload})
+	jwtToken.Header["kid"] = kid
+	return jwtToken.SignedString(signingKey)
+}
+
+func (m *JWTEdDSAMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*SigningMethodEd25519); !ok {
+			return nil, ErrInvalidToken
+		}
+
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+		return m.keyRing.VerificationKey(kid)
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, keyFunc)
+	if err != nil {
+		verr, ok := err.(*jwt.ValidationError)
+		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := jwtToken.Claims.(*jwtClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	payload := claims.Payload
+
+	if err := m.claims.verify(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
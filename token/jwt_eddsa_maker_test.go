@@ -0,0 +1,78 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_JWTEdDSAMaker(t *testing.T) {
+	maker, err := NewJWTEdDSAMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	username := utils.RandomOwner()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, err := maker.CreateToken(username, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	require.Equal(t, "EdDSA", parsed.Header["alg"])
+	require.Equal(t, "primary", parsed.Header["kid"])
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func Test_ExpiredJWTEdDSAToken(t *testing.T) {
+	maker, err := NewJWTEdDSAMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(utils.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func Test_InvalidJWTEdDSAToken(t *testing.T) {
+	maker, err := NewJWTEdDSAMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	// an HMAC token must not be accepted by the asymmetric maker
+	secretKey := utils.RandomString(32)
+	hmacMaker, err := NewJWTMaker(secretKey)
+	require.NoError(t, err)
+
+	token, err := hmacMaker.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	otherMaker, err := NewJWTEdDSAMaker(newTestKeyRing(t, "other"))
+	require.NoError(t, err)
+
+	token, err = otherMaker.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
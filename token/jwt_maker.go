@@ -12,13 +12,14 @@ const minSecretKeySize = 32
 
 type JWTMarker struct {
 	secretKey string
+	claims    claims
 }
 
-func NewJWTMaker(secretKey string) (Maker, error) {
+func NewJWTMaker(secretKey string, opts ...Option) (Maker, error) {
 	if len(secretKey) < minSecretKeySize {
 		return nil, fmt.Errorf("invalid  key size : must be at least %d character(s)", minSecretKeySize)
 	}
-	return &JWTMarker{secretKey}, nil
+	return &JWTMarker{secretKey: secretKey, claims: newClaims(opts)}, nil
 }
 
 func (m *JWTMarker) CreateToken(username string, duration time.Duration) (string, error) {
@@ -27,7 +28,12 @@ func (m *JWTMarker) CreateToken(username string, duration time.Duration) (string
 		return "", err
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	return m.CreateTokenFromPayload(payload)
+}
+
+func (m *JWTMarker) CreateTokenFromPayload(payload *Payload) (string, error) {
+	m.claims.stamp(payload)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{payload})
 	return jwtToken.SignedString([]byte(m.secretKey))
 }
 
@@ -40,7 +46,7 @@ func (m *JWTMarker) VerifyToken(token string) (*Payload, error) {
 		return []byte(m.secretKey), nil
 	}
 
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	jwtToken, err := jwt.ParseWithClaims(token, &jwtClaims{}, keyFunc)
 	if err != nil {
 		verr, ok := err.(*jwt.ValidationError)
 		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
@@ -49,9 +55,14 @@ func (m *JWTMarker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
-	payload, ok := jwtToken.Claims.(*Payload)
+	claims, ok := jwtToken.Claims.(*jwtClaims)
 	if !ok {
 		return nil, ErrInvalidToken
 	}
+	payload := claims.Payload
+
+	if err := m.claims.verify(payload); err != nil {
+		return nil, err
+	}
 	return payload, nil
 }
@@ -69,3 +69,47 @@ func Test_InvalidJWTToken(t *testing.T) {
 	require.Nil(t, payload)
 
 }
+
+func Test_JWTRegisteredClaims(t *testing.T) {
+	secretKey := utils.RandomString(32)
+	hmacMaker, err := NewJWTMaker(secretKey)
+	require.NoError(t, err)
+
+	keyRing := newTestKeyRing(t, "primary")
+	eddsaMaker, err := NewJWTEdDSAMaker(keyRing)
+	require.NoError(t, err)
+
+	// decode with plain map claims, as a service that only knows JWT would
+	keyFunc := func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+			return []byte(secretKey), nil
+		}
+		return keyRing.VerificationKey(t.Header["kid"].(string))
+	}
+
+	for name, maker := range map[string]Maker{TypeJWT: hmacMaker, TypeJWTEdDSA: eddsaMaker} {
+		t.Run(name, func(t *testing.T) {
+			username := utils.RandomOwner()
+			token, err := maker.CreateToken(username, time.Minute)
+			require.NoError(t, err)
+
+			claims := jwt.MapClaims{}
+			_, err = jwt.ParseWithClaims(token, claims, keyFunc)
+			require.NoError(t, err)
+			require.Equal(t, username, claims["sub"])
+			require.NotEmpty(t, claims["jti"])
+			for _, claim := range []string{"iat", "exp", "nbf"} {
+				require.IsType(t, float64(0), claims[claim], claim)
+			}
+			require.InDelta(t, time.Now().Add(time.Minute).Unix(), claims["exp"], 2)
+
+			expiredToken, err := maker.CreateToken(username, -time.Minute)
+			require.NoError(t, err)
+
+			_, err = jwt.ParseWithClaims(expiredToken, jwt.MapClaims{}, keyFunc)
+			var verr *jwt.ValidationError
+			require.ErrorAs(t, err, &verr)
+			require.NotZero(t, verr.Errors&jwt.ValidationErrorExpired)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUnknownKeyID   = errors.New("token signed with an unknown key id")
+	ErrMissingKeyRing = errors.New("asymmetric token makers require a signing key")
+)
+
+// KeyRing holds the Ed25519 key tokens are signed with and every public key
+// that is still accepted for verification while keys are being rotated.
+type KeyRing struct {
+	mu               sync.RWMutex
+	signingKeyID     string
+	signingKey       ed25519.PrivateKey
+	verificationKeys map[string]ed25519.PublicKey
+}
+
+// NewKeyRing creates a key ring that signs with signingKey under signingKeyID
+func NewKeyRing(signingKeyID string, signingKey ed25519.PrivateKey) (*KeyRing, error) {
+	if signingKeyID == "" {
+		return nil, errors.New("signing key id must not be empty")
+	}
+	if len(signingKey) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid signing key size : must be %d bytes", ed25519.PrivateKeySize)
+	}
+
+	ring := &KeyRing{
+		signingKeyID:     signingKeyID,
+		signingKey:       signingKey,
+		verificationKeys: map[string]ed25519.PublicKey{},
+	}
+	ring.verificationKeys[signingKeyID] = signingKey.Public().(ed25519.PublicKey)
+	return ring, nil
+}
+
+// ParseKeyRing builds a key ring from configuration values. The signing key is a
+// base64 encoded 32 byte Ed25519 seed and verificationKeys is a comma separated
+// list of kid:base64-public-key pairs for keys that were rotated out.
+func ParseKeyRing(signingKeyID, signingKey, verificationKeys string) (*KeyRing, error) {
+	seed, err := decodeKey(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode signing key: %w", err)
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid signing key size : must be a %d byte seed", ed25519.SeedSize)
+	}
+
+	ring, err := NewKeyRing(signingKeyID, ed25519.NewKeyFromSeed(seed))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range strings.Split(verificationKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, encoded, found := strings.Cut(entry, ":")
+		if !found || kid == "" {
+			return nil, fmt.Errorf("invalid verification key entry %q : expected kid:key", entry)
+		}
+
+		publicKey, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode verification key %s: %w", kid, err)
+		}
+		if err := ring.AddVerificationKey(kid, publicKey); err != nil {
+			return nil, err
+		}
+	}
+	return ring, nil
+}
+
+// AddVerificationKey accepts tokens signed by publicKey under kid
+func (k *KeyRing) AddVerificationKey(kid string, publicKey ed25519.PublicKey) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid verification key size : must be %d bytes", ed25519.PublicKeySize)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.verificationKeys[kid] = publicKey
+	return nil
+}
+
+// SigningKey returns the key id and private key new tokens are signed with
+func (k *KeyRing) SigningKey() (string, ed25519.PrivateKey) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signingKeyID, k.signingKey
+}
+
+// VerificationKey returns the public key registered under kid
+func (k *KeyRing) VerificationKey(kid string) (ed25519.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	publicKey, ok := k.verificationKeys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return publicKey, nil
+}
+
+// JWK is the JSON web key representation of an Ed25519 public key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// JWKSet is the document served to downstream services that verify our tokens
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every verification key as a JSON web key set
+func (k *KeyRing) JWKS() JWKSet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKSet{Keys: make([]JWK, 0, len(k.verificationKeys))}
+	for kid, publicKey := range k.verificationKeys {
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(publicKey),
+			KeyID:     kid,
+			Use:       "sig",
+			Algorithm: "EdDSA",
+		})
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].KeyID < set.Keys[j].KeyID
+	})
+	return set
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := encoding.DecodeString(encoded); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("key is not valid base64")
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyRing(t *testing.T, kid string) *KeyRing {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ring, err := NewKeyRing(kid, privateKey)
+	require.NoError(t, err)
+	return ring
+}
+
+func Test_ParseKeyRing(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(seed)
+	require.NoError(t, err)
+
+	retiredPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ring, err := ParseKeyRing("2023-11", base64.StdEncoding.EncodeToString(seed),
+		"2023-10:"+base64.RawURLEncoding.EncodeToString(retiredPublicKey))
+	require.NoError(t, err)
+
+	kid, signingKey := ring.SigningKey()
+	require.Equal(t, "2023-11", kid)
+	require.Equal(t, ed25519.NewKeyFromSeed(seed), signingKey)
+
+	publicKey, err := ring.VerificationKey("2023-10")
+	require.NoError(t, err)
+	require.Equal(t, retiredPublicKey, publicKey)
+
+	_, err = ring.VerificationKey("unknown")
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+
+	jwks := ring.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "2023-10", jwks.Keys[0].KeyID)
+	require.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	require.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(retiredPublicKey), jwks.Keys[0].X)
+
+	_, err = ParseKeyRing("2023-11", base64.StdEncoding.EncodeToString(seed[:16]), "")
+	require.Error(t, err)
+
+	_, err = ParseKeyRing("2023-11", base64.StdEncoding.EncodeToString(seed), "missing-separator")
+	require.Error(t, err)
+}
+
+func Test_KeyRotation(t *testing.T) {
+	oldRing := newTestKeyRing(t, "old")
+	newRing := newTestKeyRing(t, "new")
+
+	oldMaker, err := NewPasetoPublicMaker(oldRing)
+	require.NoError(t, err)
+	newMaker, err := NewPasetoPublicMaker(newRing)
+	require.NoError(t, err)
+
+	token, err := oldMaker.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	// until the old public key is published the new ring rejects the token
+	_, err = newMaker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	_, oldSigningKey := oldRing.SigningKey()
+	require.NoError(t, newRing.AddVerificationKey("old", oldSigningKey.Public().(ed25519.PublicKey)))
+
+	payload, err := newMaker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+}
@@ -1,10 +1,38 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	TypePasetoLocal  = "paseto.local"
+	TypePasetoPublic = "paseto.public"
+	TypeJWT          = "jwt"
+	TypeJWTEdDSA     = "jwt.eddsa"
+)
 
 type Maker interface {
 	// CreateToken creates a new token for a specific username and password
 	CreateToken(username string, duration time.Duration) (string, error)
+	// CreateTokenFromPayload signs an already built payload
+	CreateTokenFromPayload(payload *Payload) (string, error)
 	// VerifyToken checks if  the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
+
+// NewMaker creates the maker of the given type. Symmetric makers use
+// symmetricKey while asymmetric ones sign with the key ring.
+func NewMaker(makerType string, symmetricKey string, keyRing *KeyRing, opts ...Option) (Maker, error) {
+	switch makerType {
+	case TypePasetoLocal:
+		return NewPasetoMaker(symmetricKey, opts...)
+	case TypePasetoPublic:
+		return NewPasetoPublicMaker(keyRing, opts...)
+	case TypeJWT:
+		return NewJWTMaker(symmetricKey, opts...)
+	case TypeJWTEdDSA:
+		return NewJWTEdDSAMaker(keyRing, opts...)
+	}
+	return nil, fmt.Errorf("unsupported token maker type %q", makerType)
+}
@@ -0,0 +1,127 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const pasetoV4PublicHeader = "v4.public."
+
+type pasetoFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// PasetoPublicMaker issues PASETO v4.public tokens signed with Ed25519.
+// The signing key id travels in the footer so verifiers can pick the right key.
+type PasetoPublicMaker struct {
+	keyRing *KeyRing
+	claims  claims
+}
+
+func NewPasetoPublicMaker(keyRing *KeyRing, opts ...Option) (Maker, error) {
+	if keyRing == nil {
+		return nil, ErrMissingKeyRing
+	}
+	return &PasetoPublicMaker{keyRing: keyRing, claims: newClaims(opts)}, nil
+}
+
+// CreateToken creates a new token for a specific username and password
+func (m *PasetoPublicMaker) CreateToken(username string, duration time.Duration) (string, error) {
+	payload, err := NewPayload(username, duration)
+	if err != nil {
+		return "", err
+	}
+	return m.CreateTokenFromPayload(payload)
+}
+
+// CreateTokenFromPayload signs an already built payload
+func (m *PasetoPublicMaker) CreateTokenFromPayload(payload *Payload) (string, error) {
+	m.claims.stamp(payload)
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	kid, signingKey := m.keyRing.SigningKey()
+	footer, err := json.Marshal(pasetoFooter{KeyID: kid})
+	if err != nil {
+		return "", err
+	}
+
+	signature := ed25519.Sign(signingKey, preAuthEncode([]byte(pasetoV4PublicHeader), message, footer, nil))
+
+	return pasetoV4PublicHeader +
+		base64.RawURLEncoding.EncodeToString(append(message, signature...)) + "." +
+		base64.RawURLEncoding.EncodeToString(footer), nil
+}
+
+// VerifyToken checks if  the token is valid or not
+func (m *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	if !strings.HasPrefix(token, pasetoV4PublicHeader) {
+		return nil, ErrInvalidToken
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, pasetoV4PublicHeader), ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(body) < ed25519.SignatureSize {
+		return nil, ErrInvalidToken
+	}
+
+	footer, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var decodedFooter pasetoFooter
+	if err := json.Unmarshal(footer, &decodedFooter); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	publicKey, err := m.keyRing.VerificationKey(decodedFooter.KeyID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(publicKey, preAuthEncode([]byte(pasetoV4PublicHeader), message, footer, nil), signature) {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := m.claims.verify(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// preAuthEncode implements PASETO's PAE so every piece is bound into the signature
+func preAuthEncode(pieces ...[]byte) []byte {
+	output := make([]byte, 8)
+	binary.LittleEndian.PutUint64(output, uint64(len(pieces)))
+
+	for _, piece := range pieces {
+		length := make([]byte, 8)
+		binary.LittleEndian.PutUint64(length, uint64(len(piece))&^(1<<63))
+		output = append(output, length...)
+		output = append(output, piece...)
+	}
+	return output
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/caleberi/simple-bank/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PasetoPublicMaker(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	username := utils.RandomOwner()
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, err := maker.CreateToken(username, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func Test_ExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(utils.RandomOwner(), -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func Test_TamperedPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	token, err := maker.CreateToken(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+
+	otherMaker, err := NewPasetoPublicMaker(newTestKeyRing(t, "primary"))
+	require.NoError(t, err)
+
+	// same key id, different key
+	payload, err := otherMaker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = maker.VerifyToken("v2.local." + token[len(pasetoV4PublicHeader):])
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func Test_PasetoV4PublicSignature(t *testing.T) {
+	// PASETO test vector 4-S-1
+	secretKey, err := hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774" +
+		"1eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2")
+	require.NoError(t, err)
+
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+	signature := ed25519.Sign(secretKey, preAuthEncode([]byte(pasetoV4PublicHeader), message, nil, nil))
+
+	expected := "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9" +
+		"bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+	require.Equal(t, expected, pasetoV4PublicHeader+base64.RawURLEncoding.EncodeToString(append(message, signature...)))
+}
@@ -11,9 +11,10 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	claims       claims
 }
 
-func NewPasetoMaker(symmetricKey string) (Maker, error) {
+func NewPasetoMaker(symmetricKey string, opts ...Option) (Maker, error) {
 	if len(symmetricKey) < chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid  key size : must be at least %d character(s)", chacha20poly1305.KeySize)
 	}
@@ -21,6 +22,7 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	maker := &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		claims:       newClaims(opts),
 	}
 	return maker, nil
 }
@@ -31,6 +33,12 @@ func (m *PasetoMaker) CreateToken(username string, duration time.Duration) (stri
 	if err != nil {
 		return "", err
 	}
+	return m.CreateTokenFromPayload(payload)
+}
+
+// CreateTokenFromPayload signs an already built payload
+func (m *PasetoMaker) CreateTokenFromPayload(payload *Payload) (string, error) {
+	m.claims.stamp(payload)
 	return m.paseto.Encrypt(m.symmetricKey, payload, nil)
 }
 
@@ -48,5 +56,9 @@ func (m *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if err = m.claims.verify(payload); err != nil {
+		return nil, err
+	}
 	return payload, nil
 }
@@ -49,3 +49,21 @@ func Test_ExpiredPasetoToken(t *testing.T) {
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func Test_PasetoTokenFromPayload(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	require.NoError(t, err)
+
+	payload, err := NewPayload(utils.RandomOwner(), time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, PurposeAccess, payload.Purpose)
+	payload.Purpose = PurposeMFA
+
+	token, err := maker.CreateTokenFromPayload(payload)
+	require.NoError(t, err)
+
+	verified, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, payload.ID, verified.ID)
+	require.Equal(t, PurposeMFA, verified.Purpose)
+}
@@ -8,15 +8,44 @@ import (
 )
 
 var (
-	ErrExpiredToken = errors.New("token has expired")
-	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken     = errors.New("token has expired")
+	ErrInvalidToken     = errors.New("token is invalid")
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+	ErrInvalidIssuer    = errors.New("token was issued by an unexpected issuer")
+	ErrInvalidAudience  = errors.New("token was not issued for this audience")
 )
 
+const (
+	// PurposeAccess marks a token that grants access to the authenticated API
+	PurposeAccess = "access"
+	// PurposeMFA marks an intermediate token that can only complete a two-factor login
+	PurposeMFA = "mfa"
+)
+
+const (
+	ScopeAccountsRead    = "accounts:read"
+	ScopeAccountsWrite   = "accounts:write"
+	ScopeTransfersCreate = "transfers:create"
+)
+
+// Payload is the set of claims carried by a token. The standard claims use
+// their registered names so other JWT and PASETO libraries recognise them,
+// PASETO encodes times as RFC 3339 strings and the JWT makers as NumericDate.
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiredAt time.Time `json:"expired_at"`
+	ID        uuid.UUID `json:"jti"`
+	Username  string    `json:"sub"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiredAt time.Time `json:"exp"`
+	NotBefore time.Time `json:"nbf"`
+	Issuer    string    `json:"iss,omitempty"`
+	Audience  string    `json:"aud,omitempty"`
+	Purpose   string    `json:"purpose"`
+	// Scopes restricts what the token may be used for, an empty list grants a full user session
+	Scopes []string `json:"scopes,omitempty"`
+	// ClientID is set on tokens issued to third-party apps through OAuth2
+	ClientID string `json:"client_id,omitempty"`
+	// AccountIDs restricts a delegated token to the accounts the user consented to
+	AccountIDs []int64 `json:"account_ids,omitempty"`
 }
 
 // NewPayload creates a new token payload with a specific username and duration
@@ -25,18 +54,52 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
-		IssuedAt:  time.Now(),
-		ExpiredAt: time.Now().Add(duration),
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+		Purpose:   PurposeAccess,
+		NotBefore: now,
 	}
 	return payload, nil
 }
 
 func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
+	now := time.Now()
+	if now.After(payload.ExpiredAt) {
 		return ErrExpiredToken
 	}
+	if now.Before(payload.NotBefore) {
+		return ErrTokenNotYetValid
+	}
 	return nil
 }
+
+// CanAccessAccount reports whether the token may act on the account. Tokens
+// issued to the user themselves aren't restricted to specific accounts.
+func (payload *Payload) CanAccessAccount(accountID int64) bool {
+	if payload.ClientID == "" {
+		return true
+	}
+	for _, id := range payload.AccountIDs {
+		if id == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the token grants scope
+func (payload *Payload) HasScope(scope string) bool {
+	if len(payload.Scopes) == 0 {
+		return true
+	}
+	for _, s := range payload.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}